@@ -47,6 +47,10 @@ func main() {
 	}
 
 	authService := service.NewAuthService(log)
+	// Рабочие пространства пользователей: AUTH_USER_TENANTS=student=team-a,admin=team-b
+	if tenants := os.Getenv("AUTH_USER_TENANTS"); tenants != "" {
+		authService.AssignTenants(tenants)
+	}
 	sessionService := service.NewSessionService(log)
 
 	go func() {
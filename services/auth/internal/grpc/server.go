@@ -50,11 +50,13 @@ func (s *AuthServer) Verify(ctx context.Context, req *pb.VerifyRequest) (*pb.Ver
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	log.Info("token verified", zap.String("subject", subject))
+	tenant := s.authService.Tenant(subject)
+	log.Info("token verified", zap.String("subject", subject), zap.String("tenant", tenant))
 
 	return &pb.VerifyResponse{
 		Valid:   true,
 		Subject: subject,
+		Tenant:  tenant,
 	}, nil
 }
 
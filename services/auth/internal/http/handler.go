@@ -40,6 +40,7 @@ type loginResponse struct {
 type verifyResponse struct {
 	Valid   bool   `json:"valid"`
 	Subject string `json:"subject,omitempty"`
+	Tenant  string `json:"tenant,omitempty"`
 	Error   string `json:"error,omitempty"`
 }
 
@@ -179,6 +180,7 @@ func (h *Handlers) Verify(w http.ResponseWriter, r *http.Request) {
 				json.NewEncoder(w).Encode(verifyResponse{
 					Valid:   true,
 					Subject: subject,
+					Tenant:  h.authService.Tenant(subject),
 				})
 				return
 			}
@@ -196,6 +198,7 @@ func (h *Handlers) Verify(w http.ResponseWriter, r *http.Request) {
 			json.NewEncoder(w).Encode(verifyResponse{
 				Valid:   true,
 				Subject: session.Subject,
+				Tenant:  h.authService.Tenant(session.Subject),
 			})
 			return
 		}
@@ -1,15 +1,21 @@
 package service
 
 import (
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
 	"tech-ip-sem2/shared/logger"
 )
 
+// DefaultTenant – рабочее пространство пользователей без явной привязки;
+// в установке для одной команды все пользователи в нем
+const DefaultTenant = "default"
+
 type User struct {
 	Username string
 	Password string
+	Tenant   string
 }
 
 type AuthService struct {
@@ -23,10 +29,12 @@ func NewAuthService(log *logger.Logger) *AuthService {
 		"student": {
 			Username: "student",
 			Password: "student",
+			Tenant:   DefaultTenant,
 		},
 		"admin": {
 			Username: "admin",
 			Password: "admin123",
+			Tenant:   DefaultTenant,
 		},
 	}
 
@@ -56,6 +64,39 @@ func (s *AuthService) ValidateCredentials(username, password string) bool {
 	return valid
 }
 
+// AssignTenants переносит пользователей в рабочие пространства по строке
+// вида "student=team-a,admin=team-b"; неизвестные пользователи пропускаются
+func (s *AuthService) AssignTenants(spec string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pair := range strings.Split(spec, ",") {
+		username, tenant, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		username, tenant = strings.TrimSpace(username), strings.TrimSpace(tenant)
+		user, exists := s.users[username]
+		if !ok || !exists || tenant == "" {
+			if pair != "" {
+				s.log.Warn("invalid tenant assignment", zap.String("assignment", pair))
+			}
+			continue
+		}
+		user.Tenant = tenant
+		s.users[username] = user
+		s.log.Info("user assigned to tenant", zap.String("username", username), zap.String("tenant", tenant))
+	}
+}
+
+// Tenant возвращает рабочее пространство пользователя
+func (s *AuthService) Tenant(subject string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if user, exists := s.users[subject]; exists && user.Tenant != "" {
+		return user.Tenant
+	}
+	return DefaultTenant
+}
+
 func (s *AuthService) ValidateToken(token string) (bool, string) {
 	switch token {
 	case "demo-token-for-student":
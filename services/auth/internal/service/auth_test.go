@@ -53,3 +53,22 @@ func TestValidateToken(t *testing.T) {
 		t.Errorf("Expected empty subject, got %s", subject)
 	}
 }
+
+func TestUserTenants(t *testing.T) {
+	log := logger.New("test")
+	service := NewAuthService(log)
+
+	// Тест 1: по умолчанию все в одном пространстве
+	if tenant := service.Tenant("student"); tenant != DefaultTenant {
+		t.Errorf("Expected default tenant, got %s", tenant)
+	}
+
+	// Тест 2: привязка к пространству, неизвестный пользователь пропускается
+	service.AssignTenants("student=team-a, unknown=team-b, admin")
+	if tenant := service.Tenant("student"); tenant != "team-a" {
+		t.Errorf("Expected team-a for student, got %s", tenant)
+	}
+	if tenant := service.Tenant("admin"); tenant != DefaultTenant {
+		t.Errorf("Expected default tenant for admin, got %s", tenant)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
@@ -16,6 +17,7 @@ import (
 	"tech-ip-sem2/services/graphql/internal/middleware"
 	"tech-ip-sem2/services/graphql/internal/repository"
 	"tech-ip-sem2/services/graphql/internal/service"
+	"tech-ip-sem2/shared/authclient"
 	"tech-ip-sem2/shared/logger"
 	sharedmw "tech-ip-sem2/shared/middleware"
 )
@@ -53,6 +55,22 @@ func main() {
 	}
 	defer repo.Close()
 
+	// Квота задач рабочих пространств по умолчанию, как в tasks сервисе
+	maxTenantTasks, _ := strconv.Atoi(os.Getenv("TENANT_MAX_TASKS"))
+	repo.SetMaxTasks(maxTenantTasks)
+
+	// Проверка токенов в auth сервисе: пользователь и его рабочее
+	// пространство. Без AUTH_GRPC_ADDR – демо-пользователь в default.
+	var verifier middleware.TokenVerifier
+	if authGRPCAddr := os.Getenv("AUTH_GRPC_ADDR"); authGRPCAddr != "" {
+		authClient, err := authclient.NewClient(authGRPCAddr, 3*time.Second, log)
+		if err != nil {
+			log.Fatal("Failed to create auth client", zap.Error(err))
+		}
+		defer authClient.Close()
+		verifier = authClient
+	}
+
 	// Сервисы
 	taskService := service.NewTaskService(repo, log)
 	resolver := resolvers.NewResolver(taskService, log)
@@ -80,7 +98,7 @@ func main() {
 
 	// Middleware
 	handler := sharedmw.RequestID(mux)
-	handler = middleware.AuthMiddleware(log)(handler)
+	handler = middleware.AuthMiddleware(log, verifier)(handler)
 	handler = sharedmw.AccessLog(log)(handler)
 
 	log.Info("GraphQL running", zap.Int("port", port))
@@ -2,10 +2,23 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"tech-ip-sem2/shared/due"
+)
+
+type CreateTagInput struct {
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+}
+
 type CreateTaskInput struct {
-	Title       string  `json:"title"`
-	Description *string `json:"description,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	DueDate     *due.Date `json:"due_date,omitempty"`
 }
 
 type Mutation struct {
@@ -14,19 +27,148 @@ type Mutation struct {
 type Query struct {
 }
 
+type Tag struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	TaskCount int    `json:"task_count"`
+}
+
 type Task struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Description *string `json:"description,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
-	Done        bool    `json:"done"`
-	CreatedAt   *string `json:"created_at,omitempty"`
-	UpdatedAt   *string `json:"updated_at,omitempty"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	DueDate     *due.Date `json:"due_date,omitempty"`
+	Overdue     bool      `json:"overdue"`
+	DueToday    bool      `json:"due_today"`
+	Done        bool      `json:"done"`
+	Version     int       `json:"version"`
+	Tags        []string  `json:"tags"`
+	Assignee    *string   `json:"assignee,omitempty"`
+	Owner       *string   `json:"owner,omitempty"`
+	CreatedAt   *string   `json:"created_at,omitempty"`
+	UpdatedAt   *string   `json:"updated_at,omitempty"`
+}
+
+type UpdateTagInput struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
 }
 
 type UpdateTaskInput struct {
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
-	Done        *bool   `json:"done,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	DueDate     *due.Date `json:"due_date,omitempty"`
+	Done        *bool     `json:"done,omitempty"`
+	Version     *int      `json:"version,omitempty"`
+}
+
+type DueFilter string
+
+const (
+	DueFilterOverdue DueFilter = "OVERDUE"
+	DueFilterToday   DueFilter = "TODAY"
+)
+
+var AllDueFilter = []DueFilter{
+	DueFilterOverdue,
+	DueFilterToday,
+}
+
+func (e DueFilter) IsValid() bool {
+	switch e {
+	case DueFilterOverdue, DueFilterToday:
+		return true
+	}
+	return false
+}
+
+func (e DueFilter) String() string {
+	return string(e)
+}
+
+func (e *DueFilter) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DueFilter(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DueFilter", str)
+	}
+	return nil
+}
+
+func (e DueFilter) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DueFilter) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DueFilter) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TagMode string
+
+const (
+	TagModeAll TagMode = "ALL"
+	TagModeAny TagMode = "ANY"
+)
+
+var AllTagMode = []TagMode{
+	TagModeAll,
+	TagModeAny,
+}
+
+func (e TagMode) IsValid() bool {
+	switch e {
+	case TagModeAll, TagModeAny:
+		return true
+	}
+	return false
+}
+
+func (e TagMode) String() string {
+	return string(e)
+}
+
+func (e *TagMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TagMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TagMode", str)
+	}
+	return nil
+}
+
+func (e TagMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TagMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TagMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
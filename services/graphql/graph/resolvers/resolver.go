@@ -6,6 +6,11 @@ package resolvers
 // here.
 
 import (
+	"context"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"tech-ip-sem2/services/graphql/internal/middleware"
+	"tech-ip-sem2/services/graphql/internal/repository"
 	"tech-ip-sem2/services/graphql/internal/service"
 	"tech-ip-sem2/shared/logger"
 )
@@ -21,3 +26,22 @@ func NewResolver(taskService *service.TaskService, log *logger.Logger) *Resolver
 		log:         log,
 	}
 }
+
+// service возвращает сервис задач в рабочем пространстве пользователя запроса
+func (r *Resolver) service(ctx context.Context) *service.TaskService {
+	return r.taskService.ForTenant(middleware.GetTenant(ctx))
+}
+
+func quotaError(err *repository.QuotaError) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    err.Error(),
+		Extensions: map[string]interface{}{"code": "FORBIDDEN"},
+	}
+}
+
+func tagExistsError() *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    "tag with this name already exists",
+		Extensions: map[string]interface{}{"code": "CONFLICT"},
+	}
+}
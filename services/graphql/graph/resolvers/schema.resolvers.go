@@ -2,12 +2,15 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"go.uber.org/zap"
 	"tech-ip-sem2/services/graphql/graph/generated"
 	"tech-ip-sem2/services/graphql/graph/model"
 	"tech-ip-sem2/services/graphql/internal/middleware"
+	"tech-ip-sem2/services/graphql/internal/repository"
 )
 
 // CreateTask is the resolver for the createTask field.
@@ -18,7 +21,11 @@ func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTas
 
 	subject := middleware.GetSubject(ctx)
 
-	task, err := r.taskService.CreateTask(input, subject)
+	task, err := r.service(ctx).CreateTask(input, subject)
+	var quotaErr *repository.QuotaError
+	if errors.As(err, &quotaErr) {
+		return nil, quotaError(quotaErr)
+	}
 	if err != nil {
 		r.log.Error("failed to create task", zap.Error(err))
 		return nil, fmt.Errorf("failed to create task: %w", err)
@@ -35,7 +42,20 @@ func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input mode
 
 	subject := middleware.GetSubject(ctx)
 
-	task, err := r.taskService.UpdateTask(id, input, subject)
+	task, err := r.service(ctx).UpdateTask(id, input, subject)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		r.log.Info("task version conflict", zap.String("id", id))
+		return nil, &gqlerror.Error{
+			Message:    "task was modified by another request",
+			Extensions: map[string]interface{}{"code": "CONFLICT"},
+		}
+	}
+	if errors.Is(err, repository.ErrManagedTask) {
+		return nil, &gqlerror.Error{
+			Message:    err.Error(),
+			Extensions: map[string]interface{}{"code": "FAILED_PRECONDITION"},
+		}
+	}
 	if err != nil {
 		r.log.Error("failed to update task", zap.Error(err), zap.String("id", id))
 		return nil, fmt.Errorf("failed to update task: %w", err)
@@ -56,7 +76,7 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, err
 
 	subject := middleware.GetSubject(ctx)
 
-	deleted, err := r.taskService.DeleteTask(id, subject)
+	deleted, err := r.service(ctx).DeleteTask(id, subject)
 	if err != nil {
 		r.log.Error("failed to delete task", zap.Error(err), zap.String("id", id))
 		return false, fmt.Errorf("failed to delete task: %w", err)
@@ -65,13 +85,102 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, err
 	return deleted, nil
 }
 
+// CreateTag is the resolver for the createTag field.
+func (r *mutationResolver) CreateTag(ctx context.Context, input model.CreateTagInput) (*model.Tag, error) {
+	r.log.Info("GraphQL mutation: createTag",
+		zap.String("name", input.Name),
+	)
+
+	subject := middleware.GetSubject(ctx)
+
+	tag, err := r.service(ctx).CreateTag(input, subject)
+	if errors.Is(err, repository.ErrTagExists) {
+		return nil, tagExistsError()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// UpdateTag is the resolver for the updateTag field.
+func (r *mutationResolver) UpdateTag(ctx context.Context, id string, input model.UpdateTagInput) (*model.Tag, error) {
+	r.log.Info("GraphQL mutation: updateTag",
+		zap.String("id", id),
+	)
+
+	subject := middleware.GetSubject(ctx)
+
+	tag, err := r.service(ctx).UpdateTag(id, input, subject)
+	if errors.Is(err, repository.ErrTagExists) {
+		return nil, tagExistsError()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, id string) (bool, error) {
+	r.log.Info("GraphQL mutation: deleteTag",
+		zap.String("id", id),
+	)
+
+	subject := middleware.GetSubject(ctx)
+
+	deleted, err := r.service(ctx).DeleteTag(id, subject)
+	if err != nil {
+		r.log.Error("failed to delete tag", zap.Error(err), zap.String("id", id))
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// AddTaskTags is the resolver for the addTaskTags field.
+func (r *mutationResolver) AddTaskTags(ctx context.Context, taskID string, tags []string) (*model.Task, error) {
+	r.log.Info("GraphQL mutation: addTaskTags",
+		zap.String("task_id", taskID),
+		zap.Strings("tags", tags),
+	)
+
+	subject := middleware.GetSubject(ctx)
+
+	task, err := r.service(ctx).AddTaskTags(taskID, tags, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add task tags: %w", err)
+	}
+
+	return task, nil
+}
+
+// RemoveTaskTag is the resolver for the removeTaskTag field.
+func (r *mutationResolver) RemoveTaskTag(ctx context.Context, taskID string, tag string) (*model.Task, error) {
+	r.log.Info("GraphQL mutation: removeTaskTag",
+		zap.String("task_id", taskID),
+		zap.String("tag", tag),
+	)
+
+	subject := middleware.GetSubject(ctx)
+
+	task, err := r.service(ctx).RemoveTaskTag(taskID, tag, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove task tag: %w", err)
+	}
+
+	return task, nil
+}
+
 // Tasks is the resolver for the tasks field.
-func (r *queryResolver) Tasks(ctx context.Context) ([]*model.Task, error) {
+func (r *queryResolver) Tasks(ctx context.Context, tags []string, tagMode *model.TagMode, due *model.DueFilter, assignee *string) ([]*model.Task, error) {
 	r.log.Info("GraphQL query: tasks")
 
 	subject := middleware.GetSubject(ctx)
 
-	tasks, err := r.taskService.GetAllTasks(subject)
+	tasks, err := r.service(ctx).GetAllTasks(subject, tags, tagMode, due, assignee)
 	if err != nil {
 		r.log.Error("failed to get tasks", zap.Error(err))
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
@@ -88,7 +197,7 @@ func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error
 
 	subject := middleware.GetSubject(ctx)
 
-	task, err := r.taskService.GetTaskByID(id, subject)
+	task, err := r.service(ctx).GetTaskByID(id, subject)
 	if err != nil {
 		r.log.Error("failed to get task", zap.Error(err), zap.String("id", id))
 		return nil, fmt.Errorf("failed to get task: %w", err)
@@ -97,6 +206,35 @@ func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error
 	return task, nil
 }
 
+// AssignedTasks is the resolver for the assignedTasks field.
+func (r *queryResolver) AssignedTasks(ctx context.Context) ([]*model.Task, error) {
+	r.log.Info("GraphQL query: assignedTasks")
+
+	subject := middleware.GetSubject(ctx)
+
+	tasks, err := r.service(ctx).GetAssignedTasks(subject)
+	if err != nil {
+		r.log.Error("failed to get assigned tasks", zap.Error(err))
+		return nil, fmt.Errorf("failed to get assigned tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context) ([]*model.Tag, error) {
+	r.log.Info("GraphQL query: tags")
+
+	subject := middleware.GetSubject(ctx)
+
+	tags, err := r.service(ctx).GetTags(subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	return tags, nil
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -2,9 +2,12 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 
+	"go.uber.org/zap"
+	"tech-ip-sem2/shared/authclient"
 	"tech-ip-sem2/shared/logger"
 )
 
@@ -12,20 +15,52 @@ type contextKey string
 
 const (
 	SubjectKey contextKey = "subject"
+	TenantKey  contextKey = "tenant"
 )
 
-func AuthMiddleware(log *logger.Logger) func(http.Handler) http.Handler {
+// DefaultTenant – рабочее пространство пользователей, для которых auth
+// сервис пространство не сообщает (как в tasks сервисе)
+const DefaultTenant = "default"
+
+// TokenVerifier проверяет Bearer токен в auth сервисе
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (bool, authclient.Identity, error)
+}
+
+// AuthMiddleware определяет пользователя и его рабочее пространство. Без
+// verifier (AUTH_GRPC_ADDR не задан) любой Bearer токен – демо-пользователь
+// student в пространстве default.
+func AuthMiddleware(log *logger.Logger, verifier TokenVerifier) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := r.Context()
+			ctx := context.WithValue(r.Context(), TenantKey, DefaultTenant)
 
 			// Authorization
 			authHeader := r.Header.Get("Authorization")
 			if authHeader != "" {
 				parts := strings.Split(authHeader, " ")
 				if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-					ctx = context.WithValue(ctx, SubjectKey, "student")
-					log.Debug("authenticated via token")
+					subject, tenant := "student", DefaultTenant
+					if verifier != nil {
+						valid, identity, err := verifier.VerifyToken(r.Context(), parts[1])
+						if err != nil || !valid {
+							if err != nil {
+								log.Error("token verification failed", zap.Error(err))
+							}
+							w.Header().Set("Content-Type", "application/json")
+							w.WriteHeader(http.StatusUnauthorized)
+							json.NewEncoder(w).Encode(map[string]string{"error": "unauthorized"})
+							return
+						}
+						subject = identity.Subject
+						if identity.Tenant != "" {
+							tenant = identity.Tenant
+						}
+					}
+
+					ctx = context.WithValue(ctx, SubjectKey, subject)
+					ctx = context.WithValue(ctx, TenantKey, tenant)
+					log.Debug("authenticated via token", zap.String("tenant", tenant))
 					next.ServeHTTP(w, r.WithContext(ctx))
 					return
 				}
@@ -54,3 +89,11 @@ func GetSubject(ctx context.Context) string {
 	}
 	return "anonymous"
 }
+
+// GetTenant возвращает рабочее пространство пользователя запроса
+func GetTenant(ctx context.Context) string {
+	if tenant, ok := ctx.Value(TenantKey).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
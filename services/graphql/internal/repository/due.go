@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"tech-ip-sem2/services/graphql/graph/model"
+	"tech-ip-sem2/shared/due"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask читает колонки taskColumns и следующие за ними extra; срок
+// собирается из due_date и due_at
+func scanTask(row rowScanner, extra ...any) (*model.Task, error) {
+	task := &model.Task{}
+	var dueDay, assignee sql.NullString
+	var dueAt *time.Time
+	var createdAt, updatedAt time.Time
+	err := row.Scan(append([]any{
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&dueDay,
+		&dueAt,
+		&task.Done,
+		&task.Version,
+		&assignee,
+		&createdAt,
+		&updatedAt,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if dueDate := due.FromColumns(dueDay.String, dueAt); !dueDate.IsZero() {
+		task.DueDate = &dueDate
+	}
+	if assignee.Valid {
+		task.Assignee = &assignee.String
+	}
+	createdAtStr := createdAt.Format(time.RFC3339)
+	updatedAtStr := updatedAt.Format(time.RFC3339)
+	task.CreatedAt = &createdAtStr
+	task.UpdatedAt = &updatedAtStr
+	return task, nil
+}
+
+// dueValues возвращает значения колонок due_date и due_at
+func dueValues(dueDate *due.Date) (any, any) {
+	if dueDate == nil {
+		return nil, nil
+	}
+	return dueDate.DayValue(), dueDate.TimeValue()
+}
+
+// dueFilterCondition – то же условие, что у фильтра ?due= REST API
+func dueFilterCondition(filter model.DueFilter, loc *time.Location, args []any) (string, []any) {
+	now := time.Now()
+	today := now.In(loc).Format(time.DateOnly)
+
+	switch filter {
+	case model.DueFilterOverdue:
+		args = append(args, now, today)
+		return `
+          AND done = FALSE
+          AND ((due_at IS NOT NULL AND due_at <= $` + strconv.Itoa(len(args)-1) + `)
+            OR (due_at IS NULL AND due_date < $` + strconv.Itoa(len(args)) + `))`, args
+	case model.DueFilterToday:
+		args = append(args, today)
+		return `
+          AND due_date = $` + strconv.Itoa(len(args)), args
+	}
+	return "", args
+}
+
+func (r *PostgresTaskRepository) Location(subject string) (*time.Location, error) {
+	var timezone string
+	err := r.db.QueryRow(`SELECT timezone FROM user_settings WHERE subject = $1`, subject).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// setDueFlags вычисляет overdue и due_today в часовом поясе пользователя
+func (r *PostgresTaskRepository) setDueFlags(tasks []*model.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	loc, err := r.Location(subject)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		task.Overdue = !task.Done && task.DueDate.Overdue(now, loc)
+		task.DueToday = task.DueDate.DueToday(now, loc)
+	}
+	return nil
+}
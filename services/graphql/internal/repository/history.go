@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// ErrManagedTask – изменение задачи требует логики tasks сервиса: статусов
+// процесса проекта, зависимостей, серии или напоминаний
+var ErrManagedTask = errors.New("task has a workflow, dependencies, a series or reminders: change it via tasks REST API")
+
+// Действия ревизий (как в tasks сервисе)
+const (
+	actionCreated = "created"
+	actionUpdated = "updated"
+	actionDeleted = "deleted"
+)
+
+// taskSnapshot – состояние задачи в истории в формате tasks сервиса: по нему
+// REST API показывает историю и откатывает задачу
+type taskSnapshot struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	DueDate         due.Date `json:"due_date"`
+	Done            bool     `json:"done"`
+	Status          string   `json:"status,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	EstimatePoints  int      `json:"estimate_points,omitempty"`
+	EstimateMinutes int      `json:"estimate_minutes,omitempty"`
+	ProjectID       string   `json:"project_id,omitempty"`
+	ParentID        string   `json:"parent_id,omitempty"`
+	AutoComplete    bool     `json:"auto_complete,omitempty"`
+	Assignee        string   `json:"assignee,omitempty"`
+}
+
+// fieldChange – изменение одного поля; Old == nil при создании задачи
+type fieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Колонки задачи в порядке, ожидаемом scanSnapshot
+const snapshotColumns = `title, COALESCE(description, ''), due_date, due_at, done, status, priority,
+            estimate_points, estimate_minutes, COALESCE(project_id, ''), COALESCE(parent_id, ''),
+            auto_complete, COALESCE(assignee, '')`
+
+// scanSnapshot читает колонки snapshotColumns
+func scanSnapshot(row rowScanner) (taskSnapshot, error) {
+	var state taskSnapshot
+	var dueDay sql.NullString
+	var dueAt *time.Time
+	err := row.Scan(
+		&state.Title,
+		&state.Description,
+		&dueDay,
+		&dueAt,
+		&state.Done,
+		&state.Status,
+		&state.Priority,
+		&state.EstimatePoints,
+		&state.EstimateMinutes,
+		&state.ProjectID,
+		&state.ParentID,
+		&state.AutoComplete,
+		&state.Assignee,
+	)
+	if err != nil {
+		return taskSnapshot{}, err
+	}
+	state.DueDate = due.FromColumns(dueDay.String, dueAt)
+	return state, nil
+}
+
+// snapshot читает состояние задачи для истории в транзакции ее изменения
+func snapshot(tx *sql.Tx, id string) (taskSnapshot, error) {
+	state, err := scanSnapshot(tx.QueryRow(`SELECT `+snapshotColumns+` FROM tasks WHERE id = $1`, id))
+	if err != nil {
+		return taskSnapshot{}, fmt.Errorf("failed to read task snapshot: %w", err)
+	}
+	return state, nil
+}
+
+// diffSnapshots возвращает поля, которые меняет GraphQL API
+func diffSnapshots(before, after taskSnapshot) []fieldChange {
+	changes := []fieldChange{}
+	if before.Title != after.Title {
+		changes = append(changes, fieldChange{Field: "title", Old: before.Title, New: after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, fieldChange{Field: "description", Old: before.Description, New: after.Description})
+	}
+	if !before.DueDate.Equal(after.DueDate) {
+		changes = append(changes, fieldChange{Field: "due_date", Old: before.DueDate, New: after.DueDate})
+	}
+	if before.Done != after.Done {
+		changes = append(changes, fieldChange{Field: "done", Old: before.Done, New: after.Done})
+	}
+	if before.Status != after.Status {
+		changes = append(changes, fieldChange{Field: "status", Old: before.Status, New: after.Status})
+	}
+	return changes
+}
+
+// creationChanges описывает создание задачи как изменения с пустыми старыми
+// значениями
+func creationChanges(state taskSnapshot) []fieldChange {
+	return []fieldChange{
+		{Field: "title", New: state.Title},
+		{Field: "description", New: state.Description},
+		{Field: "due_date", New: state.DueDate},
+		{Field: "done", New: state.Done},
+		{Field: "status", New: state.Status},
+	}
+}
+
+// addRevision сохраняет ревизию задачи в транзакции ее изменения. Номер
+// ревизии совпадает с версией задачи после изменения.
+func addRevision(tx *sql.Tx, taskID string, version int, subject, action string, changes []fieldChange, state taskSnapshot) error {
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision changes: %w", err)
+	}
+	snapshotJSON, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision snapshot: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO task_revisions (task_id, revision, subject, action, actor, request_id, changes, snapshot, created_at)
+        VALUES ($1, $2, $3, $4, $3, '', $5, $6, $7)
+    `, taskID, version, subject, action, string(changesJSON), string(snapshotJSON), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add task revision: %w", err)
+	}
+	return nil
+}
+
+// checkPlainTask возвращает ErrManagedTask, если смену выполнения или срока
+// задачи должен делать tasks сервис: выполнение зависит от процесса проекта,
+// зависимостей, серии, напоминаний и автовыполнения родителя, а срок – от
+// серии и напоминаний
+func checkPlainTask(tx *sql.Tx, id string, doneChanged, dueChanged bool) error {
+	var workflow, series, reminders, dependencies, autoParent bool
+	err := tx.QueryRow(`
+        SELECT
+            EXISTS (SELECT 1 FROM projects p WHERE p.id = t.project_id AND p.workflow IS NOT NULL),
+            t.series_id IS NOT NULL,
+            EXISTS (SELECT 1 FROM task_reminders r WHERE r.task_id = t.id AND r.fired_at IS NULL),
+            EXISTS (SELECT 1 FROM task_dependencies d WHERE d.task_id = t.id OR d.blocked_by_id = t.id),
+            EXISTS (SELECT 1 FROM tasks p WHERE p.id = t.parent_id AND p.auto_complete)
+        FROM tasks t
+        WHERE t.id = $1
+    `, id).Scan(&workflow, &series, &reminders, &dependencies, &autoParent)
+	if err != nil {
+		return fmt.Errorf("failed to check task relations: %w", err)
+	}
+
+	if doneChanged && (workflow || series || reminders || dependencies || autoParent) {
+		return ErrManagedTask
+	}
+	if dueChanged && (series || reminders) {
+		return ErrManagedTask
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package repository
+
+// Задачи архивных проектов скрыты из списка по умолчанию
+const notInArchivedProject = `
+          AND (project_id IS NULL OR project_id NOT IN (
+              SELECT id FROM projects WHERE subject = $1 AND archived
+          ))`
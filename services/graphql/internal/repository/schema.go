@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// requiredMigration – последняя миграция tasks сервиса, на схему которой
+// рассчитаны запросы GraphQL API. Схему создает и обновляет только tasks
+// сервис, GraphQL API ее не меняет.
+const requiredMigration = "0023_create_workspaces"
+
+// checkSchema проверяет, что tasks сервис применил нужные миграции: запуск
+// на старой схеме завершается ошибкой, а не ошибками отдельных запросов
+func checkSchema(db *sql.DB) error {
+	var applied int
+	err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, requiredMigration).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to check tasks service migrations (is tasks service started?): %w", err)
+	}
+	if applied == 0 {
+		return fmt.Errorf("tasks service migration %s is not applied: start tasks service first", requiredMigration)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"tech-ip-sem2/services/graphql/graph/model"
+)
+
+// ErrTagExists – у пользователя уже есть метка с таким именем
+var ErrTagExists = errors.New("tag already exists")
+
+// Цвет метки по умолчанию (как в tasks сервисе)
+const DefaultTagColor = "#808080"
+
+func (r *PostgresTaskRepository) GetTags(subject string) ([]*model.Tag, error) {
+	query := `
+        SELECT t.id, t.name, t.color, COUNT(tk.id)
+        FROM tags t
+        LEFT JOIN task_tags tt ON tt.tag_id = t.id
+        LEFT JOIN tasks tk ON tk.id = tt.task_id AND tk.deleted_at IS NULL
+        WHERE t.subject = $1
+        GROUP BY t.id, t.name, t.color
+        ORDER BY t.name
+    `
+
+	rows, err := r.db.Query(query, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*model.Tag
+	for rows.Next() {
+		tag := &model.Tag{}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.TaskCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *PostgresTaskRepository) CreateTag(name, color, subject string) (*model.Tag, error) {
+	tag := &model.Tag{ID: uuid.New().String(), Name: name, Color: color}
+
+	result, err := r.db.Exec(`
+        INSERT INTO tags (id, subject, name, color, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (subject, name) DO NOTHING
+    `, tag.ID, subject, name, color, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if inserted == 0 {
+		return nil, ErrTagExists
+	}
+
+	return tag, nil
+}
+
+func (r *PostgresTaskRepository) UpdateTag(id string, input model.UpdateTagInput, subject string) (*model.Tag, error) {
+	tag := &model.Tag{}
+	err := r.db.QueryRow(`SELECT id, name, color FROM tags WHERE id = $1 AND subject = $2`, id, subject).
+		Scan(&tag.ID, &tag.Name, &tag.Color)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	if input.Name != nil {
+		tag.Name = *input.Name
+	}
+	if input.Color != nil {
+		tag.Color = *input.Color
+	}
+
+	_, err = r.db.Exec(`UPDATE tags SET name = $1, color = $2 WHERE id = $3 AND subject = $4`,
+		tag.Name, tag.Color, id, subject)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, ErrTagExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r *PostgresTaskRepository) DeleteTag(id string, subject string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM tags WHERE id = $1 AND subject = $2`, id, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// AddTaskTags назначает метки задаче, создавая недостающие
+func (r *PostgresTaskRepository) AddTaskTags(taskID string, names []string, subject string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, name := range names {
+		_, err := tx.Exec(`
+            INSERT INTO tags (id, subject, name, color, created_at)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (subject, name) DO NOTHING
+        `, uuid.New().String(), subject, name, DefaultTagColor, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+
+		_, err = tx.Exec(`
+            INSERT INTO task_tags (task_id, tag_id)
+            SELECT $1, id FROM tags WHERE subject = $2 AND name = $3
+            ON CONFLICT (task_id, tag_id) DO NOTHING
+        `, taskID, subject, name)
+		if err != nil {
+			return fmt.Errorf("failed to assign tag: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresTaskRepository) RemoveTaskTag(taskID string, name string, subject string) error {
+	_, err := r.db.Exec(`
+        DELETE FROM task_tags
+        WHERE task_id = $1 AND tag_id IN (SELECT id FROM tags WHERE subject = $2 AND name = $3)
+    `, taskID, subject, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+	return nil
+}
+
+// loadTags заполняет метки у задач пользователя
+func (r *PostgresTaskRepository) loadTags(tasks []*model.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		task.Tags = []string{}
+	}
+
+	rows, err := r.db.Query(`
+        SELECT tt.task_id, tg.name
+        FROM task_tags tt
+        JOIN tags tg ON tg.id = tt.tag_id
+        WHERE tg.subject = $1 AND tt.task_id = ANY($2)
+        ORDER BY tg.name
+    `, subject, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query task tags: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]*model.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	for rows.Next() {
+		var taskID, name string
+		if err := rows.Scan(&taskID, &name); err != nil {
+			return fmt.Errorf("failed to scan task tag: %w", err)
+		}
+		byID[taskID].Tags = append(byID[taskID].Tags, name)
+	}
+
+	return rows.Err()
+}
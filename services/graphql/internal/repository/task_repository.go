@@ -2,24 +2,52 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"tech-ip-sem2/services/graphql/graph/model"
+	"tech-ip-sem2/shared/due"
 )
 
+// ErrVersionConflict – задача была изменена другим запросом
+var ErrVersionConflict = errors.New("task version conflict")
+
 type TaskRepository interface {
 	Create(task *model.Task, subject string) (*model.Task, error)
-	GetAll(subject string) ([]*model.Task, error)
+	// Без меток возвращает все задачи; matchAll – задача должна иметь все метки,
+	// dueFilter (может быть nil) оставляет просроченные задачи или со сроком сегодня,
+	// assignee (может быть nil) – задачи исполнителя
+	GetAll(subject string, tags []string, matchAll bool, dueFilter *model.DueFilter, assignee *string) ([]*model.Task, error)
+	// Задачи, назначенные пользователю, в том числе чужие (с владельцем)
+	AssignedTasks(subject string) ([]*model.Task, error)
 	GetByID(id string, subject string) (*model.Task, error)
 	Update(id string, input model.UpdateTaskInput, subject string) (*model.Task, error)
 	Delete(id string, subject string) (bool, error)
+
+	GetTags(subject string) ([]*model.Tag, error)
+	CreateTag(name, color, subject string) (*model.Tag, error)
+	UpdateTag(id string, input model.UpdateTagInput, subject string) (*model.Tag, error)
+	DeleteTag(id string, subject string) (bool, error)
+	AddTaskTags(taskID string, names []string, subject string) error
+	RemoveTaskTag(taskID string, name string, subject string) error
+	// Часовой пояс пользователя из настроек tasks сервиса (по умолчанию UTC)
+	Location(subject string) (*time.Location, error)
 	Close() error
+
+	// Репозиторий, запросы которого ограничены пространством tenant
+	ForTenant(tenant string) TaskRepository
 }
 
+// Колонки задачи в порядке, ожидаемом scanTask
+const taskColumns = `id, title, description, due_date, due_at, done, version, assignee, created_at, updated_at`
+
 type PostgresTaskRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	tenant   string
+	maxTasks int
 }
 
 func NewPostgresTaskRepository(connStr string) (*PostgresTaskRepository, error) {
@@ -32,20 +60,10 @@ func NewPostgresTaskRepository(connStr string) (*PostgresTaskRepository, error)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	_, err = db.Exec(`
-        CREATE TABLE IF NOT EXISTS tasks (
-            id VARCHAR(50) PRIMARY KEY,
-            title VARCHAR(255) NOT NULL,
-            description TEXT,
-            due_date DATE,
-            done BOOLEAN DEFAULT FALSE,
-            subject VARCHAR(100) NOT NULL,
-            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-            updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-        )
-    `)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create table: %w", err)
+	// Схему создают миграции tasks сервиса
+	if err := checkSchema(db); err != nil {
+		db.Close()
+		return nil, err
 	}
 
 	return &PostgresTaskRepository{
@@ -57,56 +75,139 @@ func (r *PostgresTaskRepository) Close() error {
 	return r.db.Close()
 }
 
+// SetMaxTasks задает квоту задач пространств, для которых в базе не задана
+// своя (TENANT_MAX_TASKS tasks сервиса); 0 – без ограничения
+func (r *PostgresTaskRepository) SetMaxTasks(limit int) {
+	r.maxTasks = limit
+}
+
+// ForTenant возвращает копию репозитория, работающую в пространстве tenant
+func (r *PostgresTaskRepository) ForTenant(tenant string) TaskRepository {
+	scoped := *r
+	scoped.tenant = tenant
+	return &scoped
+}
+
+// workspace возвращает пространство, которым ограничены запросы репозитория
+func (r *PostgresTaskRepository) workspace() string {
+	if r.tenant == "" {
+		return defaultTenant
+	}
+	return r.tenant
+}
+
+// tenantCondition добавляет к запросу условие на пространство репозитория
+func (r *PostgresTaskRepository) tenantCondition(args []any) (string, []any) {
+	args = append(args, r.workspace())
+	return " AND tenant = $" + strconv.Itoa(len(args)), args
+}
+
+// Create создает задачу в пространстве репозитория. Квота задач проверяется
+// в той же транзакции под блокировкой пространства, как в tasks сервисе.
 func (r *PostgresTaskRepository) Create(task *model.Task, subject string) (*model.Task, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.checkTaskQuota(tx); err != nil {
+		return nil, err
+	}
+
 	query := `
-        INSERT INTO tasks (id, title, description, due_date, done, subject, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-        RETURNING id, title, description, due_date, done, created_at, updated_at
-    `
+        INSERT INTO tasks (id, title, description, due_date, due_at, done, status, subject, tenant, created_at, updated_at, completed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $6 THEN 'done' ELSE 'todo' END, $7, $8, $9, $10, CASE WHEN $6 THEN $10 END)
+        RETURNING ` + taskColumns
 
 	now := time.Now()
-	createdAt := now.Format(time.RFC3339)
-	updatedAt := now.Format(time.RFC3339)
+	dueDay, dueAt := dueValues(task.DueDate)
 
-	err := r.db.QueryRow(
+	created, err := scanTask(tx.QueryRow(
 		query,
 		task.ID,
 		task.Title,
 		task.Description,
-		task.DueDate,
+		dueDay,
+		dueAt,
 		task.Done,
 		subject,
+		r.workspace(),
 		now,
 		now,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&createdAt,
-		&updatedAt,
-	)
+	))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	task.CreatedAt = &createdAt
-	task.UpdatedAt = &updatedAt
+	// Первая ревизия истории, как у задач REST API
+	state, err := snapshot(tx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := addRevision(tx, created.ID, created.Version, subject, actionCreated, creationChanges(state), state); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit task: %w", err)
+	}
 
-	return task, nil
+	created.Tags = []string{}
+	if err := r.setDueFlags([]*model.Task{created}, subject); err != nil {
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (r *PostgresTaskRepository) GetAll(subject string) ([]*model.Task, error) {
+func (r *PostgresTaskRepository) GetAll(subject string, tags []string, matchAll bool, dueFilter *model.DueFilter, assignee *string) ([]*model.Task, error) {
+	args := []any{subject}
+	dueCondition := ""
+	if dueFilter != nil {
+		loc, err := r.Location(subject)
+		if err != nil {
+			return nil, err
+		}
+		dueCondition, args = dueFilterCondition(*dueFilter, loc, args)
+	}
+	assigneeCondition := ""
+	if assignee != nil {
+		args = append(args, *assignee)
+		assigneeCondition = ` AND assignee = $` + strconv.Itoa(len(args))
+	}
+	tenantCondition, args := r.tenantCondition(args)
+
 	query := `
-        SELECT id, title, description, due_date, done, created_at, updated_at
+        SELECT ` + taskColumns + `
+        FROM tasks
+        WHERE subject = $1 AND deleted_at IS NULL` + tenantCondition + notInArchivedProject + dueCondition + assigneeCondition + `
+        ORDER BY created_at DESC
+    `
+
+	if len(tags) > 0 {
+		// Число совпавших меток: для ALL нужно совпадение всех
+		required := 1
+		if matchAll {
+			required = len(tags)
+		}
+		args = append(args, pq.Array(tags), required)
+		query = `
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE subject = $1
+        WHERE subject = $1 AND deleted_at IS NULL` + tenantCondition + notInArchivedProject + dueCondition + assigneeCondition + ` AND id IN (
+            SELECT tt.task_id
+            FROM task_tags tt
+            JOIN tags tg ON tg.id = tt.tag_id
+            WHERE tg.subject = $1 AND tg.name = ANY($` + strconv.Itoa(len(args)-1) + `)
+            GROUP BY tt.task_id
+            HAVING COUNT(DISTINCT tg.id) >= $` + strconv.Itoa(len(args)) + `
+        )
         ORDER BY created_at DESC
     `
+	}
 
-	rows, err := r.db.Query(query, subject)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
@@ -114,48 +215,34 @@ func (r *PostgresTaskRepository) GetAll(subject string) ([]*model.Task, error) {
 
 	var tasks []*model.Task
 	for rows.Next() {
-		task := &model.Task{}
-		var createdAt, updatedAt time.Time
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.DueDate,
-			&task.Done,
-			&createdAt,
-			&updatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		createdAtStr := createdAt.Format(time.RFC3339)
-		updatedAtStr := updatedAt.Format(time.RFC3339)
-		task.CreatedAt = &createdAtStr
-		task.UpdatedAt = &updatedAtStr
 		tasks = append(tasks, task)
 	}
+	rows.Close()
+
+	if err := r.loadTags(tasks, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.setDueFlags(tasks, subject); err != nil {
+		return nil, err
+	}
 
 	return tasks, nil
 }
 
 func (r *PostgresTaskRepository) GetByID(id string, subject string) (*model.Task, error) {
+	tenantCondition, args := r.tenantCondition([]any{id, subject})
 	query := `
-        SELECT id, title, description, due_date, done, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE id = $1 AND subject = $2
+        WHERE id = $1 AND subject = $2 AND deleted_at IS NULL` + tenantCondition + `
     `
 
-	task := &model.Task{}
-	var createdAt, updatedAt time.Time
-	err := r.db.QueryRow(query, id, subject).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&createdAt,
-		&updatedAt,
-	)
+	task, err := scanTask(r.db.QueryRow(query, args...))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -164,22 +251,96 @@ func (r *PostgresTaskRepository) GetByID(id string, subject string) (*model.Task
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	createdAtStr := createdAt.Format(time.RFC3339)
-	updatedAtStr := updatedAt.Format(time.RFC3339)
-	task.CreatedAt = &createdAtStr
-	task.UpdatedAt = &updatedAtStr
+	if err := r.loadTags([]*model.Task{task}, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.setDueFlags([]*model.Task{task}, subject); err != nil {
+		return nil, err
+	}
 
 	return task, nil
 }
 
-func (r *PostgresTaskRepository) Update(id string, input model.UpdateTaskInput, subject string) (*model.Task, error) {
-	task, err := r.GetByID(id, subject)
+// AssignedTasks возвращает задачи, назначенные пользователю. У чужих задач
+// заполнен owner, метки – метки владельца.
+func (r *PostgresTaskRepository) AssignedTasks(subject string) ([]*model.Task, error) {
+	tenantCondition, args := r.tenantCondition([]any{subject})
+	rows, err := r.db.Query(`
+        SELECT `+taskColumns+`, subject
+        FROM tasks
+        WHERE assignee = $1 AND deleted_at IS NULL`+tenantCondition+`
+        ORDER BY created_at DESC
+    `, args...)
 	if err != nil {
+		return nil, fmt.Errorf("failed to query assigned tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*model.Task
+	byOwner := make(map[string][]*model.Task)
+	for rows.Next() {
+		var owner string
+		task, err := scanTask(rows, &owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		if owner != subject {
+			task.Owner = &owner
+		}
+		tasks = append(tasks, task)
+		byOwner[owner] = append(byOwner[owner], task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+	rows.Close()
+
+	for owner, owned := range byOwner {
+		if err := r.loadTags(owned, owner); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.setDueFlags(tasks, subject); err != nil {
 		return nil, err
 	}
-	if task == nil {
+
+	return tasks, nil
+}
+
+// Update меняет задачу под блокировкой ее строки и записывает ревизию в той
+// же транзакции. Смену выполнения или срока, которой нужна логика tasks
+// сервиса, отклоняет с ErrManagedTask.
+func (r *PostgresTaskRepository) Update(id string, input model.UpdateTaskInput, subject string) (*model.Task, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantCondition, args := r.tenantCondition([]any{id, subject})
+	task, err := scanTask(tx.QueryRow(`
+        SELECT `+taskColumns+`
+        FROM tasks
+        WHERE id = $1 AND subject = $2 AND deleted_at IS NULL`+tenantCondition+`
+        FOR UPDATE
+    `, args...))
+	if err == sql.ErrNoRows {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if input.Version != nil && *input.Version != task.Version {
+		return nil, ErrVersionConflict
+	}
+
+	before, err := snapshot(tx, id)
+	if err != nil {
+		return nil, err
+	}
 
 	if input.Title != nil {
 		task.Title = *input.Title
@@ -189,63 +350,110 @@ func (r *PostgresTaskRepository) Update(id string, input model.UpdateTaskInput,
 	}
 	if input.DueDate != nil {
 		task.DueDate = input.DueDate
+		if input.DueDate.IsZero() {
+			task.DueDate = nil
+		}
 	}
 	if input.Done != nil {
 		task.Done = *input.Done
 	}
+
+	var dueDate due.Date
+	if task.DueDate != nil {
+		dueDate = *task.DueDate
+	}
+	doneChanged := task.Done != before.Done
+	dueChanged := !dueDate.Equal(before.DueDate)
+	if doneChanged || dueChanged {
+		if err := checkPlainTask(tx, id, doneChanged, dueChanged); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
-	updatedAt := now.Format(time.RFC3339)
+	dueDay, dueAt := dueValues(task.DueDate)
 
+	// Задачи без процесса проекта: выполненная – done, невыполненная – todo
 	query := `
         UPDATE tasks
-        SET title = $1, description = $2, due_date = $3, done = $4, updated_at = $5
-        WHERE id = $6 AND subject = $7
-        RETURNING id, title, description, due_date, done, created_at, updated_at
-    `
+        SET title = $1, description = $2, due_date = $3, due_at = $4, done = $5, updated_at = $6, version = version + 1,
+            status = CASE WHEN done = $5 THEN status WHEN $5 THEN 'done' ELSE 'todo' END,
+            completed_at = CASE WHEN NOT $5 THEN NULL WHEN done THEN completed_at ELSE $6 END
+        WHERE id = $7
+        RETURNING ` + taskColumns
 
-	var createdAt time.Time
-	err = r.db.QueryRow(
+	updated, err := scanTask(tx.QueryRow(
 		query,
 		task.Title,
 		task.Description,
-		task.DueDate,
+		dueDay,
+		dueAt,
 		task.Done,
 		now,
 		id,
-		subject,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&createdAt,
-		&updatedAt,
-	)
-
+	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
-	createdAtStr := createdAt.Format(time.RFC3339)
-	task.CreatedAt = &createdAtStr
-	task.UpdatedAt = &updatedAt
+	after, err := snapshot(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := addRevision(tx, id, updated.Version, subject, actionUpdated, diffSnapshots(before, after), after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit task: %w", err)
+	}
 
-	return task, nil
+	if err := r.loadTags([]*model.Task{updated}, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.setDueFlags([]*model.Task{updated}, subject); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
 }
 
+// Delete перемещает задачу в корзину, как и REST API tasks сервиса, и
+// записывает ревизию удаления
 func (r *PostgresTaskRepository) Delete(id string, subject string) (bool, error) {
-	query := `DELETE FROM tasks WHERE id = $1 AND subject = $2`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.Exec(query, id, subject)
+	tenantCondition, args := r.tenantCondition([]any{time.Now(), id, subject})
+	query := `
+        UPDATE tasks
+        SET deleted_at = $1, updated_at = $1, version = version + 1
+        WHERE id = $2 AND subject = $3 AND deleted_at IS NULL` + tenantCondition + `
+        RETURNING version
+    `
+
+	var version int
+	err = tx.QueryRow(query, args...).Scan(&version)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to delete task: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	state, err := snapshot(tx, id)
 	if err != nil {
-		return false, fmt.Errorf("failed to get rows affected: %w", err)
+		return false, err
+	}
+	if err := addRevision(tx, id, version, subject, actionDeleted, []fieldChange{}, state); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit task deletion: %w", err)
 	}
 
-	return rowsAffected > 0, nil
+	return true, nil
 }
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Пространство задач пользователей, для которых auth сервис пространство не
+// сообщает (как в tasks сервисе)
+const defaultTenant = "default"
+
+// QuotaError – задача превысила бы квоту задач пространства
+type QuotaError struct {
+	Limit int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("workspace tasks quota exceeded (max %d)", e.Limit)
+}
+
+// checkTaskQuota блокирует пространство до конца транзакции tx и возвращает
+// QuotaError, если новая задача превысит его квоту. Задачи в корзине квоту не
+// расходуют.
+func (r *PostgresTaskRepository) checkTaskQuota(tx *sql.Tx) error {
+	tenant := r.workspace()
+	if _, err := tx.Exec(`
+        INSERT INTO workspaces (id, created_at) VALUES ($1, $2)
+        ON CONFLICT (id) DO NOTHING
+    `, tenant, time.Now()); err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	var maxTasks sql.NullInt64
+	err := tx.QueryRow(`SELECT max_tasks FROM workspaces WHERE id = $1 FOR UPDATE`, tenant).Scan(&maxTasks)
+	if err != nil {
+		return fmt.Errorf("failed to lock workspace: %w", err)
+	}
+	limit := r.maxTasks
+	if maxTasks.Valid {
+		limit = int(maxTasks.Int64)
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	var used int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM tasks WHERE tenant = $1 AND deleted_at IS NULL`, tenant).Scan(&used)
+	if err != nil {
+		return fmt.Errorf("failed to count workspace tasks: %w", err)
+	}
+	if used+1 > limit {
+		return &QuotaError{Limit: limit}
+	}
+	return nil
+}
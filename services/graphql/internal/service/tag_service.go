@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/graphql/graph/model"
+	"tech-ip-sem2/services/graphql/internal/repository"
+	"tech-ip-sem2/shared/sanitize"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// normalizeTagName приводит имя метки к виду, принятому в tasks сервисе
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(sanitize.SanitizeText(name)))
+	if name == "" {
+		return "", fmt.Errorf("tag name is required")
+	}
+	if len([]rune(name)) > 50 {
+		return "", fmt.Errorf("tag name too long (max 50 characters)")
+	}
+	return name, nil
+}
+
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		n, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized, nil
+}
+
+func normalizeTagColor(color *string) (string, error) {
+	if color == nil || *color == "" {
+		return repository.DefaultTagColor, nil
+	}
+	if !tagColorPattern.MatchString(*color) {
+		return "", fmt.Errorf("tag color must be in #RRGGBB format")
+	}
+	return strings.ToLower(*color), nil
+}
+
+func (s *TaskService) GetTags(subject string) ([]*model.Tag, error) {
+	tags, err := s.repo.GetTags(subject)
+	if err != nil {
+		s.log.Error("failed to get tags", zap.Error(err))
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s *TaskService) CreateTag(input model.CreateTagInput, subject string) (*model.Tag, error) {
+	name, err := normalizeTagName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	color, err := normalizeTagColor(input.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := s.repo.CreateTag(name, color, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("tag created via GraphQL", zap.String("tag_id", tag.ID))
+	return tag, nil
+}
+
+func (s *TaskService) UpdateTag(id string, input model.UpdateTagInput, subject string) (*model.Tag, error) {
+	if input.Name != nil {
+		name, err := normalizeTagName(*input.Name)
+		if err != nil {
+			return nil, err
+		}
+		input.Name = &name
+	}
+	if input.Color != nil {
+		color, err := normalizeTagColor(input.Color)
+		if err != nil {
+			return nil, err
+		}
+		input.Color = &color
+	}
+
+	return s.repo.UpdateTag(id, input, subject)
+}
+
+func (s *TaskService) DeleteTag(id string, subject string) (bool, error) {
+	return s.repo.DeleteTag(id, subject)
+}
+
+// AddTaskTags назначает метки задаче; nil – задача не найдена
+func (s *TaskService) AddTaskTags(taskID string, tags []string, subject string) (*model.Task, error) {
+	names, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.repo.GetByID(taskID, subject)
+	if err != nil || task == nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddTaskTags(taskID, names, subject); err != nil {
+		s.log.Error("failed to add task tags", zap.Error(err), zap.String("task_id", taskID))
+		return nil, err
+	}
+
+	return s.repo.GetByID(taskID, subject)
+}
+
+func (s *TaskService) RemoveTaskTag(taskID string, tag string, subject string) (*model.Task, error) {
+	name, err := normalizeTagName(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.repo.GetByID(taskID, subject)
+	if err != nil || task == nil {
+		return nil, err
+	}
+
+	if err := s.repo.RemoveTaskTag(taskID, name, subject); err != nil {
+		s.log.Error("failed to remove task tag", zap.Error(err), zap.String("task_id", taskID))
+		return nil, err
+	}
+
+	return s.repo.GetByID(taskID, subject)
+}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"tech-ip-sem2/services/graphql/graph/model"
 	"tech-ip-sem2/services/graphql/internal/repository"
+	"tech-ip-sem2/shared/due"
 	"tech-ip-sem2/shared/logger"
 )
 
@@ -20,7 +24,21 @@ func NewTaskService(repo repository.TaskRepository, log *logger.Logger) *TaskSer
 	}
 }
 
+// ForTenant возвращает копию сервиса, работающую в пространстве tenant
+func (s *TaskService) ForTenant(tenant string) *TaskService {
+	scoped := *s
+	scoped.repo = s.repo.ForTenant(tenant)
+	return &scoped
+}
+
 func (s *TaskService) CreateTask(input model.CreateTaskInput, subject string) (*model.Task, error) {
+	if err := s.resolveDueDate(input.DueDate, subject); err != nil {
+		return nil, err
+	}
+	if input.DueDate != nil && input.DueDate.IsZero() {
+		input.DueDate = nil
+	}
+
 	task := &model.Task{
 		ID:          generateUUID(),
 		Title:       input.Title,
@@ -30,6 +48,11 @@ func (s *TaskService) CreateTask(input model.CreateTaskInput, subject string) (*
 	}
 
 	created, err := s.repo.Create(task, subject)
+	var quotaErr *repository.QuotaError
+	if errors.As(err, &quotaErr) {
+		s.log.Info("workspace quota exceeded", zap.Int("limit", quotaErr.Limit))
+		return nil, err
+	}
 	if err != nil {
 		s.log.Error("failed to create task", zap.Error(err))
 		return nil, err
@@ -43,8 +66,25 @@ func (s *TaskService) CreateTask(input model.CreateTaskInput, subject string) (*
 	return created, nil
 }
 
-func (s *TaskService) GetAllTasks(subject string) ([]*model.Task, error) {
-	tasks, err := s.repo.GetAll(subject)
+// GetAllTasks возвращает задачи; tags фильтрует по меткам в режиме tagMode,
+// dueFilter по сроку (просроченные или на сегодня), assignee – по исполнителю
+// ("me" – текущий пользователь)
+func (s *TaskService) GetAllTasks(subject string, tags []string, tagMode *model.TagMode, dueFilter *model.DueFilter, assignee *string) ([]*model.Task, error) {
+	names, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+	matchAll := tagMode == nil || *tagMode == model.TagModeAll
+
+	if assignee != nil {
+		name := strings.TrimSpace(*assignee)
+		if name == "me" {
+			name = subject
+		}
+		assignee = &name
+	}
+
+	tasks, err := s.repo.GetAll(subject, names, matchAll, dueFilter, assignee)
 	if err != nil {
 		s.log.Error("failed to get tasks", zap.Error(err))
 		return nil, err
@@ -54,6 +94,16 @@ func (s *TaskService) GetAllTasks(subject string) ([]*model.Task, error) {
 	return tasks, nil
 }
 
+// GetAssignedTasks возвращает задачи, назначенные пользователю
+func (s *TaskService) GetAssignedTasks(subject string) ([]*model.Task, error) {
+	tasks, err := s.repo.AssignedTasks(subject)
+	if err != nil {
+		s.log.Error("failed to get assigned tasks", zap.Error(err))
+		return nil, err
+	}
+	return tasks, nil
+}
+
 func (s *TaskService) GetTaskByID(id string, subject string) (*model.Task, error) {
 	task, err := s.repo.GetByID(id, subject)
 	if err != nil {
@@ -69,7 +119,15 @@ func (s *TaskService) GetTaskByID(id string, subject string) (*model.Task, error
 }
 
 func (s *TaskService) UpdateTask(id string, input model.UpdateTaskInput, subject string) (*model.Task, error) {
+	if err := s.resolveDueDate(input.DueDate, subject); err != nil {
+		return nil, err
+	}
+
 	task, err := s.repo.Update(id, input, subject)
+	if errors.Is(err, repository.ErrManagedTask) {
+		s.log.Info("task change requires tasks REST API", zap.String("id", id))
+		return nil, err
+	}
 	if err != nil {
 		s.log.Error("failed to update task", zap.Error(err), zap.String("id", id))
 		return nil, err
@@ -96,6 +154,20 @@ func (s *TaskService) DeleteTask(id string, subject string) (bool, error) {
 	return deleted, nil
 }
 
+// resolveDueDate переводит срок без часового пояса в пояс пользователя
+func (s *TaskService) resolveDueDate(dueDate *due.Date, subject string) error {
+	if dueDate == nil {
+		return nil
+	}
+	loc, err := s.repo.Location(subject)
+	if err != nil {
+		s.log.Error("failed to get user timezone", zap.Error(err))
+		return err
+	}
+	*dueDate = dueDate.In(loc)
+	return nil
+}
+
 func generateUUID() string {
 	return uuid.New().String()
 }
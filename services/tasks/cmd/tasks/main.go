@@ -1,6 +1,7 @@
 package main
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,11 +10,14 @@ import (
 
 	"go.uber.org/zap"
 	"tech-ip-sem2/services/tasks/internal/cache"
-	"tech-ip-sem2/services/tasks/internal/client/authclient"
 	taskshttp "tech-ip-sem2/services/tasks/internal/http"
+	"tech-ip-sem2/services/tasks/internal/idempotency"
+	"tech-ip-sem2/services/tasks/internal/models"
 	"tech-ip-sem2/services/tasks/internal/rabbitmq"
 	"tech-ip-sem2/services/tasks/internal/repository"
 	"tech-ip-sem2/services/tasks/internal/service"
+	"tech-ip-sem2/shared/authclient"
+	"tech-ip-sem2/shared/blob"
 	"tech-ip-sem2/shared/logger"
 	"tech-ip-sem2/shared/metrics"
 	"tech-ip-sem2/shared/middleware"
@@ -60,34 +64,74 @@ func main() {
 	}
 	defer authClient.Close()
 
-	// Подключение к PostgreSQL
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	dbSSLMode := os.Getenv("DB_SSLMODE")
-	if dbSSLMode == "" {
-		dbSSLMode = "disable"
+	// Хранилище задач: PostgreSQL (по умолчанию) или встроенная SQLite
+	dbDriver := os.Getenv("DB_DRIVER")
+	if dbDriver == "" {
+		dbDriver = "postgres"
 	}
 
 	var taskRepo repository.TaskRepository
-	if dbHost != "" && dbUser != "" {
-		connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			dbHost, dbPort, dbUser, dbPass, dbName, dbSSLMode)
+	var taskDB *sql.DB
+	switch dbDriver {
+	case "postgres":
+		dbHost := os.Getenv("DB_HOST")
+		dbPort := os.Getenv("DB_PORT")
+		dbUser := os.Getenv("DB_USER")
+		dbPass := os.Getenv("DB_PASSWORD")
+		dbName := os.Getenv("DB_NAME")
+		dbSSLMode := os.Getenv("DB_SSLMODE")
+		if dbSSLMode == "" {
+			dbSSLMode = "disable"
+		}
+
+		if dbHost != "" && dbUser != "" {
+			connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+				dbHost, dbPort, dbUser, dbPass, dbName, dbSSLMode)
+
+			repo, err := repository.NewPostgresTaskRepository(connStr)
+			if err != nil {
+				log.Warn("Failed to connect to database, falling back to in-memory storage",
+					zap.Error(err))
+				taskRepo = nil
+			} else {
+				taskRepo = repo
+				taskDB = repo.DB()
+				log.Info("Connected to PostgreSQL database")
+				defer repo.Close()
+			}
+		} else {
+			log.Info("Database not configured, using in-memory storage")
+		}
+	case "sqlite":
+		sqlitePath := os.Getenv("SQLITE_PATH")
+		if sqlitePath == "" {
+			sqlitePath = "tasks.db"
+		}
 
-		repo, err := repository.NewPostgresTaskRepository(connStr)
+		repo, err := repository.NewSQLiteTaskRepository(sqlitePath)
 		if err != nil {
-			log.Warn("Failed to connect to database, falling back to in-memory storage",
+			log.Fatal("Failed to open SQLite database",
+				zap.String("path", sqlitePath),
 				zap.Error(err))
-			taskRepo = nil
-		} else {
-			taskRepo = repo
-			log.Info("Connected to PostgreSQL database")
-			defer repo.Close()
 		}
-	} else {
-		log.Info("Database not configured, using in-memory storage")
+		taskRepo = repo
+		taskDB = repo.DB()
+		log.Info("Opened SQLite database", zap.String("path", sqlitePath))
+		defer repo.Close()
+	default:
+		log.Fatal("Unknown DB_DRIVER, expected postgres or sqlite", zap.String("db_driver", dbDriver))
+	}
+
+	// Без базы данных задачи хранятся во встроенной SQLite в памяти процесса
+	databaseEnabled := taskRepo != nil
+	if !databaseEnabled {
+		repo, err := repository.NewSQLiteTaskRepository(repository.SQLiteMemory)
+		if err != nil {
+			log.Fatal("Failed to create in-memory storage", zap.Error(err))
+		}
+		taskRepo = repo
+		taskDB = repo.DB()
+		defer repo.Close()
 	}
 
 	// Подключение к Redis
@@ -194,8 +238,63 @@ func main() {
 		}
 	}
 
+	// Хранилище ключей идемпотентности: Redis, если доступен, иначе база задач
+	idempotencyTTL, _ := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_SECONDS"))
+	if idempotencyTTL == 0 {
+		idempotencyTTL = 86400
+	}
+
+	idempotencyStoreName := os.Getenv("IDEMPOTENCY_STORE")
+	if idempotencyStoreName == "" {
+		idempotencyStoreName = "db"
+		if redisCache.IsEnabled() {
+			idempotencyStoreName = "redis"
+		}
+	}
+
+	var idempotencyStore idempotency.Store
+	switch idempotencyStoreName {
+	case "redis":
+		idempotencyStore = idempotency.NewRedisStore(redisCache.Client())
+	case "db":
+		idempotencyStore = repository.NewIdempotencyRepository(taskDB)
+	default:
+		log.Fatal("Unknown IDEMPOTENCY_STORE, expected redis or db", zap.String("idempotency_store", idempotencyStoreName))
+	}
+	idempotent := idempotency.NewMiddleware(idempotencyStore, time.Duration(idempotencyTTL)*time.Second, log)
+
 	// Сервис задач с кэшем и RabbitMQ
 	tasksService := service.NewTasksService(log, taskRepo, redisCache, rabbitPublisher)
+	tasksService.SetMemoryStorage(!databaseEnabled)
+
+	// TASKS_ENFORCE_DEPENDENCIES=false разрешает выполнять заблокированные задачи
+	if os.Getenv("TASKS_ENFORCE_DEPENDENCIES") == "false" {
+		tasksService.SetEnforceDependencies(false)
+		log.Info("Task dependency enforcement disabled")
+	}
+
+	// Хранилище вложений: BLOB_STORE=local (каталог BLOB_DIR) или s3
+	blobConfig := blob.ConfigFromEnv()
+	blobStore, err := blob.New(blobConfig)
+	if err != nil {
+		log.Warn("Failed to create blob store, attachments disabled", zap.Error(err))
+	} else {
+		maxAttachmentBytes, _ := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES"), 10, 64)
+		tasksService.SetAttachmentStore(blobStore, maxAttachmentBytes)
+		log.Info("Attachment storage enabled", zap.String("blob_store", blobConfig.Backend))
+	}
+
+	// Большие импорты выполняет worker, который работает только с PostgreSQL
+	if dbDriver == "postgres" && databaseEnabled {
+		importSyncRows, _ := strconv.Atoi(os.Getenv("IMPORT_SYNC_MAX_ROWS"))
+		tasksService.SetImportQueue(importSyncRows)
+	}
+
+	// Квоты рабочих пространств по умолчанию; 0 – без ограничения
+	maxTenantTasks, _ := strconv.Atoi(os.Getenv("TENANT_MAX_TASKS"))
+	maxTenantProjects, _ := strconv.Atoi(os.Getenv("TENANT_MAX_PROJECTS"))
+	tasksService.SetQuotas(models.Quotas{MaxTasks: maxTenantTasks, MaxProjects: maxTenantProjects})
+
 	handlers := taskshttp.NewHandlers(tasksService, authClient, log)
 
 	// Job handlers (для эндпоинта /v1/jobs/*)
@@ -204,12 +303,88 @@ func main() {
 	mux := http.NewServeMux()
 
 	// Эндпоинты API для задач (REST)
-	mux.HandleFunc("POST /v1/tasks", handlers.AuthMiddleware(handlers.CreateTask))
+	mux.HandleFunc("POST /v1/tasks", handlers.AuthMiddleware(idempotent.Wrap(handlers.CreateTask)))
+	mux.HandleFunc("POST /v1/tasks/quick", handlers.AuthMiddleware(idempotent.Wrap(handlers.QuickAddTask)))
+	mux.HandleFunc("POST /v1/tasks/bulk", handlers.AuthMiddleware(idempotent.Wrap(handlers.BulkTasks)))
+	mux.HandleFunc("GET /v1/tasks/export", handlers.AuthMiddleware(handlers.ExportTasks))
+	mux.HandleFunc("POST /v1/tasks/import", handlers.AuthMiddleware(handlers.ImportTasks))
+	mux.HandleFunc("GET /v1/imports/{id}", handlers.AuthMiddleware(handlers.GetImport))
 	mux.HandleFunc("GET /v1/tasks", handlers.AuthMiddleware(handlers.ListTasks))
 	mux.HandleFunc("GET /v1/tasks/search", handlers.AuthMiddleware(handlers.SearchTasks))
+	mux.HandleFunc("GET /v1/tasks/stats", handlers.AuthMiddleware(handlers.TaskStats))
+	mux.HandleFunc("GET /v1/tasks/next", handlers.AuthMiddleware(handlers.NextTasks))
+	mux.HandleFunc("GET /v1/tasks/trash", handlers.AuthMiddleware(handlers.ListTrash))
+	mux.HandleFunc("GET /v1/tasks/shared", handlers.AuthMiddleware(handlers.SharedTasks))
+	mux.HandleFunc("GET /v1/tasks/assigned", handlers.AuthMiddleware(handlers.AssignedTasks))
 	mux.HandleFunc("GET /v1/tasks/{id}", handlers.AuthMiddleware(handlers.GetTask))
 	mux.HandleFunc("PATCH /v1/tasks/{id}", handlers.AuthMiddleware(handlers.UpdateTask))
 	mux.HandleFunc("DELETE /v1/tasks/{id}", handlers.AuthMiddleware(handlers.DeleteTask))
+	mux.HandleFunc("POST /v1/tasks/{id}/restore", handlers.AuthMiddleware(handlers.RestoreTask))
+	mux.HandleFunc("GET /v1/tasks/{id}/history", handlers.AuthMiddleware(handlers.TaskHistory))
+	mux.HandleFunc("POST /v1/tasks/{id}/revert", handlers.AuthMiddleware(handlers.RevertTask))
+	mux.HandleFunc("POST /v1/tasks/{id}/tags", handlers.AuthMiddleware(handlers.AddTaskTags))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/tags/{tag}", handlers.AuthMiddleware(handlers.RemoveTaskTag))
+	mux.HandleFunc("PUT /v1/tasks/{id}/assignee", handlers.AuthMiddleware(handlers.AssignTask))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/assignee", handlers.AuthMiddleware(handlers.UnassignTask))
+	mux.HandleFunc("GET /v1/tasks/{id}/comments", handlers.AuthMiddleware(handlers.ListComments))
+	mux.HandleFunc("POST /v1/tasks/{id}/comments", handlers.AuthMiddleware(handlers.AddComment))
+	mux.HandleFunc("PATCH /v1/tasks/{id}/comments/{comment}", handlers.AuthMiddleware(handlers.UpdateComment))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/comments/{comment}", handlers.AuthMiddleware(handlers.DeleteComment))
+	mux.HandleFunc("GET /v1/tasks/{id}/attachments", handlers.AuthMiddleware(handlers.ListAttachments))
+	mux.HandleFunc("POST /v1/tasks/{id}/attachments", handlers.AuthMiddleware(handlers.UploadAttachment))
+	mux.HandleFunc("GET /v1/tasks/{id}/attachments/{attachment}", handlers.AuthMiddleware(handlers.DownloadAttachment))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/attachments/{attachment}", handlers.AuthMiddleware(handlers.DeleteAttachment))
+	mux.HandleFunc("POST /v1/tasks/{id}/dependencies", handlers.AuthMiddleware(handlers.AddTaskDependency))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/dependencies/{blocker}", handlers.AuthMiddleware(handlers.RemoveTaskDependency))
+	mux.HandleFunc("GET /v1/tasks/{id}/subtasks", handlers.AuthMiddleware(handlers.ListSubtasks))
+	mux.HandleFunc("POST /v1/tasks/{id}/checklist", handlers.AuthMiddleware(handlers.AddChecklistItem))
+	mux.HandleFunc("PATCH /v1/tasks/{id}/checklist/{item}", handlers.AuthMiddleware(handlers.UpdateChecklistItem))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/checklist/{item}", handlers.AuthMiddleware(handlers.DeleteChecklistItem))
+	mux.HandleFunc("POST /v1/tasks/{id}/recurrence/skip", handlers.AuthMiddleware(handlers.SkipOccurrence))
+	mux.HandleFunc("POST /v1/tasks/{id}/recurrence/stop", handlers.AuthMiddleware(handlers.StopRecurrence))
+	mux.HandleFunc("GET /v1/tasks/{id}/reminders", handlers.AuthMiddleware(handlers.ListReminders))
+	mux.HandleFunc("POST /v1/tasks/{id}/reminders", handlers.AuthMiddleware(handlers.AddReminder))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/reminders/{reminder}", handlers.AuthMiddleware(handlers.DeleteReminder))
+	mux.HandleFunc("GET /v1/tasks/{id}/shares", handlers.AuthMiddleware(handlers.ListTaskShares))
+	mux.HandleFunc("POST /v1/tasks/{id}/shares", handlers.AuthMiddleware(handlers.ShareTask))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/shares/{subject}", handlers.AuthMiddleware(handlers.UnshareTask))
+	mux.HandleFunc("POST /v1/tasks/{id}/timer/start", handlers.AuthMiddleware(handlers.StartTimer))
+	mux.HandleFunc("POST /v1/tasks/{id}/timer/stop", handlers.AuthMiddleware(handlers.StopTimer))
+	mux.HandleFunc("GET /v1/tasks/{id}/time-entries", handlers.AuthMiddleware(handlers.ListTimeEntries))
+	mux.HandleFunc("POST /v1/tasks/{id}/time-entries", handlers.AuthMiddleware(handlers.LogTime))
+	mux.HandleFunc("DELETE /v1/tasks/{id}/time-entries/{entry}", handlers.AuthMiddleware(handlers.DeleteTimeEntry))
+	mux.HandleFunc("GET /v1/time-report", handlers.AuthMiddleware(handlers.TimeReport))
+	mux.HandleFunc("GET /v1/workspace", handlers.AuthMiddleware(handlers.GetWorkspace))
+	mux.HandleFunc("GET /v1/settings", handlers.AuthMiddleware(handlers.GetSettings))
+	mux.HandleFunc("PATCH /v1/settings", handlers.AuthMiddleware(handlers.UpdateSettings))
+	mux.HandleFunc("GET /v1/settings/calendar-feed", handlers.AuthMiddleware(handlers.GetCalendarFeed))
+	mux.HandleFunc("POST /v1/settings/calendar-feed", handlers.AuthMiddleware(handlers.RegenerateCalendarFeed))
+	mux.HandleFunc("DELETE /v1/settings/calendar-feed", handlers.AuthMiddleware(handlers.RevokeCalendarFeed))
+	// Календарь по секретной ссылке: токен в пути заменяет авторизацию
+	mux.HandleFunc("GET /v1/feeds/{file}", handlers.CalendarFeed)
+
+	mux.HandleFunc("GET /v1/tags", handlers.AuthMiddleware(handlers.ListTags))
+	mux.HandleFunc("POST /v1/tags", handlers.AuthMiddleware(handlers.CreateTag))
+	mux.HandleFunc("PATCH /v1/tags/{id}", handlers.AuthMiddleware(handlers.UpdateTag))
+	mux.HandleFunc("DELETE /v1/tags/{id}", handlers.AuthMiddleware(handlers.DeleteTag))
+
+	mux.HandleFunc("GET /v1/projects", handlers.AuthMiddleware(handlers.ListProjects))
+	mux.HandleFunc("POST /v1/projects", handlers.AuthMiddleware(handlers.CreateProject))
+	mux.HandleFunc("GET /v1/projects/{id}", handlers.AuthMiddleware(handlers.GetProject))
+	mux.HandleFunc("PATCH /v1/projects/{id}", handlers.AuthMiddleware(handlers.UpdateProject))
+	mux.HandleFunc("DELETE /v1/projects/{id}", handlers.AuthMiddleware(handlers.DeleteProject))
+	mux.HandleFunc("GET /v1/projects/{id}/tasks", handlers.AuthMiddleware(handlers.ListProjectTasks))
+	mux.HandleFunc("GET /v1/projects/shared", handlers.AuthMiddleware(handlers.SharedProjects))
+	mux.HandleFunc("GET /v1/projects/{id}/shares", handlers.AuthMiddleware(handlers.ListProjectShares))
+	mux.HandleFunc("POST /v1/projects/{id}/shares", handlers.AuthMiddleware(handlers.ShareProject))
+	mux.HandleFunc("DELETE /v1/projects/{id}/shares/{subject}", handlers.AuthMiddleware(handlers.UnshareProject))
+
+	mux.HandleFunc("GET /v1/templates", handlers.AuthMiddleware(handlers.ListTemplates))
+	mux.HandleFunc("POST /v1/templates", handlers.AuthMiddleware(handlers.CreateTemplate))
+	mux.HandleFunc("GET /v1/templates/{id}", handlers.AuthMiddleware(handlers.GetTemplate))
+	mux.HandleFunc("PATCH /v1/templates/{id}", handlers.AuthMiddleware(handlers.UpdateTemplate))
+	mux.HandleFunc("DELETE /v1/templates/{id}", handlers.AuthMiddleware(handlers.DeleteTemplate))
+	mux.HandleFunc("POST /v1/templates/{id}/instantiate", handlers.AuthMiddleware(idempotent.Wrap(handlers.InstantiateTemplate)))
 
 	// Эндпоинт готовности (без авторизации, для healthcheck)
 	if jobPublisher != nil {
@@ -219,7 +394,7 @@ func main() {
 
 	// Эндпоинты для задач (job queue)
 	if jobPublisher != nil {
-		mux.HandleFunc("POST /v1/jobs/process-task", handlers.AuthMiddleware(jobHandlers.ProcessTaskJob))
+		mux.HandleFunc("POST /v1/jobs/process-task", handlers.AuthMiddleware(idempotent.Wrap(jobHandlers.ProcessTaskJob)))
 		log.Info("Job endpoints registered", zap.String("path", "/v1/jobs/process-task"))
 	} else {
 		log.Warn("Job endpoints disabled (no RabbitMQ connection)")
@@ -240,10 +415,13 @@ func main() {
 	log.Info("Tasks service starting",
 		zap.Int("port", port),
 		zap.String("auth_grpc_addr", authGRPCAddr),
-		zap.Bool("database_enabled", taskRepo != nil),
+		zap.String("db_driver", dbDriver),
+		zap.Bool("database_enabled", databaseEnabled),
+		zap.String("idempotency_store", idempotencyStoreName),
 		zap.Bool("cache_enabled", redisCache.IsEnabled()),
 		zap.Bool("rabbitmq_enabled", rabbitPublisher != nil),
 		zap.Bool("job_queue_enabled", jobPublisher != nil),
+		zap.Bool("attachments_enabled", blobStore != nil),
 		zap.String("instance", instanceID),
 	)
 
@@ -23,8 +23,9 @@ type RedisCache struct {
 	jitterMax time.Duration
 
 	// Префиксы ключей
-	taskKeyPrefix string
-	listKeyPrefix string
+	taskKeyPrefix  string
+	listKeyPrefix  string
+	statsKeyPrefix string
 }
 
 type CacheConfig struct {
@@ -65,15 +66,28 @@ func NewRedisCache(cfg CacheConfig, log *logger.Logger) *RedisCache {
 		zap.Int("base_ttl", cfg.BaseTTL),
 	)
 
-	return &RedisCache{
-		client:        client,
-		log:           log,
-		enabled:       true,
-		baseTTL:       time.Duration(cfg.BaseTTL) * time.Second,
-		jitterMax:     time.Duration(cfg.JitterMax) * time.Second,
-		taskKeyPrefix: "tasks:task:",
-		listKeyPrefix: "tasks:list:",
+	return (&RedisCache{
+		client:    client,
+		log:       log,
+		enabled:   true,
+		baseTTL:   time.Duration(cfg.BaseTTL) * time.Second,
+		jitterMax: time.Duration(cfg.JitterMax) * time.Second,
+	}).ForTenant(models.DefaultTenant)
+}
+
+// ForTenant возвращает кэш с ключами пространства tenant на том же
+// соединении. Ключи пространства default не содержат его имени, как и до
+// появления пространств.
+func (c *RedisCache) ForTenant(tenant string) *RedisCache {
+	scoped := *c
+	prefix := "tasks:"
+	if tenant != "" && tenant != models.DefaultTenant {
+		prefix += tenant + ":"
 	}
+	scoped.taskKeyPrefix = prefix + "task:"
+	scoped.listKeyPrefix = prefix + "list:"
+	scoped.statsKeyPrefix = prefix + "stats:"
+	return &scoped
 }
 
 // Закрытие соединения
@@ -89,6 +103,11 @@ func (c *RedisCache) IsEnabled() bool {
 	return c.enabled
 }
 
+// Клиент Redis для других хранилищ сервиса (например, ключей идемпотентности)
+func (c *RedisCache) Client() *redis.Client {
+	return c.client
+}
+
 // Генерация ключа для задачи
 func (c *RedisCache) taskKey(id string) string {
 	return c.taskKeyPrefix + id
@@ -99,6 +118,11 @@ func (c *RedisCache) listKey(subject string) string {
 	return c.listKeyPrefix + subject
 }
 
+// Генерация ключа статистики пользователя: hash, поле – период статистики
+func (c *RedisCache) statsKey(subject string) string {
+	return c.statsKeyPrefix + subject
+}
+
 // Вычисление TTL с jitter
 func (c *RedisCache) ttlWithJitter() time.Duration {
 	if c.jitterMax <= 0 {
@@ -241,14 +265,14 @@ func (c *RedisCache) SetTaskList(ctx context.Context, subject string, tasks []mo
 	return nil
 }
 
-// Удаление списка задач
+// Удаление списка задач и статистики пользователя
 func (c *RedisCache) DeleteTaskList(ctx context.Context, subject string) error {
 	if !c.enabled {
 		return nil
 	}
 
 	key := c.listKey(subject)
-	err := c.client.Del(ctx, key).Err()
+	err := c.client.Del(ctx, key, c.statsKey(subject)).Err()
 
 	if err != nil {
 		c.log.Warn("Redis delete error for list", zap.Error(err))
@@ -258,3 +282,83 @@ func (c *RedisCache) DeleteTaskList(ctx context.Context, subject string) error {
 	c.log.Debug("Task list deleted from cache", zap.String("subject", subject))
 	return nil
 }
+
+// Удаление задач и списков задач пользователей одной командой DEL
+func (c *RedisCache) DeleteTasks(ctx context.Context, ids []string, subjects []string) error {
+	if !c.enabled || len(ids)+len(subjects) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids)+2*len(subjects))
+	for _, id := range ids {
+		keys = append(keys, c.taskKey(id))
+	}
+	for _, subject := range subjects {
+		keys = append(keys, c.listKey(subject), c.statsKey(subject))
+	}
+
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.log.Warn("Redis delete error for tasks", zap.Error(err), zap.Int("keys", len(keys)))
+		return err
+	}
+
+	c.log.Debug("Tasks deleted from cache", zap.Int("keys", len(keys)))
+	return nil
+}
+
+// Получение статистики за период из кэша
+func (c *RedisCache) GetStats(ctx context.Context, subject string, period string) (*models.TaskStats, error) {
+	if !c.enabled {
+		return nil, nil
+	}
+
+	key := c.statsKey(subject)
+	data, err := c.client.HGet(ctx, key, period).Bytes()
+
+	if err == redis.Nil {
+		c.log.Debug("Cache miss for stats", zap.String("subject", subject), zap.String("period", period))
+		return nil, nil
+	}
+
+	if err != nil {
+		c.log.Warn("Redis get error for stats", zap.Error(err))
+		return nil, err
+	}
+
+	var stats models.TaskStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		c.log.Warn("Failed to unmarshal cached stats", zap.Error(err))
+		c.client.HDel(ctx, key, period)
+		return nil, nil
+	}
+
+	c.log.Debug("Cache hit for stats", zap.String("subject", subject), zap.String("period", period))
+	return &stats, nil
+}
+
+// Сохранение статистики за период; TTL общий для всех периодов пользователя
+// и ограничивает устаревание просроченных задач
+func (c *RedisCache) SetStats(ctx context.Context, subject string, period string, stats *models.TaskStats) error {
+	if !c.enabled || stats == nil {
+		return nil
+	}
+
+	key := c.statsKey(subject)
+	data, err := json.Marshal(stats)
+	if err != nil {
+		c.log.Warn("Failed to marshal stats for cache", zap.Error(err))
+		return err
+	}
+
+	ttl := c.ttlWithJitter()
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, key, period, data)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.log.Warn("Redis set error for stats", zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("Stats cached", zap.String("subject", subject), zap.String("period", period), zap.Duration("ttl", ttl))
+	return nil
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Назначение исполнителя: {"assignee": "teacher"}
+func (h *Handlers) AssignTask(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	task, err := h.service(r).Assign(id, req, subject, r.Context())
+	h.writeAssignment(w, log, id, task, err)
+}
+
+// Снятие исполнителя; исполнитель может снять задачу с себя
+func (h *Handlers) UnassignTask(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	task, err := h.service(r).Unassign(id, subject, r.Context())
+	h.writeAssignment(w, log, id, task, err)
+}
+
+func (h *Handlers) writeAssignment(w http.ResponseWriter, log *zap.Logger, id string, task models.Task, err error) {
+	if writeTaskRefError(w, err) {
+		log.Info("assignment rejected", zap.String("task_id", id), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to change assignee", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if task.ID == "" {
+		writeTaskNotFound(w)
+		return
+	}
+
+	log.Info("task assignee set", zap.String("task_id", id), zap.String("assignee", task.Assignee))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
+// Задачи, назначенные пользователю, в том числе чужие
+func (h *Handlers) AssignedTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	tasks, err := h.service(r).AssignedTasks(subject)
+	if err != nil {
+		log.Error("failed to list assigned tasks", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tasks)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// writeAttachmentError отвечает на ошибки загрузки и выдачи вложений; false –
+// ошибка не относится к вложениям
+func writeAttachmentError(w http.ResponseWriter, err error) bool {
+	status := http.StatusBadRequest
+	message := ""
+
+	switch {
+	case errors.Is(err, models.ErrAttachmentTooLarge):
+		status = http.StatusRequestEntityTooLarge
+		message = "attachment too large"
+	case errors.Is(err, models.ErrAttachmentType):
+		status = http.StatusUnsupportedMediaType
+		message = "attachment type not allowed"
+	case errors.Is(err, models.ErrAttachmentIncomplete):
+		message = "attachment body shorter than Content-Length"
+	case errors.Is(err, models.ErrAttachmentsDisabled):
+		status = http.StatusServiceUnavailable
+		message = "attachment storage not configured"
+	default:
+		return writeTaskRefError(w, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+	return true
+}
+
+// Вложения задачи без содержимого
+func (h *Handlers) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	attachments, err := h.service(r).Attachments(id, subject)
+	if err != nil {
+		log.Error("failed to list attachments", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if attachments == nil {
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// Загрузка файла телом запроса: POST /v1/tasks/{id}/attachments?filename=report.pdf.
+// Тело не буферизуется и передается в хранилище потоком, поэтому нужен
+// Content-Length.
+func (h *Handlers) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	if r.ContentLength < 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusLengthRequired)
+		json.NewEncoder(w).Encode(errorResponse{Error: "Content-Length required"})
+		return
+	}
+
+	upload := models.AttachmentUpload{
+		Filename: r.URL.Query().Get("filename"),
+		Size:     r.ContentLength,
+		Body:     http.MaxBytesReader(w, r.Body, r.ContentLength),
+	}
+
+	attachment, err := h.service(r).UploadAttachment(id, upload, subject, r.Context())
+	if writeAttachmentError(w, err) {
+		log.Info("attachment rejected", zap.String("task_id", id), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to upload attachment", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if attachment.ID == "" {
+		writeTaskNotFound(w)
+		return
+	}
+
+	log.Info("attachment uploaded", zap.String("task_id", id), zap.String("attachment_id", attachment.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// Скачивание файла; браузер не открывает его, а сохраняет
+func (h *Handlers) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	attachmentID := r.PathValue("attachment")
+
+	attachment, content, err := h.service(r).OpenAttachment(id, attachmentID, subject, r.Context())
+	if writeAttachmentError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error("failed to open attachment", zap.Error(err), zap.String("attachment_id", attachmentID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if attachment.ID == "" {
+		writeAttachmentNotFound(w)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		log.Warn("attachment download interrupted", zap.Error(err), zap.String("attachment_id", attachmentID))
+	}
+}
+
+func (h *Handlers) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	attachmentID := r.PathValue("attachment")
+
+	deleted, err := h.service(r).DeleteAttachment(id, attachmentID, subject)
+	if writeTaskRefError(w, err) {
+		log.Info("attachment deletion rejected", zap.String("attachment_id", attachmentID), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to delete attachment", zap.Error(err), zap.String("attachment_id", attachmentID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !deleted {
+		writeAttachmentNotFound(w)
+		return
+	}
+
+	log.Info("attachment deleted", zap.String("task_id", id), zap.String("attachment_id", attachmentID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAttachmentNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(errorResponse{Error: "attachment not found"})
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/blob"
+)
+
+func TestUploadAttachmentErrors(t *testing.T) {
+	h, tasksService := newTestHandlers()
+
+	task, err := tasksService.Create(models.Task{Title: "Files"}, "student", context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	upload := func(filename, body string) int {
+		return doRequest(h.UploadAttachment, http.MethodPost, "/v1/tasks/"+task.ID+"/attachments?filename="+filename,
+			body, "student", "id", task.ID).Code
+	}
+
+	if code := upload("notes.txt", "hello"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without blob store, got %d", code)
+	}
+
+	store, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create blob store: %v", err)
+	}
+	tasksService.SetAttachmentStore(store, 16)
+
+	tests := []struct {
+		filename string
+		body     string
+		want     int
+	}{
+		{"notes.txt", "hello", http.StatusCreated},
+		{"big.txt", strings.Repeat("a", 17), http.StatusRequestEntityTooLarge},
+		{"app.exe", "MZ\x90\x00\x03\x00\x00\x00\x04\x00", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		if code := upload(tt.filename, tt.body); code != tt.want {
+			t.Errorf("Upload %s = %d, want %d", tt.filename, code, tt.want)
+		}
+	}
+
+	// Без Content-Length размер файла неизвестен
+	req := newTestRequest(http.MethodPost, "/v1/tasks/"+task.ID+"/attachments?filename=notes.txt", "hello", "student", "id", task.ID)
+	req.ContentLength = -1
+	if rec := serve(h.UploadAttachment, req); rec.Code != http.StatusLengthRequired {
+		t.Errorf("Expected 411 without Content-Length, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// bulkItemResponse – результат одного элемента; status – код, который
+// вернул бы одиночный запрос
+type bulkItemResponse struct {
+	Index  int          `json:"index"`
+	ID     string       `json:"id,omitempty"`
+	Status int          `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	Task   *models.Task `json:"task,omitempty"`
+}
+
+type bulkResponse struct {
+	Committed bool               `json:"committed"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []bulkItemResponse `json:"results"`
+}
+
+// Операция над несколькими задачами в одной транзакции:
+// POST /v1/tasks/bulk {"action":"complete","ids":[...]}. Ответ 200 содержит
+// результат по каждой задаче; 409 – atomic операция откачена из-за ошибок.
+func (h *Handlers) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	var req models.BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: decodeErrorMessage(err)})
+		return
+	}
+
+	result, err := h.service(r).Bulk(req, subject, r.Context())
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: validationErr.Message})
+		return
+	}
+	if err != nil {
+		log.Error("failed to run bulk operation", zap.Error(err), zap.String("action", req.Action))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	response := bulkResponse{
+		Committed: result.Committed,
+		Results:   make([]bulkItemResponse, 0, len(result.Items)),
+	}
+	for _, item := range result.Items {
+		itemResponse := bulkItemResponse{Index: item.Index, ID: item.ID}
+		switch {
+		case item.Err == nil:
+			response.Succeeded++
+			itemResponse.Status = http.StatusOK
+			switch req.Action {
+			case models.BulkCreate:
+				itemResponse.Status = http.StatusCreated
+			case models.BulkDelete:
+				itemResponse.Status = http.StatusNoContent
+			}
+			if item.Task.ID != "" {
+				task := item.Task
+				itemResponse.Task = &task
+			}
+		case errors.Is(item.Err, models.ErrTaskNotFound):
+			response.Failed++
+			itemResponse.Status = http.StatusNotFound
+			itemResponse.Error = "task not found"
+		default:
+			response.Failed++
+			status, message, ok := taskRefError(item.Err)
+			if !ok {
+				log.Error("bulk item failed", zap.Error(item.Err), zap.String("task_id", item.ID))
+				status, message = http.StatusInternalServerError, "internal server error"
+			}
+			itemResponse.Status = status
+			itemResponse.Error = message
+		}
+		response.Results = append(response.Results, itemResponse)
+	}
+
+	log.Info("bulk operation processed",
+		zap.String("action", req.Action),
+		zap.Bool("committed", result.Committed),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed),
+	)
+
+	status := http.StatusOK
+	if !result.Committed {
+		status = http.StatusConflict
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
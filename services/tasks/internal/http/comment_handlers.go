@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Обсуждение задачи: комментарии с ответами в поле replies
+func (h *Handlers) ListComments(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	comments, err := h.service(r).Comments(id, subject)
+	if err != nil {
+		log.Error("failed to list comments", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if comments == nil {
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(comments)
+}
+
+// Новый комментарий: {"body": "...", "parent_id": "..."}; @username уведомляет
+// упомянутого пользователя
+func (h *Handlers) AddComment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	comment, err := h.service(r).AddComment(id, req, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("comment rejected", zap.String("task_id", id), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to add comment", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if comment.ID == "" {
+		writeTaskNotFound(w)
+		return
+	}
+
+	log.Info("comment added", zap.String("task_id", id), zap.String("comment_id", comment.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// Изменение текста комментария его автором: {"body": "..."}
+func (h *Handlers) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	commentID := r.PathValue("comment")
+
+	var req models.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	comment, err := h.service(r).UpdateComment(id, commentID, req, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("comment update rejected", zap.String("comment_id", commentID), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to update comment", zap.Error(err), zap.String("comment_id", commentID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if comment.ID == "" {
+		writeCommentNotFound(w)
+		return
+	}
+
+	log.Info("comment updated", zap.String("task_id", id), zap.String("comment_id", commentID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// Удаление комментария автором или владельцем задачи
+func (h *Handlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	commentID := r.PathValue("comment")
+
+	deleted, err := h.service(r).DeleteComment(id, commentID, subject)
+	if writeTaskRefError(w, err) {
+		log.Info("comment deletion rejected", zap.String("comment_id", commentID), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to delete comment", zap.Error(err), zap.String("comment_id", commentID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !deleted {
+		writeCommentNotFound(w)
+		return
+	}
+
+	log.Info("comment deleted", zap.String("task_id", id), zap.String("comment_id", commentID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCommentNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(errorResponse{Error: "comment not found"})
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// writeDependencyError отвечает на ошибки изменения зависимостей
+func writeDependencyError(w http.ResponseWriter, log *zap.Logger, err error) {
+	status := http.StatusInternalServerError
+	message := "internal server error"
+
+	switch {
+	case errors.Is(err, models.ErrBlockerNotFound):
+		status = http.StatusBadRequest
+		message = "blocking task not found"
+	case errors.Is(err, models.ErrDependencyCycle):
+		status = http.StatusConflict
+		message = "dependency would create a cycle"
+	default:
+		log.Error("dependency operation failed", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
+
+// Зависимость: {"blocked_by": "{id}"} – задача ждет выполнения другой
+func (h *Handlers) AddTaskDependency(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.DependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.BlockedBy == "" {
+		log.Warn("invalid dependency request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "blocked_by is required"})
+		return
+	}
+
+	task, err := h.service(r).AddDependency(id, req.BlockedBy, subject)
+	if err != nil {
+		writeDependencyError(w, log, err)
+		return
+	}
+	if task.ID == "" {
+		log.Info("task not found for dependency", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *Handlers) RemoveTaskDependency(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	blockerID := r.PathValue("blocker")
+
+	task, removed, err := h.service(r).RemoveDependency(id, blockerID, subject)
+	if err != nil {
+		writeDependencyError(w, log, err)
+		return
+	}
+
+	if task.ID == "" || !removed {
+		message := "task not found"
+		if task.ID != "" {
+			message = "dependency not found"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: message})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
+// Что делать дальше: открытые задачи в порядке зависимостей, ?limit=N
+func (h *Handlers) NextTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(errorResponse{Error: "limit must be a non-negative integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	next, err := h.service(r).NextTasks(subject, limit)
+	if err != nil {
+		log.Error("failed to order tasks", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(next)
+}
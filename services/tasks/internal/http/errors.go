@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// writeTaskRefError отвечает на ссылку задачи на недоступный проект или
+// родителя, на нарушение иерархии подзадач и на выполнение заблокированной задачи
+func writeTaskRefError(w http.ResponseWriter, err error) bool {
+	status, message, ok := taskRefError(err)
+	if !ok {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+	return true
+}
+
+// taskRefError возвращает код и текст ответа для ошибок writeTaskRefError
+func taskRefError(err error) (int, string, bool) {
+	var validationErr *models.ValidationError
+	var quotaErr *models.QuotaError
+	status := http.StatusBadRequest
+	message := ""
+
+	switch {
+	case errors.As(err, &validationErr):
+		message = validationErr.Message
+	case errors.As(err, &quotaErr):
+		status = http.StatusForbidden
+		message = quotaErr.Error()
+	case errors.Is(err, models.ErrProjectNotFound):
+		message = "project not found"
+	case errors.Is(err, models.ErrProjectArchived):
+		status = http.StatusConflict
+		message = "project is archived"
+	case errors.Is(err, models.ErrParentNotFound):
+		message = "parent task not found"
+	case errors.Is(err, models.ErrTaskCycle):
+		status = http.StatusConflict
+		message = "task cannot be moved under itself or its subtask"
+	case errors.Is(err, models.ErrTaskTooDeep):
+		message = fmt.Sprintf("task hierarchy too deep (max %d levels)", models.MaxTaskDepth)
+	case errors.Is(err, models.ErrTaskBlocked):
+		status = http.StatusConflict
+		message = "task is blocked by open tasks"
+	case errors.Is(err, models.ErrUnknownStatus):
+		message = "unknown status for project workflow"
+	case errors.Is(err, models.ErrStatusTransition):
+		status = http.StatusConflict
+		message = "status transition not allowed"
+	case errors.Is(err, models.ErrNotRecurring):
+		status = http.StatusConflict
+		message = "task is not part of an active recurring series"
+	case errors.Is(err, models.ErrSeriesFinished):
+		status = http.StatusConflict
+		message = "recurring series has no more occurrences"
+	case errors.Is(err, models.ErrAccessDenied):
+		status = http.StatusForbidden
+		message = "editor access required"
+	case errors.Is(err, models.ErrNotCommentAuthor):
+		status = http.StatusForbidden
+		message = "only the comment author can change the comment"
+	default:
+		return 0, "", false
+	}
+	return status, message, true
+}
+
+// writeServiceError отвечает на ошибки сервиса: валидацию, квоты, конфликт
+// имен меток и отказ в доступе; остальные ошибки логируются как 500
+func writeServiceError(w http.ResponseWriter, log *zap.Logger, err error) {
+	var validationErr *models.ValidationError
+	var quotaErr *models.QuotaError
+	status := http.StatusInternalServerError
+	message := "internal server error"
+
+	switch {
+	case errors.As(err, &validationErr):
+		status = http.StatusBadRequest
+		message = validationErr.Message
+	case errors.As(err, &quotaErr):
+		status = http.StatusForbidden
+		message = quotaErr.Error()
+	case errors.Is(err, models.ErrTagExists):
+		status = http.StatusConflict
+		message = "tag with this name already exists"
+	case errors.Is(err, models.ErrAccessDenied):
+		status = http.StatusForbidden
+		message = "editor access required"
+	default:
+		log.Error("service operation failed", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// ETag задачи строится из ее версии, которая растет при каждом изменении
+func taskETag(task models.Task) string {
+	return `"` + strconv.FormatInt(task.Version, 10) + `"`
+}
+
+// etagCondition – разобранный заголовок If-Match / If-None-Match
+type etagCondition struct {
+	any      bool // "*"
+	versions []int64
+	weak     []int64 // версии из слабых ETag вида W/"3"
+}
+
+func parseETagCondition(header string) etagCondition {
+	var cond etagCondition
+
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(part)
+		if tag == "*" {
+			cond.any = true
+			continue
+		}
+
+		weak := strings.HasPrefix(tag, "W/")
+		tag = strings.TrimPrefix(tag, "W/")
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if weak {
+			cond.weak = append(cond.weak, version)
+		} else {
+			cond.versions = append(cond.versions, version)
+		}
+	}
+
+	return cond
+}
+
+// matchStrong – сильное сравнение (If-Match): слабые ETag не совпадают никогда
+func (c etagCondition) matchStrong(version int64) bool {
+	if c.any {
+		return true
+	}
+	for _, v := range c.versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeak – слабое сравнение (If-None-Match)
+func (c etagCondition) matchWeak(version int64) bool {
+	if c.matchStrong(version) {
+		return true
+	}
+	for _, v := range c.weak {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Состояние ссылки на календарь задач (токен не возвращается)
+func (h *Handlers) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	feed, err := h.service(r).CalendarFeed(subject)
+	if err != nil {
+		log.Error("failed to get calendar feed", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(feed)
+}
+
+// Новая секретная ссылка на календарь; прежняя перестает работать
+func (h *Handlers) RegenerateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	feed, err := h.service(r).RegenerateCalendarFeed(subject)
+	if err != nil {
+		log.Error("failed to regenerate calendar feed", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(feed)
+}
+
+func (h *Handlers) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	revoked, err := h.service(r).RevokeCalendarFeed(subject)
+	if err != nil {
+		log.Error("failed to revoke calendar feed", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !revoked {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "calendar feed not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Календарь задач по секретной ссылке без авторизации:
+// GET /v1/feeds/{token}.ics?project_id=&tag=&include_done=true&type=event
+func (h *Handlers) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+
+	token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok || token == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "calendar feed not found"})
+		return
+	}
+
+	filter := parseTaskFilter(r)
+	query := models.FeedQuery{
+		ProjectID:    filter.ProjectID,
+		Tags:         filter.Tags,
+		MatchAllTags: filter.MatchAllTags,
+		IncludeDone:  r.URL.Query().Get("include_done") == "true",
+		Type:         r.URL.Query().Get("type"),
+	}
+
+	feed, err := h.service(r).FeedTasks(token, query)
+	if writeTaskRefError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Error("failed to get calendar feed tasks", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if feed.Subject == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "calendar feed not found"})
+		return
+	}
+
+	var body bytes.Buffer
+	err = taskio.WriteCalendar(&body, taskio.Calendar{
+		Name:    "Tasks",
+		Events:  query.Type == models.FeedEvent,
+		Refresh: models.FeedRefresh,
+	}, feed.Records)
+	if err != nil {
+		log.Error("failed to write calendar feed", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	// Календарь не зависит от времени запроса, поэтому ETag – хеш содержимого
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := feed.LastModified.UTC().Truncate(time.Second)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	// Ссылка секретная: ответ не кэшируется общими прокси и проверяется заново
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", taskio.ContentType(taskio.FormatICal))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+	log.Info("calendar feed served", zap.String("subject", feed.Subject), zap.Int("count", len(feed.Records)))
+}
+
+// notModified проверяет If-None-Match, а без него – If-Modified-Since
+// (RFC 9110, 13.1.3)
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, part := range strings.Split(header, ",") {
+			tag := strings.TrimPrefix(strings.TrimSpace(part), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
@@ -3,15 +3,17 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
-	"tech-ip-sem2/services/tasks/internal/client/authclient"
 	"tech-ip-sem2/services/tasks/internal/models"
 	"tech-ip-sem2/services/tasks/internal/service"
+	"tech-ip-sem2/shared/authclient"
+	"tech-ip-sem2/shared/due"
 	"tech-ip-sem2/shared/logger"
 	"tech-ip-sem2/shared/middleware"
 )
@@ -34,6 +36,20 @@ type errorResponse struct {
 	Error string `json:"error"`
 }
 
+// service возвращает сервис задач рабочего пространства пользователя запроса
+func (h *Handlers) service(r *http.Request) *service.TasksService {
+	tenant, _ := r.Context().Value("tenant").(string)
+	return h.tasksService.ForTenant(tenant)
+}
+
+// decodeErrorMessage объясняет ошибку разбора тела: неверный срок или формат
+func decodeErrorMessage(err error) string {
+	if errors.Is(err, due.ErrInvalid) {
+		return err.Error()
+	}
+	return "invalid request format"
+}
+
 func (h *Handlers) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetRequestID(r.Context())
@@ -46,6 +62,7 @@ func (h *Handlers) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		w.Header().Set("X-Instance-ID", instanceID)
 
 		var subject string
+		tenant := models.DefaultTenant
 		var authenticated bool
 
 		// Аутентификация через Bearer token
@@ -54,13 +71,17 @@ func (h *Handlers) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			parts := strings.Split(authHeader, " ")
 			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
 				token := parts[1]
-				valid, subj, err := h.authClient.VerifyToken(r.Context(), token)
+				valid, identity, err := h.authClient.VerifyToken(r.Context(), token)
 
 				if err == nil && valid {
-					subject = subj
+					subject = identity.Subject
+					if identity.Tenant != "" {
+						tenant = identity.Tenant
+					}
 					authenticated = true
 					log.Info("token authenticated",
 						zap.String("subject", subject),
+						zap.String("tenant", tenant),
 						zap.String("instance", instanceID))
 				} else if err != nil {
 					log.Error("token verification failed", zap.Error(err))
@@ -88,7 +109,14 @@ func (h *Handlers) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		// Сбой записи участника не мешает запросу: проверка доступа к
+		// задачам от нее не зависит
+		if err := h.tasksService.ForTenant(tenant).JoinWorkspace(subject); err != nil {
+			log.Warn("failed to record workspace member", zap.Error(err), zap.String("tenant", tenant))
+		}
+
 		ctx := context.WithValue(r.Context(), "subject", subject)
+		ctx = context.WithValue(ctx, "tenant", tenant)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
@@ -103,7 +131,7 @@ func (h *Handlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 		log.Error("failed to decode request", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		json.NewEncoder(w).Encode(errorResponse{Error: decodeErrorMessage(err)})
 		return
 	}
 
@@ -115,14 +143,14 @@ func (h *Handlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task := models.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		DueDate:     req.DueDate,
-	}
+	task := req.NewTask()
 
 	// Передача контекста для RabbitMQ
-	created, err := h.tasksService.Create(task, subject, r.Context())
+	created, err := h.service(r).Create(task, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("invalid task reference", zap.Error(err))
+		return
+	}
 	if err != nil {
 		log.Error("failed to create task", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
@@ -137,6 +165,7 @@ func (h *Handlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 	)
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(created))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(created)
 }
@@ -146,7 +175,14 @@ func (h *Handlers) ListTasks(w http.ResponseWriter, r *http.Request) {
 	log := h.log.WithRequestID(requestID)
 	subject := r.Context().Value("subject").(string)
 
-	tasks, err := h.tasksService.GetAll(subject)
+	tasks, err := h.service(r).List(subject, parseTaskFilter(r))
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: validationErr.Message})
+		return
+	}
 	if err != nil {
 		log.Error("failed to get tasks", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
@@ -156,17 +192,35 @@ func (h *Handlers) ListTasks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type listItem struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
-		Done  bool   `json:"done"`
+		ID       string               `json:"id"`
+		Title    string               `json:"title"`
+		Done     bool                 `json:"done"`
+		Status   string               `json:"status"`
+		Priority string               `json:"priority,omitempty"`
+		Tags     []string             `json:"tags"`
+		ParentID string               `json:"parent_id,omitempty"`
+		Progress *models.TaskProgress `json:"progress,omitempty"`
+		Blocked  bool                 `json:"blocked"`
+		Assignee string               `json:"assignee,omitempty"`
+		Comments int                  `json:"comment_count"`
+		Role     string               `json:"role,omitempty"`
 	}
 
 	response := make([]listItem, 0, len(tasks))
 	for _, task := range tasks {
 		response = append(response, listItem{
-			ID:    task.ID,
-			Title: task.Title,
-			Done:  task.Done,
+			ID:       task.ID,
+			Title:    task.Title,
+			Done:     task.Done,
+			Status:   task.Status,
+			Priority: task.Priority,
+			Tags:     task.Tags,
+			ParentID: task.ParentID,
+			Progress: task.Progress,
+			Blocked:  task.Blocked,
+			Assignee: task.Assignee,
+			Comments: task.CommentCount,
+			Role:     task.Role,
 		})
 	}
 
@@ -194,7 +248,12 @@ func (h *Handlers) GetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.tasksService.GetByID(id, subject)
+	// Условный запрос сравнивается с версией в базе, а не в кэше
+	get := h.service(r).GetByID
+	if r.Header.Get("If-None-Match") != "" {
+		get = h.service(r).GetCurrent
+	}
+	task, err := get(id, subject)
 	if err != nil {
 		log.Error("failed to get task", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
@@ -213,6 +272,14 @@ func (h *Handlers) GetTask(w http.ResponseWriter, r *http.Request) {
 
 	log.Debug("task retrieved", zap.String("task_id", id))
 
+	w.Header().Set("ETag", taskETag(task))
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if parseETagCondition(ifNoneMatch).matchWeak(task.Version) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(task)
@@ -237,12 +304,48 @@ func (h *Handlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		log.Error("failed to decode request", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		json.NewEncoder(w).Encode(errorResponse{Error: decodeErrorMessage(err)})
 		return
 	}
 
-	// Передача контекста для RabbitMQ
-	task, err := h.tasksService.Update(id, updates, subject, r.Context())
+	// Оптимистичная блокировка: If-Match задает ожидаемую версию задачи
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		version, status := h.resolveIfMatch(r, ifMatch, id, subject)
+		if status != http.StatusOK {
+			log.Info("if-match precondition not met",
+				zap.String("task_id", id),
+				zap.String("if_match", ifMatch),
+				zap.Int("status", status))
+			h.writePreconditionError(w, status)
+			return
+		}
+		updates.Version = version
+	}
+
+	// scope=following меняет и следующие вхождения повторяющейся задачи
+	var task models.Task
+	var err error
+	switch r.URL.Query().Get("scope") {
+	case "", models.ScopeThis:
+		// Передача контекста для RabbitMQ
+		task, err = h.service(r).Update(id, updates, subject, r.Context())
+	case models.ScopeFollowing:
+		task, err = h.service(r).UpdateFollowing(id, updates, subject, r.Context())
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "scope must be this or following"})
+		return
+	}
+	if errors.Is(err, models.ErrVersionConflict) {
+		log.Info("task version conflict", zap.String("task_id", id))
+		h.writePreconditionError(w, http.StatusPreconditionFailed)
+		return
+	}
+	if writeTaskRefError(w, err) {
+		log.Info("invalid task reference", zap.String("task_id", id), zap.Error(err))
+		return
+	}
 	if err != nil {
 		log.Error("failed to update task", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
@@ -261,10 +364,53 @@ func (h *Handlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("task updated", zap.String("task_id", id))
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(task)
 }
 
+// resolveIfMatch возвращает ожидаемую версию для If-Match или HTTP-статус ошибки.
+// При нескольких ETag версия выбирается по текущему состоянию задачи, а
+// атомарная проверка в репозитории ловит гонку между чтением и записью.
+func (h *Handlers) resolveIfMatch(r *http.Request, header, id, subject string) (*int64, int) {
+	cond := parseETagCondition(header)
+	if cond.any {
+		return nil, http.StatusOK
+	}
+	if len(cond.versions) == 1 {
+		return &cond.versions[0], http.StatusOK
+	}
+	if len(cond.versions) == 0 {
+		return nil, http.StatusPreconditionFailed
+	}
+
+	current, err := h.service(r).GetCurrent(id, subject)
+	if err != nil {
+		return nil, http.StatusInternalServerError
+	}
+	if current.ID == "" {
+		return nil, http.StatusNotFound
+	}
+	if !cond.matchStrong(current.Version) {
+		return nil, http.StatusPreconditionFailed
+	}
+	return &current.Version, http.StatusOK
+}
+
+func (h *Handlers) writePreconditionError(w http.ResponseWriter, status int) {
+	message := "task was modified by another request"
+	switch status {
+	case http.StatusNotFound:
+		message = "task not found"
+	case http.StatusInternalServerError:
+		message = "internal server error"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
+
 func (h *Handlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetRequestID(r.Context())
 	log := h.log.WithRequestID(requestID)
@@ -280,7 +426,7 @@ func (h *Handlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Передача контекста для RabbitMQ
-	deleted, err := h.tasksService.Delete(id, subject, r.Context())
+	deleted, err := h.service(r).Delete(id, subject, r.Context())
 	if err != nil {
 		log.Error("failed to delete task", zap.Error(err))
 		w.Header().Set("Content-Type", "application/json")
@@ -301,6 +447,205 @@ func (h *Handlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Корзина: удаленные задачи, ожидающие окончательного удаления
+func (h *Handlers) ListTrash(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	tasks, err := h.service(r).Trash(subject)
+	if err != nil {
+		log.Error("failed to get trash", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	type trashItem struct {
+		ID        string    `json:"id"`
+		Title     string    `json:"title"`
+		Done      bool      `json:"done"`
+		DeletedAt time.Time `json:"deleted_at"`
+	}
+
+	response := make([]trashItem, 0, len(tasks))
+	for _, task := range tasks {
+		response = append(response, trashItem{
+			ID:        task.ID,
+			Title:     task.Title,
+			Done:      task.Done,
+			DeletedAt: *task.DeletedAt,
+		})
+	}
+
+	log.Debug("trash listed", zap.Int("count", len(response)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handlers) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("missing task id")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task id is required"})
+		return
+	}
+
+	// Передача контекста для RabbitMQ
+	task, err := h.service(r).Restore(id, subject, r.Context())
+	if err != nil {
+		// Восстановление сверх квоты пространства – 403
+		writeServiceError(w, log, err)
+		return
+	}
+
+	if task.ID == "" {
+		log.Info("task not found in trash", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found in trash"})
+		return
+	}
+
+	log.Info("task restored", zap.String("task_id", id))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
+// История изменений задачи
+func (h *Handlers) TaskHistory(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("missing task id")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task id is required"})
+		return
+	}
+
+	history, err := h.service(r).History(id, subject)
+	if err != nil {
+		log.Error("failed to get task history", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	// Задачи, созданные до появления истории, не имеют ревизий
+	if len(history) == 0 {
+		task, err := h.service(r).GetByID(id, subject)
+		if err != nil || task.ID == "" {
+			log.Info("task not found for history", zap.String("task_id", id))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+			return
+		}
+		history = []models.TaskRevision{}
+	}
+
+	log.Debug("task history retrieved", zap.String("task_id", id), zap.Int("count", len(history)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+type revertRequest struct {
+	Revision int64 `json:"revision"`
+}
+
+// Откат задачи к одной из предыдущих ревизий
+func (h *Handlers) RevertTask(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("missing task id")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task id is required"})
+		return
+	}
+
+	var req revertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Revision <= 0 {
+		log.Warn("invalid revert request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "revision is required"})
+		return
+	}
+
+	var expectedVersion *int64
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		version, status := h.resolveIfMatch(r, ifMatch, id, subject)
+		if status != http.StatusOK {
+			h.writePreconditionError(w, status)
+			return
+		}
+		expectedVersion = version
+	}
+
+	task, err := h.service(r).Revert(id, req.Revision, expectedVersion, subject, r.Context())
+	if errors.Is(err, models.ErrRevisionNotFound) {
+		log.Info("revision not found", zap.String("task_id", id), zap.Int64("revision", req.Revision))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "revision not found"})
+		return
+	}
+	if errors.Is(err, models.ErrVersionConflict) {
+		log.Info("task version conflict", zap.String("task_id", id))
+		h.writePreconditionError(w, http.StatusPreconditionFailed)
+		return
+	}
+	if writeTaskRefError(w, err) {
+		log.Info("invalid task reference", zap.String("task_id", id), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to revert task", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	// Ревизия есть, но задача в корзине
+	if task.ID == "" {
+		log.Info("task not found for revert", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+		return
+	}
+
+	log.Info("task reverted", zap.String("task_id", id), zap.Int64("revision", req.Revision))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
 // Поиск задач по названию
 func (h *Handlers) SearchTasks(w http.ResponseWriter, r *http.Request) {
 	requestID := middleware.GetRequestID(r.Context())
@@ -324,9 +669,9 @@ func (h *Handlers) SearchTasks(w http.ResponseWriter, r *http.Request) {
 
 	if useVulnerable {
 		log.Warn("Using VULNERABLE search - FOR DEMO ONLY", zap.String("term", term))
-		tasks, err = h.tasksService.SearchByTitleVulnerable(term, subject)
+		tasks, err = h.service(r).SearchByTitleVulnerable(term, subject)
 	} else {
-		tasks, err = h.tasksService.SearchByTitle(term, subject)
+		tasks, err = h.service(r).SearchByTitle(term, subject)
 	}
 
 	if err != nil {
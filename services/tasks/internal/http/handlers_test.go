@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/services/tasks/internal/service"
+	"tech-ip-sem2/shared/logger"
+)
+
+func newTestHandlers() (*Handlers, *service.TasksService) {
+	tasksService := service.NewTasksService(logger.New("test"), nil, nil, nil)
+	return NewHandlers(tasksService, nil, logger.New("test")), tasksService
+}
+
+// newTestRequest создает запрос от имени subject, как после AuthMiddleware
+func newTestRequest(method, target, body, subject string, pathValues ...string) *http.Request {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	ctx := context.WithValue(req.Context(), "subject", subject)
+	ctx = context.WithValue(ctx, "tenant", models.DefaultTenant)
+	req = req.WithContext(ctx)
+	for i := 0; i+1 < len(pathValues); i += 2 {
+		req.SetPathValue(pathValues[i], pathValues[i+1])
+	}
+	return req
+}
+
+// doRequest вызывает обработчик от имени subject
+func doRequest(handler http.HandlerFunc, method, target, body, subject string, pathValues ...string) *httptest.ResponseRecorder {
+	return serve(handler, newTestRequest(method, target, body, subject, pathValues...))
+}
+
+func serve(handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestParseTaskFilterTags(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"tag=work", []string{"work"}},
+		{"tag=a&tag=a", []string{"a"}},
+		{"tag=%20Work%20,urgent&tag=WORK", []string{"work", "urgent"}},
+		{"tag=,%20,&tag=", nil},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tasks?"+tt.query, nil)
+		if got := parseTaskFilter(req).Tags; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTaskFilter(%q).Tags = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestListTasksByRepeatedTag(t *testing.T) {
+	h, tasksService := newTestHandlers()
+	ctx := context.Background()
+
+	task, err := tasksService.Create(models.Task{Title: "Report"}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := tasksService.AddTaskTags(task.ID, []string{"Work"}, "student"); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	if _, err := tasksService.Create(models.Task{Title: "Untagged"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// Повтор и регистр метки не меняют результат отбора по всем меткам
+	for _, query := range []string{"tag=work", "tag=a&tag=a", "tag=work&tag=work", "tag=%20WORK%20,work"} {
+		rec := doRequest(h.ListTasks, http.MethodGet, "/v1/tasks?"+query, "", "student")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET ?%s: expected 200, got %d", query, rec.Code)
+		}
+		var items []struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+			t.Fatalf("Failed to decode list: %v", err)
+		}
+		want := 1
+		if query == "tag=a&tag=a" {
+			want = 0
+		}
+		if len(items) != want || (want == 1 && items[0].ID != task.ID) {
+			t.Errorf("GET ?%s: expected %d tagged task, got %+v", query, want, items)
+		}
+	}
+}
+
+func TestTaskPreconditions(t *testing.T) {
+	h, tasksService := newTestHandlers()
+
+	task, err := tasksService.Create(models.Task{Title: "Versioned"}, "student", context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	etag := `"` + strconv.FormatInt(task.Version, 10) + `"`
+
+	// If-None-Match с текущей версией – 304 без тела
+	req := newTestRequest(http.MethodGet, "/v1/tasks/"+task.ID, "", "student", "id", task.ID)
+	req.Header.Set("If-None-Match", "W/"+etag)
+	if rec := serve(h.GetTask, req); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+		t.Errorf("Expected 304 with ETag %s, got %d %q", etag, rec.Code, rec.Header().Get("ETag"))
+	}
+
+	update := func(ifMatch string) *httptest.ResponseRecorder {
+		req := newTestRequest(http.MethodPatch, "/v1/tasks/"+task.ID, `{"title":"Changed"}`, "student", "id", task.ID)
+		req.Header.Set("If-Match", ifMatch)
+		return serve(h.UpdateTask, req)
+	}
+
+	rec := update(etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("Expected update with new ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// Устаревшая версия и ETag без версий – 412
+	for _, ifMatch := range []string{etag, `"abc"`} {
+		if rec := update(ifMatch); rec.Code != http.StatusPreconditionFailed {
+			t.Errorf("Expected 412 for If-Match %s, got %d", ifMatch, rec.Code)
+		}
+	}
+	// Из нескольких ETag подходит текущий
+	if rec := update(etag + `, ` + rec.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Errorf("Expected update with one matching ETag, got %d", rec.Code)
+	}
+}
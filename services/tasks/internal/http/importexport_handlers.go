@@ -0,0 +1,147 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Формат файла по типу содержимого, если ?format= не указан
+var importFormats = map[string]string{
+	"application/json": taskio.FormatJSON,
+	"text/csv":         taskio.FormatCSV,
+	"text/calendar":    taskio.FormatICal,
+}
+
+// Выгрузка своих задач: GET /v1/tasks/export?format=json|csv|ics
+func (h *Handlers) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = taskio.FormatJSON
+	}
+	if err := (models.ImportRequest{Format: format}).Validate(); err != nil {
+		writeTaskRefError(w, err)
+		return
+	}
+
+	records, err := h.service(r).Export(subject)
+	if err != nil {
+		log.Error("failed to export tasks", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", taskio.ContentType(format))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "tasks." + format}))
+	w.WriteHeader(http.StatusOK)
+	if err := taskio.Write(w, format, records); err != nil {
+		log.Error("failed to write export", zap.Error(err), zap.String("format", format))
+		return
+	}
+	log.Info("tasks exported", zap.String("format", format), zap.Int("count", len(records)))
+}
+
+// Загрузка задач из файла телом запроса: POST /v1/tasks/import?format=csv&dry_run=true.
+// Ответ 200 – отчет по строкам, 202 – импорт поставлен в очередь worker'а.
+func (h *Handlers) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[contentType]
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, models.MaxImportBytes))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(errorResponse{Error: "import file too large (max 5 MB)"})
+		return
+	}
+	if err != nil {
+		log.Warn("failed to read import body", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "failed to read request body"})
+		return
+	}
+
+	job, err := h.service(r).Import(models.ImportRequest{
+		Format: format,
+		DryRun: r.URL.Query().Get("dry_run") == "true",
+		Body:   bytes.NewReader(body),
+	}, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("import rejected", zap.String("format", format), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to import tasks", zap.Error(err), zap.String("format", format))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	status := http.StatusOK
+	if job.Status == models.ImportQueued {
+		status = http.StatusAccepted
+		w.Header().Set("Location", "/v1/imports/"+job.ID)
+	}
+
+	log.Info("import processed",
+		zap.String("format", format),
+		zap.String("status", job.Status),
+		zap.Bool("dry_run", job.DryRun),
+		zap.Int("total", job.Total),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(job)
+}
+
+// Состояние и отчет импорта, выполняемого worker'ом
+func (h *Handlers) GetImport(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	job, err := h.service(r).ImportStatus(id, subject)
+	if err != nil {
+		log.Error("failed to get import", zap.Error(err), zap.String("import_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if job.ID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "import not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func TestImportTasksErrors(t *testing.T) {
+	h, tasksService := newTestHandlers()
+	tasksService.SetQuotas(models.Quotas{MaxTasks: 2})
+
+	importCSV := func(body string) *httptest.ResponseRecorder {
+		return doRequest(h.ImportTasks, http.MethodPost, "/v1/tasks/import?format=csv", body, "student")
+	}
+
+	// Строки сверх квоты пространства не создаются, остальные – создаются
+	rec := importCSV("title\nFirst\nSecond\nThird\n")
+	var job models.ImportJob
+	if rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&job) != nil {
+		t.Fatalf("Expected 200 for import, got %d", rec.Code)
+	}
+	if job.Created != 2 || job.Failed != 1 || !strings.Contains(job.Rows[2].Error, "quota") {
+		t.Errorf("Expected third row to exceed quota, got %+v", job.Report)
+	}
+
+	if code := importCSV("title\n" + strings.Repeat("x", models.MaxImportBytes) + "\n").Code; code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for oversized file, got %d", code)
+	}
+	if code := doRequest(h.ImportTasks, http.MethodPost, "/v1/tasks/import?format=xml", "<tasks/>", "student").Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown format, got %d", code)
+	}
+}
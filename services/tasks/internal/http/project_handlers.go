@@ -0,0 +1,165 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+func writeProjectNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(errorResponse{Error: "project not found"})
+}
+
+// Список проектов; ?archived=true включает архивные
+func (h *Handlers) ListProjects(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	projects, err := h.service(r).Projects(subject, r.URL.Query().Get("archived") == "true")
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if projects == nil {
+		projects = []models.Project{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(projects)
+}
+
+func (h *Handlers) CreateProject(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	var req models.CreateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	project, err := h.service(r).CreateProject(req, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	log.Info("project created", zap.String("project_id", project.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	project, err := h.service(r).GetProject(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if project.ID == "" {
+		log.Info("project not found", zap.String("project_id", id))
+		writeProjectNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(project)
+}
+
+func (h *Handlers) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var updates models.ProjectUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	project, err := h.service(r).UpdateProject(id, updates, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if project.ID == "" {
+		log.Info("project not found for update", zap.String("project_id", id))
+		writeProjectNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(project)
+}
+
+// Удаление проекта; его задачи остаются без проекта
+func (h *Handlers) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	deleted, err := h.service(r).DeleteProject(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if !deleted {
+		log.Info("project not found for deletion", zap.String("project_id", id))
+		writeProjectNotFound(w)
+		return
+	}
+
+	log.Info("project deleted", zap.String("project_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Задачи проекта; фильтры по меткам работают так же, как в /v1/tasks
+func (h *Handlers) ListProjectTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	project, err := h.service(r).GetProject(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if project.ID == "" {
+		log.Info("project not found", zap.String("project_id", id))
+		writeProjectNotFound(w)
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("project_id", id)
+	r.URL.RawQuery = query.Encode()
+	h.ListTasks(w, r)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/shared/middleware"
+)
+
+type quickAddRequest struct {
+	Text string `json:"text"`
+	// Только разбор строки без создания задачи
+	Preview bool `json:"preview"`
+}
+
+func (h *Handlers) QuickAddTask(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	var req quickAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	if req.Preview {
+		parsed, err := h.service(r).ParseQuickAdd(req.Text, subject)
+		if err != nil {
+			writeServiceError(w, log, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(parsed)
+		return
+	}
+
+	created, err := h.service(r).QuickAdd(req.Text, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("invalid quick-add task", zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to quick-add task", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	log.Info("task quick-added",
+		zap.String("task_id", created.ID),
+		zap.String("title", created.Title),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(created))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Пропуск вхождения: срок переносится на следующую дату серии
+func (h *Handlers) SkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	h.changeRecurrence(w, r, "skip", h.service(r).SkipOccurrence)
+}
+
+// Остановка серии: текущая задача остается, следующие не создаются
+func (h *Handlers) StopRecurrence(w http.ResponseWriter, r *http.Request) {
+	h.changeRecurrence(w, r, "stop", h.service(r).StopRecurrence)
+}
+
+func (h *Handlers) changeRecurrence(w http.ResponseWriter, r *http.Request, action string,
+	change func(id string, subject string, ctx context.Context) (models.Task, error)) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	task, err := change(id, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("recurrence change rejected", zap.String("task_id", id), zap.String("action", action), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to change recurrence", zap.Error(err), zap.String("task_id", id), zap.String("action", action))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if task.ID == "" {
+		writeTaskNotFound(w)
+		return
+	}
+
+	log.Info("recurrence changed", zap.String("task_id", id), zap.String("action", action))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+func (h *Handlers) ListReminders(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	reminders, err := h.service(r).Reminders(id, subject)
+	if err != nil {
+		log.Error("failed to list reminders", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if reminders == nil {
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reminders)
+}
+
+// Напоминание о сроке: {"minutes_before": 60, "channel": "email", "target": "me@example.com"}
+func (h *Handlers) AddReminder(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.CreateReminderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	reminder, err := h.service(r).AddReminder(id, req, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if reminder.ID == "" {
+		log.Info("task not found for reminder", zap.String("task_id", id))
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reminder)
+}
+
+func (h *Handlers) DeleteReminder(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	reminderID := r.PathValue("reminder")
+
+	deleted, err := h.service(r).DeleteReminder(id, reminderID, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if !deleted {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "reminder not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Операции с доступом к задаче или проекту; ресурс задает notFound
+type shareOps struct {
+	list     func(id string, subject string) ([]models.Share, error)
+	share    func(id string, req models.ShareRequest, subject string, ctx context.Context) (models.Share, error)
+	unshare  func(id string, grantee string, subject string, ctx context.Context) (bool, error)
+	notFound func(w http.ResponseWriter)
+}
+
+func (h *Handlers) taskShareOps(r *http.Request) shareOps {
+	tasks := h.service(r)
+	return shareOps{tasks.TaskShares, tasks.ShareTask, tasks.UnshareTask, writeTaskNotFound}
+}
+
+func (h *Handlers) projectShareOps(r *http.Request) shareOps {
+	tasks := h.service(r)
+	return shareOps{tasks.ProjectShares, tasks.ShareProject, tasks.UnshareProject, writeProjectNotFound}
+}
+
+func (h *Handlers) ListTaskShares(w http.ResponseWriter, r *http.Request) {
+	h.listShares(w, r, h.taskShareOps(r))
+}
+
+// Доступ к задаче: {"subject": "teacher", "role": "editor"}
+func (h *Handlers) ShareTask(w http.ResponseWriter, r *http.Request) {
+	h.share(w, r, h.taskShareOps(r))
+}
+
+func (h *Handlers) UnshareTask(w http.ResponseWriter, r *http.Request) {
+	h.unshare(w, r, h.taskShareOps(r))
+}
+
+func (h *Handlers) ListProjectShares(w http.ResponseWriter, r *http.Request) {
+	h.listShares(w, r, h.projectShareOps(r))
+}
+
+// Доступ к проекту открывает все его задачи
+func (h *Handlers) ShareProject(w http.ResponseWriter, r *http.Request) {
+	h.share(w, r, h.projectShareOps(r))
+}
+
+func (h *Handlers) UnshareProject(w http.ResponseWriter, r *http.Request) {
+	h.unshare(w, r, h.projectShareOps(r))
+}
+
+func (h *Handlers) listShares(w http.ResponseWriter, r *http.Request, ops shareOps) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	shares, err := ops.list(id, subject)
+	if err != nil {
+		log.Error("failed to list shares", zap.Error(err), zap.String("resource_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if shares == nil {
+		ops.notFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(shares)
+}
+
+func (h *Handlers) share(w http.ResponseWriter, r *http.Request, ops shareOps) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.ShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	share, err := ops.share(id, req, subject, r.Context())
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if share.ResourceID == "" {
+		ops.notFound(w)
+		return
+	}
+
+	log.Info("access granted",
+		zap.String("resource_id", id),
+		zap.String("grantee", share.Subject),
+		zap.String("role", share.Role),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(share)
+}
+
+func (h *Handlers) unshare(w http.ResponseWriter, r *http.Request, ops shareOps) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	grantee := r.PathValue("subject")
+
+	deleted, err := ops.unshare(id, grantee, subject, r.Context())
+	if err != nil {
+		log.Error("failed to revoke access", zap.Error(err), zap.String("resource_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !deleted {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "share not found"})
+		return
+	}
+
+	log.Info("access revoked", zap.String("resource_id", id), zap.String("grantee", grantee))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Задачи, открытые пользователю другими (напрямую или через проект)
+func (h *Handlers) SharedTasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	tasks, err := h.service(r).SharedTasks(subject)
+	if err != nil {
+		log.Error("failed to list shared tasks", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tasks)
+}
+
+func (h *Handlers) SharedProjects(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	projects, err := h.service(r).SharedProjects(subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(projects)
+}
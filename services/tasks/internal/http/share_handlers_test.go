@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func TestSharedTaskAccess(t *testing.T) {
+	h, tasksService := newTestHandlers()
+
+	task, err := tasksService.Create(models.Task{Title: "Shared"}, "student", context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	share := func(role string) {
+		rec := doRequest(h.ShareTask, http.MethodPost, "/v1/tasks/"+task.ID+"/shares",
+			`{"subject":"teacher","role":"`+role+`"}`, "student", "id", task.ID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Failed to share task: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+	update := func() int {
+		return doRequest(h.UpdateTask, http.MethodPatch, "/v1/tasks/"+task.ID, `{"title":"Changed"}`, "teacher", "id", task.ID).Code
+	}
+	tag := func() int {
+		return doRequest(h.AddTaskTags, http.MethodPost, "/v1/tasks/"+task.ID+"/tags", `{"tags":["work"]}`, "teacher", "id", task.ID).Code
+	}
+
+	// Без доступа задача не видна
+	if code := doRequest(h.GetTask, http.MethodGet, "/v1/tasks/"+task.ID, "", "teacher", "id", task.ID).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 before sharing, got %d", code)
+	}
+
+	// Наблюдатель читает, но не меняет задачу
+	share(models.RoleViewer)
+	if code := doRequest(h.GetTask, http.MethodGet, "/v1/tasks/"+task.ID, "", "teacher", "id", task.ID).Code; code != http.StatusOK {
+		t.Errorf("Expected viewer to read task, got %d", code)
+	}
+	if code := update(); code != http.StatusForbidden {
+		t.Errorf("Expected 403 on viewer update, got %d", code)
+	}
+	if code := tag(); code != http.StatusForbidden {
+		t.Errorf("Expected 403 on viewer tagging, got %d", code)
+	}
+
+	share(models.RoleEditor)
+	if code := update(); code != http.StatusOK {
+		t.Errorf("Expected editor update, got %d", code)
+	}
+
+	// Делиться задачей может только владелец: для остальных ее нет
+	rec := doRequest(h.ShareTask, http.MethodPost, "/v1/tasks/"+task.ID+"/shares", `{"subject":"guest"}`, "teacher", "id", task.ID)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 on editor sharing, got %d", rec.Code)
+	}
+}
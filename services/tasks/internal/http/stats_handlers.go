@@ -0,0 +1,40 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Статистика задач: ?from=2026-03-01&to=2026-03-31 (даты включительно, в
+// часовом поясе пользователя; по умолчанию – последние 30 дней)
+func (h *Handlers) TaskStats(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	query := models.StatsQuery{
+		From: r.URL.Query().Get("from"),
+		To:   r.URL.Query().Get("to"),
+	}
+
+	stats, err := h.service(r).Stats(query, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("stats request rejected", zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to compute task stats", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
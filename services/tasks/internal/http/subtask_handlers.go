@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Прямые подзадачи задачи в формате GET /v1/tasks
+func (h *Handlers) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	task, err := h.service(r).GetByID(id, subject)
+	if err != nil {
+		log.Error("failed to get task", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if task.ID == "" {
+		log.Info("task not found for subtasks", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("parent_id", id)
+	r.URL.RawQuery = query.Encode()
+	h.ListTasks(w, r)
+}
+
+// Пункт чек-листа: {"title": "Buy milk"}
+func (h *Handlers) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.CreateChecklistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	item, err := h.service(r).AddChecklistItem(id, req, subject, r.Context())
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if item.ID == "" {
+		log.Info("task not found for checklist", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+		return
+	}
+
+	log.Info("checklist item added", zap.String("task_id", id), zap.String("item_id", item.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *Handlers) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	itemID := r.PathValue("item")
+
+	var updates models.ChecklistItemUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	item, err := h.service(r).UpdateChecklistItem(id, itemID, updates, subject, r.Context())
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if item.ID == "" {
+		log.Info("checklist item not found", zap.String("task_id", id), zap.String("item_id", itemID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "checklist item not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *Handlers) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	itemID := r.PathValue("item")
+
+	deleted, err := h.service(r).DeleteChecklistItem(id, itemID, subject, r.Context())
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if !deleted {
+		log.Info("checklist item not found for deletion", zap.String("task_id", id), zap.String("item_id", itemID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "checklist item not found"})
+		return
+	}
+
+	log.Info("checklist item deleted", zap.String("task_id", id), zap.String("item_id", itemID))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// parseTaskFilter читает фильтр списка задач:
+// ?tag=work&tag=urgent или ?tag=work,urgent; tag_mode=any – любая из меток;
+// project_id и parent_id – задачи проекта и прямые подзадачи задачи; status – статус;
+// sort=priority – сначала срочные задачи. Метки, как и при хранении,
+// сравниваются без учета регистра и пробелов; повторы отбрасываются.
+func parseTaskFilter(r *http.Request) models.TaskFilter {
+	var filter models.TaskFilter
+	seen := make(map[string]bool)
+	for _, value := range r.URL.Query()["tag"] {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "" && !seen[name] {
+				seen[name] = true
+				filter.Tags = append(filter.Tags, name)
+			}
+		}
+	}
+	filter.MatchAllTags = r.URL.Query().Get("tag_mode") != "any"
+	filter.ProjectID = r.URL.Query().Get("project_id")
+	filter.ParentID = r.URL.Query().Get("parent_id")
+	filter.Status = models.NormalizeStatus(r.URL.Query().Get("status"))
+	filter.SortByPriority = r.URL.Query().Get("sort") == "priority"
+	filter.Due = r.URL.Query().Get("due")
+	// ?assignee=me – задачи, назначенные текущему пользователю
+	filter.Assignee = strings.TrimSpace(r.URL.Query().Get("assignee"))
+	if filter.Assignee == "me" {
+		filter.Assignee, _ = r.Context().Value("subject").(string)
+	}
+	return filter
+}
+
+func (h *Handlers) ListTags(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	tags, err := h.service(r).Tags(subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tags)
+}
+
+func (h *Handlers) CreateTag(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	var req models.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	tag, err := h.service(r).CreateTag(req, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	log.Info("tag created", zap.String("tag_id", tag.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tag)
+}
+
+func (h *Handlers) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var updates models.TagUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	tag, err := h.service(r).UpdateTag(id, updates, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	if tag.ID == "" {
+		log.Info("tag not found for update", zap.String("tag_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "tag not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tag)
+}
+
+func (h *Handlers) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	deleted, err := h.service(r).DeleteTag(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	if !deleted {
+		log.Info("tag not found for deletion", zap.String("tag_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "tag not found"})
+		return
+	}
+
+	log.Info("tag deleted", zap.String("tag_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Назначение меток задаче: {"tags": ["work", "urgent"]}
+func (h *Handlers) AddTaskTags(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.TaskTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	task, err := h.service(r).AddTaskTags(id, req.Tags, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	if task.ID == "" {
+		log.Info("task not found for tagging", zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *Handlers) RemoveTaskTag(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	name := r.PathValue("tag")
+
+	task, removed, err := h.service(r).RemoveTaskTag(id, name, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	if task.ID == "" || !removed {
+		message := "task not found"
+		if task.ID != "" {
+			message = "tag is not assigned to task"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: message})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(task)
+}
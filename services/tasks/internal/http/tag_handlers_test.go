@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateTagErrors(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	if rec := doRequest(h.CreateTag, http.MethodPost, "/v1/tags", `{"name":"Work"}`, "student"); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"name":"work"}`, http.StatusConflict},
+		{`{"name":"  "}`, http.StatusBadRequest},
+		{`{"name":"Home","color":"red"}`, http.StatusBadRequest},
+		{`{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := doRequest(h.CreateTag, http.MethodPost, "/v1/tags", tt.body, "student"); rec.Code != tt.want {
+			t.Errorf("CreateTag(%s) = %d, want %d: %s", tt.body, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+
+	// Метки разных пользователей не конфликтуют
+	if rec := doRequest(h.CreateTag, http.MethodPost, "/v1/tags", `{"name":"Work"}`, "teacher"); rec.Code != http.StatusCreated {
+		t.Errorf("Expected another user's tag to be created, got %d", rec.Code)
+	}
+}
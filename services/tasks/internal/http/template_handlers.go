@@ -0,0 +1,180 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+func writeTemplateNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(errorResponse{Error: "template not found"})
+}
+
+func (h *Handlers) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	templates, err := h.service(r).Templates(subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if templates == nil {
+		templates = []models.TaskTemplate{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	var req models.CreateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	template, err := h.service(r).CreateTemplate(req, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	log.Info("template created", zap.String("template_id", template.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *Handlers) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	template, err := h.service(r).GetTemplate(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if template.ID == "" {
+		log.Info("template not found", zap.String("template_id", id))
+		writeTemplateNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *Handlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var updates models.TemplateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	template, err := h.service(r).UpdateTemplate(id, updates, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if template.ID == "" {
+		log.Info("template not found for update", zap.String("template_id", id))
+		writeTemplateNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+// Удаление шаблона; созданные из него задачи остаются
+func (h *Handlers) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	deleted, err := h.service(r).DeleteTemplate(id, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if !deleted {
+		log.Info("template not found for deletion", zap.String("template_id", id))
+		writeTemplateNotFound(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Создание дерева задач из шаблона в одной транзакции:
+// {"start_date": "2026-03-10", "project_id": "...", "variables": {"name": "Иван"}}
+func (h *Handlers) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.InstantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	instance, err := h.service(r).InstantiateTemplate(id, req, subject, r.Context())
+	if writeTaskRefError(w, err) {
+		log.Info("template instantiation rejected", zap.String("template_id", id), zap.Error(err))
+		return
+	}
+	if err != nil {
+		log.Error("failed to instantiate template", zap.Error(err), zap.String("template_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if instance.Task.ID == "" {
+		log.Info("template not found for instantiation", zap.String("template_id", id))
+		writeTemplateNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/v1/tasks/"+instance.Task.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(instance)
+}
@@ -0,0 +1,203 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+type timeEntriesResponse struct {
+	Entries      []models.TimeEntry `json:"entries"`
+	TotalSeconds int64              `json:"total_seconds"` // без запущенного таймера
+}
+
+func writeTaskNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(errorResponse{Error: "task not found"})
+}
+
+// Запуск таймера; тело {"note": "..."} необязательно
+func (h *Handlers) StartTimer(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.TimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	entry, err := h.service(r).StartTimer(id, req, subject)
+	if errors.Is(err, models.ErrTimerRunning) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(errorResponse{Error: "another timer is already running"})
+		return
+	}
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if entry.ID == "" {
+		log.Info("task not found for timer", zap.String("task_id", id))
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *Handlers) StopTimer(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	entry, err := h.service(r).StopTimer(id, subject)
+	if errors.Is(err, models.ErrTimerNotRunning) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(errorResponse{Error: "timer is not running on this task"})
+		return
+	}
+	if err != nil {
+		log.Error("failed to stop timer", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *Handlers) ListTimeEntries(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	entries, found, err := h.service(r).TimeEntries(id, subject)
+	if err != nil {
+		log.Error("failed to list time entries", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !found {
+		writeTaskNotFound(w)
+		return
+	}
+
+	response := timeEntriesResponse{Entries: entries}
+	for _, entry := range entries {
+		response.TotalSeconds += entry.DurationSeconds
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Ручная запись времени: {"minutes": 30, "started_at": "...", "note": "..."}
+func (h *Handlers) LogTime(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+
+	var req models.CreateTimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request", zap.Error(err))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{Error: "invalid request format"})
+		return
+	}
+
+	entry, err := h.service(r).LogTime(id, req, subject)
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+	if entry.ID == "" {
+		log.Info("task not found for time entry", zap.String("task_id", id))
+		writeTaskNotFound(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *Handlers) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	id := r.PathValue("id")
+	entryID := r.PathValue("entry")
+
+	deleted, err := h.service(r).DeleteTimeEntry(id, entryID, subject)
+	if err != nil {
+		log.Error("failed to delete time entry", zap.Error(err), zap.String("task_id", id))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "internal server error"})
+		return
+	}
+	if !deleted {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{Error: "time entry not found"})
+		return
+	}
+
+	log.Info("time entry deleted", zap.String("task_id", id), zap.String("entry_id", entryID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Отчет по учтенному времени: ?group_by=task|project|day&from=2026-03-01&to=2026-03-31
+// (даты включительно, в часовом поясе пользователя)
+func (h *Handlers) TimeReport(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+	log := h.log.WithRequestID(requestID)
+	subject := r.Context().Value("subject").(string)
+
+	query := r.URL.Query()
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = models.TimeReportByTask
+	}
+
+	report, err := h.service(r).TimeReport(subject, groupBy, query.Get("from"), query.Get("to"))
+	if err != nil {
+		writeServiceError(w, log, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
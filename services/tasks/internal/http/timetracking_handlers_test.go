@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func TestTimerAndReportErrors(t *testing.T) {
+	h, tasksService := newTestHandlers()
+
+	task, err := tasksService.Create(models.Task{Title: "Timed"}, "student", context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	timer := func(handler http.HandlerFunc, action string) int {
+		return doRequest(handler, http.MethodPost, "/v1/tasks/"+task.ID+"/timer/"+action, "", "student", "id", task.ID).Code
+	}
+
+	if code := timer(h.StopTimer, "stop"); code != http.StatusConflict {
+		t.Errorf("Expected 409 when timer is not running, got %d", code)
+	}
+	if code := timer(h.StartTimer, "start"); code != http.StatusOK {
+		t.Fatalf("Expected 200 on start, got %d", code)
+	}
+	if code := timer(h.StopTimer, "stop"); code != http.StatusOK {
+		t.Errorf("Expected 200 on stop, got %d", code)
+	}
+	if code := doRequest(h.StartTimer, http.MethodPost, "/v1/tasks/missing/timer/start", "", "student", "id", "missing").Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing task, got %d", code)
+	}
+
+	for _, query := range []string{"group_by=week", "from=10.03.2026", "to=2026-13-01"} {
+		if code := doRequest(h.TimeReport, http.MethodGet, "/v1/time-report?"+query, "", "student").Code; code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, code)
+		}
+	}
+	if code := doRequest(h.TimeReport, http.MethodGet, "/v1/time-report?group_by=day&from=2026-03-01&to=2026-03-31", "", "student").Code; code != http.StatusOK {
+		t.Errorf("Expected 200 for report, got %d", code)
+	}
+}
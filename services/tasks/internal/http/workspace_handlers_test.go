@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func TestWorkspaceQuotaResponses(t *testing.T) {
+	h, tasksService := newTestHandlers()
+	tasksService.SetQuotas(models.Quotas{MaxTasks: 1, MaxProjects: 1})
+
+	create := func() int {
+		return doRequest(h.CreateTask, http.MethodPost, "/v1/tasks", `{"title":"Task"}`, "student").Code
+	}
+
+	rec := doRequest(h.CreateTask, http.MethodPost, "/v1/tasks", `{"title":"Task"}`, "student")
+	var task models.Task
+	if rec.Code != http.StatusCreated || json.NewDecoder(rec.Body).Decode(&task) != nil {
+		t.Fatalf("Expected 201, got %d", rec.Code)
+	}
+	if code := create(); code != http.StatusForbidden {
+		t.Errorf("Expected 403 over task quota, got %d", code)
+	}
+
+	// Задача в корзине не расходует квоту, но восстановить ее сверх квоты нельзя
+	if code := doRequest(h.DeleteTask, http.MethodDelete, "/v1/tasks/"+task.ID, "", "student", "id", task.ID).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204 on delete, got %d", code)
+	}
+	if code := create(); code != http.StatusCreated {
+		t.Fatalf("Expected 201 after moving task to trash, got %d", code)
+	}
+	if code := doRequest(h.RestoreTask, http.MethodPost, "/v1/tasks/"+task.ID+"/restore", "", "student", "id", task.ID).Code; code != http.StatusForbidden {
+		t.Errorf("Expected 403 on restore over quota, got %d", code)
+	}
+
+	if code := doRequest(h.CreateProject, http.MethodPost, "/v1/projects", `{"name":"One"}`, "student").Code; code != http.StatusCreated {
+		t.Fatalf("Expected 201 for project, got %d", code)
+	}
+	if code := doRequest(h.CreateProject, http.MethodPost, "/v1/projects", `{"name":"Two"}`, "student").Code; code != http.StatusForbidden {
+		t.Errorf("Expected 403 over project quota, got %d", code)
+	}
+
+	rec = doRequest(h.GetWorkspace, http.MethodGet, "/v1/workspace", "", "student")
+	var workspace models.Workspace
+	if rec.Code != http.StatusOK || json.NewDecoder(rec.Body).Decode(&workspace) != nil {
+		t.Fatalf("Expected workspace, got %d", rec.Code)
+	}
+	if workspace.Usage.Tasks != 1 || workspace.Usage.Projects != 1 {
+		t.Errorf("Unexpected workspace usage: %+v", workspace.Usage)
+	}
+}
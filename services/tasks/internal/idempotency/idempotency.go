@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record – сохраненный результат запроса с заголовком Idempotency-Key
+type Record struct {
+	Fingerprint string            `json:"fingerprint"`
+	StatusCode  int               `json:"status_code"` // 0 – запрос еще выполняется
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// InProgress сообщает, что первый запрос с этим ключом еще не завершился
+func (r Record) InProgress() bool {
+	return r.StatusCode == 0
+}
+
+// Store хранит ключи идемпотентности в течение окна ttl
+type Store interface {
+	// Claim резервирует ключ за запросом с отпечатком fingerprint.
+	// Если ключ уже занят, возвращает существующую запись и false.
+	Claim(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error)
+	// Complete сохраняет ответ для повторов
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release освобождает ключ, если ответ не нужно запоминать (ошибка 5xx)
+	Release(ctx context.Context, key string) error
+}
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	MaxKeyLength = 255
+)
@@ -0,0 +1,185 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/shared/logger"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// Заголовки ответа, которые воспроизводятся при повторе
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+const (
+	maxBodySize = 1 << 20
+	// Сколько держится ключ незавершенного запроса: если обработчик упал,
+	// повтор станет возможен после истечения этого времени
+	claimTTL = time.Minute
+)
+
+type Middleware struct {
+	store Store
+	ttl   time.Duration
+	log   *logger.Logger
+}
+
+func NewMiddleware(store Store, ttl time.Duration, log *logger.Logger) *Middleware {
+	return &Middleware{
+		store: store,
+		ttl:   ttl,
+		log:   log,
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Wrap делает обработчик идемпотентным по заголовку Idempotency-Key.
+// Должен вызываться внутри AuthMiddleware: ключи изолированы по subject.
+func (m *Middleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestID := middleware.GetRequestID(r.Context())
+		log := m.log.WithRequestID(requestID).With(zap.String("idempotency_key", key))
+
+		if len(key) > MaxKeyLength {
+			writeError(w, http.StatusBadRequest, "idempotency key too long")
+			return
+		}
+
+		// Лишний байт отличает тело ровно maxBodySize от обрезанного
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			log.Error("failed to read request body", zap.Error(err))
+			writeError(w, http.StatusBadRequest, "invalid request format")
+			return
+		}
+		if len(body) > maxBodySize {
+			log.Warn("idempotent request body too large")
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large (max 1 MB)")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		subject, _ := r.Context().Value("subject").(string)
+		storeKey := subject + ":" + key
+		fingerprint := requestFingerprint(r, body)
+
+		existing, claimed, err := m.store.Claim(r.Context(), storeKey, fingerprint, claimTTL)
+		if err != nil {
+			// Хранилище недоступно – выполняем запрос без защиты от повторов
+			log.Warn("idempotency store unavailable, processing request without it", zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !claimed {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				log.Warn("idempotency key reused with different request")
+				writeError(w, http.StatusUnprocessableEntity, "idempotency key was used with a different request")
+			case existing.InProgress():
+				log.Info("request with idempotency key is still in progress")
+				writeError(w, http.StatusConflict, "request with this idempotency key is in progress")
+			default:
+				log.Info("replaying stored response", zap.Int("status", existing.StatusCode))
+				replay(w, existing)
+			}
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// Ответы 5xx не запоминаются, чтобы клиент мог повторить запрос
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if rec.status >= http.StatusInternalServerError {
+			if err := m.store.Release(ctx, storeKey); err != nil {
+				log.Warn("failed to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		record := Record{
+			Fingerprint: fingerprint,
+			StatusCode:  rec.status,
+			Header:      make(map[string]string),
+			Body:        rec.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				record.Header[name] = value
+			}
+		}
+
+		if err := m.store.Complete(ctx, storeKey, record, m.ttl); err != nil {
+			log.Warn("failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// Отпечаток запроса: метод, путь и тело
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, record *Record) {
+	for name, value := range record.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
+
+// recorder пишет ответ клиенту и одновременно запоминает его
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
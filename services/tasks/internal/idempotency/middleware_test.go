@@ -0,0 +1,152 @@
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/idempotency"
+	"tech-ip-sem2/services/tasks/internal/repository"
+	"tech-ip-sem2/shared/logger"
+)
+
+func newTestMiddleware(t *testing.T) *idempotency.Middleware {
+	repo, err := repository.NewSQLiteTaskRepository(repository.SQLiteMemory)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	store := repository.NewIdempotencyRepository(repo.DB())
+	return idempotency.NewMiddleware(store, time.Hour, logger.New("test"))
+}
+
+func doRequest(handler http.HandlerFunc, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), "subject", "student"))
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	handler := newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"task-%d"}`, calls)
+	})
+
+	first := doRequest(handler, "key-1", `{"title":"Pay rent"}`)
+	second := doRequest(handler, "key-1", `{"title":"Pay rent"}`)
+
+	if calls != 1 {
+		t.Fatalf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("Expected replayed 201 %q, got %d %q", first.Body.String(), second.Code, second.Body.String())
+	}
+	if second.Header().Get(idempotency.HeaderReplayed) != "true" {
+		t.Error("Expected replayed response to be marked")
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Error("Expected Content-Type to be replayed")
+	}
+
+	// Без ключа запрос выполняется всегда
+	doRequest(handler, "", `{"title":"Pay rent"}`)
+	if calls != 2 {
+		t.Errorf("Expected request without key to run, calls=%d", calls)
+	}
+}
+
+func TestIdempotencyKeyReusedWithDifferentBody(t *testing.T) {
+	handler := newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	doRequest(handler, "key-2", `{"title":"A"}`)
+	rec := doRequest(handler, "key-2", `{"title":"B"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", rec.Code)
+	}
+}
+
+func TestIdempotencyServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	handler := newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	doRequest(handler, "key-3", `{}`)
+	rec := doRequest(handler, "key-3", `{}`)
+
+	if calls != 2 || rec.Code != http.StatusCreated {
+		t.Errorf("Expected retry after 500 to run again, calls=%d status=%d", calls, rec.Code)
+	}
+}
+
+func TestIdempotencyBodyTooLarge(t *testing.T) {
+	calls := 0
+	handler := newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rec := doRequest(handler, "key-4", strings.Repeat("a", 1<<20+1))
+	if calls != 0 || rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 without running handler, calls=%d status=%d", calls, rec.Code)
+	}
+
+	// Тело ровно на пределе обрабатывается полностью
+	var body string
+	handler = newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusCreated)
+	})
+	limit := strings.Repeat("a", 1<<20)
+	if rec := doRequest(handler, "key-5", limit); rec.Code != http.StatusCreated || body != limit {
+		t.Errorf("Expected body at limit to pass through, status=%d len=%d", rec.Code, len(body))
+	}
+}
+
+func TestIdempotencyRequestInProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := newTestMiddleware(t).Wrap(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- doRequest(handler, "key-6", `{}`) }()
+	<-started
+
+	// Повтор до завершения первого запроса не выполняется
+	if rec := doRequest(handler, "key-6", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 while first request is in progress, got %d", rec.Code)
+	}
+
+	close(release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Errorf("Expected first request to complete with 201, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore хранит ключи идемпотентности в Redis с истечением по TTL
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client:    client,
+		keyPrefix: "tasks:idempotency:",
+	}
+}
+
+func (s *RedisStore) Claim(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	claimed, err := s.client.SetNX(ctx, s.keyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if claimed {
+		return nil, true, nil
+	}
+
+	stored, err := s.client.Get(ctx, s.keyPrefix+key).Bytes()
+	if err == redis.Nil {
+		// Ключ истек между SETNX и GET – пробуем занять еще раз
+		return s.Claim(ctx, key, fingerprint, ttl)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(stored, &record); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+
+	return &record, false, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.keyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.keyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// События, о которых worker уведомляет исполнителя
+const (
+	EventTaskAssigned   = "task.assigned"
+	EventTaskUnassigned = "task.unassigned"
+)
+
+// AssignRequest – {"assignee": "teacher"}
+type AssignRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// Validate проверяет имя исполнителя
+func (r *AssignRequest) Validate() error {
+	r.Assignee = strings.TrimSpace(r.Assignee)
+	if r.Assignee == "" {
+		return &ValidationError{"assignee is required"}
+	}
+	if len(r.Assignee) > 100 {
+		return &ValidationError{"assignee too long (max 100 characters)"}
+	}
+	return nil
+}
+
+// TaskNotification – уведомление пользователю Recipient о событии задачи,
+// которое доставляет worker
+type TaskNotification struct {
+	ID        string
+	TaskID    string
+	Recipient string
+	Event     string
+	Actor     string
+	// Комментарий с упоминанием; "" для назначений
+	CommentID string
+	CreatedAt time.Time
+}
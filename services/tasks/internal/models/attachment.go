@@ -0,0 +1,81 @@
+package models
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Размер вложения по умолчанию не больше 10 МБ
+const DefaultMaxAttachmentSize = 10 << 20
+
+// Типы вложений, определяемые по содержимому файла. HTML, SVG и скрипты не
+// принимаются: браузер мог бы выполнить их при скачивании.
+var AttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"application/zip": true,
+	"image/gif":       true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"text/plain":      true,
+}
+
+var (
+	ErrAttachmentTooLarge   = errors.New("attachment too large")
+	ErrAttachmentType       = errors.New("attachment type not allowed")
+	ErrAttachmentsDisabled  = errors.New("attachment storage not configured")
+	ErrAttachmentIncomplete = errors.New("attachment body shorter than declared size")
+)
+
+// Attachment – файл, приложенный к задаче; содержимое лежит в хранилище
+// под ключом StorageKey
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  string    `json:"uploaded_by"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentUpload – загружаемый файл: Body читается потоком, Size – длина
+// тела запроса
+type AttachmentUpload struct {
+	Filename string
+	Size     int64
+	Body     io.Reader
+}
+
+// Validate оставляет от имени файла только базовое имя без управляющих
+// символов и проверяет размер
+func (u *AttachmentUpload) Validate(maxSize int64) error {
+	name := strings.ReplaceAll(u.Filename, "\\", "/")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, path.Base(name))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return &ValidationError{"filename is required"}
+	}
+	if len(name) > 255 {
+		return &ValidationError{"filename too long (max 255 characters)"}
+	}
+	u.Filename = name
+
+	if u.Size <= 0 {
+		return &ValidationError{"attachment is empty"}
+	}
+	if u.Size > maxSize {
+		return ErrAttachmentTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Операции POST /v1/tasks/bulk
+const (
+	BulkCreate   = "create"
+	BulkUpdate   = "update"
+	BulkDelete   = "delete"
+	BulkComplete = "complete"
+)
+
+// Не больше 100 задач за один запрос
+const MaxBulkItems = 100
+
+// ErrTaskNotFound – результат элемента bulk для задачи, которой нет
+var ErrTaskNotFound = errors.New("task not found")
+
+// BulkRequest – операция над списком задач, выполняемая в одной транзакции.
+// create принимает задачи в tasks, остальные операции – идентификаторы ids
+// или фильтр filter; update применяет ко всем задачам изменения update.
+type BulkRequest struct {
+	Action string              `json:"action"`
+	IDs    []string            `json:"ids,omitempty"`
+	Filter *BulkFilter         `json:"filter,omitempty"`
+	Update *TaskUpdate         `json:"update,omitempty"`
+	Tasks  []CreateTaskRequest `json:"tasks,omitempty"`
+	// true – ошибка любого элемента откатывает все изменения
+	Atomic bool `json:"atomic"`
+}
+
+// BulkFilter – фильтр задач как у GET /v1/tasks
+type BulkFilter struct {
+	Tags      []string `json:"tags,omitempty"`
+	TagMode   string   `json:"tag_mode,omitempty"`
+	ProjectID string   `json:"project_id,omitempty"`
+	ParentID  string   `json:"parent_id,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	Due       string   `json:"due,omitempty"`
+	Assignee  string   `json:"assignee,omitempty"`
+}
+
+// TaskFilter переводит фильтр в фильтр списка задач
+func (f BulkFilter) TaskFilter() TaskFilter {
+	return TaskFilter{
+		Tags:         f.Tags,
+		MatchAllTags: f.TagMode != "any",
+		ProjectID:    f.ProjectID,
+		ParentID:     f.ParentID,
+		Status:       NormalizeStatus(f.Status),
+		Due:          f.Due,
+		Assignee:     strings.TrimSpace(f.Assignee),
+	}
+}
+
+// Validate проверяет операцию и убирает повторы идентификаторов
+func (r *BulkRequest) Validate() error {
+	switch r.Action {
+	case BulkCreate:
+		if len(r.Tasks) == 0 {
+			return &ValidationError{"tasks are required for create"}
+		}
+		if len(r.IDs) > 0 || r.Filter != nil {
+			return &ValidationError{"create does not accept ids or filter"}
+		}
+		if len(r.Tasks) > MaxBulkItems {
+			return &ValidationError{"too many tasks (max 100)"}
+		}
+		return nil
+	case BulkUpdate, BulkDelete, BulkComplete:
+	default:
+		return &ValidationError{"action must be create, update, delete or complete"}
+	}
+
+	if (len(r.IDs) == 0) == (r.Filter == nil) {
+		return &ValidationError{"either ids or filter is required"}
+	}
+	if r.Filter != nil && r.Filter.TaskFilter().IsEmpty() {
+		return &ValidationError{"filter must not be empty"}
+	}
+	if r.Action == BulkUpdate && r.Update == nil {
+		return &ValidationError{"update is required for update"}
+	}
+	if r.Update != nil {
+		// Версия относится к одной задаче и в bulk не проверяется
+		r.Update.Version = nil
+	}
+
+	seen := make(map[string]bool, len(r.IDs))
+	ids := make([]string, 0, len(r.IDs))
+	for _, id := range r.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > MaxBulkItems {
+		return &ValidationError{"too many tasks (max 100)"}
+	}
+	r.IDs = ids
+	return nil
+}
+
+// NewTask переводит запрос создания в задачу
+func (r CreateTaskRequest) NewTask() Task {
+	return Task{
+		Title:           r.Title,
+		Description:     r.Description,
+		DueDate:         r.DueDate,
+		ProjectID:       r.ProjectID,
+		ParentID:        r.ParentID,
+		AutoComplete:    r.AutoComplete,
+		Status:          r.Status,
+		Recurrence:      r.Recurrence,
+		Priority:        r.Priority,
+		EstimatePoints:  r.EstimatePoints,
+		EstimateMinutes: r.EstimateMinutes,
+	}
+}
+
+// BulkItem – результат операции над одной задачей: Index – позиция в tasks
+// для create, иначе в списке задач
+type BulkItem struct {
+	Index int
+	ID    string
+	Task  Task
+	Err   error
+}
+
+// BulkResult – результаты по элементам; Committed – изменения сохранены
+type BulkResult struct {
+	Items     []BulkItem
+	Committed bool
+}
+
+// Failed возвращает число элементов с ошибкой
+func (r BulkResult) Failed() int {
+	failed := 0
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// TaskEvent – событие задачи, опубликованное после завершения bulk
+// транзакции; PreviousStatus – для task.status_changed
+type TaskEvent struct {
+	Event          string
+	Task           Task
+	PreviousStatus string
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// Упоминание пользователя в комментарии
+const EventCommentMentioned = "comment.mentioned"
+
+// ErrNotCommentAuthor – изменить комментарий может только его автор
+var ErrNotCommentAuthor = errors.New("not the comment author")
+
+// @username в начале текста или после пробела и знаков препинания
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_][A-Za-z0-9_.-]{0,99})`)
+
+// Comment – комментарий к задаче; ответы образуют дерево через ParentID
+type Comment struct {
+	ID       string   `json:"id"`
+	TaskID   string   `json:"task_id"`
+	ParentID string   `json:"parent_id,omitempty"`
+	Author   string   `json:"author"`
+	Body     string   `json:"body"`
+	Mentions []string `json:"mentions"`
+	// Ответы на комментарий при выдаче обсуждения деревом
+	Replies   []Comment `json:"replies"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentRequest – {"body": "...", "parent_id": "..."}; parent_id – ответ на
+// комментарий, при изменении не учитывается
+type CommentRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"`
+}
+
+// Validate проверяет и очищает текст комментария
+func (r *CommentRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	r.ParentID = strings.TrimSpace(r.ParentID)
+	if r.Body == "" {
+		return &ValidationError{"comment body is required"}
+	}
+	if len(r.Body) > 5000 {
+		return &ValidationError{"comment too long (max 5000 characters)"}
+	}
+	r.Body = sanitize.SanitizeHTML(r.Body)
+	return nil
+}
+
+// ParseMentions возвращает упомянутых пользователей без повторов в порядке
+// появления; точка в конце имени считается концом предложения
+func ParseMentions(body string) []string {
+	mentions := []string{}
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		mentions = append(mentions, name)
+	}
+	return mentions
+}
+
+// CommentThread собирает комментарии задачи в дерево ответов; comments
+// упорядочены по времени создания
+func CommentThread(comments []Comment) []Comment {
+	children := make(map[string][]Comment)
+	for _, comment := range comments {
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+
+	var build func(parentID string) []Comment
+	build = func(parentID string) []Comment {
+		thread := make([]Comment, 0, len(children[parentID]))
+		for _, comment := range children[parentID] {
+			comment.Replies = build(comment.ID)
+			thread = append(thread, comment)
+		}
+		return thread
+	}
+	return build("")
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// ErrBlockerNotFound – блокирующая задача не существует или в корзине
+var ErrBlockerNotFound = errors.New("blocking task not found")
+
+// ErrDependencyCycle – зависимость замкнула бы цепочку блокировок
+var ErrDependencyCycle = errors.New("task dependency cycle")
+
+// ErrTaskBlocked – задачу нельзя выполнить, пока открыты блокирующие задачи
+var ErrTaskBlocked = errors.New("task is blocked by open tasks")
+
+// TaskDependency – задача TaskID заблокирована задачей BlockedByID
+type TaskDependency struct {
+	TaskID      string
+	BlockedByID string
+}
+
+type DependencyRequest struct {
+	BlockedBy string `json:"blocked_by"`
+}
+
+// NextTask – открытая задача в порядке выполнения. Level 0 – можно делать
+// сейчас, Level N – после выполнения блокирующих задач уровня N-1.
+type NextTask struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	DueDate   due.Date `json:"due_date"`
+	Level     int      `json:"level"`
+	BlockedBy []string `json:"blocked_by"`
+}
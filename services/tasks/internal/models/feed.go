@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Компоненты задач в календаре: todo – VTODO, event – VEVENT в срок задачи
+// (VTODO показывают не все приложения календаря)
+const (
+	FeedTodo  = "todo"
+	FeedEvent = "event"
+)
+
+// Как часто приложению календаря обновлять подписку
+const FeedRefresh = time.Hour
+
+// CalendarFeed – секретная ссылка на календарь задач пользователя. Token и
+// URL возвращаются только при создании ссылки: хранится лишь хеш токена.
+type CalendarFeed struct {
+	Enabled   bool       `json:"enabled"`
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// FeedOwner – владелец секретной ссылки и время ее создания
+type FeedOwner struct {
+	Subject   string
+	Tenant    string
+	CreatedAt time.Time
+}
+
+// FeedQuery – параметры календаря из ссылки: задачи проекта, с метками и
+// выполненные задачи (по умолчанию скрыты)
+type FeedQuery struct {
+	ProjectID    string
+	Tags         []string
+	MatchAllTags bool
+	IncludeDone  bool
+	Type         string
+}
+
+// Validate проверяет тип компонентов календаря
+func (q *FeedQuery) Validate() error {
+	switch q.Type {
+	case "":
+		q.Type = FeedTodo
+	case FeedTodo, FeedEvent:
+	default:
+		return &ValidationError{"type must be todo or event"}
+	}
+	return nil
+}
+
+// Feed – задачи календаря по ссылке; LastModified – время последнего
+// изменения задач, влияющих на календарь
+type Feed struct {
+	Subject      string
+	Records      []taskio.Record
+	LastModified time.Time
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"io"
+	"time"
+
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Ограничения импорта: файл не больше 5 МБ и 10000 задач
+const (
+	MaxImportBytes = 5 << 20
+	MaxImportRows  = 10000
+	// Импорт с большим числом задач по умолчанию выполняет worker
+	DefaultImportSyncRows = 200
+)
+
+// Состояния импорта
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// ImportRequest – файл импорта; DryRun только проверяет строки
+type ImportRequest struct {
+	Format string
+	DryRun bool
+	Body   io.Reader
+}
+
+// Validate проверяет формат файла
+func (r ImportRequest) Validate() error {
+	switch r.Format {
+	case taskio.FormatJSON, taskio.FormatCSV, taskio.FormatICal:
+		return nil
+	}
+	return &ValidationError{"format must be json, csv or ics"}
+}
+
+// ImportJob – импорт и отчет по нему. Выполненный сразу импорт не имеет
+// ID; импорт worker'ом сохраняется и доступен по ID до завершения и после.
+type ImportJob struct {
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Format string `json:"format"`
+	DryRun bool   `json:"dry_run"`
+	taskio.Report
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	Subject  string          `json:"-"`
+	Tenant   string          `json:"-"`
+	Timezone string          `json:"-"`
+	Pending  []taskio.Queued `json:"-"`
+}
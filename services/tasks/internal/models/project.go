@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrProjectNotFound – проект не существует или принадлежит другому пользователю
+var ErrProjectNotFound = errors.New("project not found")
+
+// ErrProjectArchived – в архивный проект нельзя добавлять задачи
+var ErrProjectArchived = errors.New("project is archived")
+
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Archived    bool      `json:"archived"`
+	Position    int       `json:"position"`           // порядок в списке проектов
+	Workflow    *Workflow `json:"workflow,omitempty"` // nil – процесс по умолчанию
+	TaskCount   int       `json:"task_count"`         // число активных задач
+	Subject     string    `json:"-"`
+	// Чужой проект, открытый пользователю: владелец и роль; пусто для своих
+	Owner     string    `json:"owner,omitempty"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateProjectRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Workflow    *Workflow `json:"workflow,omitempty"`
+}
+
+type ProjectUpdate struct {
+	Name        *string   `json:"name,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Archived    *bool     `json:"archived,omitempty"`
+	Position    *int      `json:"position,omitempty"`
+	Workflow    *Workflow `json:"workflow,omitempty"`
+}
+
+// Validate проверяет и очищает поля нового проекта
+func (p *CreateProjectRequest) Validate() error {
+	name, err := validateProjectName(p.Name)
+	if err != nil {
+		return err
+	}
+	p.Name = name
+	p.Description = sanitizeDescription(p.Description)
+	if p.Workflow != nil {
+		return p.Workflow.Validate()
+	}
+	return nil
+}
+
+// Validate проверяет и очищает изменяемые поля проекта
+func (p *ProjectUpdate) Validate() error {
+	if p.Name != nil {
+		name, err := validateProjectName(*p.Name)
+		if err != nil {
+			return err
+		}
+		p.Name = &name
+	}
+	if p.Description != nil {
+		description := sanitizeDescription(*p.Description)
+		p.Description = &description
+	}
+	if p.Position != nil && *p.Position < 0 {
+		return &ValidationError{"project position must not be negative"}
+	}
+	if p.Workflow != nil {
+		return p.Workflow.Validate()
+	}
+	return nil
+}
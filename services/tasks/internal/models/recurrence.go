@@ -0,0 +1,435 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// Частоты повторения RRULE (RFC 5545), которые поддерживает сервис
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// Область изменения повторяющейся задачи
+const (
+	ScopeThis      = "this"      // только это вхождение
+	ScopeFollowing = "following" // это и следующие вхождения серии
+)
+
+// ErrNotRecurring – задача не входит в действующую серию
+var ErrNotRecurring = errors.New("task is not recurring")
+
+// ErrSeriesFinished – по правилу серии больше нет вхождений
+var ErrSeriesFinished = errors.New("recurring series has no more occurrences")
+
+// Защита от правил без вхождений (например, 30 февраля)
+const maxRecurrencePeriods = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// TaskSeries – серия повторяющейся задачи. Открыто одно вхождение: следующее
+// создается, когда текущее выполнено. Template – поля новых вхождений.
+type TaskSeries struct {
+	ID        string
+	Subject   string
+	RRule     string
+	StartDate string // DTSTART, первое вхождение в формате YYYY-MM-DD
+	Template  TaskSnapshot
+	StoppedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Next возвращает срок вхождения серии после after. Время суток срока
+// сохраняется в часовом поясе loc.
+func (s TaskSeries) Next(after due.Date, loc *time.Location) (due.Date, error) {
+	rule, err := ParseRRule(s.RRule)
+	if err != nil {
+		return due.Date{}, err
+	}
+	start, err := ParseDueDate(s.StartDate)
+	if err != nil {
+		return due.Date{}, fmt.Errorf("invalid series start date: %w", err)
+	}
+	// Вхождение без срока продолжает серию от текущего дня
+	from := after.Date()
+	if after.IsZero() {
+		from = dateOf(time.Now().In(loc))
+	}
+
+	next, ok := rule.Next(start, from)
+	if !ok {
+		return due.Date{}, ErrSeriesFinished
+	}
+	return after.OnDay(next, loc), nil
+}
+
+// ByDay – день недели из BYDAY; N != 0 – N-й (с конца при N < 0) в месяце
+type ByDay struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// RRule – разобранное правило повторения. Задачи планируются по датам,
+// поэтому части правила меньше дня (BYHOUR и т.п.) не поддерживаются.
+type RRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time // включительно; нулевое – без ограничения
+	ByDay      []ByDay
+	ByMonthDay []int
+	ByMonth    []int
+}
+
+// ParseRRule разбирает правило вида FREQ=WEEKLY;BYDAY=MO,TH (префикс RRULE:
+// допускается). Ошибки возвращаются как ValidationError.
+func ParseRRule(value string) (RRule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	rule := RRule{Interval: 1}
+	if value == "" {
+		return RRule{}, &ValidationError{"recurrence rule is empty"}
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return RRule{}, &ValidationError{fmt.Sprintf("invalid recurrence rule part %q", part)}
+		}
+		if seen[name] {
+			return RRule{}, &ValidationError{fmt.Sprintf("duplicate recurrence rule part %s", name)}
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = val
+			if !slices.Contains([]string{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}, val) {
+				err = fmt.Errorf("unsupported FREQ %s (use DAILY, WEEKLY, MONTHLY or YEARLY)", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseRRuleInt(val, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseRRuleInt(val, 1, 10000)
+		case "UNTIL":
+			rule.Until, err = parseRRuleUntil(val)
+		case "WKST":
+			// Недели всегда начинаются с понедельника
+			if val != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		case "BYDAY":
+			rule.ByDay, err = parseRRuleByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseRRuleIntList(val, -31, 31)
+		case "BYMONTH":
+			rule.ByMonth, err = parseRRuleIntList(val, 1, 12)
+		default:
+			err = fmt.Errorf("unsupported recurrence rule part %s", name)
+		}
+		if err != nil {
+			return RRule{}, &ValidationError{err.Error()}
+		}
+	}
+
+	if rule.Freq == "" {
+		return RRule{}, &ValidationError{"recurrence rule requires FREQ"}
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return RRule{}, &ValidationError{"COUNT and UNTIL cannot be used together"}
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != FreqMonthly && rule.Freq != FreqYearly {
+			return RRule{}, &ValidationError{"numbered BYDAY is only allowed with MONTHLY or YEARLY"}
+		}
+	}
+	if rule.Freq == FreqYearly && len(rule.ByDay) > 0 && len(rule.ByMonth) == 0 {
+		return RRule{}, &ValidationError{"BYDAY with YEARLY requires BYMONTH"}
+	}
+	if rule.Freq == FreqWeekly && len(rule.ByMonthDay) > 0 {
+		return RRule{}, &ValidationError{"BYMONTHDAY is not allowed with WEEKLY"}
+	}
+
+	return rule, nil
+}
+
+// String возвращает правило в каноническом виде
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := ""
+			for c, wd := range weekdayCodes {
+				if wd == day.Weekday {
+					code = c
+				}
+			}
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает первое вхождение после after для серии, начатой в start
+// (DTSTART всегда первое вхождение). false – вхождения закончились по
+// COUNT или UNTIL либо правило не дает дат.
+func (r RRule) Next(start, after time.Time) (time.Time, bool) {
+	start = dateOf(start)
+	after = dateOf(after)
+
+	count := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, date := range r.expand(start, period) {
+			if date.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && date.After(r.Until) {
+				return time.Time{}, false
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return time.Time{}, false
+			}
+			if date.After(after) {
+				return date, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// expand возвращает отсортированные даты-кандидаты периода с номером period.
+// DTSTART добавляется в первый период, даже если не подходит под правило.
+func (r RRule) expand(start time.Time, period int) []time.Time {
+	var dates []time.Time
+	step := period * r.Interval
+
+	switch r.Freq {
+	case FreqDaily:
+		date := start.AddDate(0, 0, step)
+		if r.matchesMonth(date) && r.matchesMonthDay(date) && r.matchesWeekday(date) {
+			dates = append(dates, date)
+		}
+	case FreqWeekly:
+		// Неделя с понедельника, содержащая start
+		monday := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*step)
+		weekdays := []time.Weekday{start.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, day := range r.ByDay {
+				weekdays = append(weekdays, day.Weekday)
+			}
+		}
+		for i := 0; i < 7; i++ {
+			date := monday.AddDate(0, 0, i)
+			if slices.Contains(weekdays, date.Weekday()) && r.matchesMonth(date) {
+				dates = append(dates, date)
+			}
+		}
+	case FreqMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if r.matchesMonth(month) {
+			dates = r.expandMonth(month, start.Day())
+		}
+	case FreqYearly:
+		// Без BYMONTH: BYMONTHDAY – дни каждого месяца, иначе месяц DTSTART
+		months := r.ByMonth
+		switch {
+		case len(months) > 0:
+		case len(r.ByMonthDay) > 0:
+			months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		default:
+			months = []int{int(start.Month())}
+		}
+		for _, m := range months {
+			month := time.Date(start.Year()+step, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+			dates = append(dates, r.expandMonth(month, start.Day())...)
+		}
+	}
+
+	if period == 0 && !slices.ContainsFunc(dates, start.Equal) {
+		dates = append(dates, start)
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(dates, func(a, b time.Time) bool { return a.Equal(b) })
+}
+
+// expandMonth возвращает даты месяца по BYMONTHDAY и BYDAY; без них – день
+// defaultDay, если он есть в месяце
+func (r RRule) expandMonth(month time.Time, defaultDay int) []time.Time {
+	last := month.AddDate(0, 1, -1).Day()
+	var dates []time.Time
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay <= last {
+			dates = append(dates, month.AddDate(0, 0, defaultDay-1))
+		}
+		return dates
+	}
+
+	for day := 1; day <= last; day++ {
+		date := month.AddDate(0, 0, day-1)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(date) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesMonthWeekday(date, last) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+func (r RRule) matchesMonth(date time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, int(date.Month()))
+}
+
+func (r RRule) matchesMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := date.AddDate(0, 1, -date.Day()).Day()
+	for _, day := range r.ByMonthDay {
+		if day == date.Day() || (day < 0 && last+day+1 == date.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RRule) matchesWeekday(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.ByDay, func(day ByDay) bool { return day.Weekday == date.Weekday() })
+}
+
+// matchesMonthWeekday учитывает номер дня недели внутри месяца (1MO, -1FR)
+func (r RRule) matchesMonthWeekday(date time.Time, lastDay int) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday != date.Weekday() {
+			continue
+		}
+		switch {
+		case day.N == 0:
+			return true
+		case day.N > 0 && (date.Day()-1)/7+1 == day.N:
+			return true
+		case day.N < 0 && (lastDay-date.Day())/7+1 == -day.N:
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDueDate читает срок задачи: YYYY-MM-DD или дата, которую база
+// возвращает для колонки DATE (2026-03-02T00:00:00Z)
+func ParseDueDate(value string) (time.Time, error) {
+	if len(value) > len(time.DateOnly) && value[len(time.DateOnly)] == 'T' {
+		value = value[:len(time.DateOnly)]
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func parseRRuleInt(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max || n == 0 {
+		return 0, fmt.Errorf("invalid recurrence rule number %q", value)
+	}
+	return n, nil
+}
+
+func parseRRuleIntList(value string, min, max int) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := parseRRuleInt(item, min, max)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+func parseRRuleUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return dateOf(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q (use YYYYMMDD)", value)
+}
+
+func parseRRuleByDay(value string) ([]ByDay, error) {
+	var days []ByDay
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		day := ByDay{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := parseRRuleInt(prefix, -5, 5)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYDAY %q", item)
+			}
+			day.N = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func joinInts(values []int) string {
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		items = append(items, strconv.Itoa(v))
+	}
+	return strings.Join(items, ",")
+}
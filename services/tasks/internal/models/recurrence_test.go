@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+func TestRRuleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		want  []string // следующие вхождения после start; после них серия закончена
+		more  bool     // серия продолжается после want
+	}{
+		{"daily count includes start", "FREQ=DAILY;COUNT=3", "2026-01-30", []string{"2026-01-31", "2026-02-01"}, false},
+		{"weekly until inclusive", "FREQ=WEEKLY;UNTIL=20260120", "2026-01-06", []string{"2026-01-13", "2026-01-20"}, false},
+		{"weekly byday count", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3", "2026-01-05", []string{"2026-01-07", "2026-01-12"}, false},
+		{"biweekly byday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2026-01-05", []string{"2026-01-09", "2026-01-19", "2026-01-23"}, true},
+		{"second tuesday", "FREQ=MONTHLY;BYDAY=2TU", "2026-01-13", []string{"2026-02-10", "2026-03-10"}, true},
+		{"last friday", "FREQ=MONTHLY;BYDAY=-1FR", "2026-01-30", []string{"2026-02-27", "2026-03-27"}, true},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1", "2026-01-31", []string{"2026-02-28", "2026-03-31", "2026-04-30"}, true},
+		{"31st skips short months", "FREQ=MONTHLY", "2026-01-31", []string{"2026-03-31", "2026-05-31"}, true},
+		{"leap day", "FREQ=YEARLY", "2024-02-29", []string{"2028-02-29"}, true},
+		{"yearly bymonthday every month", "FREQ=YEARLY;BYMONTHDAY=15", "2026-01-15", []string{"2026-02-15", "2026-03-15"}, true},
+		{"yearly bymonth numbered byday", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "2026-11-26", []string{"2027-11-25"}, true},
+		{"yearly until", "FREQ=YEARLY;BYMONTH=1,7;UNTIL=20270101", "2026-01-10", []string{"2026-07-10"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.rule, err)
+			}
+			start, _ := time.Parse(time.DateOnly, tt.start)
+
+			after := start
+			for _, want := range tt.want {
+				next, ok := rule.Next(start, after)
+				if !ok || next.Format(time.DateOnly) != want {
+					t.Fatalf("Expected %s after %s, got %s (%v)", want, after.Format(time.DateOnly), next.Format(time.DateOnly), ok)
+				}
+				after = next
+			}
+			if _, ok := rule.Next(start, after); ok != tt.more {
+				t.Errorf("Expected more occurrences after %s: %v, got %v", after.Format(time.DateOnly), tt.more, ok)
+			}
+		})
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=WEEKLY;WKST=SU",
+	} {
+		var validationErr *ValidationError
+		if _, err := ParseRRule(value); !errors.As(err, &validationErr) {
+			t.Errorf("Expected validation error for %q, got %v", value, err)
+		}
+	}
+
+	rule, err := ParseRRule("rrule:freq=monthly;byday=-1fr;interval=2")
+	if err != nil || rule.String() != "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR" {
+		t.Errorf("Unexpected canonical rule %q, %v", rule.String(), err)
+	}
+}
+
+func TestTaskSeriesNextAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Skipping DST test - no time zone data: %v", err)
+	}
+
+	// Время суток срока сохраняется в часовом поясе пользователя при
+	// переходе на летнее и зимнее время
+	for _, tt := range []struct {
+		start, want string
+	}{
+		{"2026-03-28T09:00:00+01:00", "2026-03-29T09:00:00+02:00"},
+		{"2026-10-24T09:00:00+02:00", "2026-10-25T09:00:00+01:00"},
+	} {
+		at, _ := time.Parse(time.RFC3339, tt.start)
+		series := TaskSeries{RRule: "FREQ=DAILY", StartDate: at.Format(time.DateOnly)}
+		next, err := series.Next(due.At(at, berlin), berlin)
+		if err != nil {
+			t.Fatalf("Failed to get next occurrence: %v", err)
+		}
+		if got := next.Deadline(berlin).In(berlin).Format(time.RFC3339); got != tt.want {
+			t.Errorf("Expected next occurrence at %s, got %s", tt.want, got)
+		}
+	}
+
+	// Срок без времени остается сроком на весь день
+	series := TaskSeries{RRule: "FREQ=DAILY", StartDate: "2026-03-28"}
+	next, err := series.Next(due.Day(2026, 3, 28), berlin)
+	if err != nil || next.HasTime() || next.String() != "2026-03-29" {
+		t.Errorf("Unexpected all-day occurrence %s, %v", next.String(), err)
+	}
+}
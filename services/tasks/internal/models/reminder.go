@@ -0,0 +1,90 @@
+package models
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// Каналы доставки напоминаний worker'ом
+const (
+	ReminderChannelLog     = "log"
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelEmail   = "email"
+)
+
+// Напоминание не раньше чем за год до срока
+const MaxReminderMinutesBefore = 365 * 24 * 60
+
+// Reminder – правило напоминания о сроке задачи. FireAt – момент отправки
+// (срок минус MinutesBefore), nil – у задачи нет срока.
+type Reminder struct {
+	ID            string     `json:"id"`
+	TaskID        string     `json:"task_id"`
+	MinutesBefore int        `json:"minutes_before"`
+	Channel       string     `json:"channel"`
+	Target        string     `json:"target,omitempty"`
+	FireAt        *time.Time `json:"fire_at"`
+	FiredAt       *time.Time `json:"fired_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CreateReminderRequest – {"minutes_before": 1440, "channel": "webhook", "target": "https://..."}.
+// Target – URL для webhook, адрес для email; для webhook без target worker
+// использует адрес по умолчанию.
+type CreateReminderRequest struct {
+	MinutesBefore int    `json:"minutes_before"`
+	Channel       string `json:"channel"`
+	Target        string `json:"target"`
+}
+
+// Validate проверяет канал и адрес доставки напоминания
+func (r *CreateReminderRequest) Validate() error {
+	if r.MinutesBefore < 0 || r.MinutesBefore > MaxReminderMinutesBefore {
+		return &ValidationError{"minutes_before must be between 0 and 525600"}
+	}
+
+	r.Channel = strings.ToLower(strings.TrimSpace(r.Channel))
+	if r.Channel == "" {
+		r.Channel = ReminderChannelLog
+	}
+	r.Target = strings.TrimSpace(r.Target)
+	if len(r.Target) > 255 {
+		return &ValidationError{"reminder target too long (max 255 characters)"}
+	}
+
+	switch r.Channel {
+	case ReminderChannelLog:
+		r.Target = ""
+	case ReminderChannelWebhook:
+		if r.Target == "" {
+			return nil
+		}
+		u, err := url.Parse(r.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ValidationError{"webhook target must be an http(s) URL"}
+		}
+	case ReminderChannelEmail:
+		address, err := mail.ParseAddress(r.Target)
+		if err != nil {
+			return &ValidationError{"email target must be a valid email address"}
+		}
+		r.Target = address.Address
+	default:
+		return &ValidationError{"channel must be one of log, webhook, email"}
+	}
+	return nil
+}
+
+// ReminderFireAt возвращает момент напоминания: начало срока (для срока на
+// весь день – начало дня в loc) минус minutesBefore; nil – срок не задан
+func ReminderFireAt(dueDate due.Date, loc *time.Location, minutesBefore int) *time.Time {
+	if dueDate.IsZero() {
+		return nil
+	}
+	fireAt := dueDate.Start(loc).Add(-time.Duration(minutesBefore) * time.Minute)
+	return &fireAt
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// Действия, после которых сохраняется ревизия задачи
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionDeleted  = "deleted"
+	ActionRestored = "restored"
+	ActionReverted = "reverted"
+)
+
+// ErrRevisionNotFound – у задачи нет ревизии с таким номером
+var ErrRevisionNotFound = errors.New("task revision not found")
+
+// TaskRevision – запись истории: кто, когда и какие поля изменил.
+// Номер ревизии совпадает с версией задачи после изменения.
+type TaskRevision struct {
+	TaskID    string        `json:"task_id"`
+	Revision  int64         `json:"revision"`
+	Subject   string        `json:"-"`
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+	Changes   []FieldChange `json:"changes"`
+	Snapshot  TaskSnapshot  `json:"snapshot"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange – изменение одного поля; Old == nil при создании задачи
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskSnapshot – состояние редактируемых полей задачи после изменения,
+// к которому можно откатиться
+type TaskSnapshot struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	DueDate         due.Date `json:"due_date"`
+	Done            bool     `json:"done"`
+	Status          string   `json:"status,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	EstimatePoints  int      `json:"estimate_points,omitempty"`
+	EstimateMinutes int      `json:"estimate_minutes,omitempty"`
+	ProjectID       string   `json:"project_id,omitempty"`
+	ParentID        string   `json:"parent_id,omitempty"`
+	AutoComplete    bool     `json:"auto_complete,omitempty"`
+	// Исполнитель попадает в историю, но откатом не меняется
+	Assignee string `json:"assignee,omitempty"`
+}
+
+func SnapshotOf(task Task) TaskSnapshot {
+	return TaskSnapshot{
+		Title:           task.Title,
+		Description:     task.Description,
+		DueDate:         task.DueDate,
+		Done:            task.Done,
+		Status:          task.Status,
+		Priority:        task.Priority,
+		EstimatePoints:  task.EstimatePoints,
+		EstimateMinutes: task.EstimateMinutes,
+		ProjectID:       task.ProjectID,
+		ParentID:        task.ParentID,
+		AutoComplete:    task.AutoComplete,
+		Assignee:        task.Assignee,
+	}
+}
+
+// DiffTasks возвращает изменившиеся поля между двумя состояниями задачи
+func DiffTasks(before, after TaskSnapshot) []FieldChange {
+	changes := []FieldChange{}
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", Old: before.Title, New: after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, FieldChange{Field: "description", Old: before.Description, New: after.Description})
+	}
+	if !before.DueDate.Equal(after.DueDate) {
+		changes = append(changes, FieldChange{Field: "due_date", Old: before.DueDate, New: after.DueDate})
+	}
+	if before.Done != after.Done {
+		changes = append(changes, FieldChange{Field: "done", Old: before.Done, New: after.Done})
+	}
+	if before.Status != after.Status {
+		changes = append(changes, FieldChange{Field: "status", Old: before.Status, New: after.Status})
+	}
+	if before.Priority != after.Priority {
+		changes = append(changes, FieldChange{Field: "priority", Old: before.Priority, New: after.Priority})
+	}
+	if before.EstimatePoints != after.EstimatePoints {
+		changes = append(changes, FieldChange{Field: "estimate_points", Old: before.EstimatePoints, New: after.EstimatePoints})
+	}
+	if before.EstimateMinutes != after.EstimateMinutes {
+		changes = append(changes, FieldChange{Field: "estimate_minutes", Old: before.EstimateMinutes, New: after.EstimateMinutes})
+	}
+	if before.ProjectID != after.ProjectID {
+		changes = append(changes, FieldChange{Field: "project_id", Old: before.ProjectID, New: after.ProjectID})
+	}
+	if before.ParentID != after.ParentID {
+		changes = append(changes, FieldChange{Field: "parent_id", Old: before.ParentID, New: after.ParentID})
+	}
+	if before.AutoComplete != after.AutoComplete {
+		changes = append(changes, FieldChange{Field: "auto_complete", Old: before.AutoComplete, New: after.AutoComplete})
+	}
+	if before.Assignee != after.Assignee {
+		changes = append(changes, FieldChange{Field: "assignee", Old: before.Assignee, New: after.Assignee})
+	}
+	return changes
+}
+
+// CreationChanges описывает создание задачи как изменения с пустыми старыми значениями
+func CreationChanges(snapshot TaskSnapshot) []FieldChange {
+	changes := []FieldChange{
+		{Field: "title", New: snapshot.Title},
+		{Field: "description", New: snapshot.Description},
+		{Field: "due_date", New: snapshot.DueDate},
+		{Field: "done", New: snapshot.Done},
+		{Field: "status", New: snapshot.Status},
+	}
+	if snapshot.Priority != "" {
+		changes = append(changes, FieldChange{Field: "priority", New: snapshot.Priority})
+	}
+	if snapshot.EstimatePoints != 0 {
+		changes = append(changes, FieldChange{Field: "estimate_points", New: snapshot.EstimatePoints})
+	}
+	if snapshot.EstimateMinutes != 0 {
+		changes = append(changes, FieldChange{Field: "estimate_minutes", New: snapshot.EstimateMinutes})
+	}
+	if snapshot.ProjectID != "" {
+		changes = append(changes, FieldChange{Field: "project_id", New: snapshot.ProjectID})
+	}
+	if snapshot.ParentID != "" {
+		changes = append(changes, FieldChange{Field: "parent_id", New: snapshot.ParentID})
+	}
+	if snapshot.AutoComplete {
+		changes = append(changes, FieldChange{Field: "auto_complete", New: true})
+	}
+	if snapshot.Assignee != "" {
+		changes = append(changes, FieldChange{Field: "assignee", New: snapshot.Assignee})
+	}
+	return changes
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	// База часовых поясов в бинарнике: в образе может не быть zoneinfo
+	_ "time/tzdata"
+)
+
+// Часовой пояс пользователя, пока он не задан
+const DefaultTimezone = "UTC"
+
+// UserSettings – настройки пользователя. Timezone (IANA, например
+// Europe/Moscow) задает сроки без смещения и границы дня для overdue/due_today.
+type UserSettings struct {
+	Timezone  string     `json:"timezone"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Location возвращает часовой пояс пользователя
+func (s UserSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil || s.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// UpdateSettingsRequest – {"timezone": "Europe/Moscow"}
+type UpdateSettingsRequest struct {
+	Timezone *string `json:"timezone"`
+}
+
+// Validate проверяет, что часовой пояс есть в базе IANA
+func (r *UpdateSettingsRequest) Validate() error {
+	if r.Timezone == nil {
+		return nil
+	}
+	timezone := strings.TrimSpace(*r.Timezone)
+	if timezone == "" || timezone == "Local" {
+		return &ValidationError{"timezone must be an IANA time zone name"}
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return &ValidationError{"unknown timezone " + timezone}
+	}
+	r.Timezone = &timezone
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Роли доступа к задаче или проекту; owner – владелец, не выдается
+const (
+	RoleViewer = "viewer" // чтение задачи, ее истории, подзадач и напоминаний
+	RoleEditor = "editor" // также изменение полей, меток, чек-листа, напоминаний и серии
+	RoleOwner  = "owner"
+)
+
+// Что можно открыть для другого пользователя
+const (
+	ShareTask    = "task"
+	ShareProject = "project" // доступ ко всем задачам проекта
+)
+
+// ErrAccessDenied – у пользователя есть доступ к задаче, но роль недостаточна
+var ErrAccessDenied = errors.New("insufficient access")
+
+var roleRank = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// Share – доступ пользователя Subject к задаче или проекту владельца Owner
+type Share struct {
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	Owner        string    `json:"owner"`
+	Subject      string    `json:"subject"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ShareRequest – {"subject": "teacher", "role": "editor"}
+type ShareRequest struct {
+	Subject string `json:"subject"`
+	Role    string `json:"role"`
+}
+
+// Validate проверяет получателя и роль; owner – владелец ресурса
+func (r *ShareRequest) Validate(owner string) error {
+	r.Subject = strings.TrimSpace(r.Subject)
+	r.Role = strings.ToLower(strings.TrimSpace(r.Role))
+	if r.Role == "" {
+		r.Role = RoleViewer
+	}
+
+	if r.Subject == "" {
+		return &ValidationError{"subject is required"}
+	}
+	if len(r.Subject) > 100 {
+		return &ValidationError{"subject too long (max 100 characters)"}
+	}
+	if r.Subject == owner {
+		return &ValidationError{"cannot share with yourself"}
+	}
+	if r.Role != RoleViewer && r.Role != RoleEditor {
+		return &ValidationError{"role must be viewer or editor"}
+	}
+	return nil
+}
+
+// Access – доступ пользователя к задаче или проекту. Пустой Owner – доступа нет.
+type Access struct {
+	Owner string
+	Role  string
+}
+
+// Allows сообщает, достаточно ли доступа для роли role
+func (a Access) Allows(role string) bool {
+	return a.Owner != "" && roleRank[a.Role] >= roleRank[role]
+}
+
+// MaxRole возвращает большую из ролей
+func MaxRole(a, b string) string {
+	if roleRank[b] > roleRank[a] {
+		return b
+	}
+	return a
+}
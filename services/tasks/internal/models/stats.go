@@ -0,0 +1,120 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Период статистики по умолчанию и наибольший период, дней
+const (
+	DefaultStatsDays = 30
+	MaxStatsDays     = 366
+)
+
+// StatsQuery – период статистики ?from=2026-03-01&to=2026-03-31 (даты
+// включительно, в часовом поясе пользователя). Без from – 30 дней до to,
+// без to – по сегодняшний день.
+type StatsQuery struct {
+	From string
+	To   string
+}
+
+// StatsRange – период статистики: дни [From, To] в часовом поясе пользователя
+type StatsRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Resolve проверяет период и подставляет значения по умолчанию от now
+func (q StatsQuery) Resolve(now time.Time, loc *time.Location) (StatsRange, error) {
+	now = now.In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if q.To != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, q.To, loc)
+		if err != nil {
+			return StatsRange{}, &ValidationError{"from and to must be dates in YYYY-MM-DD format"}
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, 1-DefaultStatsDays)
+	if q.From != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, q.From, loc)
+		if err != nil {
+			return StatsRange{}, &ValidationError{"from and to must be dates in YYYY-MM-DD format"}
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return StatsRange{}, &ValidationError{"from must not be after to"}
+	}
+	if !to.Before(from.AddDate(0, 0, MaxStatsDays)) {
+		return StatsRange{}, &ValidationError{"stats period too long (max 366 days)"}
+	}
+	return StatsRange{From: from, To: to}, nil
+}
+
+// Days возвращает начала дней периода и начало дня после него; границы
+// считаются по календарю, поэтому переход на летнее время не сдвигает дни
+func (r StatsRange) Days() []time.Time {
+	var days []time.Time
+	for day := r.From; !day.After(r.To); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return append(days, r.To.AddDate(0, 0, 1))
+}
+
+// TaskTimeline – число задач, созданных и выполненных до каждой из границ
+type TaskTimeline struct {
+	CreatedBefore   []int
+	CompletedBefore []int
+}
+
+// Open – число невыполненных задач на момент границы i
+func (t TaskTimeline) Open(i int) int {
+	return t.CreatedBefore[i] - t.CompletedBefore[i]
+}
+
+// TaskStats – статистика задач пользователя; задачи в корзине не учитываются
+type TaskStats struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Timezone string `json:"timezone"`
+	// Текущее состояние: задачи по статусам и просроченные по приоритетам
+	// ("none" – без приоритета)
+	Total             int            `json:"total"`
+	ByStatus          map[string]int `json:"by_status"`
+	Overdue           int            `json:"overdue"`
+	OverdueByPriority map[string]int `json:"overdue_by_priority"`
+	// Выполненные за период и среднее время от создания до выполнения
+	Completed            int             `json:"completed"`
+	AvgCompletionSeconds int64           `json:"avg_completion_seconds"`
+	Weeks                []WeekStats     `json:"weeks"`
+	Burndown             []BurndownPoint `json:"burndown"`
+}
+
+// WeekStats – неделя периода (с понедельника; первая и последняя недели
+// обрезаются по периоду). CompletionRate – доля выполненных из открытых в
+// начале недели и созданных за неделю.
+type WeekStats struct {
+	WeekStart      string  `json:"week_start"`
+	Created        int     `json:"created"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// BurndownPoint – невыполненные задачи на конец дня
+type BurndownPoint struct {
+	Date string `json:"date"`
+	Open int    `json:"open"`
+}
+
+// CompletionRate возвращает долю выполненных задач, округленную до тысячных
+func CompletionRate(completed, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	rate := float64(completed) / float64(total)
+	return math.Min(1, math.Round(rate*1000)/1000)
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// MaxTaskDepth – максимальная глубина иерархии: задача, подзадача и
+// подзадача подзадачи
+const MaxTaskDepth = 3
+
+// ErrParentNotFound – родительская задача не существует или в корзине
+var ErrParentNotFound = errors.New("parent task not found")
+
+// ErrTaskCycle – задача не может стать подзадачей самой себя или своего потомка
+var ErrTaskCycle = errors.New("task hierarchy cycle")
+
+// ErrTaskTooDeep – перенос превысил бы MaxTaskDepth
+var ErrTaskTooDeep = errors.New("task hierarchy too deep")
+
+// TaskProgress – выполнение задачи по прямым подзадачам и пунктам чек-листа
+type TaskProgress struct {
+	Done    int `json:"done"`
+	Total   int `json:"total"`
+	Percent int `json:"percent"`
+}
+
+// NewTaskProgress возвращает nil, если у задачи нет ни подзадач, ни пунктов
+func NewTaskProgress(done, total int) *TaskProgress {
+	if total == 0 {
+		return nil
+	}
+	return &TaskProgress{Done: done, Total: total, Percent: done * 100 / total}
+}
+
+// Complete – все подзадачи и пункты чек-листа выполнены
+func (p *TaskProgress) Complete() bool {
+	return p != nil && p.Done == p.Total
+}
+
+type ChecklistItem struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateChecklistItemRequest struct {
+	Title string `json:"title"`
+}
+
+type ChecklistItemUpdate struct {
+	Title    *string `json:"title,omitempty"`
+	Done     *bool   `json:"done,omitempty"`
+	Position *int    `json:"position,omitempty"`
+}
+
+func validateChecklistTitle(title string) (string, error) {
+	title = strings.TrimSpace(sanitize.SanitizeText(title))
+	if title == "" {
+		return "", &ValidationError{"checklist item title is required"}
+	}
+	if len(title) > 255 {
+		return "", &ValidationError{"checklist item title too long (max 255 characters)"}
+	}
+	return title, nil
+}
+
+// Validate проверяет и очищает название пункта
+func (c *CreateChecklistItemRequest) Validate() error {
+	title, err := validateChecklistTitle(c.Title)
+	if err != nil {
+		return err
+	}
+	c.Title = title
+	return nil
+}
+
+// Validate проверяет и очищает изменяемые поля пункта
+func (c *ChecklistItemUpdate) Validate() error {
+	if c.Title != nil {
+		title, err := validateChecklistTitle(*c.Title)
+		if err != nil {
+			return err
+		}
+		c.Title = &title
+	}
+	if c.Position != nil && *c.Position < 0 {
+		return &ValidationError{"checklist item position must not be negative"}
+	}
+	return nil
+}
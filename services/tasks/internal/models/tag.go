@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// Цвет метки по умолчанию
+const DefaultTagColor = "#808080"
+
+// ErrTagExists – у пользователя уже есть метка с таким именем
+var ErrTagExists = errors.New("tag already exists")
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	TaskCount int       `json:"task_count"` // число активных задач с меткой
+	Subject   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateTagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TagUpdate struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+type TaskTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// TaskFilter – условия выборки списка задач
+type TaskFilter struct {
+	Tags []string
+	// true – задача должна иметь все метки (AND), false – любую из них (OR)
+	MatchAllTags bool
+	// Только задачи проекта (в том числе архивного). Без проекта задачи
+	// архивных проектов в выборку не попадают.
+	ProjectID string
+	// Только прямые подзадачи задачи
+	ParentID string
+	Status   string
+	// Сначала срочные задачи, задачи без приоритета – в конце
+	SortByPriority bool
+	// Только просроченные (overdue) или со сроком сегодня (today); сегодняшний
+	// день определяется в часовом поясе пользователя
+	Due string
+	// Только задачи исполнителя
+	Assignee string
+}
+
+// Значения фильтра по сроку
+const (
+	DueFilterOverdue = "overdue"
+	DueFilterToday   = "today"
+)
+
+// IsEmpty – фильтр соответствует списку по умолчанию
+func (f TaskFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && f.ProjectID == "" && f.ParentID == "" && f.Status == "" && !f.SortByPriority && f.Due == "" && f.Assignee == ""
+}
+
+// NormalizeTagName приводит имя метки к каноническому виду: метки
+// сравниваются без учета регистра и пробелов по краям
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(sanitize.SanitizeText(name)))
+	if name == "" {
+		return "", &ValidationError{"tag name is required"}
+	}
+	if len([]rune(name)) > 50 {
+		return "", &ValidationError{"tag name too long (max 50 characters)"}
+	}
+	return name, nil
+}
+
+// NormalizeTagColor проверяет цвет в формате #RRGGBB
+func NormalizeTagColor(color string) (string, error) {
+	if color == "" {
+		return DefaultTagColor, nil
+	}
+	if !tagColorPattern.MatchString(color) {
+		return "", &ValidationError{"tag color must be in #RRGGBB format"}
+	}
+	return strings.ToLower(color), nil
+}
@@ -1,44 +1,130 @@
 package models
 
 import (
+	"errors"
+	"strings"
+	"tech-ip-sem2/shared/due"
 	"tech-ip-sem2/shared/sanitize"
 	"time"
 )
 
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	DueDate     string    `json:"due_date"`
-	Done        bool      `json:"done"`
-	Subject     string    `json:"-"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Срок: дата или момент (RFC 3339); null – срока нет
+	DueDate due.Date `json:"due_date"`
+	// Вычисляются в часовом поясе пользователя при выдаче задачи
+	Overdue  bool `json:"overdue"`
+	DueToday bool `json:"due_today"`
+	Done     bool `json:"done"`
+	// Статус по процессу проекта; done == true для статусов выполнения
+	Status string `json:"status"`
+	// Приоритет (low, medium, high, urgent; "" – не задан) и оценки; 0 – не задана
+	Priority        string `json:"priority,omitempty"`
+	EstimatePoints  int    `json:"estimate_points,omitempty"`
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+	ProjectID       string `json:"project_id,omitempty"`
+	ParentID        string `json:"parent_id,omitempty"`
+	// Серия повторяющейся задачи и ее правило RRULE (пустое, если серия остановлена)
+	SeriesID   string `json:"series_id,omitempty"`
+	Recurrence string `json:"recurrence,omitempty"`
+	// Выполнить задачу, когда выполнены все подзадачи и пункты чек-листа
+	AutoComplete bool `json:"auto_complete"`
+	// Исполнитель: владелец или пользователь с доступом к задаче; "" – не назначен
+	Assignee string `json:"assignee,omitempty"`
+	// Число комментариев вместе с ответами
+	CommentCount int             `json:"comment_count"`
+	Progress     *TaskProgress   `json:"progress,omitempty"`
+	Checklist    []ChecklistItem `json:"checklist,omitempty"`
+	// Открытые и выполненные задачи, блокирующие эту, и задачи, которые блокирует она
+	BlockedBy []string `json:"blocked_by"`
+	Blocks    []string `json:"blocks"`
+	Blocked   bool     `json:"blocked"` // есть невыполненная блокирующая задача
+	Subject   string   `json:"-"`
+	// Чужая задача, открытая пользователю: владелец и роль; пусто для своих
+	Owner     string     `json:"owner,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	Version   int64      `json:"version"`
+	Tags      []string   `json:"tags"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // nil, если задача не в корзине
 }
 
 type TaskUpdate struct {
 	Title       *string `json:"title,omitempty"`
 	Description *string `json:"description,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
-	Done        *bool   `json:"done,omitempty"`
+	// "" снимает срок; время без смещения – в часовом поясе пользователя
+	DueDate *due.Date `json:"due_date,omitempty"`
+	Done    *bool     `json:"done,omitempty"`
+	// Статус имеет приоритет над done; done: true/false переводит задачу в
+	// статус выполнения или начальный статус процесса
+	Status *string `json:"status,omitempty"`
+	// "" снимает приоритет, 0 – оценку
+	Priority        *string `json:"priority,omitempty"`
+	EstimatePoints  *int    `json:"estimate_points,omitempty"`
+	EstimateMinutes *int    `json:"estimate_minutes,omitempty"`
+	// Перенос в другой проект; "" – убрать задачу из проекта
+	ProjectID *string `json:"project_id,omitempty"`
+	// Перенос под другую задачу; "" – сделать задачу корневой
+	ParentID     *string `json:"parent_id,omitempty"`
+	AutoComplete *bool   `json:"auto_complete,omitempty"`
+	// Правило RRULE: задает серию для задачи без серии, "" останавливает серию
+	Recurrence *string `json:"recurrence,omitempty"`
+	// Серия, к которой привязывается задача; задается сервисом
+	SeriesID *string `json:"-"`
+	// Исполнитель; меняется только через назначение, "" – снять
+	Assignee *string `json:"-"`
+	// Ожидаемая текущая версия задачи (из If-Match); nil – без проверки
+	Version *int64 `json:"version,omitempty"`
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	DueDate     string `json:"due_date"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	DueDate         due.Date `json:"due_date"`
+	ProjectID       string   `json:"project_id"`
+	Status          string   `json:"status"`
+	Priority        string   `json:"priority"`
+	EstimatePoints  int      `json:"estimate_points"`
+	EstimateMinutes int      `json:"estimate_minutes"`
+	ParentID        string   `json:"parent_id"`
+	AutoComplete    bool     `json:"auto_complete"`
+	Recurrence      string   `json:"recurrence"`
 }
 
 type SearchTaskRequest struct {
 	Term string `json:"term"`
 }
 
+// SetDueFlags вычисляет overdue и due_today на момент now в часовом поясе loc
+func (t *Task) SetDueFlags(now time.Time, loc *time.Location) {
+	t.Overdue = !t.Done && t.DueDate.Overdue(now, loc)
+	t.DueToday = t.DueDate.DueToday(now, loc)
+}
+
 // Sanitize очищает поля задачи от потенциально опасного содержимого
 func (t *Task) Sanitize() {
 	t.Title = sanitize.SanitizeText(t.Title)
 	t.Description = sanitize.SanitizeHTML(t.Description)
 }
 
+func validateProjectName(name string) (string, error) {
+	name = strings.TrimSpace(sanitize.SanitizeText(name))
+	if name == "" {
+		return "", &ValidationError{"project name is required"}
+	}
+	if len(name) > 255 {
+		return "", &ValidationError{"project name too long (max 255 characters)"}
+	}
+	return name, nil
+}
+
+func sanitizeDescription(description string) string {
+	return sanitize.SanitizeHTML(description)
+}
+
 // Validate проверяет корректность задачи
 func (t *CreateTaskRequest) Validate() error {
 	if t.Title == "" {
@@ -53,6 +139,16 @@ func (t *CreateTaskRequest) Validate() error {
 		return &ValidationError{"description too long (max 1000 characters)"}
 	}
 
+	priority, err := NormalizePriority(t.Priority)
+	if err != nil {
+		return err
+	}
+	t.Priority = priority
+
+	if err := ValidateEstimates(t.EstimatePoints, t.EstimateMinutes); err != nil {
+		return err
+	}
+
 	// Очистка полей
 	t.Title = sanitize.SanitizeText(t.Title)
 	t.Description = sanitize.SanitizeHTML(t.Description)
@@ -60,6 +156,9 @@ func (t *CreateTaskRequest) Validate() error {
 	return nil
 }
 
+// ErrVersionConflict – задача была изменена другим запросом
+var ErrVersionConflict = errors.New("task version conflict")
+
 type ValidationError struct {
 	Message string
 }
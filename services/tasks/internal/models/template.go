@@ -0,0 +1,251 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// Ограничения шаблона: задач в дереве (с корнем) и пунктов чек-листа задачи
+const (
+	MaxTemplateTasks     = 100
+	MaxTemplateChecklist = 50
+)
+
+// Подстановка {{имя}} в названии и описании задач шаблона; {{date}} –
+// дата начала, от которой отсчитываются сроки
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TemplateDateVariable задается при создании задач из шаблона
+const TemplateDateVariable = "date"
+
+// TaskTemplate – шаблон дерева задач (например, чек-лист онбординга)
+type TaskTemplate struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Task        TemplateTask `json:"task"`
+	// Переменные из названий и описаний задач, кроме date
+	Variables []string  `json:"variables"`
+	Subject   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TemplateTask – задача шаблона. Срок задается смещением в днях от даты
+// начала и временем суток в часовом поясе пользователя.
+type TemplateTask struct {
+	Title           string         `json:"title"`
+	Description     string         `json:"description,omitempty"`
+	Priority        string         `json:"priority,omitempty"`
+	EstimatePoints  int            `json:"estimate_points,omitempty"`
+	EstimateMinutes int            `json:"estimate_minutes,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	DueInDays       *int           `json:"due_in_days,omitempty"`
+	DueTime         string         `json:"due_time,omitempty"` // HH:MM
+	Checklist       []string       `json:"checklist,omitempty"`
+	Subtasks        []TemplateTask `json:"subtasks,omitempty"`
+}
+
+type CreateTemplateRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Task        TemplateTask `json:"task"`
+}
+
+// TemplateUpdate – изменяемые поля шаблона; task заменяет дерево целиком
+type TemplateUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Task        *TemplateTask `json:"task,omitempty"`
+}
+
+// InstantiateTemplateRequest – {"start_date": "2026-03-10", "variables": {"name": "Иван"}}.
+// Без start_date сроки отсчитываются от текущего дня пользователя.
+type InstantiateTemplateRequest struct {
+	StartDate string            `json:"start_date,omitempty"`
+	ProjectID string            `json:"project_id,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// TemplateInstance – задачи, созданные из шаблона: корень и все подзадачи
+// в порядке обхода дерева
+type TemplateInstance struct {
+	Task     Task   `json:"task"`
+	Subtasks []Task `json:"subtasks"`
+}
+
+func validateTemplateName(name string) (string, error) {
+	name = strings.TrimSpace(sanitize.SanitizeText(name))
+	if name == "" {
+		return "", &ValidationError{"template name is required"}
+	}
+	if len(name) > 255 {
+		return "", &ValidationError{"template name too long (max 255 characters)"}
+	}
+	return name, nil
+}
+
+// Validate проверяет и очищает поля нового шаблона
+func (r *CreateTemplateRequest) Validate() error {
+	name, err := validateTemplateName(r.Name)
+	if err != nil {
+		return err
+	}
+	r.Name = name
+	r.Description = sanitizeDescription(r.Description)
+	return r.Task.Validate()
+}
+
+// Validate проверяет и очищает изменяемые поля шаблона
+func (u *TemplateUpdate) Validate() error {
+	if u.Name != nil {
+		name, err := validateTemplateName(*u.Name)
+		if err != nil {
+			return err
+		}
+		u.Name = &name
+	}
+	if u.Description != nil {
+		description := sanitizeDescription(*u.Description)
+		u.Description = &description
+	}
+	if u.Task != nil {
+		return u.Task.Validate()
+	}
+	return nil
+}
+
+// Validate проверяет дерево задач шаблона: глубину, число задач и поля
+func (t *TemplateTask) Validate() error {
+	count := 0
+	return t.validate(1, &count)
+}
+
+func (t *TemplateTask) validate(depth int, count *int) error {
+	*count++
+	if *count > MaxTemplateTasks {
+		return &ValidationError{fmt.Sprintf("template has too many tasks (max %d)", MaxTemplateTasks)}
+	}
+	if depth > MaxTaskDepth {
+		return &ValidationError{fmt.Sprintf("template hierarchy too deep (max %d levels)", MaxTaskDepth)}
+	}
+
+	task := Task{Title: t.Title, Description: t.Description}
+	task.Sanitize()
+	t.Title = strings.TrimSpace(task.Title)
+	t.Description = task.Description
+	if t.Title == "" {
+		return &ValidationError{"template task title is required"}
+	}
+	if len(t.Title) > 255 {
+		return &ValidationError{"template task title too long (max 255 characters)"}
+	}
+
+	priority, err := NormalizePriority(t.Priority)
+	if err != nil {
+		return err
+	}
+	t.Priority = priority
+	if err := ValidateEstimates(t.EstimatePoints, t.EstimateMinutes); err != nil {
+		return err
+	}
+
+	tags := make([]string, 0, len(t.Tags))
+	for _, name := range t.Tags {
+		tag, err := NormalizeTagName(name)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+	}
+	t.Tags = tags
+
+	if t.DueInDays != nil && (*t.DueInDays < 0 || *t.DueInDays > 3650) {
+		return &ValidationError{"due_in_days must be between 0 and 3650"}
+	}
+	if t.DueTime != "" {
+		if t.DueInDays == nil {
+			return &ValidationError{"due_time requires due_in_days"}
+		}
+		if _, err := time.Parse("15:04", t.DueTime); err != nil {
+			return &ValidationError{"due_time must be in HH:MM format"}
+		}
+	}
+
+	if len(t.Checklist) > MaxTemplateChecklist {
+		return &ValidationError{fmt.Sprintf("template checklist too long (max %d items)", MaxTemplateChecklist)}
+	}
+	for i := range t.Checklist {
+		title, err := validateChecklistTitle(t.Checklist[i])
+		if err != nil {
+			return err
+		}
+		t.Checklist[i] = title
+	}
+
+	for i := range t.Subtasks {
+		if err := t.Subtasks[i].validate(depth+1, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateVariables возвращает переменные из названий и описаний задач
+// дерева, кроме date, в порядке появления
+func (t TemplateTask) TemplateVariables() []string {
+	seen := map[string]bool{TemplateDateVariable: true}
+	variables := []string{}
+	var walk func(task TemplateTask)
+	walk = func(task TemplateTask) {
+		for _, text := range []string{task.Title, task.Description} {
+			for _, match := range templateVariable.FindAllStringSubmatch(text, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					variables = append(variables, match[1])
+				}
+			}
+		}
+		for _, subtask := range task.Subtasks {
+			walk(subtask)
+		}
+	}
+	walk(t)
+	return variables
+}
+
+// ExpandTemplate подставляет значения переменных; переменная без значения –
+// ошибка валидации
+func ExpandTemplate(text string, values map[string]string) (string, error) {
+	var missing string
+	expanded := templateVariable.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVariable.FindStringSubmatch(match)[1]
+		value, ok := values[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", &ValidationError{"missing value for template variable " + missing}
+	}
+	return expanded, nil
+}
+
+// TemplateDue возвращает срок задачи шаблона от даты начала; время суток –
+// без смещения, в часовом поясе пользователя
+func (t TemplateTask) TemplateDue(start time.Time) (due.Date, error) {
+	if t.DueInDays == nil {
+		return due.Date{}, nil
+	}
+	value := start.AddDate(0, 0, *t.DueInDays).Format(time.DateOnly)
+	if t.DueTime != "" {
+		value += "T" + t.DueTime
+	}
+	return due.Parse(value)
+}
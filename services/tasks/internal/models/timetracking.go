@@ -0,0 +1,150 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// Приоритеты задачи по возрастанию; пустой приоритет – не задан
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// Группировки отчета по учтенному времени
+const (
+	TimeReportByTask    = "task"
+	TimeReportByProject = "project"
+	TimeReportByDay     = "day"
+)
+
+// Ограничения оценок и ручных записей времени
+const (
+	MaxEstimatePoints   = 1000
+	MaxEstimateMinutes  = 100000
+	MaxTimeEntryMinutes = 24 * 60
+)
+
+// ErrTimerNotRunning – на задаче нет запущенного таймера
+var ErrTimerNotRunning = errors.New("timer is not running")
+
+// ErrTimerRunning – у пользователя уже запущен таймер на другой задаче
+var ErrTimerRunning = errors.New("another timer is already running")
+
+// TimeEntry – отрезок работы над задачей. Запущенный таймер – запись
+// без EndedAt, его длительность считается при остановке.
+type TimeEntry struct {
+	ID              string     `json:"id"`
+	TaskID          string     `json:"task_id"`
+	Subject         string     `json:"-"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Note            string     `json:"note,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// Running – таймер еще не остановлен
+func (e TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// CreateTimeEntryRequest – ручная запись времени; без started_at запись
+// заканчивается в момент запроса
+type CreateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note"`
+}
+
+// TimerRequest – необязательная заметка к запускаемому таймеру
+type TimerRequest struct {
+	Note string `json:"note"`
+}
+
+// LoggedTime – завершенная запись времени с задачей и проектом для отчетов
+type LoggedTime struct {
+	TaskID          string
+	TaskTitle       string
+	ProjectID       string
+	ProjectName     string
+	StartedAt       time.Time
+	DurationSeconds int64
+}
+
+// TimeReport – учтенное время, сгруппированное по задачам, проектам или дням
+type TimeReport struct {
+	GroupBy      string          `json:"group_by"`
+	From         string          `json:"from,omitempty"`
+	To           string          `json:"to,omitempty"`
+	TotalSeconds int64           `json:"total_seconds"`
+	Rows         []TimeReportRow `json:"rows"`
+}
+
+// TimeReportRow – строка отчета: Key – id задачи или проекта ("" – без
+// проекта) либо дата YYYY-MM-DD
+type TimeReportRow struct {
+	Key     string `json:"key"`
+	Title   string `json:"title,omitempty"`
+	Seconds int64  `json:"seconds"`
+}
+
+// NormalizePriority проверяет приоритет; "" снимает приоритет
+func NormalizePriority(priority string) (string, error) {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	switch priority {
+	case "", PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return priority, nil
+	}
+	return "", &ValidationError{"priority must be one of low, medium, high, urgent"}
+}
+
+// ValidateEstimates проверяет оценки в story points и минутах; 0 – не задана
+func ValidateEstimates(points, minutes int) error {
+	if points < 0 || points > MaxEstimatePoints {
+		return &ValidationError{"estimate_points must be between 0 and 1000"}
+	}
+	if minutes < 0 || minutes > MaxEstimateMinutes {
+		return &ValidationError{"estimate_minutes must be between 0 and 100000"}
+	}
+	return nil
+}
+
+// Validate проверяет длительность и очищает заметку ручной записи
+func (r *CreateTimeEntryRequest) Validate(now time.Time) error {
+	if r.Minutes <= 0 || r.Minutes > MaxTimeEntryMinutes {
+		return &ValidationError{"minutes must be between 1 and 1440"}
+	}
+	if r.StartedAt != nil && r.StartedAt.After(now) {
+		return &ValidationError{"started_at must not be in the future"}
+	}
+	note, err := normalizeTimeNote(r.Note)
+	if err != nil {
+		return err
+	}
+	r.Note = note
+	return nil
+}
+
+// Validate очищает заметку таймера
+func (r *TimerRequest) Validate() error {
+	note, err := normalizeTimeNote(r.Note)
+	if err != nil {
+		return err
+	}
+	r.Note = note
+	return nil
+}
+
+func normalizeTimeNote(note string) (string, error) {
+	note = strings.TrimSpace(sanitize.SanitizeText(note))
+	if len(note) > 255 {
+		return "", &ValidationError{"note too long (max 255 characters)"}
+	}
+	return note, nil
+}
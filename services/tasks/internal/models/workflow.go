@@ -0,0 +1,134 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Статусы процесса по умолчанию
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusReview     = "review"
+	StatusDone       = "done"
+)
+
+// ErrUnknownStatus – статуса нет в процессе проекта задачи
+var ErrUnknownStatus = errors.New("unknown task status")
+
+// ErrStatusTransition – процесс проекта не разрешает такой переход
+var ErrStatusTransition = errors.New("status transition not allowed")
+
+var statusPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Workflow – допустимые статусы задач проекта и переходы между ними.
+// Первый статус – начальный, он же используется при снятии done.
+type Workflow struct {
+	Statuses []string `json:"statuses"`
+	// Статусы, в которых задача считается выполненной (done == true)
+	DoneStatuses []string `json:"done_statuses"`
+	// Разрешенные переходы; пустая карта – разрешен любой переход
+	Transitions map[string][]string `json:"transitions,omitempty"`
+}
+
+// DefaultWorkflow действует для задач без проекта и проектов без своего процесса
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:     []string{StatusTodo, StatusInProgress, StatusReview, StatusDone},
+		DoneStatuses: []string{StatusDone},
+	}
+}
+
+func (w Workflow) Has(status string) bool {
+	return slices.Contains(w.Statuses, status)
+}
+
+func (w Workflow) IsDone(status string) bool {
+	return slices.Contains(w.DoneStatuses, status)
+}
+
+func (w Workflow) InitialStatus() string {
+	return w.Statuses[0]
+}
+
+// DoneStatus – статус, в который переходит задача при done: true
+func (w Workflow) DoneStatus() string {
+	return w.DoneStatuses[0]
+}
+
+// StatusFor сопоставляет флаг done статусу для клиентов без поддержки статусов
+func (w Workflow) StatusFor(done bool) string {
+	if done {
+		return w.DoneStatus()
+	}
+	return w.InitialStatus()
+}
+
+// Allows проверяет переход. Из статуса, которого нет в процессе (например,
+// после смены процесса), можно перейти в любой.
+func (w Workflow) Allows(from, to string) bool {
+	if from == to || len(w.Transitions) == 0 || !w.Has(from) {
+		return true
+	}
+	return slices.Contains(w.Transitions[from], to)
+}
+
+// Validate нормализует имена статусов и проверяет целостность процесса
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return &ValidationError{"workflow must have at least one status"}
+	}
+
+	seen := make(map[string]bool, len(w.Statuses))
+	for i, status := range w.Statuses {
+		status = NormalizeStatus(status)
+		if !statusPattern.MatchString(status) {
+			return &ValidationError{fmt.Sprintf("invalid status name %q", status)}
+		}
+		if seen[status] {
+			return &ValidationError{fmt.Sprintf("duplicate status %q", status)}
+		}
+		seen[status] = true
+		w.Statuses[i] = status
+	}
+
+	if len(w.DoneStatuses) == 0 {
+		return &ValidationError{"workflow must have at least one done status"}
+	}
+	for i, status := range w.DoneStatuses {
+		status = NormalizeStatus(status)
+		if !seen[status] {
+			return &ValidationError{fmt.Sprintf("done status %q is not in statuses", status)}
+		}
+		w.DoneStatuses[i] = status
+	}
+	if w.IsDone(w.InitialStatus()) {
+		return &ValidationError{"initial status must not be a done status"}
+	}
+
+	transitions := make(map[string][]string, len(w.Transitions))
+	for from, targets := range w.Transitions {
+		from = NormalizeStatus(from)
+		if !seen[from] {
+			return &ValidationError{fmt.Sprintf("transition from unknown status %q", from)}
+		}
+		for _, to := range targets {
+			to = NormalizeStatus(to)
+			if !seen[to] {
+				return &ValidationError{fmt.Sprintf("transition to unknown status %q", to)}
+			}
+			transitions[from] = append(transitions[from], to)
+		}
+	}
+	w.Transitions = transitions
+
+	return nil
+}
+
+// NormalizeStatus приводит статус из запроса к виду, в котором он хранится
+func NormalizeStatus(status string) string {
+	return strings.ToLower(strings.TrimSpace(status))
+}
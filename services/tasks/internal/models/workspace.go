@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+// DefaultTenant – рабочее пространство пользователей, для которых auth
+// сервис не сообщил другого, и всех данных, созданных до появления
+// пространств
+const DefaultTenant = "default"
+
+// Ресурсы с квотами пространства
+const (
+	QuotaTasks    = "task"
+	QuotaProjects = "project"
+)
+
+// Quotas – наибольшее число задач (включая корзину) и проектов в
+// пространстве; 0 – без ограничения
+type Quotas struct {
+	MaxTasks    int `json:"max_tasks"`
+	MaxProjects int `json:"max_projects"`
+}
+
+// WorkspaceUsage – участники, задачи (включая корзину) и проекты пространства
+type WorkspaceUsage struct {
+	Members  int `json:"members"`
+	Tasks    int `json:"tasks"`
+	Projects int `json:"projects"`
+}
+
+// Workspace – рабочее пространство с действующими квотами и их расходом
+type Workspace struct {
+	ID     string         `json:"id"`
+	Quotas Quotas         `json:"quotas"`
+	Usage  WorkspaceUsage `json:"usage"`
+
+	// Квоты пространства из базы; nil – квота сервиса по умолчанию
+	MaxTasks    *int `json:"-"`
+	MaxProjects *int `json:"-"`
+}
+
+// ApplyDefaults заполняет Quotas квотами пространства или defaults
+func (w *Workspace) ApplyDefaults(defaults Quotas) {
+	w.Quotas = defaults
+	if w.MaxTasks != nil {
+		w.Quotas.MaxTasks = *w.MaxTasks
+	}
+	if w.MaxProjects != nil {
+		w.Quotas.MaxProjects = *w.MaxProjects
+	}
+}
+
+// Check возвращает QuotaError, если added новых ресурсов превысят квоту
+func (w Workspace) Check(resource string, added int) error {
+	limit, used := w.Quotas.MaxTasks, w.Usage.Tasks
+	if resource == QuotaProjects {
+		limit, used = w.Quotas.MaxProjects, w.Usage.Projects
+	}
+	if limit > 0 && used+added > limit {
+		return &QuotaError{Resource: resource, Limit: limit}
+	}
+	return nil
+}
+
+// QuotaError – операция превысила бы квоту пространства
+type QuotaError struct {
+	Resource string
+	Limit    int
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("workspace %s quota exceeded (max %d)", e.Resource, e.Limit)
+}
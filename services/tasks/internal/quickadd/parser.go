@@ -0,0 +1,631 @@
+// Package quickadd разбирает строку быстрого добавления задачи на английском
+// или русском: "Pay rent every month on the 1st #home !high",
+// "Позвонить маме завтра в 18:00 #семья". Распознанные части убираются из
+// названия задачи, остальные слова остаются названием.
+package quickadd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+)
+
+// Result – задача, разобранная из строки
+type Result struct {
+	Title      string   `json:"title"`
+	DueDate    due.Date `json:"due_date"`
+	Recurrence string   `json:"recurrence,omitempty"`
+	Tags       []string `json:"tags"`
+	Priority   string   `json:"priority,omitempty"`
+}
+
+// Parse разбирает text относительно момента now. Даты считаются в часовом
+// поясе now (поясе пользователя). Метки возвращаются как написаны, их
+// проверяет сервис.
+func Parse(text string, now time.Time) Result {
+	p := &parser{
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		tags:  []string{},
+	}
+	for _, word := range strings.Fields(text) {
+		p.words = append(p.words, word)
+		p.lower = append(p.lower, strings.ToLower(strings.TrimRight(word, ",.;")))
+	}
+
+	matchers := []func(i int) int{p.matchTag, p.matchPriority, p.matchRecurrence, p.matchDate, p.matchTime}
+	var title []string
+	for i := 0; i < len(p.words); {
+		n := 0
+		for _, match := range matchers {
+			if n = match(i); n > 0 {
+				break
+			}
+		}
+		if n == 0 {
+			title = append(title, p.words[i])
+			n = 1
+		}
+		i += n
+	}
+
+	return Result{
+		Title:      strings.Trim(strings.Join(title, " "), " ,;:-"),
+		DueDate:    p.dueDate(),
+		Recurrence: p.recurrence(),
+		Tags:       p.tags,
+		Priority:   p.priority,
+	}
+}
+
+type parser struct {
+	now   time.Time
+	today time.Time // текущий день, 00:00 UTC
+	words []string
+	lower []string // слова в нижнем регистре без завершающей пунктуации
+
+	day          time.Time // нулевой – дата не указана
+	hour, minute int
+	hasTime      bool
+	freq         string
+	interval     int
+	byDay        []time.Weekday
+	tags         []string
+	priority     string
+}
+
+var priorities = map[string]string{
+	"low":     models.PriorityLow,
+	"medium":  models.PriorityMedium,
+	"high":    models.PriorityHigh,
+	"urgent":  models.PriorityUrgent,
+	"p4":      models.PriorityLow,
+	"p3":      models.PriorityMedium,
+	"p2":      models.PriorityHigh,
+	"p1":      models.PriorityUrgent,
+	"низкий":  models.PriorityLow,
+	"средний": models.PriorityMedium,
+	"высокий": models.PriorityHigh,
+	"срочно":  models.PriorityUrgent,
+	"срочный": models.PriorityUrgent,
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"понедельник": time.Monday, "пн": time.Monday,
+	"вторник": time.Tuesday, "вт": time.Tuesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday,
+	"четверг": time.Thursday, "чт": time.Thursday,
+	"пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday,
+	"воскресенье": time.Sunday, "вс": time.Sunday,
+}
+
+// Сокращения дней недели без предлога бывают частью названия ("Talk to Sun
+// Li"): датой они считаются только после "on", "every", "next" и т. п.
+var weekdayAbbreviations = map[string]bool{
+	"mon": true, "tue": true, "tues": true, "wed": true, "thu": true, "thur": true, "thurs": true,
+	"fri": true, "sat": true, "sun": true,
+	"пн": true, "вт": true, "ср": true, "чт": true, "пт": true, "сб": true, "вс": true,
+}
+
+// "по понедельникам" – каждую неделю
+var weekdaysPlural = map[string]time.Weekday{
+	"понедельникам": time.Monday,
+	"вторникам":     time.Tuesday,
+	"средам":        time.Wednesday,
+	"четвергам":     time.Thursday,
+	"пятницам":      time.Friday,
+	"субботам":      time.Saturday,
+	"воскресеньям":  time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January, "января": time.January,
+	"february": time.February, "feb": time.February, "февраля": time.February,
+	"march": time.March, "mar": time.March, "марта": time.March,
+	"april": time.April, "apr": time.April, "апреля": time.April,
+	"may": time.May, "мая": time.May,
+	"june": time.June, "jun": time.June, "июня": time.June,
+	"july": time.July, "jul": time.July, "июля": time.July,
+	"august": time.August, "aug": time.August, "августа": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September, "сентября": time.September,
+	"october": time.October, "oct": time.October, "октября": time.October,
+	"november": time.November, "nov": time.November, "ноября": time.November,
+	"december": time.December, "dec": time.December, "декабря": time.December,
+}
+
+// Единицы периода: "every 2 weeks", "in 3 days", "через неделю", "каждые 2 месяца"
+var units = map[string]string{
+	"day": models.FreqDaily, "days": models.FreqDaily,
+	"день": models.FreqDaily, "дня": models.FreqDaily, "дней": models.FreqDaily,
+	"week": models.FreqWeekly, "weeks": models.FreqWeekly,
+	"неделю": models.FreqWeekly, "недели": models.FreqWeekly, "недель": models.FreqWeekly,
+	"month": models.FreqMonthly, "months": models.FreqMonthly,
+	"месяц": models.FreqMonthly, "месяца": models.FreqMonthly, "месяцев": models.FreqMonthly,
+	"year": models.FreqYearly, "years": models.FreqYearly,
+	"год": models.FreqYearly, "года": models.FreqYearly, "лет": models.FreqYearly,
+}
+
+var frequencies = map[string]string{
+	"daily": models.FreqDaily, "ежедневно": models.FreqDaily,
+	"weekly": models.FreqWeekly, "еженедельно": models.FreqWeekly,
+	"monthly": models.FreqMonthly, "ежемесячно": models.FreqMonthly,
+	"yearly": models.FreqYearly, "annually": models.FreqYearly, "ежегодно": models.FreqYearly,
+}
+
+var workdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// word возвращает слово i в нижнем регистре или "" за концом строки
+func (p *parser) word(i int) string {
+	if i < 0 || i >= len(p.lower) {
+		return ""
+	}
+	return p.lower[i]
+}
+
+func oneOf(word string, options ...string) bool {
+	for _, option := range options {
+		if word == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) matchTag(i int) int {
+	name := strings.TrimRight(p.words[i], ",.;")
+	if !strings.HasPrefix(name, "#") || len(name) < 2 {
+		return 0
+	}
+	p.tags = append(p.tags, name[1:])
+	return 1
+}
+
+func (p *parser) matchPriority(i int) int {
+	word := p.word(i)
+	if !strings.HasPrefix(word, "!") {
+		return 0
+	}
+	priority, ok := priorities[word[1:]]
+	if !ok {
+		return 0
+	}
+	p.priority = priority
+	return 1
+}
+
+// matchRecurrence: "daily", "every 2 weeks", "every mon and thu", "every
+// weekday", "every 1st", "каждую пятницу", "каждые 3 дня", "по будням"
+func (p *parser) matchRecurrence(i int) int {
+	word := p.word(i)
+	if freq, ok := frequencies[word]; ok {
+		p.setFreq(freq, 1, nil)
+		return 1
+	}
+	if word == "по" {
+		if p.word(i+1) == "будням" {
+			p.setFreq(models.FreqWeekly, 1, workdays)
+			return 2
+		}
+		if day, ok := weekdaysPlural[p.word(i+1)]; ok {
+			p.setFreq(models.FreqWeekly, 1, []time.Weekday{day})
+			return 2
+		}
+		return 0
+	}
+	if !oneOf(word, "every", "each", "каждый", "каждую", "каждое", "каждые") {
+		return 0
+	}
+
+	j := i + 1
+	interval := 1
+	if n, err := strconv.Atoi(p.word(j)); err == nil && n > 1 && n <= 1000 {
+		if _, ok := units[p.word(j+1)]; ok {
+			interval = n
+			j++
+		}
+	} else if p.word(j) == "other" {
+		interval = 2
+		j++
+	}
+	if freq, ok := units[p.word(j)]; ok {
+		p.setFreq(freq, interval, nil)
+		return j + 1 - i
+	}
+	if interval > 1 {
+		return 0
+	}
+
+	switch {
+	case oneOf(p.word(j), "weekday", "workday"):
+		p.setFreq(models.FreqWeekly, 1, workdays)
+		return j + 1 - i
+	case p.word(j) == "будний" && p.word(j+1) == "день":
+		p.setFreq(models.FreqWeekly, 1, workdays)
+		return j + 2 - i
+	}
+
+	// Дни недели через "and", "и" или запятую
+	var days []time.Weekday
+	for {
+		day, ok := weekdays[p.word(j)]
+		if !ok {
+			break
+		}
+		days = append(days, day)
+		j++
+		if oneOf(p.word(j), "and", "и") {
+			if _, ok := weekdays[p.word(j+1)]; ok {
+				j++
+			}
+		}
+	}
+	if len(days) > 0 {
+		p.setFreq(models.FreqWeekly, 1, days)
+		return j - i
+	}
+
+	// "every 1st", "каждое 1 число" – день месяца
+	if n := p.matchDayOfMonth(j); n > 0 {
+		p.setFreq(models.FreqMonthly, 1, nil)
+		return j + n - i
+	}
+	return 0
+}
+
+func (p *parser) setFreq(freq string, interval int, days []time.Weekday) {
+	p.freq = freq
+	p.interval = interval
+	p.byDay = days
+}
+
+// matchDate распознает дату с необязательным предлогом: "on friday",
+// "by March 10", "в понедельник", "до 10 марта"
+func (p *parser) matchDate(i int) int {
+	if n := p.dateAt(i, false); n > 0 {
+		return n
+	}
+	if oneOf(p.word(i), "on", "by", "due", "до", "к", "ко", "на", "в", "во") {
+		if n := p.dateAt(i+1, true); n > 0 {
+			return n + 1
+		}
+		// "on 1st"; без предлога порядковое число – часть названия ("2nd chapter")
+		if n := p.matchDayOfMonth(i + 1); n > 0 {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+// dateAt распознает дату в слове i; afterKeyword – перед ней стоит предлог
+func (p *parser) dateAt(i int, afterKeyword bool) int {
+	word := p.word(i)
+	switch word {
+	case "":
+		return 0
+	case "today", "сегодня":
+		p.day = p.today
+		return 1
+	case "tomorrow", "завтра":
+		p.day = p.today.AddDate(0, 0, 1)
+		return 1
+	case "послезавтра":
+		p.day = p.today.AddDate(0, 0, 2)
+		return 1
+	case "day":
+		if p.word(i+1) == "after" && p.word(i+2) == "tomorrow" {
+			p.day = p.today.AddDate(0, 0, 2)
+			return 3
+		}
+		return 0
+	case "next", "следующий", "следующую", "следующей", "следующем", "следующее":
+		return p.matchNext(i)
+	case "in", "через":
+		return p.matchIn(i)
+	case "the":
+		if n := p.matchDayOfMonth(i + 1); n > 0 {
+			return n + 1
+		}
+		return 0
+	}
+
+	if day, ok := weekdays[word]; ok && (afterKeyword || !weekdayAbbreviations[word]) {
+		p.day = p.nextWeekday(day, 0)
+		return 1
+	}
+	if t, err := time.Parse(time.DateOnly, word); err == nil {
+		p.day = t
+		return 1
+	}
+	if day, ok := parseDotDate(word, p.today); ok {
+		p.day = day
+		return 1
+	}
+	// "March 10", "mar 10th 2027"
+	if month, ok := months[word]; ok {
+		if day, ok := parseOrdinal(p.word(i + 1)); ok {
+			if n, ok := p.setMonthDay(month, day, p.word(i+2)); ok {
+				return 2 + n
+			}
+		}
+		return 0
+	}
+	// "10 March", "10-го марта 2027"
+	if day, ok := parseOrdinal(word); ok {
+		if month, ok := months[p.word(i+1)]; ok {
+			if n, ok := p.setMonthDay(month, day, p.word(i+2)); ok {
+				return 2 + n
+			}
+			return 0
+		}
+	}
+	if oneOf(p.word(i+1), "числа", "число") {
+		return p.matchDayOfMonth(i)
+	}
+	return 0
+}
+
+// matchNext: "next week", "next month", "next friday", "в следующую пятницу".
+// "next friday" – пятница следующей календарной недели, а не ближайшая
+// пятница ("friday"): в четверг это пятница через 8 дней.
+func (p *parser) matchNext(i int) int {
+	word := p.word(i + 1)
+	if day, ok := weekdays[word]; ok {
+		monday := p.nextWeekday(time.Monday, 1)
+		p.day = monday.AddDate(0, 0, (int(day)+6)%7)
+		return 2
+	}
+	switch units[word] {
+	case models.FreqWeekly:
+		p.day = p.nextWeekday(time.Monday, 1)
+	case models.FreqMonthly:
+		p.day = time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	case models.FreqYearly:
+		p.day = time.Date(p.today.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return 0
+	}
+	return 2
+}
+
+// matchIn: "in 3 days", "in a week", "через 2 недели", "через месяц"
+func (p *parser) matchIn(i int) int {
+	j := i + 1
+	n := 1
+	if count, err := strconv.Atoi(p.word(j)); err == nil && count > 0 && count <= 1000 {
+		n = count
+		j++
+	} else if oneOf(p.word(j), "a", "an", "one") {
+		j++
+	} else if p.word(i) == "in" {
+		return 0
+	}
+
+	switch units[p.word(j)] {
+	case models.FreqDaily:
+		p.day = p.today.AddDate(0, 0, n)
+	case models.FreqWeekly:
+		p.day = p.today.AddDate(0, 0, 7*n)
+	case models.FreqMonthly:
+		p.day = p.today.AddDate(0, n, 0)
+	case models.FreqYearly:
+		p.day = p.today.AddDate(n, 0, 0)
+	default:
+		return 0
+	}
+	return j + 1 - i
+}
+
+// matchDayOfMonth: "1st", "15th", "1-го", "1 числа" – ближайший такой день
+// месяца, начиная с сегодняшнего. Вызывается после "the", "on" или "every".
+func (p *parser) matchDayOfMonth(i int) int {
+	word := p.word(i)
+	day, ok := parseOrdinal(word)
+	if !ok {
+		return 0
+	}
+	n := 1
+	if oneOf(p.word(i+1), "числа", "число") {
+		n = 2
+	} else if _, err := strconv.Atoi(word); err == nil {
+		// Число без суффикса – не дата
+		return 0
+	}
+
+	for m := 0; m < 12; m++ {
+		month := time.Date(p.today.Year(), p.today.Month()+time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+		date := month.AddDate(0, 0, day-1)
+		if date.Month() == month.Month() && !date.Before(p.today) {
+			p.day = date
+			return n
+		}
+	}
+	return 0
+}
+
+// setMonthDay задает дату без года: ближайшая, начиная с сегодняшней.
+// Возвращает 1, если year – год даты; false – такой даты нет ("31 февраля").
+func (p *parser) setMonthDay(month time.Month, day int, year string) (int, bool) {
+	if y, err := strconv.Atoi(year); err == nil && y >= 1970 && y <= 9999 {
+		date := time.Date(y, month, day, 0, 0, 0, 0, time.UTC)
+		if date.Day() != day {
+			return 0, false
+		}
+		p.day = date
+		return 1, true
+	}
+	date := time.Date(p.today.Year(), month, day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return 0, false
+	}
+	if date.Before(p.today) {
+		// 29 февраля в следующем году может не быть
+		date = time.Date(p.today.Year()+1, month, day, 0, 0, 0, 0, time.UTC)
+		if date.Day() != day {
+			return 0, false
+		}
+	}
+	p.day = date
+	return 0, true
+}
+
+// nextWeekday возвращает ближайший день недели day; skip > 0 – не раньше завтра
+func (p *parser) nextWeekday(day time.Weekday, skip int) time.Time {
+	offset := (int(day) - int(p.today.Weekday()) + 7) % 7
+	if offset < skip {
+		offset += 7
+	}
+	return p.today.AddDate(0, 0, offset)
+}
+
+// matchTime: "at 18:00", "at 6pm", "6:30 pm", "в 18:00"
+func (p *parser) matchTime(i int) int {
+	if n := p.timeAt(i); n > 0 {
+		return n
+	}
+	if oneOf(p.word(i), "at", "@", "в", "во") {
+		if n := p.timeAt(i + 1); n > 0 {
+			return n + 1
+		}
+	}
+	return 0
+}
+
+func (p *parser) timeAt(i int) int {
+	word := p.word(i)
+	suffix := ""
+	n := 1
+	for _, s := range []string{"am", "pm"} {
+		if strings.HasSuffix(word, s) {
+			word, suffix = strings.TrimSuffix(word, s), s
+		}
+	}
+	if suffix == "" && oneOf(p.word(i+1), "am", "pm") {
+		suffix = p.word(i + 1)
+		n = 2
+	}
+
+	hourPart, minutePart, hasMinutes := strings.Cut(word, ":")
+	hour, err := strconv.Atoi(hourPart)
+	if err != nil || (!hasMinutes && suffix == "") {
+		return 0
+	}
+	minute := 0
+	if hasMinutes {
+		if len(minutePart) != 2 {
+			return 0
+		}
+		if minute, err = strconv.Atoi(minutePart); err != nil || minute > 59 {
+			return 0
+		}
+	}
+
+	switch {
+	case suffix != "" && (hour < 1 || hour > 12):
+		return 0
+	case suffix == "am" && hour == 12:
+		hour = 0
+	case suffix == "pm" && hour < 12:
+		hour += 12
+	case hour > 23:
+		return 0
+	}
+
+	p.hour, p.minute, p.hasTime = hour, minute, true
+	return n
+}
+
+// parseOrdinal читает день месяца: 1, 1st, 2nd, 3rd, 10th, 1-го, 1-е
+func parseOrdinal(word string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th", "-го", "-е", "-ое"} {
+		word = strings.TrimSuffix(word, suffix)
+	}
+	day, err := strconv.Atoi(word)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
+
+// parseDotDate читает 10.03 или 10.03.2026; дата без года – ближайшая
+func parseDotDate(word string, today time.Time) (time.Time, bool) {
+	parts := strings.Split(word, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return time.Time{}, false
+	}
+	day, err1 := strconv.Atoi(parts[0])
+	month, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+
+	year := today.Year()
+	if len(parts) == 3 {
+		y, err := strconv.Atoi(parts[2])
+		if err != nil || len(parts[2]) != 4 {
+			return time.Time{}, false
+		}
+		year = y
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, false
+	}
+	if len(parts) == 2 && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true
+}
+
+// dueDate собирает срок. Повторение по дням недели без даты начинается с
+// ближайшего такого дня, время без даты – сегодня или завтра, если прошло.
+func (p *parser) dueDate() due.Date {
+	// Сегодняшнее время уже прошло – срок не раньше завтра
+	skip := 0
+	if p.hasTime && (p.hour < p.now.Hour() || (p.hour == p.now.Hour() && p.minute <= p.now.Minute())) {
+		skip = 1
+	}
+
+	day := p.day
+	if day.IsZero() && len(p.byDay) > 0 {
+		for _, weekday := range p.byDay {
+			if next := p.nextWeekday(weekday, skip); day.IsZero() || next.Before(day) {
+				day = next
+			}
+		}
+	}
+	if day.IsZero() && p.hasTime {
+		day = p.today.AddDate(0, 0, skip)
+	}
+	if day.IsZero() {
+		return due.Date{}
+	}
+
+	if p.hasTime {
+		loc := p.now.Location()
+		return due.At(time.Date(day.Year(), day.Month(), day.Day(), p.hour, p.minute, 0, 0, loc), loc)
+	}
+	return due.Day(day.Year(), day.Month(), day.Day())
+}
+
+// recurrence возвращает правило RRULE или ""
+func (p *parser) recurrence() string {
+	if p.freq == "" {
+		return ""
+	}
+	rule := models.RRule{Freq: p.freq, Interval: p.interval}
+	for _, day := range p.byDay {
+		rule.ByDay = append(rule.ByDay, models.ByDay{Weekday: day})
+	}
+	return rule.String()
+}
@@ -0,0 +1,82 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Четверг, 15 октября 2026, 10:30 по Москве
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("Skipping quick-add test - no time zone data: %v", err)
+	}
+	now := time.Date(2026, 10, 15, 10, 30, 0, 0, moscow)
+
+	cases := []struct {
+		text       string
+		title      string
+		dueDate    string
+		recurrence string
+		tags       []string
+		priority   string
+	}{
+		{"Pay rent every month on the 1st #home !high", "Pay rent", "2026-11-01", "FREQ=MONTHLY", []string{"home"}, "high"},
+		{"Call mom tomorrow at 6pm #family", "Call mom", "2026-10-16T15:00:00Z", "", []string{"family"}, ""},
+		{"Standup every weekday at 9:30", "Standup", "2026-10-16T06:30:00Z", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", nil, ""},
+		{"Gym every mon and thu", "Gym", "2026-10-15", "FREQ=WEEKLY;BYDAY=MO,TH", nil, ""},
+		{"Submit report by March 10 !p1", "Submit report", "2027-03-10", "", nil, "urgent"},
+		{"Read 2nd chapter in 3 days", "Read 2nd chapter", "2026-10-18", "", nil, ""},
+		{"Water plants every 2 weeks", "Water plants", "", "FREQ=WEEKLY;INTERVAL=2", nil, ""},
+		{"Buy 3 apples", "Buy 3 apples", "", "", nil, ""},
+		{"Plan #work #Q4 !low", "Plan", "", "", []string{"work", "Q4"}, "low"},
+		{"Deploy 2026-11-02 at 23:30", "Deploy", "2026-11-02T20:30:00Z", "", nil, ""},
+
+		// Ближайший день недели и день следующей календарной недели
+		{"Review friday", "Review", "2026-10-16", "", nil, ""},
+		{"Review next friday", "Review", "2026-10-23", "", nil, ""},
+		{"Review next monday", "Review", "2026-10-19", "", nil, ""},
+		{"Review thursday", "Review", "2026-10-15", "", nil, ""},
+		{"Review next thursday", "Review", "2026-10-22", "", nil, ""},
+		{"Sprint next week", "Sprint", "2026-10-19", "", nil, ""},
+		{"Close books next month", "Close books", "2026-11-01", "", nil, ""},
+
+		{"Позвонить маме завтра в 18:00 #семья", "Позвонить маме", "2026-10-16T15:00:00Z", "", []string{"семья"}, ""},
+		{"Оплатить интернет каждое 25 число !высокий", "Оплатить интернет", "2026-10-25", "FREQ=MONTHLY", nil, "high"},
+		{"Сдать отчет до 10 марта", "Сдать отчет", "2027-03-10", "", nil, ""},
+		{"Планерка по понедельникам", "Планерка", "2026-10-19", "FREQ=WEEKLY;BYDAY=MO", nil, ""},
+		{"Купить билеты через неделю", "Купить билеты", "2026-10-22", "", nil, ""},
+		{"Ревью в следующую пятницу", "Ревью", "2026-10-23", "", nil, ""},
+
+		// Несуществующая дата остается в названии
+		{"Report 31 February", "Report 31 February", "", "", nil, ""},
+		{"Report Feb 30", "Report Feb 30", "", "", nil, ""},
+		{"Отчет 29 февраля", "Отчет 29 февраля", "", "", nil, ""},
+		{"Отчет 29 февраля 2028", "Отчет", "2028-02-29", "", nil, ""},
+
+		// Сокращение дня недели без предлога – часть названия
+		{"Talk to Sun Li", "Talk to Sun Li", "", "", nil, ""},
+		{"Review on fri", "Review", "2026-10-16", "", nil, ""},
+		{"Ревью в пт", "Ревью", "2026-10-16", "", nil, ""},
+	}
+	for _, c := range cases {
+		parsed := Parse(c.text, now)
+		if parsed.Title != c.title || parsed.DueDate.String() != c.dueDate || parsed.Recurrence != c.recurrence ||
+			parsed.Priority != c.priority || !slices.Equal(parsed.Tags, append([]string{}, c.tags...)) {
+			t.Errorf("Parse(%q) = %+v (due %s)", c.text, parsed, parsed.DueDate)
+		}
+	}
+}
+
+func TestParseTimeWithoutDate(t *testing.T) {
+	now := time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)
+
+	// Время без даты – сегодня или завтра, если уже прошло
+	if parsed := Parse("Call at 18:00", now); parsed.DueDate.String() != "2026-10-15T18:00:00Z" {
+		t.Errorf("Expected today at 18:00, got %s", parsed.DueDate)
+	}
+	if parsed := Parse("Call at 9:00", now); parsed.DueDate.String() != "2026-10-16T09:00:00Z" {
+		t.Errorf("Expected tomorrow at 9:00, got %s", parsed.DueDate)
+	}
+}
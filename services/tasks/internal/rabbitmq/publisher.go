@@ -66,16 +66,69 @@ func NewPublisher(config PublisherConfig, log *logger.Logger) (*Publisher, error
 }
 
 func (p *Publisher) PublishEvent(ctx context.Context, eventType string, task models.Task, requestID string) error {
-	// Создание события
+	return p.publish(ctx, taskEvent(eventType, task, requestID))
+}
+
+// PublishStatusChanged публикует task.status_changed с предыдущим статусом
+func (p *Publisher) PublishStatusChanged(ctx context.Context, task models.Task, previousStatus string, requestID string) error {
+	event := taskEvent("task.status_changed", task, requestID)
+	event["previous_status"] = previousStatus
+	return p.publish(ctx, event)
+}
+
+// PublishBatch публикует события bulk операции одним сообщением task.bulk
+func (p *Publisher) PublishBatch(ctx context.Context, events []models.TaskEvent, requestID string) error {
+	items := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		item := taskEvent(e.Event, e.Task, requestID)
+		if e.Event == "task.status_changed" {
+			item["previous_status"] = e.PreviousStatus
+		}
+		items = append(items, item)
+	}
+	return p.publish(ctx, map[string]interface{}{
+		"event":      "task.bulk",
+		"events":     items,
+		"count":      len(items),
+		"ts":         time.Now().Format(time.RFC3339),
+		"request_id": requestID,
+	})
+}
+
+// PublishShareEvent публикует task.shared/unshared или project.shared/unshared
+func (p *Publisher) PublishShareEvent(ctx context.Context, eventType string, share models.Share, requestID string) error {
+	event := map[string]interface{}{
+		"event":         eventType,
+		"resource_type": share.ResourceType,
+		"subject":       share.Owner,
+		"grantee":       share.Subject,
+		"role":          share.Role,
+		"ts":            time.Now().Format(time.RFC3339),
+		"request_id":    requestID,
+	}
+	event[share.ResourceType+"_id"] = share.ResourceID
+	return p.publish(ctx, event)
+}
+
+// taskEvent собирает событие задачи; task.unassigned содержит снятого исполнителя
+func taskEvent(eventType string, task models.Task, requestID string) map[string]interface{} {
 	event := map[string]interface{}{
 		"event":      eventType,
 		"task_id":    task.ID,
 		"title":      task.Title,
+		"status":     task.Status,
+		"due_date":   task.DueDate,
 		"subject":    task.Subject,
 		"ts":         time.Now().Format(time.RFC3339),
 		"request_id": requestID,
 	}
+	if task.Assignee != "" {
+		event["assignee"] = task.Assignee
+	}
+	return event
+}
 
+func (p *Publisher) publish(ctx context.Context, event map[string]interface{}) error {
 	body, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
@@ -98,8 +151,8 @@ func (p *Publisher) PublishEvent(ctx context.Context, eventType string, task mod
 	}
 
 	p.log.Debug("Event published",
-		zap.String("event", eventType),
-		zap.String("task_id", task.ID),
+		zap.Any("event", event["event"]),
+		zap.Any("task_id", event["task_id"]),
 		zap.String("queue", p.queue),
 	)
 
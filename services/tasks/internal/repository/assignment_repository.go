@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// assigneeFilterCondition ограничивает выборку задачами исполнителя
+func assigneeFilterCondition(filter models.TaskFilter, args []any) (string, []any) {
+	if filter.Assignee == "" {
+		return "", args
+	}
+	args = append(args, filter.Assignee)
+	return `
+          AND assignee = $` + strconv.Itoa(len(args)), args
+}
+
+// AssignedTasks возвращает задачи, назначенные пользователю: свои и чужие,
+// открытые ему, с владельцем и ролью
+func (r *sqlTaskRepository) AssignedTasks(subject string) ([]models.Task, error) {
+	rows, err := r.db.Query(`
+        SELECT `+taskColumns+`
+        FROM tasks
+        WHERE assignee = $1 AND tenant = $2 AND deleted_at IS NULL
+        ORDER BY created_at DESC
+    `, subject, r.workspace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assigned tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		if err := r.loadTaskDetails(&tasks[i]); err != nil {
+			return nil, err
+		}
+		if tasks[i].Subject == subject {
+			continue
+		}
+		access, err := r.TaskAccess(tasks[i].ID, subject)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i].Owner = access.Owner
+		tasks[i].Role = access.Role
+	}
+
+	if err := r.setDueFlags(tasks, subject); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ReleaseAssignments снимает grantee с задач owner, к которым у него больше
+// нет доступа, и возвращает их идентификаторы
+func (r *sqlTaskRepository) ReleaseAssignments(owner string, grantee string) ([]string, error) {
+	rows, err := r.db.Query(`
+        UPDATE tasks
+        SET assignee = NULL, updated_at = $3, version = version + 1
+        WHERE subject = $1 AND assignee = $2 AND NOT EXISTS (
+            SELECT 1 FROM shares s
+            WHERE s.owner = tasks.subject AND s.grantee = tasks.assignee
+              AND ((s.resource_type = 'task' AND s.resource_id = tasks.id)
+                OR (s.resource_type = 'project' AND s.resource_id = tasks.project_id))
+        )
+        RETURNING id
+    `, owner, grantee, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to release assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan task id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+	return ids, nil
+}
+
+// AddNotification ставит уведомление в очередь на доставку worker'ом
+func (r *sqlTaskRepository) AddNotification(notification models.TaskNotification) error {
+	_, err := r.db.Exec(`
+        INSERT INTO task_notifications (id, task_id, recipient, event, actor, comment_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, notification.ID, notification.TaskID, notification.Recipient, notification.Event, notification.Actor,
+		nullIfEmpty(notification.CommentID), notification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const attachmentColumns = `id, task_id, filename, content_type, size, storage_key, uploaded_by, created_at`
+
+func scanAttachment(row rowScanner) (models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	return attachment, err
+}
+
+// GetAttachments возвращает вложения задачи в порядке загрузки
+func (r *sqlTaskRepository) GetAttachments(taskID string) ([]models.Attachment, error) {
+	rows, err := r.db.Query(`
+        SELECT `+attachmentColumns+`
+        FROM task_attachments
+        WHERE task_id = $1
+        ORDER BY created_at, id
+    `, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// GetAttachment возвращает вложение задачи; пустое – вложения нет
+func (r *sqlTaskRepository) GetAttachment(taskID string, id string) (models.Attachment, error) {
+	attachment, err := scanAttachment(r.db.QueryRow(`
+        SELECT `+attachmentColumns+`
+        FROM task_attachments
+        WHERE id = $1 AND task_id = $2
+    `, id, taskID))
+	if err == sql.ErrNoRows {
+		return models.Attachment{}, nil
+	}
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// AddAttachment сохраняет метаданные загруженного файла
+func (r *sqlTaskRepository) AddAttachment(attachment models.Attachment) (models.Attachment, error) {
+	created, err := scanAttachment(r.db.QueryRow(`
+        INSERT INTO task_attachments (id, task_id, filename, content_type, size, storage_key, uploaded_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING `+attachmentColumns,
+		attachment.ID, attachment.TaskID, attachment.Filename, attachment.ContentType, attachment.Size,
+		attachment.StorageKey, attachment.UploadedBy, attachment.CreatedAt))
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("failed to create attachment: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteAttachment удаляет метаданные вложения; файл удаляет сервис
+func (r *sqlTaskRepository) DeleteAttachment(taskID string, id string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM task_attachments
+        WHERE id = $1 AND task_id = $2
+    `, id, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const commentColumns = `id, task_id, COALESCE(parent_id, ''), author, body, created_at, updated_at`
+
+func scanComment(row rowScanner) (models.Comment, error) {
+	var comment models.Comment
+	err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.ParentID,
+		&comment.Author,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	comment.Mentions = models.ParseMentions(comment.Body)
+	return comment, err
+}
+
+// GetComments возвращает комментарии задачи в порядке создания
+func (r *sqlTaskRepository) GetComments(taskID string) ([]models.Comment, error) {
+	rows, err := r.db.Query(`
+        SELECT `+commentColumns+`
+        FROM task_comments
+        WHERE task_id = $1
+        ORDER BY created_at, id
+    `, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+	return comments, nil
+}
+
+// GetComment возвращает комментарий задачи; пустой – комментария нет
+func (r *sqlTaskRepository) GetComment(taskID string, id string) (models.Comment, error) {
+	comment, err := scanComment(r.db.QueryRow(`
+        SELECT `+commentColumns+`
+        FROM task_comments
+        WHERE id = $1 AND task_id = $2
+    `, id, taskID))
+	if err == sql.ErrNoRows {
+		return models.Comment{}, nil
+	}
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return comment, nil
+}
+
+// AddComment сохраняет комментарий
+func (r *sqlTaskRepository) AddComment(comment models.Comment) (models.Comment, error) {
+	created, err := scanComment(r.db.QueryRow(`
+        INSERT INTO task_comments (id, task_id, parent_id, author, body, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $6)
+        RETURNING `+commentColumns,
+		comment.ID, comment.TaskID, nullIfEmpty(comment.ParentID), comment.Author, comment.Body, comment.CreatedAt))
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to create comment: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateComment меняет текст комментария
+func (r *sqlTaskRepository) UpdateComment(taskID string, id string, body string) (models.Comment, error) {
+	updated, err := scanComment(r.db.QueryRow(`
+        UPDATE task_comments
+        SET body = $1, updated_at = $2
+        WHERE id = $3 AND task_id = $4
+        RETURNING `+commentColumns,
+		body, time.Now(), id, taskID))
+	if err == sql.ErrNoRows {
+		return models.Comment{}, nil
+	}
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to update comment: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteComment удаляет комментарий вместе с ответами на него
+func (r *sqlTaskRepository) DeleteComment(taskID string, id string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM task_comments
+        WHERE id = $1 AND task_id = $2
+    `, id, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// loadCommentCounts заполняет число комментариев у задач subject
+func (r *sqlTaskRepository) loadCommentCounts(tasks []models.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := `
+        SELECT c.task_id, COUNT(*)
+        FROM task_comments c
+        JOIN tasks t ON t.id = c.task_id
+        WHERE t.subject = $1`
+	args := []any{subject}
+	if len(tasks) == 1 {
+		query += `
+          AND c.task_id = $2`
+		args = append(args, tasks[0].ID)
+	}
+	query += `
+        GROUP BY c.task_id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query comment counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var taskID string
+		var count int
+		if err := rows.Scan(&taskID, &count); err != nil {
+			return fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[taskID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate comment counts: %w", err)
+	}
+
+	for i := range tasks {
+		tasks[i].CommentCount = counts[tasks[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,635 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+)
+
+// Набор проверок, которые обязана проходить любая реализация TaskRepository
+
+func TestSQLiteTaskRepositoryConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) TaskRepository {
+		repo, err := NewSQLiteTaskRepository(filepath.Join(t.TempDir(), "tasks.db"))
+		if err != nil {
+			t.Fatalf("Failed to open SQLite repository: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestSQLiteTaskRepositoryMemoryConformance(t *testing.T) {
+	runConformance(t, func(t *testing.T) TaskRepository {
+		repo, err := NewSQLiteTaskRepository(SQLiteMemory)
+		if err != nil {
+			t.Fatalf("Failed to open SQLite repository: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+// Требует запущенный PostgreSQL, например:
+// TASKS_TEST_POSTGRES_DSN="host=localhost port=5432 user=postgres password=postgres dbname=postgres sslmode=disable"
+func TestPostgresTaskRepositoryConformance(t *testing.T) {
+	connStr := os.Getenv("TASKS_TEST_POSTGRES_DSN")
+	if connStr == "" {
+		t.Skip("Skipping PostgreSQL conformance test - TASKS_TEST_POSTGRES_DSN not set")
+	}
+
+	runConformance(t, func(t *testing.T) TaskRepository {
+		repo, err := NewPostgresTaskRepository(connStr)
+		if err != nil {
+			t.Fatalf("Failed to connect to PostgreSQL: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestSQLiteMigrationsAreIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	for i := 0; i < 2; i++ {
+		repo, err := NewSQLiteTaskRepository(path)
+		if err != nil {
+			t.Fatalf("Open #%d failed: %v", i+1, err)
+		}
+		repo.Close()
+	}
+}
+
+func runConformance(t *testing.T, newRepo func(t *testing.T) TaskRepository) {
+	// Уникальные subject изолируют прогоны на общей базе PostgreSQL
+	newSubject := func() string { return "conformance-" + uuid.New().String()[:8] }
+
+	newTask := func(title string) models.Task {
+		return models.Task{
+			ID:          uuid.New().String(),
+			Title:       title,
+			Description: "Conformance",
+			DueDate:     due.Day(2026, 3, 10),
+		}
+	}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		created, err := repo.Create(newTask("Write tests"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if created.ID == "" || created.Subject != subject {
+			t.Fatalf("Unexpected created task: %+v", created)
+		}
+		if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+			t.Error("Expected timestamps to be set")
+		}
+
+		got, err := repo.GetByID(created.ID, subject)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if got.Title != "Write tests" || got.Description != "Conformance" || got.Done {
+			t.Errorf("Unexpected task: %+v", got)
+		}
+		if !got.DueDate.Equal(due.Day(2026, 3, 10)) {
+			t.Errorf("Expected due date 2026-03-10, got %q", got.DueDate)
+		}
+	})
+
+	t.Run("GetByIDMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		got, err := repo.GetByID(uuid.New().String(), newSubject())
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if got.ID != "" {
+			t.Errorf("Expected empty task, got %+v", got)
+		}
+	})
+
+	t.Run("SubjectIsolation", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, other := newSubject(), newSubject()
+
+		created, err := repo.Create(newTask("Private"), owner)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		got, err := repo.GetByID(created.ID, other)
+		if err != nil || got.ID != "" {
+			t.Errorf("Expected task to be hidden from other subject, got %+v (err %v)", got, err)
+		}
+
+		tasks, err := repo.GetAll(other)
+		if err != nil || len(tasks) != 0 {
+			t.Errorf("Expected no tasks for other subject, got %d (err %v)", len(tasks), err)
+		}
+
+		deleted, err := repo.Delete(created.ID, other)
+		if err != nil || deleted.ID != "" {
+			t.Errorf("Expected delete by other subject to fail, got %+v (err %v)", deleted, err)
+		}
+	})
+
+	t.Run("GetAllNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		for _, title := range []string{"First", "Second", "Third"} {
+			if _, err := repo.Create(newTask(title), subject); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		tasks, err := repo.GetAll(subject)
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if len(tasks) != 3 {
+			t.Fatalf("Expected 3 tasks, got %d", len(tasks))
+		}
+		if tasks[0].Title != "Third" || tasks[2].Title != "First" {
+			t.Errorf("Expected newest first, got %s..%s", tasks[0].Title, tasks[2].Title)
+		}
+	})
+
+	t.Run("UpdatePartial", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		created, err := repo.Create(newTask("Draft"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		done := true
+		updated, err := repo.Update(created.ID, models.TaskUpdate{Done: &done}, subject)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if !updated.Done || updated.Title != "Draft" || updated.Description != "Conformance" {
+			t.Errorf("Unexpected updated task: %+v", updated)
+		}
+		if updated.UpdatedAt.Before(created.UpdatedAt) {
+			t.Error("Expected updated_at to move forward")
+		}
+
+		title := "Final"
+		updated, err = repo.Update(created.ID, models.TaskUpdate{Title: &title}, subject)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if updated.Title != "Final" || !updated.Done {
+			t.Errorf("Unexpected updated task: %+v", updated)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		title := "Nothing"
+		updated, err := repo.Update(uuid.New().String(), models.TaskUpdate{Title: &title}, newSubject())
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if updated.ID != "" {
+			t.Errorf("Expected empty task, got %+v", updated)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		created, err := repo.Create(newTask("Remove me"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		deleted, err := repo.Delete(created.ID, subject)
+		if err != nil || deleted.ID != created.ID || deleted.Version != created.Version+1 {
+			t.Fatalf("Expected task to be deleted with next version, got %+v (err %v)", deleted, err)
+		}
+
+		deleted, err = repo.Delete(created.ID, subject)
+		if err != nil || deleted.ID != "" {
+			t.Errorf("Expected second delete to report missing task, got %+v (err %v)", deleted, err)
+		}
+	})
+
+	t.Run("TrashAndRestore", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		created, err := repo.Create(newTask("Trash me"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if _, err := repo.Delete(created.ID, subject); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+
+		// Удаленная задача скрыта из обычных выборок
+		got, err := repo.GetByID(created.ID, subject)
+		if err != nil || got.ID != "" {
+			t.Errorf("Expected deleted task to be hidden, got %+v (err %v)", got, err)
+		}
+		if tasks, _ := repo.GetAll(subject); len(tasks) != 0 {
+			t.Errorf("Expected no active tasks, got %d", len(tasks))
+		}
+		if results, _ := repo.SearchByTitle("trash", subject); len(results) != 0 {
+			t.Errorf("Expected deleted task to be excluded from search, got %d", len(results))
+		}
+
+		trash, err := repo.GetTrash(subject)
+		if err != nil {
+			t.Fatalf("GetTrash failed: %v", err)
+		}
+		if len(trash) != 1 || trash[0].ID != created.ID || trash[0].DeletedAt == nil {
+			t.Fatalf("Expected task in trash, got %+v", trash)
+		}
+
+		restored, err := repo.Restore(created.ID, subject)
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if restored.ID != created.ID || restored.DeletedAt != nil {
+			t.Errorf("Unexpected restored task: %+v", restored)
+		}
+
+		again, err := repo.Restore(created.ID, subject)
+		if err != nil || again.ID != "" {
+			t.Errorf("Expected restore of active task to find nothing, got %+v (err %v)", again, err)
+		}
+		if trash, _ := repo.GetTrash(subject); len(trash) != 0 {
+			t.Errorf("Expected empty trash, got %d", len(trash))
+		}
+	})
+
+	t.Run("TagsAndFiltering", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		both, _ := repo.Create(newTask("Both"), subject)
+		workOnly, _ := repo.Create(newTask("Work only"), subject)
+		if _, err := repo.Create(newTask("Untagged"), subject); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		if err := repo.AddTaskTags(both.ID, []string{"work", "urgent"}, subject); err != nil {
+			t.Fatalf("AddTaskTags failed: %v", err)
+		}
+		// Повторное назначение не создает дублей
+		if err := repo.AddTaskTags(workOnly.ID, []string{"work", "work"}, subject); err != nil {
+			t.Fatalf("AddTaskTags failed: %v", err)
+		}
+
+		got, _ := repo.GetByID(both.ID, subject)
+		if len(got.Tags) != 2 || got.Tags[0] != "urgent" || got.Tags[1] != "work" {
+			t.Errorf("Expected tags [urgent work], got %v", got.Tags)
+		}
+
+		all, err := repo.List(subject, models.TaskFilter{Tags: []string{"work", "urgent"}, MatchAllTags: true})
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(all) != 1 || all[0].ID != both.ID {
+			t.Errorf("Expected only 'Both' for AND filter, got %+v", all)
+		}
+
+		anyOf, _ := repo.List(subject, models.TaskFilter{Tags: []string{"work", "urgent"}})
+		if len(anyOf) != 2 {
+			t.Errorf("Expected 2 tasks for OR filter, got %d", len(anyOf))
+		}
+
+		tags, err := repo.GetTags(subject)
+		if err != nil {
+			t.Fatalf("GetTags failed: %v", err)
+		}
+		if len(tags) != 2 || tags[1].Name != "work" || tags[1].TaskCount != 2 || tags[1].Color != models.DefaultTagColor {
+			t.Errorf("Unexpected tags: %+v", tags)
+		}
+
+		if _, err := repo.CreateTag(models.Tag{Name: "work", Color: "#ff0000"}, subject); !errors.Is(err, models.ErrTagExists) {
+			t.Errorf("Expected ErrTagExists, got %v", err)
+		}
+
+		removed, err := repo.RemoveTaskTag(both.ID, "urgent", subject)
+		if err != nil || !removed {
+			t.Errorf("Expected tag to be removed, got %v (err %v)", removed, err)
+		}
+
+		// Удаление метки снимает ее со всех задач
+		if deleted, err := repo.DeleteTag(tags[1].ID, subject); err != nil || !deleted {
+			t.Fatalf("Expected tag to be deleted, got %v (err %v)", deleted, err)
+		}
+		got, _ = repo.GetByID(workOnly.ID, subject)
+		if len(got.Tags) != 0 {
+			t.Errorf("Expected no tags after tag deletion, got %v", got.Tags)
+		}
+	})
+
+	t.Run("ProjectsAndArchive", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		first, err := repo.CreateProject(models.Project{Name: "Home"}, subject)
+		if err != nil {
+			t.Fatalf("CreateProject failed: %v", err)
+		}
+		second, _ := repo.CreateProject(models.Project{Name: "Work"}, subject)
+		if second.Position <= first.Position {
+			t.Errorf("Expected new project at the end, got positions %d, %d", first.Position, second.Position)
+		}
+
+		task := newTask("In project")
+		task.ProjectID = second.ID
+		inProject, err := repo.Create(task, subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if inProject.ProjectID != second.ID {
+			t.Errorf("Expected project %s, got %q", second.ID, inProject.ProjectID)
+		}
+		loose, _ := repo.Create(newTask("Loose"), subject)
+
+		// Перенос задачи в другой проект
+		target := first.ID
+		moved, err := repo.Update(loose.ID, models.TaskUpdate{ProjectID: &target}, subject)
+		if err != nil || moved.ProjectID != first.ID {
+			t.Fatalf("Expected task to move to %s, got %+v (err %v)", first.ID, moved, err)
+		}
+
+		byProject, _ := repo.List(subject, models.TaskFilter{ProjectID: second.ID})
+		if len(byProject) != 1 || byProject[0].ID != inProject.ID {
+			t.Errorf("Expected one task in project, got %+v", byProject)
+		}
+
+		archived := true
+		if _, err := repo.UpdateProject(second.ID, models.ProjectUpdate{Archived: &archived}, subject); err != nil {
+			t.Fatalf("UpdateProject failed: %v", err)
+		}
+
+		visible, _ := repo.GetAll(subject)
+		if len(visible) != 1 || visible[0].ID != loose.ID {
+			t.Errorf("Expected archived project tasks to be hidden, got %+v", visible)
+		}
+		if projects, _ := repo.GetProjects(subject, false); len(projects) != 1 {
+			t.Errorf("Expected archived project to be hidden, got %d projects", len(projects))
+		}
+		projects, _ := repo.GetProjects(subject, true)
+		if len(projects) != 2 || projects[1].TaskCount != 1 {
+			t.Errorf("Unexpected projects with archived: %+v", projects)
+		}
+
+		// После удаления проекта задачи остаются без проекта
+		if deleted, err := repo.DeleteProject(second.ID, subject); err != nil || !deleted {
+			t.Fatalf("Expected project to be deleted, got %v (err %v)", deleted, err)
+		}
+		got, _ := repo.GetByID(inProject.ID, subject)
+		if got.ID == "" || got.ProjectID != "" {
+			t.Errorf("Expected task without project, got %+v", got)
+		}
+	})
+
+	t.Run("SearchByTitleCaseInsensitive", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		for _, title := range []string{"Go Task", "Docker Task", "K8s Task"} {
+			if _, err := repo.Create(newTask(title), subject); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+		}
+
+		results, err := repo.SearchByTitle("go", subject)
+		if err != nil {
+			t.Fatalf("SearchByTitle failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Title != "Go Task" {
+			t.Errorf("Expected only 'Go Task', got %+v", results)
+		}
+
+		results, err = repo.SearchByTitle("TASK", subject)
+		if err != nil {
+			t.Fatalf("SearchByTitle failed: %v", err)
+		}
+		if len(results) != 3 {
+			t.Errorf("Expected 3 results, got %d", len(results))
+		}
+
+		// Уязвимая версия ищет так же (ILIKE в PostgreSQL)
+		results, err = repo.SearchByTitleVulnerable("DOCKER", subject)
+		if err != nil {
+			t.Fatalf("SearchByTitleVulnerable failed: %v", err)
+		}
+		if len(results) != 1 || results[0].Title != "Docker Task" {
+			t.Errorf("Expected only 'Docker Task', got %+v", results)
+		}
+	})
+
+	t.Run("VersionConflict", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		created, err := repo.Create(newTask("Versioned"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if created.Version != 1 {
+			t.Fatalf("Expected version 1, got %d", created.Version)
+		}
+
+		title := "Updated"
+		expected := created.Version
+		updated, err := repo.Update(created.ID, models.TaskUpdate{Title: &title, Version: &expected}, subject)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if updated.Version != 2 {
+			t.Errorf("Expected version 2, got %d", updated.Version)
+		}
+
+		// Повтор с устаревшей версией
+		title = "Stale"
+		_, err = repo.Update(created.ID, models.TaskUpdate{Title: &title, Version: &expected}, subject)
+		if !errors.Is(err, models.ErrVersionConflict) {
+			t.Fatalf("Expected ErrVersionConflict, got %v", err)
+		}
+
+		got, _ := repo.GetByID(created.ID, subject)
+		if got.Title != "Updated" || got.Version != 2 {
+			t.Errorf("Stale update must not apply, got %+v", got)
+		}
+	})
+
+	t.Run("StatsAggregates", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		before := time.Now().Add(-time.Minute)
+		repo.Create(newTask("Open"), subject)
+		second, _ := repo.Create(newTask("Reopened"), subject)
+		done := true
+		if _, err := repo.Update(second.ID, models.TaskUpdate{Done: &done}, subject); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		after := time.Now().Add(time.Minute)
+
+		timeline, err := repo.GetTaskTimeline(subject, []time.Time{before, after})
+		if err != nil {
+			t.Fatalf("GetTaskTimeline failed: %v", err)
+		}
+		if timeline.CreatedBefore[0] != 0 || timeline.CreatedBefore[1] != 2 || timeline.CompletedBefore[1] != 1 {
+			t.Errorf("Unexpected timeline: %+v", timeline)
+		}
+
+		count, _, err := repo.GetCompletionTime(subject, before, after)
+		if err != nil || count != 1 {
+			t.Fatalf("Expected one completed task, got %d, %v", count, err)
+		}
+
+		// Повторное открытие сбрасывает время выполнения
+		done = false
+		if _, err := repo.Update(second.ID, models.TaskUpdate{Done: &done}, subject); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if count, _, _ := repo.GetCompletionTime(subject, before, after); count != 0 {
+			t.Errorf("Expected reopened task to drop completion time, got %d", count)
+		}
+
+		counts, err := repo.GetStatusCounts(subject)
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		if err != nil || total != 2 {
+			t.Errorf("Expected 2 tasks by status, got %v, %v", counts, err)
+		}
+		overdue, err := repo.GetOverdueCounts(subject, time.UTC)
+		if err != nil || overdue[""] != 2 {
+			t.Errorf("Expected 2 overdue tasks without priority, got %v, %v", overdue, err)
+		}
+	})
+
+	t.Run("TenantScope", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+		tenant := "tenant-" + uuid.New().String()[:8]
+		scoped := repo.ForTenant(tenant)
+
+		if err := scoped.JoinWorkspace(subject, tenant); err != nil {
+			t.Fatalf("JoinWorkspace failed: %v", err)
+		}
+		if member, err := repo.MemberTenant(subject); err != nil || member != tenant {
+			t.Errorf("Expected member of %s, got %q, %v", tenant, member, err)
+		}
+
+		task, err := scoped.Create(newTask("Scoped"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		project, err := scoped.CreateProject(models.Project{Name: "Scoped"}, subject)
+		if err != nil {
+			t.Fatalf("CreateProject failed: %v", err)
+		}
+
+		if got, _ := repo.GetByID(task.ID, subject); got.ID != "" {
+			t.Error("Expected task to be hidden outside its workspace")
+		}
+		if access, _ := repo.ProjectAccess(project.ID, subject); access.Owner != "" {
+			t.Error("Expected project to be hidden outside its workspace")
+		}
+		if got, err := scoped.GetByID(task.ID, subject); err != nil || got.ID != task.ID {
+			t.Errorf("Expected task in its workspace, got %+v, %v", got, err)
+		}
+
+		// Транзакция сохраняет пространство репозитория
+		err = scoped.InTx(func(tx TaskRepository) error {
+			tasks, err := tx.List(subject, models.TaskFilter{})
+			if err == nil && len(tasks) != 1 {
+				t.Errorf("Expected 1 task in transaction, got %d", len(tasks))
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("InTx failed: %v", err)
+		}
+
+		workspace, err := repo.GetWorkspace(tenant)
+		if err != nil || workspace.Usage != (models.WorkspaceUsage{Members: 1, Tasks: 1, Projects: 1}) || workspace.MaxTasks != nil {
+			t.Errorf("Unexpected workspace: %+v, %v", workspace, err)
+		}
+
+		// Задачи в корзине не расходуют квоту
+		err = scoped.InTx(func(tx TaskRepository) error {
+			if err := tx.LockWorkspace(tenant); err != nil {
+				return err
+			}
+			_, err := tx.Delete(task.ID, subject)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Failed to delete task: %v", err)
+		}
+		if workspace, _ := repo.GetWorkspace(tenant); workspace.Usage.Tasks != 0 {
+			t.Errorf("Expected trashed task not to be counted, got %d", workspace.Usage.Tasks)
+		}
+	})
+
+	t.Run("TimeEntries", func(t *testing.T) {
+		repo := newRepo(t)
+		subject := newSubject()
+
+		first, err := repo.Create(newTask("First"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		second, err := repo.Create(newTask("Second"), subject)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		// Второй запущенный таймер пользователя не сохраняется
+		running, err := repo.AddTimeEntry(models.TimeEntry{TaskID: first.ID, StartedAt: time.Now()}, subject)
+		if err != nil || running.ID == "" {
+			t.Fatalf("Failed to start timer: %+v, %v", running, err)
+		}
+		if entry, err := repo.AddTimeEntry(models.TimeEntry{TaskID: second.ID, StartedAt: time.Now()}, subject); err != nil || entry.ID != "" {
+			t.Errorf("Expected second running timer to be rejected, got %+v, %v", entry, err)
+		}
+
+		// Период отчета отбирается в запросе; граница to не входит в период
+		day := time.Date(2026, 3, 10, 22, 30, 0, 0, time.UTC)
+		for _, startedAt := range []time.Time{day, day.Add(2 * time.Hour)} {
+			endedAt := startedAt.Add(time.Hour)
+			_, err := repo.AddTimeEntry(models.TimeEntry{TaskID: second.ID, StartedAt: startedAt, EndedAt: &endedAt, DurationSeconds: 3600}, subject)
+			if err != nil {
+				t.Fatalf("Failed to log time: %v", err)
+			}
+		}
+		logged, err := repo.GetLoggedTime(subject, day, day.Add(2*time.Hour))
+		if err != nil {
+			t.Fatalf("GetLoggedTime failed: %v", err)
+		}
+		if len(logged) != 1 || !logged[0].StartedAt.Equal(day) || logged[0].TaskTitle != "Second" {
+			t.Errorf("Expected one entry in period, got %+v", logged)
+		}
+		if logged, _ := repo.GetLoggedTime(subject, time.Time{}, time.Time{}); len(logged) != 2 {
+			t.Errorf("Expected 2 logged entries without period, got %d", len(logged))
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// GetDependencies возвращает все зависимости между задачами пользователя,
+// в том числе задачами в корзине: их можно восстановить
+func (r *sqlTaskRepository) GetDependencies(subject string) ([]models.TaskDependency, error) {
+	rows, err := r.db.Query(`
+        SELECT d.task_id, d.blocked_by_id
+        FROM task_dependencies d
+        JOIN tasks t ON t.id = d.task_id
+        WHERE t.subject = $1
+        ORDER BY d.created_at
+    `, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []models.TaskDependency
+	for rows.Next() {
+		var dep models.TaskDependency
+		if err := rows.Scan(&dep.TaskID, &dep.BlockedByID); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dependencies: %w", err)
+	}
+
+	return deps, nil
+}
+
+// AddDependency отмечает, что taskID заблокирована blockedByID.
+// Существующая зависимость не дублируется.
+func (r *sqlTaskRepository) AddDependency(taskID string, blockedByID string) error {
+	_, err := r.db.Exec(`
+        INSERT INTO task_dependencies (task_id, blocked_by_id, created_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (task_id, blocked_by_id) DO NOTHING
+    `, taskID, blockedByID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlTaskRepository) RemoveDependency(taskID string, blockedByID string, subject string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM task_dependencies
+        WHERE task_id = $1 AND blocked_by_id = $2
+          AND task_id IN (SELECT id FROM tasks WHERE subject = $3)
+    `, taskID, blockedByID, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// loadDependencies заполняет blocked_by, blocks и blocked. Зависимости
+// от задач в корзине не учитываются.
+func (r *sqlTaskRepository) loadDependencies(tasks []models.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := `
+        SELECT d.task_id, d.blocked_by_id, b.done
+        FROM task_dependencies d
+        JOIN tasks t ON t.id = d.task_id
+        JOIN tasks b ON b.id = d.blocked_by_id
+        WHERE t.subject = $1 AND t.deleted_at IS NULL AND b.deleted_at IS NULL`
+	args := []any{subject}
+	// Для одной задачи не нужно читать все зависимости пользователя
+	if len(tasks) == 1 {
+		query += `
+          AND (d.task_id = $2 OR d.blocked_by_id = $2)`
+		args = append(args, tasks[0].ID)
+	}
+	query += `
+        ORDER BY d.created_at`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query task dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	blockedBy := make(map[string][]string)
+	blocks := make(map[string][]string)
+	blocked := make(map[string]bool)
+	for rows.Next() {
+		var taskID, blockerID string
+		var blockerDone bool
+		if err := rows.Scan(&taskID, &blockerID, &blockerDone); err != nil {
+			return fmt.Errorf("failed to scan task dependency: %w", err)
+		}
+		blockedBy[taskID] = append(blockedBy[taskID], blockerID)
+		blocks[blockerID] = append(blocks[blockerID], taskID)
+		if !blockerDone {
+			blocked[taskID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate task dependencies: %w", err)
+	}
+
+	for i := range tasks {
+		id := tasks[i].ID
+		tasks[i].BlockedBy = append([]string{}, blockedBy[id]...)
+		tasks[i].Blocks = append([]string{}, blocks[id]...)
+		tasks[i].Blocked = blocked[id]
+	}
+
+	return nil
+}
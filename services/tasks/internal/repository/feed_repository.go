@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// GetCalendarFeed возвращает ссылку на календарь пользователя без токена;
+// Enabled == false – ссылки нет
+func (r *sqlTaskRepository) GetCalendarFeed(subject string) (models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := r.db.QueryRow(`
+        SELECT created_at FROM calendar_feeds WHERE subject = $1
+    `, subject).Scan(&feed.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.CalendarFeed{}, nil
+	}
+	if err != nil {
+		return models.CalendarFeed{}, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	feed.Enabled = true
+	return feed, nil
+}
+
+// SaveCalendarFeed заменяет токен ссылки пользователя на новый; ссылка
+// открывает задачи пространства репозитория
+func (r *sqlTaskRepository) SaveCalendarFeed(subject string, tokenHash string) (models.CalendarFeed, error) {
+	now := time.Now()
+	_, err := r.db.Exec(`
+        INSERT INTO calendar_feeds (subject, token_hash, created_at, tenant)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (subject) DO UPDATE
+        SET token_hash = excluded.token_hash, created_at = excluded.created_at, tenant = excluded.tenant
+    `, subject, tokenHash, now, r.workspace())
+	if err != nil {
+		return models.CalendarFeed{}, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+
+	return models.CalendarFeed{Enabled: true, CreatedAt: &now}, nil
+}
+
+func (r *sqlTaskRepository) DeleteCalendarFeed(subject string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM calendar_feeds WHERE subject = $1`, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CalendarFeedOwner находит пользователя и его пространство по хешу токена
+// ссылки в любом пространстве; пустой Subject – ссылки нет или она заменена
+// новой
+func (r *sqlTaskRepository) CalendarFeedOwner(tokenHash string) (models.FeedOwner, error) {
+	var owner models.FeedOwner
+	err := r.db.QueryRow(`
+        SELECT subject, tenant, created_at FROM calendar_feeds WHERE token_hash = $1
+    `, tokenHash).Scan(&owner.Subject, &owner.Tenant, &owner.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.FeedOwner{}, nil
+	}
+	if err != nil {
+		return models.FeedOwner{}, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	return owner, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/idempotency"
+)
+
+// IdempotencyRepository – реализация idempotency.Store поверх базы задач
+// (PostgreSQL или SQLite), если Redis недоступен
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+func (r *IdempotencyRepository) Claim(ctx context.Context, key, fingerprint string, ttl time.Duration) (*idempotency.Record, bool, error) {
+	now := time.Now().UTC()
+
+	// Заодно удаляются все истекшие ключи
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, now); err != nil {
+		return nil, false, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+        INSERT INTO idempotency_keys (idempotency_key, fingerprint, status_code, created_at, expires_at)
+        VALUES ($1, $2, 0, $3, $4)
+        ON CONFLICT (idempotency_key) DO NOTHING
+    `, key, fingerprint, now, now.Add(ttl))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if inserted > 0 {
+		return nil, true, nil
+	}
+
+	var record idempotency.Record
+	var headers, body sql.NullString
+	err = r.db.QueryRowContext(ctx, `
+        SELECT fingerprint, status_code, headers, body
+        FROM idempotency_keys
+        WHERE idempotency_key = $1
+    `, key).Scan(&record.Fingerprint, &record.StatusCode, &headers, &body)
+	if err == sql.ErrNoRows {
+		// Ключ освободили между INSERT и SELECT
+		return r.Claim(ctx, key, fingerprint, ttl)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	if headers.Valid && headers.String != "" {
+		if err := json.Unmarshal([]byte(headers.String), &record.Header); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal headers: %w", err)
+		}
+	}
+	record.Body = []byte(body.String)
+
+	return &record, false, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, record idempotency.Record, ttl time.Duration) error {
+	headers, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal headers: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+        UPDATE idempotency_keys
+        SET status_code = $1, headers = $2, body = $3, expires_at = $4
+        WHERE idempotency_key = $5 AND fingerprint = $6
+    `, record.StatusCode, string(headers), string(record.Body), time.Now().UTC().Add(ttl), key, record.Fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE idempotency_key = $1`, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
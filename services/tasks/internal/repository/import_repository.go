@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// CreateImport ставит импорт в очередь worker'а вместе с проверенными
+// записями и отчетом по уже отклоненным строкам
+func (r *sqlTaskRepository) CreateImport(job models.ImportJob) (models.ImportJob, error) {
+	payload, err := json.Marshal(job.Pending)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to marshal import payload: %w", err)
+	}
+	report, err := json.Marshal(job.Report)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to marshal import report: %w", err)
+	}
+
+	createdAt := time.Now()
+	job.Tenant = r.workspace()
+	_, err = r.db.Exec(`
+        INSERT INTO task_imports (id, subject, format, status, timezone, payload, report, created_at, tenant)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `, job.ID, job.Subject, job.Format, job.Status, job.Timezone, string(payload), string(report), createdAt, job.Tenant)
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	job.CreatedAt = &createdAt
+	return job, nil
+}
+
+// GetImport возвращает импорт пользователя без записей; пустой – импорта нет
+func (r *sqlTaskRepository) GetImport(id string, subject string) (models.ImportJob, error) {
+	job := models.ImportJob{ID: id, Subject: subject, Tenant: r.workspace()}
+	var report string
+	var errorMessage sql.NullString
+	var createdAt time.Time
+	var finishedAt sql.NullTime
+
+	err := r.db.QueryRow(`
+        SELECT format, status, timezone, report, error, created_at, finished_at
+        FROM task_imports
+        WHERE id = $1 AND subject = $2 AND tenant = $3
+    `, id, subject, job.Tenant).Scan(&job.Format, &job.Status, &job.Timezone, &report, &errorMessage, &createdAt, &finishedAt)
+	if err == sql.ErrNoRows {
+		return models.ImportJob{}, nil
+	}
+	if err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(report), &job.Report); err != nil {
+		return models.ImportJob{}, fmt.Errorf("failed to unmarshal import report: %w", err)
+	}
+	job.Error = errorMessage.String
+	job.CreatedAt = &createdAt
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
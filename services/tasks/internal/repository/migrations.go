@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// dialect описывает отличия СУБД, которые нужно учитывать при выполнении
+// общих миграций и запросов
+type dialect struct {
+	name string
+	// Блокировка, исключающая одновременный прогон миграций несколькими репликами
+	migrationLock string
+	// Замены в DDL миграций (общая схема пишется в синтаксисе PostgreSQL)
+	ddlReplacements []string
+	// Выражение секунд с начала эпохи для колонки времени (формат для fmt)
+	epoch string
+	// Блокировка прочитанной строки до конца транзакции; SQLite выполняет
+	// транзакции по одной
+	rowLock string
+	// Сравнение колонки с шаблоном LIKE без учета регистра (формат для fmt:
+	// колонка, шаблон); в SQLite нет ILIKE
+	ilike string
+}
+
+var (
+	postgresDialect = dialect{
+		name:          "postgres",
+		migrationLock: "SELECT pg_advisory_xact_lock(72010001)",
+		epoch:         "EXTRACT(EPOCH FROM %s)",
+		rowLock:       " FOR UPDATE",
+		ilike:         "%s ILIKE %s",
+	}
+
+	sqliteDialect = dialect{
+		name: "sqlite",
+		ddlReplacements: []string{
+			// Драйвер SQLite разбирает время только в колонках с типом TIMESTAMP
+			"TIMESTAMP WITH TIME ZONE", "TIMESTAMP",
+			// SQLite не поддерживает IF NOT EXISTS для колонок; в PostgreSQL
+			// колонку может заранее добавить GraphQL сервис
+			"ADD COLUMN IF NOT EXISTS", "ADD COLUMN",
+		},
+		epoch: "unixepoch(%s)",
+		ilike: "LOWER(%s) LIKE LOWER(%s)",
+	}
+)
+
+func (d dialect) rewriteDDL(ddl string) string {
+	if len(d.ddlReplacements) == 0 {
+		return ddl
+	}
+	return strings.NewReplacer(d.ddlReplacements...).Replace(ddl)
+}
+
+// migrate применяет еще не выполненные миграции из каталога migrations
+// в лексикографическом порядке имен файлов
+func migrate(db *sql.DB, d dialect) error {
+	_, err := db.Exec(d.rewriteDDL(`
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version VARCHAR(255) PRIMARY KEY,
+            applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        )
+    `))
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+		if err := applyMigration(db, d, name, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(db *sql.DB, d dialect, name, version string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", version, err)
+	}
+	defer tx.Rollback()
+
+	if d.migrationLock != "" {
+		if _, err := tx.Exec(d.migrationLock); err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+	}
+
+	var applied int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, version).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to check migration %s: %w", version, err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	ddl, err := migrationsFS.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", version, err)
+	}
+
+	if _, err := tx.Exec(d.rewriteDDL(string(ddl))); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", version, err)
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", version, err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,2 @@
+-- Версия задачи для оптимистичной блокировки (ETag / If-Match)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,12 @@
+-- Ключи идемпотентности (Idempotency-Key) с сохраненными ответами
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(400) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    headers TEXT,
+    body TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
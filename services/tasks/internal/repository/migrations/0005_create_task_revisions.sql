@@ -0,0 +1,15 @@
+-- История изменений задачи: ревизия N соответствует версии задачи N
+CREATE TABLE IF NOT EXISTS task_revisions (
+    task_id VARCHAR(50) NOT NULL,
+    revision INTEGER NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    request_id VARCHAR(100),
+    changes TEXT NOT NULL,
+    snapshot TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_revisions_subject ON task_revisions(subject);
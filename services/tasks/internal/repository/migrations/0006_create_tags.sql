@@ -0,0 +1,17 @@
+-- Метки задач: набор меток свой у каждого пользователя
+CREATE TABLE IF NOT EXISTS tags (
+    id VARCHAR(50) PRIMARY KEY,
+    subject VARCHAR(100) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#808080',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subject, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag_id VARCHAR(50) NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id);
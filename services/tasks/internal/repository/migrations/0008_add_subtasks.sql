@@ -0,0 +1,20 @@
+-- Иерархия задач: при окончательном удалении родителя подзадачи становятся корневыми
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id VARCHAR(50) REFERENCES tasks(id) ON DELETE SET NULL;
+
+-- Автоматически выполнять задачу, когда выполнены все подзадачи и пункты чек-листа
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS auto_complete BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+
+-- Пункты чек-листа: легкие шаги задачи без собственной истории и меток
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task_id ON checklist_items(task_id);
@@ -0,0 +1,9 @@
+-- Зависимости: задача task_id заблокирована задачей blocked_by_id
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by ON task_dependencies(blocked_by_id);
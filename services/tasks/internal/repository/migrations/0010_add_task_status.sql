@@ -0,0 +1,9 @@
+-- Статус задачи по процессу проекта; done сохраняется для старых клиентов
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status VARCHAR(50) NOT NULL DEFAULT 'todo';
+
+UPDATE tasks SET status = 'done' WHERE done = TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_subject_status ON tasks(subject, status);
+
+-- Процесс проекта в JSON; NULL – процесс по умолчанию
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS workflow TEXT;
@@ -0,0 +1,15 @@
+-- Серии повторяющихся задач: правило RRULE и шаблон следующих вхождений (JSON)
+CREATE TABLE IF NOT EXISTS task_series (
+    id VARCHAR(50) PRIMARY KEY,
+    subject VARCHAR(100) NOT NULL,
+    rrule VARCHAR(500) NOT NULL,
+    start_date VARCHAR(10) NOT NULL,
+    template TEXT NOT NULL,
+    stopped_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id VARCHAR(50) REFERENCES task_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_series ON tasks(series_id);
@@ -0,0 +1,20 @@
+-- Напоминания о сроке задачи. fire_at считает tasks сервис по сроку задачи
+-- (NULL – срока нет), рассылает worker: claimed_by/claimed_until – аренда
+-- напоминания одним worker'ом, fired_at – напоминание доставлено
+CREATE TABLE IF NOT EXISTS task_reminders (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    minutes_before INTEGER NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    fire_at TIMESTAMP WITH TIME ZONE,
+    fired_at TIMESTAMP WITH TIME ZONE,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_reminders_task ON task_reminders(task_id);
+CREATE INDEX IF NOT EXISTS idx_task_reminders_pending ON task_reminders(fire_at) WHERE fired_at IS NULL;
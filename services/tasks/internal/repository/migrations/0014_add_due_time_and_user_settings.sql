@@ -0,0 +1,12 @@
+-- Срок со временем: due_at – момент срока (NULL – срок на весь день),
+-- due_date – календарный день срока в часовом поясе пользователя
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due ON tasks(subject, due_date);
+
+-- Настройки пользователя: часовой пояс IANA для сроков
+CREATE TABLE IF NOT EXISTS user_settings (
+    subject VARCHAR(100) PRIMARY KEY,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+-- Доступ других пользователей к задаче или проекту (ко всем его задачам).
+-- Ресурс ссылается на tasks или projects по resource_type, поэтому без
+-- внешнего ключа: доступ к удаленному ресурсу не проверяется.
+CREATE TABLE IF NOT EXISTS shares (
+    resource_type VARCHAR(20) NOT NULL,
+    resource_id VARCHAR(50) NOT NULL,
+    owner VARCHAR(100) NOT NULL,
+    grantee VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (resource_type, resource_id, grantee)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_grantee ON shares(grantee, resource_type);
@@ -0,0 +1,23 @@
+-- Исполнитель задачи: владелец или пользователь с доступом к задаче
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee ON tasks(assignee);
+
+-- Уведомления исполнителю о назначении и снятии задачи. Создает tasks
+-- сервис, доставляет worker: claimed_by/claimed_until – аренда уведомления
+-- одним worker'ом, sent_at – уведомление доставлено
+CREATE TABLE IF NOT EXISTS task_notifications (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    recipient VARCHAR(100) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_notifications_pending ON task_notifications(created_at) WHERE sent_at IS NULL;
@@ -0,0 +1,16 @@
+-- Обсуждение задачи: комментарии и ответы на них (parent_id). Удаление
+-- комментария удаляет и ответы на него.
+CREATE TABLE IF NOT EXISTS task_comments (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    parent_id VARCHAR(50) REFERENCES task_comments(id) ON DELETE CASCADE,
+    author VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task ON task_comments(task_id, created_at);
+
+-- Комментарий, в котором упомянут получатель уведомления
+ALTER TABLE task_notifications ADD COLUMN IF NOT EXISTS comment_id VARCHAR(50);
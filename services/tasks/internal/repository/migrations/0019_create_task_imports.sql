@@ -0,0 +1,22 @@
+-- Большие импорты задач выполняет worker: payload – проверенные записи
+-- (JSON), report – отчет по строкам файла, timezone – часовой пояс
+-- пользователя для сроков. claimed_by/claimed_until – аренда импорта одним
+-- worker'ом, как у напоминаний.
+CREATE TABLE IF NOT EXISTS task_imports (
+    id VARCHAR(50) PRIMARY KEY,
+    subject VARCHAR(100) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    payload TEXT NOT NULL,
+    report TEXT NOT NULL,
+    error VARCHAR(255),
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_imports_subject ON task_imports(subject, created_at);
+CREATE INDEX IF NOT EXISTS idx_task_imports_queued ON task_imports(created_at) WHERE status = 'queued';
@@ -0,0 +1,7 @@
+-- Секретные ссылки на календарь задач пользователя. Хранится только хеш
+-- токена (SHA-256): новая ссылка заменяет старую, и та перестает работать.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    subject VARCHAR(100) PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
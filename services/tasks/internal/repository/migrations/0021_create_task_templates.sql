@@ -0,0 +1,13 @@
+-- Шаблоны деревьев задач: template – корневая задача шаблона с подзадачами,
+-- метками, чек-листами и смещениями сроков (JSON)
+CREATE TABLE IF NOT EXISTS task_templates (
+    id VARCHAR(50) PRIMARY KEY,
+    subject VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    template TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_templates_subject ON task_templates(subject, name);
@@ -0,0 +1,7 @@
+-- Время выполнения задачи для статистики; NULL – задача не выполнена.
+-- Для уже выполненных задач берется время последнего изменения.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE tasks SET completed_at = updated_at WHERE done = TRUE AND completed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_subject_completed ON tasks(subject, completed_at);
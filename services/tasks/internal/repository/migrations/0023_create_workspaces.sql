@@ -0,0 +1,28 @@
+-- Рабочие пространства (tenant) изолируют команды друг от друга. Пространство
+-- пользователя сообщает auth сервис; установки для одной команды работают в
+-- пространстве default. Квоты NULL – значения сервиса по умолчанию.
+CREATE TABLE IF NOT EXISTS workspaces (
+    id VARCHAR(100) PRIMARY KEY,
+    max_tasks INTEGER,
+    max_projects INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO workspaces (id) VALUES ('default') ON CONFLICT (id) DO NOTHING;
+
+-- Пространство пользователя по последнему запросу: по нему проверяется, что
+-- задачей делятся с участником того же пространства
+CREATE TABLE IF NOT EXISTS workspace_members (
+    subject VARCHAR(100) PRIMARY KEY,
+    tenant VARCHAR(100) NOT NULL REFERENCES workspaces(id),
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Существующие данные попадают в пространство default
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE task_imports ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE calendar_feeds ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_subject ON tasks(tenant, subject);
+CREATE INDEX IF NOT EXISTS idx_projects_tenant_subject ON projects(tenant, subject);
@@ -0,0 +1,12 @@
+-- У пользователя один запущенный таймер. Лишние запущенные записи прежних
+-- гонок закрываются без учтенного времени: остается самая поздняя.
+UPDATE time_entries
+SET ended_at = started_at
+WHERE ended_at IS NULL AND EXISTS (
+    SELECT 1 FROM time_entries newer
+    WHERE newer.subject = time_entries.subject AND newer.ended_at IS NULL
+      AND (newer.started_at > time_entries.started_at
+           OR (newer.started_at = time_entries.started_at AND newer.id > time_entries.id))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(subject) WHERE ended_at IS NULL;
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const projectColumns = `id, name, description, archived, position, workflow, subject, created_at, updated_at`
+
+// nullIfEmpty сохраняет пустую ссылку как NULL (для внешних ключей)
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// projectFilterCondition ограничивает выборку проектом из фильтра, а без
+// него скрывает задачи архивных проектов
+func projectFilterCondition(filter models.TaskFilter, args []any) (string, []any) {
+	if filter.ProjectID != "" {
+		args = append(args, filter.ProjectID)
+		return `
+          AND project_id = $` + strconv.Itoa(len(args)), args
+	}
+
+	return `
+          AND (project_id IS NULL OR project_id NOT IN (
+            SELECT id FROM projects WHERE subject = $1 AND archived = TRUE
+          ))`, args
+}
+
+// statusFilterCondition ограничивает выборку задачами в статусе
+func statusFilterCondition(filter models.TaskFilter, args []any) (string, []any) {
+	if filter.Status == "" {
+		return "", args
+	}
+	args = append(args, filter.Status)
+	return `
+          AND status = $` + strconv.Itoa(len(args)), args
+}
+
+// dueFilterCondition отбирает просроченные задачи или задачи со сроком
+// сегодня. Срок на весь день сравнивается с сегодняшней датой в часовом поясе
+// пользователя, срок-момент – с текущим временем.
+func dueFilterCondition(filter models.TaskFilter, loc *time.Location, args []any) (string, []any) {
+	if filter.Due == "" {
+		return "", args
+	}
+	now := time.Now()
+	today := now.In(loc).Format(time.DateOnly)
+
+	switch filter.Due {
+	case models.DueFilterOverdue:
+		args = append(args, now.UTC(), today)
+		return `
+          AND done = FALSE
+          AND ((due_at IS NOT NULL AND due_at <= $` + strconv.Itoa(len(args)-1) + `)
+            OR (due_at IS NULL AND due_date < $` + strconv.Itoa(len(args)) + `))`, args
+	case models.DueFilterToday:
+		args = append(args, today)
+		return `
+          AND due_date = $` + strconv.Itoa(len(args)), args
+	}
+	return "", args
+}
+
+// workflowValue сохраняет процесс проекта как JSON; nil – процесс по умолчанию
+func workflowValue(workflow *models.Workflow) (any, error) {
+	if workflow == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(workflow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workflow: %w", err)
+	}
+	return string(data), nil
+}
+
+func parseWorkflow(value sql.NullString) (*models.Workflow, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var workflow models.Workflow
+	if err := json.Unmarshal([]byte(value.String), &workflow); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow: %w", err)
+	}
+	return &workflow, nil
+}
+
+// scanProject читает колонки projectColumns и затем extra
+func scanProject(row rowScanner, extra ...any) (models.Project, error) {
+	var project models.Project
+	var workflow sql.NullString
+	dest := []any{
+		&project.ID,
+		&project.Name,
+		&project.Description,
+		&project.Archived,
+		&project.Position,
+		&workflow,
+		&project.Subject,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return project, err
+	}
+
+	var err error
+	project.Workflow, err = parseWorkflow(workflow)
+	return project, err
+}
+
+// CreateProject добавляет проект в конец списка проектов пользователя
+func (r *sqlTaskRepository) CreateProject(project models.Project, subject string) (models.Project, error) {
+	now := time.Now()
+	project.ID = uuid.New().String()
+
+	query := `
+        INSERT INTO projects (id, subject, name, description, archived, position, workflow, created_at, updated_at, tenant)
+        VALUES ($1, $2, $3, $4, FALSE,
+            (SELECT COALESCE(MAX(position), 0) + 1 FROM projects WHERE subject = $2 AND tenant = $7), $5, $6, $6, $7)
+        RETURNING ` + projectColumns
+
+	workflow, err := workflowValue(project.Workflow)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	created, err := scanProject(r.db.QueryRow(query, project.ID, subject, project.Name, project.Description, workflow, now, r.workspace()))
+	if err != nil {
+		return models.Project{}, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetProjects возвращает проекты в порядке position с числом активных задач
+func (r *sqlTaskRepository) GetProjects(subject string, includeArchived bool) ([]models.Project, error) {
+	query := `
+        SELECT p.id, p.name, p.description, p.archived, p.position, p.workflow, p.subject, p.created_at, p.updated_at,
+            COUNT(t.id)
+        FROM projects p
+        LEFT JOIN tasks t ON t.project_id = p.id AND t.deleted_at IS NULL
+        WHERE p.subject = $1 AND p.tenant = $3 AND (p.archived = FALSE OR $2)
+        GROUP BY p.id, p.name, p.description, p.archived, p.position, p.workflow, p.subject, p.created_at, p.updated_at
+        ORDER BY p.position, p.created_at
+    `
+
+	rows, err := r.db.Query(query, subject, includeArchived, r.workspace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		var taskCount int
+		project, err := scanProject(rows, &taskCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		project.TaskCount = taskCount
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+func (r *sqlTaskRepository) GetProject(id string, subject string) (models.Project, error) {
+	query := `
+        SELECT ` + projectColumns + `
+        FROM projects
+        WHERE id = $1 AND subject = $2 AND tenant = $3
+    `
+
+	project, err := scanProject(r.db.QueryRow(query, id, subject, r.workspace()))
+	if err == sql.ErrNoRows {
+		return models.Project{}, nil
+	}
+	if err != nil {
+		return models.Project{}, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *sqlTaskRepository) UpdateProject(id string, updates models.ProjectUpdate, subject string) (models.Project, error) {
+	project, err := r.GetProject(id, subject)
+	if err != nil || project.ID == "" {
+		return models.Project{}, err
+	}
+
+	if updates.Name != nil {
+		project.Name = *updates.Name
+	}
+	if updates.Description != nil {
+		project.Description = *updates.Description
+	}
+	if updates.Archived != nil {
+		project.Archived = *updates.Archived
+	}
+	if updates.Position != nil {
+		project.Position = *updates.Position
+	}
+	if updates.Workflow != nil {
+		project.Workflow = updates.Workflow
+	}
+
+	workflow, err := workflowValue(project.Workflow)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	query := `
+        UPDATE projects
+        SET name = $1, description = $2, archived = $3, position = $4, workflow = $5, updated_at = $6
+        WHERE id = $7 AND subject = $8
+        RETURNING ` + projectColumns
+
+	updated, err := scanProject(r.db.QueryRow(
+		query,
+		project.Name,
+		project.Description,
+		project.Archived,
+		project.Position,
+		workflow,
+		time.Now(),
+		id,
+		subject,
+	))
+	if err == sql.ErrNoRows {
+		return models.Project{}, nil
+	}
+	if err != nil {
+		return models.Project{}, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteProject удаляет проект; его задачи остаются без проекта
+func (r *sqlTaskRepository) DeleteProject(id string, subject string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM projects WHERE id = $1 AND subject = $2 AND tenant = $3`, id, subject, r.workspace())
+	if err != nil {
+		return false, fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const reminderColumns = `id, task_id, minutes_before, channel, target, fire_at, fired_at, created_at`
+
+func scanReminder(row rowScanner) (models.Reminder, error) {
+	var reminder models.Reminder
+	err := row.Scan(
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.MinutesBefore,
+		&reminder.Channel,
+		&reminder.Target,
+		&reminder.FireAt,
+		&reminder.FiredAt,
+		&reminder.CreatedAt,
+	)
+	return reminder, err
+}
+
+// GetReminders возвращает напоминания задачи в порядке отправки
+func (r *sqlTaskRepository) GetReminders(taskID string, subject string) ([]models.Reminder, error) {
+	query := `
+        SELECT ` + reminderColumns + `
+        FROM task_reminders
+        WHERE task_id = $1 AND task_id IN (SELECT id FROM tasks WHERE subject = $2)
+        ORDER BY minutes_before DESC, created_at
+    `
+
+	rows, err := r.db.Query(query, taskID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []models.Reminder{}
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// AddReminder добавляет напоминание активной задаче пользователя; пустое
+// напоминание – задачи нет
+func (r *sqlTaskRepository) AddReminder(reminder models.Reminder, subject string) (models.Reminder, error) {
+	query := `
+        INSERT INTO task_reminders (id, task_id, minutes_before, channel, target, fire_at, created_at)
+        SELECT $1, id, $2, $3, $4, $5, $6
+        FROM tasks
+        WHERE id = $7 AND subject = $8 AND deleted_at IS NULL
+        RETURNING ` + reminderColumns
+
+	created, err := scanReminder(r.db.QueryRow(
+		query,
+		uuid.New().String(),
+		reminder.MinutesBefore,
+		reminder.Channel,
+		reminder.Target,
+		reminder.FireAt,
+		time.Now(),
+		reminder.TaskID,
+		subject,
+	))
+	if err == sql.ErrNoRows {
+		return models.Reminder{}, nil
+	}
+	if err != nil {
+		return models.Reminder{}, fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *sqlTaskRepository) DeleteReminder(taskID string, reminderID string, subject string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM task_reminders
+        WHERE id = $1 AND task_id = $2
+          AND task_id IN (SELECT id FROM tasks WHERE subject = $3)
+    `, reminderID, taskID, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RescheduleReminder задает новый момент отправки и снова делает
+// напоминание ожидающим
+func (r *sqlTaskRepository) RescheduleReminder(id string, fireAt *time.Time) error {
+	_, err := r.db.Exec(`
+        UPDATE task_reminders
+        SET fire_at = $1, fired_at = NULL, claimed_by = NULL, claimed_until = NULL,
+            attempts = 0, last_error = NULL
+        WHERE id = $2
+    `, fireAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule reminder: %w", err)
+	}
+	return nil
+}
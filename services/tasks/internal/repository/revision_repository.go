@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const revisionColumns = `task_id, revision, subject, action, actor, request_id, changes, snapshot, created_at`
+
+func (r *sqlTaskRepository) AddRevision(rev models.TaskRevision) error {
+	changes, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision changes: %w", err)
+	}
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision snapshot: %w", err)
+	}
+
+	query := `
+        INSERT INTO task_revisions (` + revisionColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `
+
+	_, err = r.db.Exec(
+		query,
+		rev.TaskID,
+		rev.Revision,
+		rev.Subject,
+		rev.Action,
+		rev.Actor,
+		rev.RequestID,
+		string(changes),
+		string(snapshot),
+		rev.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add task revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistory возвращает ревизии задачи, новые первыми
+func (r *sqlTaskRepository) GetHistory(taskID string, subject string) ([]models.TaskRevision, error) {
+	query := `
+        SELECT ` + revisionColumns + `
+        FROM task_revisions
+        WHERE task_id = $1 AND subject = $2
+        ORDER BY revision DESC
+    `
+
+	rows, err := r.db.Query(query, taskID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.TaskRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (r *sqlTaskRepository) GetRevision(taskID string, revision int64, subject string) (models.TaskRevision, error) {
+	query := `
+        SELECT ` + revisionColumns + `
+        FROM task_revisions
+        WHERE task_id = $1 AND revision = $2 AND subject = $3
+    `
+
+	rev, err := scanRevision(r.db.QueryRow(query, taskID, revision, subject))
+	if err == sql.ErrNoRows {
+		return models.TaskRevision{}, models.ErrRevisionNotFound
+	}
+	if err != nil {
+		return models.TaskRevision{}, err
+	}
+
+	return rev, nil
+}
+
+func scanRevision(row rowScanner) (models.TaskRevision, error) {
+	var rev models.TaskRevision
+	var requestID sql.NullString
+	var changes, snapshot string
+
+	err := row.Scan(
+		&rev.TaskID,
+		&rev.Revision,
+		&rev.Subject,
+		&rev.Action,
+		&rev.Actor,
+		&requestID,
+		&changes,
+		&snapshot,
+		&rev.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return rev, err
+	}
+	if err != nil {
+		return rev, fmt.Errorf("failed to scan task revision: %w", err)
+	}
+
+	rev.RequestID = requestID.String
+	if err := json.Unmarshal([]byte(changes), &rev.Changes); err != nil {
+		return rev, fmt.Errorf("failed to unmarshal revision changes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(snapshot), &rev.Snapshot); err != nil {
+		return rev, fmt.Errorf("failed to unmarshal revision snapshot: %w", err)
+	}
+
+	return rev, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const seriesColumns = `id, subject, rrule, start_date, template, stopped_at, created_at, updated_at`
+
+func scanSeries(row rowScanner) (models.TaskSeries, error) {
+	var series models.TaskSeries
+	var template string
+	err := row.Scan(
+		&series.ID,
+		&series.Subject,
+		&series.RRule,
+		&series.StartDate,
+		&template,
+		&series.StoppedAt,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		return models.TaskSeries{}, err
+	}
+	if err := json.Unmarshal([]byte(template), &series.Template); err != nil {
+		return models.TaskSeries{}, fmt.Errorf("failed to decode series template: %w", err)
+	}
+	return series, nil
+}
+
+func (r *sqlTaskRepository) CreateSeries(series models.TaskSeries) (models.TaskSeries, error) {
+	template, err := json.Marshal(series.Template)
+	if err != nil {
+		return models.TaskSeries{}, fmt.Errorf("failed to encode series template: %w", err)
+	}
+
+	query := `
+        INSERT INTO task_series (id, subject, rrule, start_date, template, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $6)
+        RETURNING ` + seriesColumns
+
+	created, err := scanSeries(r.db.QueryRow(
+		query,
+		series.ID,
+		series.Subject,
+		series.RRule,
+		series.StartDate,
+		string(template),
+		time.Now(),
+	))
+	if err != nil {
+		return models.TaskSeries{}, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetSeries возвращает серию пользователя; пустая серия – серии нет
+func (r *sqlTaskRepository) GetSeries(id string, subject string) (models.TaskSeries, error) {
+	query := `
+        SELECT ` + seriesColumns + `
+        FROM task_series
+        WHERE id = $1 AND subject = $2
+    `
+
+	series, err := scanSeries(r.db.QueryRow(query, id, subject))
+	if err == sql.ErrNoRows {
+		return models.TaskSeries{}, nil
+	}
+	if err != nil {
+		return models.TaskSeries{}, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	return series, nil
+}
+
+// UpdateSeries сохраняет правило, начало, шаблон и остановку серии
+func (r *sqlTaskRepository) UpdateSeries(series models.TaskSeries) error {
+	template, err := json.Marshal(series.Template)
+	if err != nil {
+		return fmt.Errorf("failed to encode series template: %w", err)
+	}
+
+	_, err = r.db.Exec(`
+        UPDATE task_series
+        SET rrule = $1, start_date = $2, template = $3, stopped_at = $4, updated_at = $5
+        WHERE id = $6 AND subject = $7
+    `, series.RRule, series.StartDate, string(template), series.StoppedAt, time.Now(), series.ID, series.Subject)
+	if err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	return nil
+}
+
+// HasOccurrence проверяет, создано ли уже вхождение серии на дату
+// (в том числе выполненное); задачи в корзине не учитываются
+func (r *sqlTaskRepository) HasOccurrence(seriesID string, dueDate string, subject string) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`
+        SELECT COUNT(*)
+        FROM tasks
+        WHERE series_id = $1 AND due_date = $2 AND subject = $3 AND deleted_at IS NULL
+    `, seriesID, dueDate, subject).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check occurrence: %w", err)
+	}
+	return count > 0, nil
+}
+
+// loadRecurrence заполняет правило действующей серии задачи
+func (r *sqlTaskRepository) loadRecurrence(task *models.Task) error {
+	if task.SeriesID == "" {
+		return nil
+	}
+
+	series, err := r.GetSeries(task.SeriesID, task.Subject)
+	if err != nil {
+		return err
+	}
+	if series.ID != "" && series.StoppedAt == nil {
+		task.Recurrence = series.RRule
+	}
+	return nil
+}
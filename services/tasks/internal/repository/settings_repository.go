@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// GetSettings возвращает настройки пользователя; без сохраненных настроек –
+// значения по умолчанию
+func (r *sqlTaskRepository) GetSettings(subject string) (models.UserSettings, error) {
+	var settings models.UserSettings
+	err := r.db.QueryRow(`
+        SELECT timezone, updated_at FROM user_settings WHERE subject = $1
+    `, subject).Scan(&settings.Timezone, &settings.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.UserSettings{Timezone: models.DefaultTimezone}, nil
+	}
+	if err != nil {
+		return models.UserSettings{}, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *sqlTaskRepository) SaveSettings(settings models.UserSettings, subject string) (models.UserSettings, error) {
+	now := time.Now()
+	_, err := r.db.Exec(`
+        INSERT INTO user_settings (subject, timezone, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (subject) DO UPDATE SET timezone = excluded.timezone, updated_at = excluded.updated_at
+    `, subject, settings.Timezone, now)
+	if err != nil {
+		return models.UserSettings{}, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	settings.UpdatedAt = &now
+	return settings, nil
+}
+
+// setDueFlags вычисляет overdue и due_today задач в часовом поясе пользователя
+func (r *sqlTaskRepository) setDueFlags(tasks []models.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	settings, err := r.GetSettings(subject)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	loc := settings.Location()
+	for i := range tasks {
+		tasks[i].SetDueFlags(now, loc)
+	}
+	return nil
+}
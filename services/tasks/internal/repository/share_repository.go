@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// SaveShare выдает доступ или меняет роль уже выданного
+func (r *sqlTaskRepository) SaveShare(share models.Share) (models.Share, error) {
+	now := time.Now()
+	err := r.db.QueryRow(`
+        INSERT INTO shares (resource_type, resource_id, owner, grantee, role, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $6)
+        ON CONFLICT (resource_type, resource_id, grantee) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+        RETURNING created_at, updated_at
+    `, share.ResourceType, share.ResourceID, share.Owner, share.Subject, share.Role, now).Scan(&share.CreatedAt, &share.UpdatedAt)
+	if err != nil {
+		return models.Share{}, fmt.Errorf("failed to save share: %w", err)
+	}
+
+	return share, nil
+}
+
+// DeleteShare отзывает доступ; false – доступа не было
+func (r *sqlTaskRepository) DeleteShare(resourceType string, resourceID string, grantee string, owner string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM shares
+        WHERE resource_type = $1 AND resource_id = $2 AND grantee = $3 AND owner = $4
+    `, resourceType, resourceID, grantee, owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete share: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// GetShares возвращает пользователей с доступом к задаче или проекту владельца
+func (r *sqlTaskRepository) GetShares(resourceType string, resourceID string, owner string) ([]models.Share, error) {
+	rows, err := r.db.Query(`
+        SELECT resource_type, resource_id, owner, grantee, role, created_at, updated_at
+        FROM shares
+        WHERE resource_type = $1 AND resource_id = $2 AND owner = $3
+        ORDER BY created_at, grantee
+    `, resourceType, resourceID, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []models.Share{}
+	for rows.Next() {
+		var share models.Share
+		err := rows.Scan(&share.ResourceType, &share.ResourceID, &share.Owner, &share.Subject, &share.Role, &share.CreatedAt, &share.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate shares: %w", err)
+	}
+	return shares, nil
+}
+
+// TaskAccess возвращает доступ пользователя к задаче: владелец или роль по
+// доступу к задаче или ее проекту. Задачи в корзине доступны только владельцу.
+func (r *sqlTaskRepository) TaskAccess(id string, subject string) (models.Access, error) {
+	var owner, projectID string
+	var deleted bool
+	err := r.db.QueryRow(`
+        SELECT subject, COALESCE(project_id, ''), deleted_at IS NOT NULL FROM tasks WHERE id = $1 AND tenant = $2
+    `, id, r.workspace()).Scan(&owner, &projectID, &deleted)
+	if err == sql.ErrNoRows {
+		return models.Access{}, nil
+	}
+	if err != nil {
+		return models.Access{}, fmt.Errorf("failed to get task owner: %w", err)
+	}
+	if owner == subject {
+		return models.Access{Owner: owner, Role: models.RoleOwner}, nil
+	}
+	if deleted {
+		return models.Access{}, nil
+	}
+
+	return r.grantedAccess(owner, subject, `
+          AND ((resource_type = 'task' AND resource_id = $3)
+            OR (resource_type = 'project' AND resource_id = $4))`, id, projectID)
+}
+
+// ProjectAccess возвращает доступ пользователя к проекту
+func (r *sqlTaskRepository) ProjectAccess(id string, subject string) (models.Access, error) {
+	var owner string
+	err := r.db.QueryRow(`SELECT subject FROM projects WHERE id = $1 AND tenant = $2`, id, r.workspace()).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return models.Access{}, nil
+	}
+	if err != nil {
+		return models.Access{}, fmt.Errorf("failed to get project owner: %w", err)
+	}
+	if owner == subject {
+		return models.Access{Owner: owner, Role: models.RoleOwner}, nil
+	}
+
+	return r.grantedAccess(owner, subject, `
+          AND resource_type = 'project' AND resource_id = $3`, id)
+}
+
+// grantedAccess выбирает наибольшую роль из доступов owner для subject по condition
+func (r *sqlTaskRepository) grantedAccess(owner string, subject string, condition string, args ...any) (models.Access, error) {
+	rows, err := r.db.Query(`
+        SELECT role FROM shares
+        WHERE owner = $1 AND grantee = $2`+condition, append([]any{owner, subject}, args...)...)
+	if err != nil {
+		return models.Access{}, fmt.Errorf("failed to query shares: %w", err)
+	}
+	defer rows.Close()
+
+	access := models.Access{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return models.Access{}, fmt.Errorf("failed to scan share: %w", err)
+		}
+		access.Owner = owner
+		access.Role = models.MaxRole(access.Role, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.Access{}, fmt.Errorf("failed to iterate shares: %w", err)
+	}
+	return access, nil
+}
+
+// SharedTasks возвращает чужие задачи, открытые пользователю напрямую или
+// через проект, с владельцем и ролью
+func (r *sqlTaskRepository) SharedTasks(subject string) ([]models.Task, error) {
+	rows, err := r.db.Query(`
+        SELECT `+taskColumns+`
+        FROM tasks t
+        WHERE t.deleted_at IS NULL AND t.subject <> $1 AND t.tenant = $2 AND EXISTS (
+            SELECT 1 FROM shares s
+            WHERE s.grantee = $1 AND s.owner = t.subject
+              AND ((s.resource_type = 'task' AND s.resource_id = t.id)
+                OR (s.resource_type = 'project' AND s.resource_id = t.project_id))
+        )
+        ORDER BY t.created_at DESC
+    `, subject, r.workspace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shared tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Задачи разных владельцев: детали читаются по задаче
+	for i := range tasks {
+		if err := r.loadTaskDetails(&tasks[i]); err != nil {
+			return nil, err
+		}
+		access, err := r.TaskAccess(tasks[i].ID, subject)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i].Owner = access.Owner
+		tasks[i].Role = access.Role
+	}
+
+	if err := r.setDueFlags(tasks, subject); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// SharedProjects возвращает чужие проекты, открытые пользователю
+func (r *sqlTaskRepository) SharedProjects(subject string) ([]models.Project, error) {
+	rows, err := r.db.Query(`
+        SELECT p.id, p.name, p.description, p.archived, p.position, p.workflow, p.subject, p.created_at, p.updated_at,
+            s.role
+        FROM projects p
+        JOIN shares s ON s.resource_type = 'project' AND s.resource_id = p.id AND s.owner = p.subject
+        WHERE s.grantee = $1 AND p.tenant = $2
+        ORDER BY p.created_at
+    `, subject, r.workspace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shared projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		var role string
+		project, err := scanProject(rows, &role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		project.Owner = project.Subject
+		project.Role = role
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate projects: %w", err)
+	}
+	return projects, nil
+}
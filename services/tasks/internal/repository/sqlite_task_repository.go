@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteMemory – путь для хранения задач в памяти процесса (без файла)
+const SQLiteMemory = ":memory:"
+
+// SQLiteTaskRepository хранит задачи во встроенной базе SQLite
+// (драйвер на чистом Go, CGO не требуется)
+type SQLiteTaskRepository struct {
+	sqlTaskRepository
+}
+
+func NewSQLiteTaskRepository(path string) (*SQLiteTaskRepository, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite допускает только одного писателя, а база в памяти
+	// существует ровно в одном соединении
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	if err := migrate(db, sqliteDialect); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return &SQLiteTaskRepository{
+		sqlTaskRepository{db: db, conn: db, dialect: sqliteDialect},
+	}, nil
+}
+
+func sqliteDSN(path string) string {
+	params := "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+	if path == SQLiteMemory {
+		return "file::memory:?" + params
+	}
+	if strings.Contains(path, "?") {
+		return "file:" + path + "&" + params
+	}
+	return "file:" + path + "?" + params
+}
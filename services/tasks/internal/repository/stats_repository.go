@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// GetStatusCounts возвращает число задач пользователя не из корзины по статусам
+func (r *sqlTaskRepository) GetStatusCounts(subject string) (map[string]int, error) {
+	rows, err := r.db.Query(`
+        SELECT status, COUNT(*)
+        FROM tasks
+        WHERE subject = $1 AND tenant = $2 AND deleted_at IS NULL
+        GROUP BY status
+    `, subject, r.workspace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+// GetOverdueCounts возвращает число просроченных задач по приоритетам;
+// просрочка считается так же, как в фильтре due=overdue
+func (r *sqlTaskRepository) GetOverdueCounts(subject string, loc *time.Location) (map[string]int, error) {
+	condition, args := dueFilterCondition(models.TaskFilter{Due: models.DueFilterOverdue}, loc, []any{subject})
+	tenantCondition, args := r.tenantCondition("tenant", args)
+	rows, err := r.db.Query(`
+        SELECT priority, COUNT(*)
+        FROM tasks
+        WHERE subject = $1 AND deleted_at IS NULL`+condition+tenantCondition+`
+        GROUP BY priority
+    `, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count overdue tasks: %w", err)
+	}
+	return scanCounts(rows)
+}
+
+func scanCounts(rows *sql.Rows) (map[string]int, error) {
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan count: %w", err)
+		}
+		counts[key] += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetTaskTimeline считает задачи не из корзины, созданные и выполненные до
+// каждой из возрастающих границ. Одним проходом по задачам: CASE относит
+// время к первому промежутку между границами, суммы по промежуткам
+// накапливаются в Go.
+func (r *sqlTaskRepository) GetTaskTimeline(subject string, boundaries []time.Time) (models.TaskTimeline, error) {
+	timeline := models.TaskTimeline{
+		CreatedBefore:   make([]int, len(boundaries)),
+		CompletedBefore: make([]int, len(boundaries)),
+	}
+	if len(boundaries) == 0 {
+		return timeline, nil
+	}
+
+	// Время в SQLite сравнивается как текст, поэтому границы передаются в
+	// том же часовом поясе, что и сохраненное время задач
+	args := []any{subject}
+	for _, boundary := range boundaries {
+		args = append(args, boundary.Local())
+	}
+	last := "$" + strconv.Itoa(len(args))
+	tenantCondition, args := r.tenantCondition("tenant", args)
+
+	rows, err := r.db.Query(`
+        SELECT 'created', `+bucketExpr("created_at", len(boundaries))+`, COUNT(*)
+        FROM tasks
+        WHERE subject = $1 AND deleted_at IS NULL AND created_at < `+last+tenantCondition+`
+        GROUP BY 2
+        UNION ALL
+        SELECT 'completed', `+bucketExpr("completed_at", len(boundaries))+`, COUNT(*)
+        FROM tasks
+        WHERE subject = $1 AND deleted_at IS NULL AND completed_at < `+last+tenantCondition+`
+        GROUP BY 2
+    `, args...)
+	if err != nil {
+		return models.TaskTimeline{}, fmt.Errorf("failed to query task timeline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var bucket, count int
+		if err := rows.Scan(&kind, &bucket, &count); err != nil {
+			return models.TaskTimeline{}, fmt.Errorf("failed to scan task timeline: %w", err)
+		}
+		if kind == "completed" {
+			timeline.CompletedBefore[bucket] += count
+		} else {
+			timeline.CreatedBefore[bucket] += count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.TaskTimeline{}, fmt.Errorf("failed to iterate task timeline: %w", err)
+	}
+
+	// Время из промежутка раньше этой и всех следующих границ
+	for i := 1; i < len(boundaries); i++ {
+		timeline.CreatedBefore[i] += timeline.CreatedBefore[i-1]
+		timeline.CompletedBefore[i] += timeline.CompletedBefore[i-1]
+	}
+
+	return timeline, nil
+}
+
+// bucketExpr возвращает номер первой границы ($2, $3, ...), которая позже
+// времени в колонке
+func bucketExpr(column string, boundaries int) string {
+	var expr strings.Builder
+	expr.WriteString("CASE")
+	for i := 0; i < boundaries; i++ {
+		fmt.Fprintf(&expr, " WHEN %s < $%d THEN %d", column, i+2, i)
+	}
+	fmt.Fprintf(&expr, " ELSE %d END", boundaries)
+	return expr.String()
+}
+
+// GetCompletionTime возвращает число задач, выполненных в [from, to), и
+// среднее время от создания до выполнения в секундах
+func (r *sqlTaskRepository) GetCompletionTime(subject string, from, to time.Time) (int, int64, error) {
+	duration := fmt.Sprintf(r.dialect.epoch, "completed_at") + " - " + fmt.Sprintf(r.dialect.epoch, "created_at")
+
+	var count int
+	var avg sql.NullFloat64
+	err := r.db.QueryRow(`
+        SELECT COUNT(*), AVG(`+duration+`)
+        FROM tasks
+        WHERE subject = $1 AND tenant = $4 AND deleted_at IS NULL AND completed_at >= $2 AND completed_at < $3
+    `, subject, from.Local(), to.Local(), r.workspace()).Scan(&count, &avg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query completion time: %w", err)
+	}
+
+	return count, int64(avg.Float64), nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const checklistColumns = `id, task_id, title, done, position, created_at, updated_at`
+
+// parentFilterCondition ограничивает выборку прямыми подзадачами задачи
+func parentFilterCondition(filter models.TaskFilter, args []any) (string, []any) {
+	if filter.ParentID == "" {
+		return "", args
+	}
+	args = append(args, filter.ParentID)
+	return `
+          AND parent_id = $` + strconv.Itoa(len(args)), args
+}
+
+// GetParentID возвращает родителя задачи, в том числе удаленной:
+// проверка циклов должна видеть задачи в корзине, их можно восстановить
+func (r *sqlTaskRepository) GetParentID(id string, subject string) (string, bool, error) {
+	var parentID sql.NullString
+	err := r.db.QueryRow(`SELECT parent_id FROM tasks WHERE id = $1 AND subject = $2`, id, subject).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get task parent: %w", err)
+	}
+	return parentID.String, true, nil
+}
+
+// GetChildIDs возвращает прямые подзадачи, в том числе удаленные
+func (r *sqlTaskRepository) GetChildIDs(parentID string, subject string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT id FROM tasks WHERE parent_id = $1 AND subject = $2`, parentID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subtasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan subtask: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subtasks: %w", err)
+	}
+
+	return ids, nil
+}
+
+// loadProgress считает выполнение задач по активным прямым подзадачам и
+// пунктам чек-листа
+func (r *sqlTaskRepository) loadProgress(tasks []models.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := `
+        SELECT item.task_id, COUNT(*), SUM(CASE WHEN item.done THEN 1 ELSE 0 END)
+        FROM (
+            SELECT parent_id AS task_id, done
+            FROM tasks
+            WHERE subject = $1 AND deleted_at IS NULL AND parent_id IS NOT NULL
+            UNION ALL
+            SELECT c.task_id, c.done
+            FROM checklist_items c
+            JOIN tasks t ON t.id = c.task_id
+            WHERE t.subject = $1
+        ) item`
+	args := []any{subject}
+	if len(tasks) == 1 {
+		query += `
+        WHERE item.task_id = $2`
+		args = append(args, tasks[0].ID)
+	}
+	query += `
+        GROUP BY item.task_id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query task progress: %w", err)
+	}
+	defer rows.Close()
+
+	byTask := make(map[string]*models.TaskProgress)
+	for rows.Next() {
+		var taskID string
+		var total, done int
+		if err := rows.Scan(&taskID, &total, &done); err != nil {
+			return fmt.Errorf("failed to scan task progress: %w", err)
+		}
+		byTask[taskID] = models.NewTaskProgress(done, total)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate task progress: %w", err)
+	}
+
+	for i := range tasks {
+		tasks[i].Progress = byTask[tasks[i].ID]
+	}
+
+	return nil
+}
+
+// loadTaskDetails заполняет у одной задачи метки, прогресс, зависимости, признаки
+// срока, число комментариев и чек-лист
+func (r *sqlTaskRepository) loadTaskDetails(task *models.Task) error {
+	if task.ID == "" {
+		return nil
+	}
+	if err := r.loadTaskTags(task); err != nil {
+		return err
+	}
+
+	tasks := []models.Task{*task}
+	if err := r.loadProgress(tasks, task.Subject); err != nil {
+		return err
+	}
+	if err := r.loadDependencies(tasks, task.Subject); err != nil {
+		return err
+	}
+	if err := r.setDueFlags(tasks, task.Subject); err != nil {
+		return err
+	}
+	if err := r.loadCommentCounts(tasks, task.Subject); err != nil {
+		return err
+	}
+	task.Progress = tasks[0].Progress
+	task.BlockedBy = tasks[0].BlockedBy
+	task.Blocks = tasks[0].Blocks
+	task.Blocked = tasks[0].Blocked
+	task.Overdue = tasks[0].Overdue
+	task.DueToday = tasks[0].DueToday
+	task.CommentCount = tasks[0].CommentCount
+
+	checklist, err := r.GetChecklist(task.ID, task.Subject)
+	if err != nil {
+		return err
+	}
+	task.Checklist = checklist
+
+	return r.loadRecurrence(task)
+}
+
+func scanChecklistItem(row rowScanner) (models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := row.Scan(
+		&item.ID,
+		&item.TaskID,
+		&item.Title,
+		&item.Done,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	return item, err
+}
+
+// GetChecklist возвращает пункты чек-листа задачи в порядке position
+func (r *sqlTaskRepository) GetChecklist(taskID string, subject string) ([]models.ChecklistItem, error) {
+	query := `
+        SELECT ` + checklistColumns + `
+        FROM checklist_items
+        WHERE task_id = $1 AND task_id IN (SELECT id FROM tasks WHERE subject = $2)
+        ORDER BY position, created_at
+    `
+
+	rows, err := r.db.Query(query, taskID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checklist: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate checklist: %w", err)
+	}
+
+	return items, nil
+}
+
+// AddChecklistItem добавляет пункт в конец чек-листа задачи
+func (r *sqlTaskRepository) AddChecklistItem(taskID string, title string, subject string) (models.ChecklistItem, error) {
+	query := `
+        INSERT INTO checklist_items (id, task_id, title, done, position, created_at, updated_at)
+        SELECT $1, id, $2, FALSE,
+            (SELECT COALESCE(MAX(position), 0) + 1 FROM checklist_items WHERE task_id = $3), $4, $4
+        FROM tasks
+        WHERE id = $3 AND subject = $5 AND deleted_at IS NULL
+        RETURNING ` + checklistColumns
+
+	item, err := scanChecklistItem(r.db.QueryRow(query, uuid.New().String(), title, taskID, time.Now(), subject))
+	if err == sql.ErrNoRows {
+		return models.ChecklistItem{}, nil
+	}
+	if err != nil {
+		return models.ChecklistItem{}, fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *sqlTaskRepository) getChecklistItem(taskID string, itemID string, subject string) (models.ChecklistItem, error) {
+	query := `
+        SELECT ` + checklistColumns + `
+        FROM checklist_items
+        WHERE id = $1 AND task_id = $2 AND task_id IN (SELECT id FROM tasks WHERE subject = $3)
+    `
+
+	item, err := scanChecklistItem(r.db.QueryRow(query, itemID, taskID, subject))
+	if err == sql.ErrNoRows {
+		return models.ChecklistItem{}, nil
+	}
+	if err != nil {
+		return models.ChecklistItem{}, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *sqlTaskRepository) UpdateChecklistItem(taskID string, itemID string, updates models.ChecklistItemUpdate, subject string) (models.ChecklistItem, error) {
+	item, err := r.getChecklistItem(taskID, itemID, subject)
+	if err != nil || item.ID == "" {
+		return models.ChecklistItem{}, err
+	}
+
+	if updates.Title != nil {
+		item.Title = *updates.Title
+	}
+	if updates.Done != nil {
+		item.Done = *updates.Done
+	}
+	if updates.Position != nil {
+		item.Position = *updates.Position
+	}
+
+	query := `
+        UPDATE checklist_items
+        SET title = $1, done = $2, position = $3, updated_at = $4
+        WHERE id = $5 AND task_id = $6
+        RETURNING ` + checklistColumns
+
+	updated, err := scanChecklistItem(r.db.QueryRow(
+		query,
+		item.Title,
+		item.Done,
+		item.Position,
+		time.Now(),
+		itemID,
+		taskID,
+	))
+	if err == sql.ErrNoRows {
+		return models.ChecklistItem{}, nil
+	}
+	if err != nil {
+		return models.ChecklistItem{}, fmt.Errorf("failed to update checklist item: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *sqlTaskRepository) DeleteChecklistItem(taskID string, itemID string, subject string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM checklist_items
+        WHERE id = $1 AND task_id = $2 AND task_id IN (SELECT id FROM tasks WHERE subject = $3)
+    `, itemID, taskID, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
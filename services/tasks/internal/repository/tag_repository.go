@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func (r *sqlTaskRepository) CreateTag(tag models.Tag, subject string) (models.Tag, error) {
+	existing, err := r.getTagByName(tag.Name, subject)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	if existing.ID != "" {
+		return models.Tag{}, models.ErrTagExists
+	}
+
+	tag.ID = uuid.New().String()
+	tag.Subject = subject
+	tag.CreatedAt = time.Now()
+
+	_, err = r.db.Exec(`
+        INSERT INTO tags (id, subject, name, color, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `, tag.ID, subject, tag.Name, tag.Color, tag.CreatedAt)
+	if err != nil {
+		// Метку с тем же именем мог создать параллельный запрос
+		if existing, _ := r.getTagByName(tag.Name, subject); existing.ID != "" {
+			return models.Tag{}, models.ErrTagExists
+		}
+		return models.Tag{}, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetTags возвращает метки пользователя с числом активных задач
+func (r *sqlTaskRepository) GetTags(subject string) ([]models.Tag, error) {
+	query := `
+        SELECT t.id, t.name, t.color, t.subject, t.created_at, COUNT(tk.id)
+        FROM tags t
+        LEFT JOIN task_tags tt ON tt.tag_id = t.id
+        LEFT JOIN tasks tk ON tk.id = tt.task_id AND tk.deleted_at IS NULL
+        WHERE t.subject = $1
+        GROUP BY t.id, t.name, t.color, t.subject, t.created_at
+        ORDER BY t.name
+    `
+
+	rows, err := r.db.Query(query, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Color, &tag.Subject, &tag.CreatedAt, &tag.TaskCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (r *sqlTaskRepository) GetTag(id string, subject string) (models.Tag, error) {
+	return r.getTag(`id = $1`, id, subject)
+}
+
+func (r *sqlTaskRepository) getTagByName(name string, subject string) (models.Tag, error) {
+	return r.getTag(`name = $1`, name, subject)
+}
+
+func (r *sqlTaskRepository) getTag(condition string, value string, subject string) (models.Tag, error) {
+	query := `
+        SELECT id, name, color, subject, created_at
+        FROM tags
+        WHERE ` + condition + ` AND subject = $2
+    `
+
+	var tag models.Tag
+	err := r.db.QueryRow(query, value, subject).Scan(&tag.ID, &tag.Name, &tag.Color, &tag.Subject, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.Tag{}, nil
+	}
+	if err != nil {
+		return models.Tag{}, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r *sqlTaskRepository) UpdateTag(id string, updates models.TagUpdate, subject string) (models.Tag, error) {
+	tag, err := r.GetTag(id, subject)
+	if err != nil || tag.ID == "" {
+		return models.Tag{}, err
+	}
+
+	if updates.Name != nil && *updates.Name != tag.Name {
+		existing, err := r.getTagByName(*updates.Name, subject)
+		if err != nil {
+			return models.Tag{}, err
+		}
+		if existing.ID != "" {
+			return models.Tag{}, models.ErrTagExists
+		}
+		tag.Name = *updates.Name
+	}
+	if updates.Color != nil {
+		tag.Color = *updates.Color
+	}
+
+	_, err = r.db.Exec(`UPDATE tags SET name = $1, color = $2 WHERE id = $3 AND subject = $4`,
+		tag.Name, tag.Color, id, subject)
+	if err != nil {
+		return models.Tag{}, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag удаляет метку; связи с задачами удаляются каскадно
+func (r *sqlTaskRepository) DeleteTag(id string, subject string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM tags WHERE id = $1 AND subject = $2`, id, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// AddTaskTags назначает задаче метки по именам, создавая недостающие
+// метки с цветом по умолчанию. Уже назначенные метки пропускаются.
+func (r *sqlTaskRepository) AddTaskTags(taskID string, names []string, subject string) error {
+	return r.inTx(func(tx *sqlTaskRepository) error {
+		return tx.addTaskTags(taskID, names, subject)
+	})
+}
+
+func (r *sqlTaskRepository) addTaskTags(taskID string, names []string, subject string) error {
+	for _, name := range names {
+		_, err := r.db.Exec(`
+            INSERT INTO tags (id, subject, name, color, created_at)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (subject, name) DO NOTHING
+        `, uuid.New().String(), subject, name, models.DefaultTagColor, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create tag: %w", err)
+		}
+
+		_, err = r.db.Exec(`
+            INSERT INTO task_tags (task_id, tag_id)
+            SELECT $1, id FROM tags WHERE subject = $2 AND name = $3
+            ON CONFLICT (task_id, tag_id) DO NOTHING
+        `, taskID, subject, name)
+		if err != nil {
+			return fmt.Errorf("failed to assign tag: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *sqlTaskRepository) RemoveTaskTag(taskID string, name string, subject string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM task_tags
+        WHERE task_id = $1 AND tag_id IN (SELECT id FROM tags WHERE subject = $2 AND name = $3)
+    `, taskID, subject, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// tagFilterCondition строит условие отбора задач по меткам.
+// Аргументы добавляются после уже использованных в запросе args.
+func tagFilterCondition(filter models.TaskFilter, args []any) (string, []any) {
+	if len(filter.Tags) == 0 {
+		return "", args
+	}
+
+	placeholders := make([]string, 0, len(filter.Tags))
+	for _, name := range filter.Tags {
+		args = append(args, name)
+		placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+	}
+
+	condition := `
+          AND id IN (
+            SELECT tt.task_id
+            FROM task_tags tt
+            JOIN tags tg ON tg.id = tt.tag_id
+            WHERE tg.subject = $1 AND tg.name IN (` + strings.Join(placeholders, ", ") + `)
+            GROUP BY tt.task_id`
+	if filter.MatchAllTags {
+		condition += `
+            HAVING COUNT(DISTINCT tg.id) = ` + strconv.Itoa(len(filter.Tags))
+	}
+	condition += `
+          )`
+
+	return condition, args
+}
+
+// loadTags заполняет метки у задач одного пользователя
+func (r *sqlTaskRepository) loadTags(tasks []models.Task, subject string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	query := `
+        SELECT tt.task_id, tg.name
+        FROM task_tags tt
+        JOIN tags tg ON tg.id = tt.tag_id
+        WHERE tg.subject = $1
+        ORDER BY tg.name
+    `
+	args := []any{subject}
+	// Для одной задачи не нужно читать все связи пользователя
+	if len(tasks) == 1 {
+		query = `
+        SELECT tt.task_id, tg.name
+        FROM task_tags tt
+        JOIN tags tg ON tg.id = tt.tag_id
+        WHERE tg.subject = $1 AND tt.task_id = $2
+        ORDER BY tg.name
+    `
+		args = append(args, tasks[0].ID)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query task tags: %w", err)
+	}
+	defer rows.Close()
+
+	byTask := make(map[string][]string)
+	for rows.Next() {
+		var taskID, name string
+		if err := rows.Scan(&taskID, &name); err != nil {
+			return fmt.Errorf("failed to scan task tag: %w", err)
+		}
+		byTask[taskID] = append(byTask[taskID], name)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate task tags: %w", err)
+	}
+
+	for i := range tasks {
+		if tags, ok := byTask[tasks[i].ID]; ok {
+			tasks[i].Tags = tags
+		} else {
+			tasks[i].Tags = []string{}
+		}
+	}
+
+	return nil
+}
+
+func (r *sqlTaskRepository) loadTaskTags(task *models.Task) error {
+	if task.ID == "" {
+		return nil
+	}
+	tasks := []models.Task{*task}
+	if err := r.loadTags(tasks, task.Subject); err != nil {
+		return err
+	}
+	task.Tags = tasks[0].Tags
+	return nil
+}
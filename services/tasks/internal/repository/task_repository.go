@@ -7,24 +7,177 @@ import (
 
 	_ "github.com/lib/pq"
 	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
 )
 
 type TaskRepository interface {
 	Create(task models.Task, subject string) (models.Task, error)
 	GetAll(subject string) ([]models.Task, error)
+	List(subject string, filter models.TaskFilter) ([]models.Task, error)
 	GetByID(id string, subject string) (models.Task, error)
+	// LockTask – GetByID с блокировкой задачи до конца транзакции InTx
+	LockTask(id string, subject string) (models.Task, error)
 	Update(id string, updates models.TaskUpdate, subject string) (models.Task, error)
-	Delete(id string, subject string) (bool, error)
+	Delete(id string, subject string) (models.Task, error)
 	SearchByTitle(term string, subject string) ([]models.Task, error)
 
 	// УЯЗВИМАЯ ВЕРСИЯ
 	SearchByTitleVulnerable(term string, subject string) ([]models.Task, error)
 
+	// Корзина: Delete только помечает задачу удаленной
+	GetTrash(subject string) ([]models.Task, error)
+	Restore(id string, subject string) (models.Task, error)
+
+	// История изменений задачи
+	AddRevision(rev models.TaskRevision) error
+	GetHistory(taskID string, subject string) ([]models.TaskRevision, error)
+	GetRevision(taskID string, revision int64, subject string) (models.TaskRevision, error)
+
+	// Проекты пользователя
+	CreateProject(project models.Project, subject string) (models.Project, error)
+	GetProjects(subject string, includeArchived bool) ([]models.Project, error)
+	GetProject(id string, subject string) (models.Project, error)
+	UpdateProject(id string, updates models.ProjectUpdate, subject string) (models.Project, error)
+	DeleteProject(id string, subject string) (bool, error)
+
+	// Метки пользователя и их назначение задачам (по имени метки)
+	CreateTag(tag models.Tag, subject string) (models.Tag, error)
+	GetTags(subject string) ([]models.Tag, error)
+	GetTag(id string, subject string) (models.Tag, error)
+	UpdateTag(id string, updates models.TagUpdate, subject string) (models.Tag, error)
+	DeleteTag(id string, subject string) (bool, error)
+	AddTaskTags(taskID string, names []string, subject string) error
+	RemoveTaskTag(taskID string, name string, subject string) (bool, error)
+
+	// Иерархия задач и чек-листы
+	GetParentID(id string, subject string) (string, bool, error)
+	GetChildIDs(parentID string, subject string) ([]string, error)
+	GetChecklist(taskID string, subject string) ([]models.ChecklistItem, error)
+	AddChecklistItem(taskID string, title string, subject string) (models.ChecklistItem, error)
+	UpdateChecklistItem(taskID string, itemID string, updates models.ChecklistItemUpdate, subject string) (models.ChecklistItem, error)
+	DeleteChecklistItem(taskID string, itemID string, subject string) (bool, error)
+
+	// Зависимости между задачами
+	GetDependencies(subject string) ([]models.TaskDependency, error)
+	AddDependency(taskID string, blockedByID string) error
+	RemoveDependency(taskID string, blockedByID string, subject string) (bool, error)
+
+	// Учет времени
+	GetRunningTimeEntry(subject string) (models.TimeEntry, error)
+	AddTimeEntry(entry models.TimeEntry, subject string) (models.TimeEntry, error)
+	StopTimeEntry(id string, endedAt time.Time, durationSeconds int64, subject string) (models.TimeEntry, error)
+	GetTimeEntries(taskID string, subject string) ([]models.TimeEntry, error)
+	DeleteTimeEntry(taskID string, entryID string, subject string) (bool, error)
+	GetLoggedTime(subject string, from, to time.Time) ([]models.LoggedTime, error)
+
+	// Серии повторяющихся задач
+	CreateSeries(series models.TaskSeries) (models.TaskSeries, error)
+	GetSeries(id string, subject string) (models.TaskSeries, error)
+	UpdateSeries(series models.TaskSeries) error
+	HasOccurrence(seriesID string, dueDate string, subject string) (bool, error)
+
+	// Напоминания о сроке; рассылает их worker
+	GetReminders(taskID string, subject string) ([]models.Reminder, error)
+	AddReminder(reminder models.Reminder, subject string) (models.Reminder, error)
+	DeleteReminder(taskID string, reminderID string, subject string) (bool, error)
+	RescheduleReminder(id string, fireAt *time.Time) error
+
+	// Настройки пользователя (часовой пояс сроков)
+	GetSettings(subject string) (models.UserSettings, error)
+	SaveSettings(settings models.UserSettings, subject string) (models.UserSettings, error)
+
+	// Доступ других пользователей к задачам и проектам
+	SaveShare(share models.Share) (models.Share, error)
+	DeleteShare(resourceType string, resourceID string, grantee string, owner string) (bool, error)
+	GetShares(resourceType string, resourceID string, owner string) ([]models.Share, error)
+	TaskAccess(id string, subject string) (models.Access, error)
+	ProjectAccess(id string, subject string) (models.Access, error)
+	SharedTasks(subject string) ([]models.Task, error)
+	SharedProjects(subject string) ([]models.Project, error)
+
+	// Исполнители задач и уведомления им
+	AssignedTasks(subject string) ([]models.Task, error)
+	ReleaseAssignments(owner string, grantee string) ([]string, error)
+	AddNotification(notification models.TaskNotification) error
+
+	// Обсуждение задачи; доступ к задаче проверяет сервис
+	GetComments(taskID string) ([]models.Comment, error)
+	GetComment(taskID string, id string) (models.Comment, error)
+	AddComment(comment models.Comment) (models.Comment, error)
+	UpdateComment(taskID string, id string, body string) (models.Comment, error)
+	DeleteComment(taskID string, id string) (bool, error)
+
+	// Метаданные вложений; содержимое файлов хранит blob.Store
+	GetAttachments(taskID string) ([]models.Attachment, error)
+	GetAttachment(taskID string, id string) (models.Attachment, error)
+	AddAttachment(attachment models.Attachment) (models.Attachment, error)
+	DeleteAttachment(taskID string, id string) (bool, error)
+
+	// Импорты, которые выполняет worker
+	CreateImport(job models.ImportJob) (models.ImportJob, error)
+	GetImport(id string, subject string) (models.ImportJob, error)
+
+	// Секретная ссылка на календарь задач
+	GetCalendarFeed(subject string) (models.CalendarFeed, error)
+	SaveCalendarFeed(subject string, tokenHash string) (models.CalendarFeed, error)
+	DeleteCalendarFeed(subject string) (bool, error)
+	CalendarFeedOwner(tokenHash string) (models.FeedOwner, error)
+
+	// Шаблоны деревьев задач
+	GetTemplates(subject string) ([]models.TaskTemplate, error)
+	GetTemplate(id string, subject string) (models.TaskTemplate, error)
+	CreateTemplate(template models.TaskTemplate, subject string) (models.TaskTemplate, error)
+	UpdateTemplate(id string, updates models.TemplateUpdate, subject string) (models.TaskTemplate, error)
+	DeleteTemplate(id string, subject string) (bool, error)
+
+	// Агрегаты статистики задач
+	GetStatusCounts(subject string) (map[string]int, error)
+	GetOverdueCounts(subject string, loc *time.Location) (map[string]int, error)
+	GetTaskTimeline(subject string, boundaries []time.Time) (models.TaskTimeline, error)
+	GetCompletionTime(subject string, from, to time.Time) (int, int64, error)
+
+	// Рабочие пространства: участники, квоты и расход
+	JoinWorkspace(subject string, tenant string) error
+	MemberTenant(subject string) (string, error)
+	GetWorkspace(tenant string) (models.Workspace, error)
+	LockWorkspace(tenant string) error
+
 	Close() error
+
+	// Репозиторий, запросы которого ограничены пространством tenant
+	ForTenant(tenant string) TaskRepository
+
+	// Несколько операций в одной транзакции
+	InTx(fn func(repo TaskRepository) error) error
+}
+
+// Колонки задачи в порядке, ожидаемом scanTask
+const taskColumns = `id, title, description, due_date, due_at, done, status, priority, estimate_points, estimate_minutes, project_id, parent_id, series_id, auto_complete, assignee, subject, version, created_at, updated_at, deleted_at`
+
+// priorityOrder сортирует задачи от срочных к задачам без приоритета
+const priorityOrder = `CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END`
+
+// sqlTaskRepository содержит общую для PostgreSQL и SQLite реализацию
+// TaskRepository: запросы написаны так, чтобы выполняться в обеих СУБД
+type sqlTaskRepository struct {
+	// db – соединение или транзакция InTx, conn – пул соединений (nil
+	// внутри транзакции)
+	db      dbtx
+	conn    *sql.DB
+	dialect dialect
+	// Рабочее пространство, которым ограничены запросы; "" – default
+	tenant string
+}
+
+// dbtx – общие методы *sql.DB и *sql.Tx, через которые идут запросы
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
 }
 
 type PostgresTaskRepository struct {
-	db *sql.DB
+	sqlTaskRepository
 }
 
 func NewPostgresTaskRepository(connStr string) (*PostgresTaskRepository, error) {
@@ -38,109 +191,279 @@ func NewPostgresTaskRepository(connStr string) (*PostgresTaskRepository, error)
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if err := migrate(db, postgresDialect); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
 	return &PostgresTaskRepository{
-		db: db,
+		sqlTaskRepository{db: db, conn: db, dialect: postgresDialect},
 	}, nil
 }
 
-func (r *PostgresTaskRepository) Close() error {
-	return r.db.Close()
+// ForTenant возвращает репозиторий на том же соединении, задачи и проекты
+// которого ограничены пространством tenant. Закрывать его не нужно.
+func (r *sqlTaskRepository) ForTenant(tenant string) TaskRepository {
+	return &sqlTaskRepository{db: r.db, conn: r.conn, dialect: r.dialect, tenant: tenant}
+}
+
+func (r *sqlTaskRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+	return r.conn.Close()
+}
+
+// DB открывает доступ к соединению для хранилищ, живущих в той же базе
+func (r *sqlTaskRepository) DB() *sql.DB {
+	return r.conn
+}
+
+// InTx выполняет fn в одной транзакции: все запросы репозитория, переданного
+// в fn, идут в ней. Ошибка fn откатывает транзакцию. Вложенный InTx
+// выполняет fn в точке сохранения и при ошибке откатывает только ее.
+func (r *sqlTaskRepository) InTx(fn func(repo TaskRepository) error) error {
+	return r.inTx(func(tx *sqlTaskRepository) error {
+		return fn(tx)
+	})
+}
+
+func (r *sqlTaskRepository) inTx(fn func(tx *sqlTaskRepository) error) error {
+	if r.conn == nil {
+		return r.inSavepoint(fn)
+	}
+
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&sqlTaskRepository{db: tx, dialect: r.dialect, tenant: r.tenant}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// inSavepoint выполняет fn внутри уже открытой транзакции; точки сохранения
+// с одним именем вкладываются и в PostgreSQL, и в SQLite
+func (r *sqlTaskRepository) inSavepoint(fn func(tx *sqlTaskRepository) error) error {
+	if _, err := r.db.Exec(`SAVEPOINT nested`); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	if err := fn(r); err != nil {
+		if _, rollbackErr := r.db.Exec(`ROLLBACK TO SAVEPOINT nested`); rollbackErr != nil {
+			return fmt.Errorf("failed to roll back savepoint: %w", rollbackErr)
+		}
+		r.db.Exec(`RELEASE SAVEPOINT nested`)
+		return err
+	}
+	if _, err := r.db.Exec(`RELEASE SAVEPOINT nested`); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	var projectID, parentID, seriesID, assignee, dueDay sql.NullString
+	var dueAt *time.Time
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&dueDay,
+		&dueAt,
+		&task.Done,
+		&task.Status,
+		&task.Priority,
+		&task.EstimatePoints,
+		&task.EstimateMinutes,
+		&projectID,
+		&parentID,
+		&seriesID,
+		&task.AutoComplete,
+		&assignee,
+		&task.Subject,
+		&task.Version,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.DeletedAt,
+	)
+	task.ProjectID = projectID.String
+	task.ParentID = parentID.String
+	task.SeriesID = seriesID.String
+	task.Assignee = assignee.String
+	task.DueDate = due.FromColumns(dueDay.String, dueAt)
+	return task, err
+}
+
+func scanTasks(rows *sql.Rows) ([]models.Task, error) {
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+
+	return tasks, nil
 }
 
 // БЕЗОПАСНАЯ ВЕРСИЯ
-func (r *PostgresTaskRepository) Create(task models.Task, subject string) (models.Task, error) {
+func (r *sqlTaskRepository) Create(task models.Task, subject string) (models.Task, error) {
 	query := `
-        INSERT INTO tasks (id, title, description, due_date, done, subject, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-        RETURNING id, title, description, due_date, done, subject, created_at, updated_at
-    `
+        INSERT INTO tasks (id, title, description, due_date, due_at, done, status, priority, estimate_points, estimate_minutes,
+            project_id, parent_id, series_id, auto_complete, assignee, subject, created_at, updated_at, completed_at, tenant)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, CASE WHEN $6 THEN $18 END, $19)
+        RETURNING ` + taskColumns
 
 	now := time.Now()
 	task.CreatedAt = now
 	task.UpdatedAt = now
 
-	err := r.db.QueryRow(
+	created, err := scanTask(r.db.QueryRow(
 		query,
 		task.ID,
 		task.Title,
 		task.Description,
-		task.DueDate,
+		task.DueDate.DayValue(),
+		task.DueDate.TimeValue(),
 		task.Done,
+		task.Status,
+		task.Priority,
+		task.EstimatePoints,
+		task.EstimateMinutes,
+		nullIfEmpty(task.ProjectID),
+		nullIfEmpty(task.ParentID),
+		nullIfEmpty(task.SeriesID),
+		task.AutoComplete,
+		nullIfEmpty(task.Assignee),
 		subject,
 		task.CreatedAt,
 		task.UpdatedAt,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&task.Subject,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+		r.workspace(),
+	))
 
 	if err != nil {
 		return models.Task{}, fmt.Errorf("failed to create task: %w", err)
 	}
+	created.Tags = []string{}
+	created.BlockedBy = []string{}
+	created.Blocks = []string{}
+	tasks := []models.Task{created}
+	if err := r.setDueFlags(tasks, subject); err != nil {
+		return models.Task{}, err
+	}
+	created = tasks[0]
 
-	return task, nil
+	return created, nil
 }
 
-func (r *PostgresTaskRepository) GetAll(subject string) ([]models.Task, error) {
+func (r *sqlTaskRepository) GetAll(subject string) ([]models.Task, error) {
+	return r.List(subject, models.TaskFilter{})
+}
+
+// List возвращает активные задачи пользователя, подходящие под фильтр
+func (r *sqlTaskRepository) List(subject string, filter models.TaskFilter) ([]models.Task, error) {
+	args := []any{subject}
+	tagCondition, args := tagFilterCondition(filter, args)
+	projectCondition, args := projectFilterCondition(filter, args)
+	parentCondition, args := parentFilterCondition(filter, args)
+	statusCondition, args := statusFilterCondition(filter, args)
+	var loc *time.Location
+	if filter.Due != "" {
+		settings, err := r.GetSettings(subject)
+		if err != nil {
+			return nil, err
+		}
+		loc = settings.Location()
+	}
+	dueCondition, args := dueFilterCondition(filter, loc, args)
+	assigneeCondition, args := assigneeFilterCondition(filter, args)
+	tenantCondition, args := r.tenantCondition("tenant", args)
+
+	order := "created_at DESC"
+	if filter.SortByPriority {
+		order = priorityOrder + ", " + order
+	}
+
 	query := `
-        SELECT id, title, description, due_date, done, subject, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE subject = $1
-        ORDER BY created_at DESC
+        WHERE subject = $1 AND deleted_at IS NULL` + tagCondition + projectCondition + parentCondition + statusCondition + dueCondition + assigneeCondition + tenantCondition + `
+        ORDER BY ` + order + `
     `
 
-	rows, err := r.db.Query(query, subject)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
-	defer rows.Close()
 
-	var tasks []models.Task
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.DueDate,
-			&task.Done,
-			&task.Subject,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
+	return r.scanTasksWithTags(rows, subject)
+}
+
+// scanTasksWithTags читает задачи и затем их метки, прогресс, зависимости и
+// число комментариев: с SQLite доступно одно соединение, поэтому запросы
+// выполняются после закрытия rows
+func (r *sqlTaskRepository) scanTasksWithTags(rows *sql.Rows, subject string) ([]models.Task, error) {
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadTags(tasks, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadProgress(tasks, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDependencies(tasks, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCommentCounts(tasks, subject); err != nil {
+		return nil, err
+	}
+
+	if err := r.setDueFlags(tasks, subject); err != nil {
+		return nil, err
 	}
 
 	return tasks, nil
 }
 
-func (r *PostgresTaskRepository) GetByID(id string, subject string) (models.Task, error) {
+func (r *sqlTaskRepository) GetByID(id string, subject string) (models.Task, error) {
+	return r.getByID(id, subject, "")
+}
+
+func (r *sqlTaskRepository) LockTask(id string, subject string) (models.Task, error) {
+	return r.getByID(id, subject, r.dialect.rowLock)
+}
+
+func (r *sqlTaskRepository) getByID(id string, subject string, lock string) (models.Task, error) {
 	query := `
-        SELECT id, title, description, due_date, done, subject, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE id = $1 AND subject = $2
-    `
+        WHERE id = $1 AND subject = $2 AND tenant = $3 AND deleted_at IS NULL` + lock
 
-	var task models.Task
-	err := r.db.QueryRow(query, id, subject).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&task.Subject,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	task, err := scanTask(r.db.QueryRow(query, id, subject, r.workspace()))
 
 	if err == sql.ErrNoRows {
 		return models.Task{}, nil
@@ -149,10 +472,14 @@ func (r *PostgresTaskRepository) GetByID(id string, subject string) (models.Task
 		return models.Task{}, fmt.Errorf("failed to get task: %w", err)
 	}
 
+	if err := r.loadTaskDetails(&task); err != nil {
+		return models.Task{}, err
+	}
+
 	return task, nil
 }
 
-func (r *PostgresTaskRepository) Update(id string, updates models.TaskUpdate, subject string) (models.Task, error) {
+func (r *sqlTaskRepository) Update(id string, updates models.TaskUpdate, subject string) (models.Task, error) {
 	task, err := r.GetByID(id, subject)
 	if err != nil {
 		return models.Task{}, err
@@ -161,6 +488,11 @@ func (r *PostgresTaskRepository) Update(id string, updates models.TaskUpdate, su
 		return models.Task{}, nil
 	}
 
+	if updates.Version != nil && *updates.Version != task.Version {
+		return models.Task{}, models.ErrVersionConflict
+	}
+	readVersion := task.Version
+
 	if updates.Title != nil {
 		task.Title = *updates.Title
 	}
@@ -173,128 +505,177 @@ func (r *PostgresTaskRepository) Update(id string, updates models.TaskUpdate, su
 	if updates.Done != nil {
 		task.Done = *updates.Done
 	}
+	if updates.Status != nil {
+		task.Status = *updates.Status
+	}
+	if updates.Priority != nil {
+		task.Priority = *updates.Priority
+	}
+	if updates.EstimatePoints != nil {
+		task.EstimatePoints = *updates.EstimatePoints
+	}
+	if updates.EstimateMinutes != nil {
+		task.EstimateMinutes = *updates.EstimateMinutes
+	}
+	if updates.ProjectID != nil {
+		task.ProjectID = *updates.ProjectID
+	}
+	if updates.ParentID != nil {
+		task.ParentID = *updates.ParentID
+	}
+	if updates.AutoComplete != nil {
+		task.AutoComplete = *updates.AutoComplete
+	}
+	if updates.SeriesID != nil {
+		task.SeriesID = *updates.SeriesID
+	}
+	if updates.Assignee != nil {
+		task.Assignee = *updates.Assignee
+	}
 	task.UpdatedAt = time.Now()
 
+	// Условие по прочитанной версии защищает от потерянных обновлений
 	query := `
         UPDATE tasks
-        SET title = $1, description = $2, due_date = $3, done = $4, updated_at = $5
-        WHERE id = $6 AND subject = $7
-        RETURNING id, title, description, due_date, done, subject, created_at, updated_at
-    `
-
-	err = r.db.QueryRow(
+        SET title = $1, description = $2, due_date = $3, due_at = $4, done = $5, status = $6, priority = $7,
+            estimate_points = $8, estimate_minutes = $9, project_id = $10, parent_id = $11,
+            series_id = $12, auto_complete = $13, assignee = $14, updated_at = $15, version = version + 1,
+            completed_at = CASE WHEN NOT $5 THEN NULL WHEN done THEN completed_at ELSE $15 END
+        WHERE id = $16 AND subject = $17 AND version = $18 AND deleted_at IS NULL
+        RETURNING ` + taskColumns
+
+	task, err = scanTask(r.db.QueryRow(
 		query,
 		task.Title,
 		task.Description,
-		task.DueDate,
+		task.DueDate.DayValue(),
+		task.DueDate.TimeValue(),
 		task.Done,
+		task.Status,
+		task.Priority,
+		task.EstimatePoints,
+		task.EstimateMinutes,
+		nullIfEmpty(task.ProjectID),
+		nullIfEmpty(task.ParentID),
+		nullIfEmpty(task.SeriesID),
+		task.AutoComplete,
+		nullIfEmpty(task.Assignee),
 		task.UpdatedAt,
 		id,
 		subject,
-	).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.DueDate,
-		&task.Done,
-		&task.Subject,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+		readVersion,
+	))
 
+	if err == sql.ErrNoRows {
+		return models.Task{}, models.ErrVersionConflict
+	}
 	if err != nil {
 		return models.Task{}, fmt.Errorf("failed to update task: %w", err)
 	}
 
+	if err := r.loadTaskDetails(&task); err != nil {
+		return models.Task{}, err
+	}
+
 	return task, nil
 }
 
-func (r *PostgresTaskRepository) Delete(id string, subject string) (bool, error) {
-	query := `DELETE FROM tasks WHERE id = $1 AND subject = $2`
+// Delete перемещает задачу в корзину и возвращает ее с новой версией;
+// окончательно удаляет ее worker по истечении срока хранения. Пустая задача –
+// задачи нет.
+func (r *sqlTaskRepository) Delete(id string, subject string) (models.Task, error) {
+	query := `
+        UPDATE tasks
+        SET deleted_at = $1, updated_at = $1, version = version + 1
+        WHERE id = $2 AND subject = $3 AND tenant = $4 AND deleted_at IS NULL
+        RETURNING ` + taskColumns
+
+	task, err := scanTask(r.db.QueryRow(query, time.Now(), id, subject, r.workspace()))
 
-	result, err := r.db.Exec(query, id, subject)
+	if err == sql.ErrNoRows {
+		return models.Task{}, nil
+	}
+	if err != nil {
+		return models.Task{}, fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if err := r.loadTaskDetails(&task); err != nil {
+		return models.Task{}, err
+	}
+
+	return task, nil
+}
+
+// GetTrash возвращает удаленные задачи, недавно удаленные первыми
+func (r *sqlTaskRepository) GetTrash(subject string) ([]models.Task, error) {
+	query := `
+        SELECT ` + taskColumns + `
+        FROM tasks
+        WHERE subject = $1 AND tenant = $2 AND deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC
+    `
+
+	rows, err := r.db.Query(query, subject, r.workspace())
 	if err != nil {
-		return false, fmt.Errorf("failed to delete task: %w", err)
+		return nil, fmt.Errorf("failed to query trash: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return r.scanTasksWithTags(rows, subject)
+}
+
+func (r *sqlTaskRepository) Restore(id string, subject string) (models.Task, error) {
+	query := `
+        UPDATE tasks
+        SET deleted_at = NULL, updated_at = $1, version = version + 1
+        WHERE id = $2 AND subject = $3 AND tenant = $4 AND deleted_at IS NOT NULL
+        RETURNING ` + taskColumns
+
+	task, err := scanTask(r.db.QueryRow(query, time.Now(), id, subject, r.workspace()))
+
+	if err == sql.ErrNoRows {
+		return models.Task{}, nil
+	}
 	if err != nil {
-		return false, fmt.Errorf("failed to get rows affected: %w", err)
+		return models.Task{}, fmt.Errorf("failed to restore task: %w", err)
 	}
 
-	return rowsAffected > 0, nil
+	if err := r.loadTaskDetails(&task); err != nil {
+		return models.Task{}, err
+	}
+
+	return task, nil
 }
 
 // БЕЗОПАСНАЯ ВЕРСИЯ
-func (r *PostgresTaskRepository) SearchByTitle(term string, subject string) ([]models.Task, error) {
+func (r *sqlTaskRepository) SearchByTitle(term string, subject string) ([]models.Task, error) {
 	query := `
-        SELECT id, title, description, due_date, done, subject, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE subject = $1 AND title ILIKE $2
+        WHERE subject = $1 AND tenant = $3 AND deleted_at IS NULL AND ` + fmt.Sprintf(r.dialect.ilike, "title", "$2") + `
         ORDER BY created_at DESC
     `
-	rows, err := r.db.Query(query, subject, "%"+term+"%")
+	rows, err := r.db.Query(query, subject, "%"+term+"%", r.workspace())
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks: %w", err)
 	}
-	defer rows.Close()
-
-	var tasks []models.Task
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.DueDate,
-			&task.Done,
-			&task.Subject,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
 
-	return tasks, nil
+	return r.scanTasksWithTags(rows, subject)
 }
 
 // УЯЗВИМАЯ ВЕРСИЯ
-func (r *PostgresTaskRepository) SearchByTitleVulnerable(term string, subject string) ([]models.Task, error) {
+func (r *sqlTaskRepository) SearchByTitleVulnerable(term string, subject string) ([]models.Task, error) {
 	// SQL-инъекция
 	query := fmt.Sprintf(`
-        SELECT id, title, description, due_date, done, subject, created_at, updated_at
+        SELECT `+taskColumns+`
         FROM tasks
-        WHERE subject = '%s' AND title ILIKE '%%%s%%'
+        WHERE subject = '%s' AND tenant = '%s' AND deleted_at IS NULL AND `+fmt.Sprintf(r.dialect.ilike, "title", "'%%%s%%'")+`
         ORDER BY created_at DESC
-    `, subject, term)
+    `, subject, r.workspace(), term)
 
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks: %w", err)
 	}
-	defer rows.Close()
-
-	var tasks []models.Task
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.DueDate,
-			&task.Done,
-			&task.Subject,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
 
-	return tasks, nil
+	return r.scanTasksWithTags(rows, subject)
 }
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const templateColumns = `id, name, description, template, subject, created_at, updated_at`
+
+func scanTemplate(row rowScanner) (models.TaskTemplate, error) {
+	var template models.TaskTemplate
+	var task string
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Description,
+		&task,
+		&template.Subject,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return template, err
+	}
+
+	if err := json.Unmarshal([]byte(task), &template.Task); err != nil {
+		return template, fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+	return template, nil
+}
+
+// GetTemplates возвращает шаблоны пользователя по имени
+func (r *sqlTaskRepository) GetTemplates(subject string) ([]models.TaskTemplate, error) {
+	rows, err := r.db.Query(`
+        SELECT `+templateColumns+`
+        FROM task_templates
+        WHERE subject = $1
+        ORDER BY name, created_at
+    `, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []models.TaskTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// GetTemplate возвращает шаблон пользователя; пустой – шаблона нет
+func (r *sqlTaskRepository) GetTemplate(id string, subject string) (models.TaskTemplate, error) {
+	template, err := scanTemplate(r.db.QueryRow(`
+        SELECT `+templateColumns+`
+        FROM task_templates
+        WHERE id = $1 AND subject = $2
+    `, id, subject))
+	if err == sql.ErrNoRows {
+		return models.TaskTemplate{}, nil
+	}
+	if err != nil {
+		return models.TaskTemplate{}, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (r *sqlTaskRepository) CreateTemplate(template models.TaskTemplate, subject string) (models.TaskTemplate, error) {
+	task, err := json.Marshal(template.Task)
+	if err != nil {
+		return models.TaskTemplate{}, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	now := time.Now()
+	created, err := scanTemplate(r.db.QueryRow(`
+        INSERT INTO task_templates (id, subject, name, description, template, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $6)
+        RETURNING `+templateColumns,
+		uuid.New().String(), subject, template.Name, template.Description, string(task), now))
+	if err != nil {
+		return models.TaskTemplate{}, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *sqlTaskRepository) UpdateTemplate(id string, updates models.TemplateUpdate, subject string) (models.TaskTemplate, error) {
+	template, err := r.GetTemplate(id, subject)
+	if err != nil || template.ID == "" {
+		return models.TaskTemplate{}, err
+	}
+
+	if updates.Name != nil {
+		template.Name = *updates.Name
+	}
+	if updates.Description != nil {
+		template.Description = *updates.Description
+	}
+	if updates.Task != nil {
+		template.Task = *updates.Task
+	}
+
+	task, err := json.Marshal(template.Task)
+	if err != nil {
+		return models.TaskTemplate{}, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	updated, err := scanTemplate(r.db.QueryRow(`
+        UPDATE task_templates
+        SET name = $1, description = $2, template = $3, updated_at = $4
+        WHERE id = $5 AND subject = $6
+        RETURNING `+templateColumns,
+		template.Name, template.Description, string(task), time.Now(), id, subject))
+	if err == sql.ErrNoRows {
+		return models.TaskTemplate{}, nil
+	}
+	if err != nil {
+		return models.TaskTemplate{}, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *sqlTaskRepository) DeleteTemplate(id string, subject string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM task_templates WHERE id = $1 AND subject = $2`, id, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+const timeEntryColumns = `id, task_id, subject, started_at, ended_at, duration_seconds, note, created_at`
+
+func scanTimeEntry(row rowScanner) (models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.Subject,
+		&entry.StartedAt,
+		&entry.EndedAt,
+		&entry.DurationSeconds,
+		&entry.Note,
+		&entry.CreatedAt,
+	)
+	return entry, err
+}
+
+// GetRunningTimeEntry возвращает запущенный таймер пользователя; пустая
+// запись – таймер не запущен
+func (r *sqlTaskRepository) GetRunningTimeEntry(subject string) (models.TimeEntry, error) {
+	query := `
+        SELECT ` + timeEntryColumns + `
+        FROM time_entries
+        WHERE subject = $1 AND ended_at IS NULL
+        ORDER BY started_at DESC
+        LIMIT 1
+    `
+
+	entry, err := scanTimeEntry(r.db.QueryRow(query, subject))
+	if err == sql.ErrNoRows {
+		return models.TimeEntry{}, nil
+	}
+	if err != nil {
+		return models.TimeEntry{}, fmt.Errorf("failed to get running time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// AddTimeEntry сохраняет запись времени активной задачи пользователя;
+// пустая запись – задачи нет или запись – таймер, а у пользователя уже
+// запущен другой (уникальный индекс idx_time_entries_running)
+func (r *sqlTaskRepository) AddTimeEntry(entry models.TimeEntry, subject string) (models.TimeEntry, error) {
+	query := `
+        INSERT INTO time_entries (id, task_id, subject, started_at, ended_at, duration_seconds, note, created_at)
+        SELECT $1, id, subject, $2, $3, $4, $5, $6
+        FROM tasks
+        WHERE id = $7 AND subject = $8 AND deleted_at IS NULL
+        ON CONFLICT DO NOTHING
+        RETURNING ` + timeEntryColumns
+
+	// Время – в местном поясе, как и остальное сохраненное время: по нему
+	// отчет сравнивает границы периода
+	var endedAt *time.Time
+	if entry.EndedAt != nil {
+		local := entry.EndedAt.Local()
+		endedAt = &local
+	}
+
+	created, err := scanTimeEntry(r.db.QueryRow(
+		query,
+		uuid.New().String(),
+		entry.StartedAt.Local(),
+		endedAt,
+		entry.DurationSeconds,
+		entry.Note,
+		time.Now(),
+		entry.TaskID,
+		subject,
+	))
+	if err == sql.ErrNoRows {
+		return models.TimeEntry{}, nil
+	}
+	if err != nil {
+		return models.TimeEntry{}, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	return created, nil
+}
+
+// StopTimeEntry останавливает таймер; пустая запись – таймер уже остановлен
+func (r *sqlTaskRepository) StopTimeEntry(id string, endedAt time.Time, durationSeconds int64, subject string) (models.TimeEntry, error) {
+	query := `
+        UPDATE time_entries
+        SET ended_at = $1, duration_seconds = $2
+        WHERE id = $3 AND subject = $4 AND ended_at IS NULL
+        RETURNING ` + timeEntryColumns
+
+	entry, err := scanTimeEntry(r.db.QueryRow(query, endedAt, durationSeconds, id, subject))
+	if err == sql.ErrNoRows {
+		return models.TimeEntry{}, nil
+	}
+	if err != nil {
+		return models.TimeEntry{}, fmt.Errorf("failed to stop time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetTimeEntries возвращает записи времени задачи, последние первыми
+func (r *sqlTaskRepository) GetTimeEntries(taskID string, subject string) ([]models.TimeEntry, error) {
+	query := `
+        SELECT ` + timeEntryColumns + `
+        FROM time_entries
+        WHERE task_id = $1 AND subject = $2
+        ORDER BY started_at DESC
+    `
+
+	rows, err := r.db.Query(query, taskID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.TimeEntry{}
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *sqlTaskRepository) DeleteTimeEntry(taskID string, entryID string, subject string) (bool, error) {
+	result, err := r.db.Exec(`
+        DELETE FROM time_entries
+        WHERE id = $1 AND task_id = $2 AND subject = $3
+    `, entryID, taskID, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetLoggedTime возвращает завершенные записи времени по задачам не из
+// корзины, начатые в [from, to), вместе с названиями задач и проектов.
+// Нулевые границы не ограничивают период.
+func (r *sqlTaskRepository) GetLoggedTime(subject string, from, to time.Time) ([]models.LoggedTime, error) {
+	// Время в SQLite сравнивается как текст, поэтому границы передаются в
+	// том же часовом поясе, что и сохраненное время записей
+	args := []any{subject}
+	period := ""
+	if !from.IsZero() {
+		args = append(args, from.Local())
+		period += " AND e.started_at >= $" + strconv.Itoa(len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to.Local())
+		period += " AND e.started_at < $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.Query(`
+        SELECT e.task_id, t.title, COALESCE(p.id, ''), COALESCE(p.name, ''), e.started_at, e.duration_seconds
+        FROM time_entries e
+        JOIN tasks t ON t.id = e.task_id
+        LEFT JOIN projects p ON p.id = t.project_id
+        WHERE e.subject = $1 AND e.ended_at IS NOT NULL AND t.deleted_at IS NULL`+period+`
+        ORDER BY e.started_at
+    `, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query logged time: %w", err)
+	}
+	defer rows.Close()
+
+	var logged []models.LoggedTime
+	for rows.Next() {
+		var entry models.LoggedTime
+		if err := rows.Scan(
+			&entry.TaskID,
+			&entry.TaskTitle,
+			&entry.ProjectID,
+			&entry.ProjectName,
+			&entry.StartedAt,
+			&entry.DurationSeconds,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan logged time: %w", err)
+		}
+		logged = append(logged, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate logged time: %w", err)
+	}
+
+	return logged, nil
+}
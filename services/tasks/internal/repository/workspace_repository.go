@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// workspace возвращает пространство, которым ограничены запросы репозитория
+func (r *sqlTaskRepository) workspace() string {
+	if r.tenant == "" {
+		return models.DefaultTenant
+	}
+	return r.tenant
+}
+
+// tenantCondition добавляет к запросу условие на пространство репозитория
+func (r *sqlTaskRepository) tenantCondition(column string, args []any) (string, []any) {
+	args = append(args, r.workspace())
+	return " AND " + column + " = $" + strconv.Itoa(len(args)), args
+}
+
+// JoinWorkspace запоминает пространство пользователя, создавая пространство
+// при первом входе его участника
+func (r *sqlTaskRepository) JoinWorkspace(subject string, tenant string) error {
+	return r.inTx(func(tx *sqlTaskRepository) error {
+		now := time.Now()
+		if _, err := tx.db.Exec(`
+            INSERT INTO workspaces (id, created_at) VALUES ($1, $2)
+            ON CONFLICT (id) DO NOTHING
+        `, tenant, now); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+
+		if _, err := tx.db.Exec(`
+            INSERT INTO workspace_members (subject, tenant, joined_at) VALUES ($1, $2, $3)
+            ON CONFLICT (subject) DO UPDATE SET tenant = excluded.tenant, joined_at = excluded.joined_at
+            WHERE workspace_members.tenant <> excluded.tenant
+        `, subject, tenant, now); err != nil {
+			return fmt.Errorf("failed to save workspace member: %w", err)
+		}
+		return nil
+	})
+}
+
+// MemberTenant возвращает пространство пользователя; пустая строка –
+// пользователь еще не входил
+func (r *sqlTaskRepository) MemberTenant(subject string) (string, error) {
+	var tenant string
+	err := r.db.QueryRow(`SELECT tenant FROM workspace_members WHERE subject = $1`, subject).Scan(&tenant)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return tenant, nil
+}
+
+// LockWorkspace блокирует пространство до конца транзакции, чтобы проверка
+// квоты и создание в ней не пересекались с параллельными
+func (r *sqlTaskRepository) LockWorkspace(tenant string) error {
+	if _, err := r.db.Exec(`
+        INSERT INTO workspaces (id, created_at) VALUES ($1, $2)
+        ON CONFLICT (id) DO NOTHING
+    `, tenant, time.Now()); err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+	if _, err := r.db.Exec(`UPDATE workspaces SET id = id WHERE id = $1`, tenant); err != nil {
+		return fmt.Errorf("failed to lock workspace: %w", err)
+	}
+	return nil
+}
+
+// GetWorkspace возвращает квоты пространства из базы и его расход. Задачи
+// в корзине не считаются: при восстановлении квота проверяется заново.
+func (r *sqlTaskRepository) GetWorkspace(tenant string) (models.Workspace, error) {
+	workspace := models.Workspace{ID: tenant}
+	var maxTasks, maxProjects sql.NullInt64
+	err := r.db.QueryRow(`
+        SELECT max_tasks, max_projects FROM workspaces WHERE id = $1
+    `, tenant).Scan(&maxTasks, &maxProjects)
+	if err != nil && err != sql.ErrNoRows {
+		return models.Workspace{}, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	if maxTasks.Valid {
+		limit := int(maxTasks.Int64)
+		workspace.MaxTasks = &limit
+	}
+	if maxProjects.Valid {
+		limit := int(maxProjects.Int64)
+		workspace.MaxProjects = &limit
+	}
+
+	err = r.db.QueryRow(`
+        SELECT
+            (SELECT COUNT(*) FROM workspace_members WHERE tenant = $1),
+            (SELECT COUNT(*) FROM tasks WHERE tenant = $1 AND deleted_at IS NULL),
+            (SELECT COUNT(*) FROM projects WHERE tenant = $1)
+    `, tenant).Scan(&workspace.Usage.Members, &workspace.Usage.Tasks, &workspace.Usage.Projects)
+	if err != nil {
+		return models.Workspace{}, fmt.Errorf("failed to count workspace usage: %w", err)
+	}
+
+	return workspace, nil
+}
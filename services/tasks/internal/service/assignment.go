@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// Assign назначает исполнителем задачи владельца или пользователя с доступом
+// к ней. Назначать может редактор; пустая задача – задачи нет.
+func (s *TasksService) Assign(id string, req models.AssignRequest, subject string, ctx context.Context) (models.Task, error) {
+	if err := req.Validate(); err != nil {
+		return models.Task{}, err
+	}
+
+	access, err := s.repo.TaskAccess(id, subject)
+	if err != nil || access.Owner == "" {
+		return models.Task{}, err
+	}
+	if !access.Allows(models.RoleEditor) {
+		return models.Task{}, models.ErrAccessDenied
+	}
+
+	assigneeAccess, err := s.repo.TaskAccess(id, req.Assignee)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if assigneeAccess.Owner == "" {
+		return models.Task{}, &models.ValidationError{Message: "assignee has no access to the task"}
+	}
+
+	return s.setAssignee(id, req.Assignee, access, subject, ctx)
+}
+
+// Unassign снимает исполнителя. Кроме редактора, снять задачу с себя может
+// сам исполнитель.
+func (s *TasksService) Unassign(id string, subject string, ctx context.Context) (models.Task, error) {
+	access, err := s.repo.TaskAccess(id, subject)
+	if err != nil || access.Owner == "" {
+		return models.Task{}, err
+	}
+
+	if !access.Allows(models.RoleEditor) {
+		task, err := s.repo.GetByID(id, access.Owner)
+		if err != nil || task.ID == "" {
+			return models.Task{}, err
+		}
+		if task.Assignee != subject {
+			return models.Task{}, models.ErrAccessDenied
+		}
+	}
+
+	return s.setAssignee(id, "", access, subject, ctx)
+}
+
+// AssignedTasks возвращает задачи, назначенные пользователю
+func (s *TasksService) AssignedTasks(subject string) ([]models.Task, error) {
+	return s.repo.AssignedTasks(subject)
+}
+
+// setAssignee меняет исполнителя задачи владельца access.Owner и уведомляет
+// прежнего и нового исполнителя
+func (s *TasksService) setAssignee(id string, assignee string, access models.Access, subject string, ctx context.Context) (models.Task, error) {
+	before, err := s.repo.GetByID(id, access.Owner)
+	if err != nil || before.ID == "" {
+		return models.Task{}, err
+	}
+
+	updated := before
+	if before.Assignee != assignee {
+		updated, err = s.update(id, models.TaskUpdate{Assignee: &assignee}, access.Owner, models.ActionUpdated, ctx)
+		if err != nil || updated.ID == "" {
+			return models.Task{}, err
+		}
+
+		if before.Assignee != "" {
+			s.notify(ctx, updated, before.Assignee, models.EventTaskUnassigned)
+		}
+		if assignee != "" {
+			s.notify(ctx, updated, assignee, models.EventTaskAssigned)
+			s.publishEvent(ctx, models.EventTaskAssigned, updated)
+		} else {
+			s.publishEvent(ctx, models.EventTaskUnassigned, before)
+		}
+
+		s.log.Info("Task assignee changed",
+			zap.String("task_id", id),
+			zap.String("previous_assignee", before.Assignee),
+			zap.String("assignee", assignee),
+		)
+	}
+
+	if access.Owner != subject {
+		updated.Owner = access.Owner
+		updated.Role = access.Role
+	}
+	return updated, nil
+}
+
+// notify ставит уведомление исполнителю в очередь worker'а. Об изменениях,
+// сделанных им самим, исполнитель не уведомляется.
+func (s *TasksService) notify(ctx context.Context, task models.Task, recipient string, event string) {
+	s.queueNotification(ctx, task, recipient, event, "")
+}
+
+// queueNotification ставит уведомление в очередь; commentID – комментарий,
+// в котором упомянут получатель
+func (s *TasksService) queueNotification(ctx context.Context, task models.Task, recipient string, event string, commentID string) {
+	actor, _ := ctx.Value("subject").(string)
+	if actor == "" {
+		actor = task.Subject
+	}
+	if recipient == actor {
+		return
+	}
+
+	err := s.repo.AddNotification(models.TaskNotification{
+		ID:        generateUUID(),
+		TaskID:    task.ID,
+		Recipient: recipient,
+		Event:     event,
+		Actor:     actor,
+		CommentID: commentID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.log.Error("Failed to queue notification",
+			zap.Error(err),
+			zap.String("task_id", task.ID),
+			zap.String("event", event),
+		)
+	}
+}
+
+// releaseAssignments снимает grantee с задач owner, к которым он потерял доступ
+func (s *TasksService) releaseAssignments(owner string, grantee string) {
+	ids, err := s.repo.ReleaseAssignments(owner, grantee)
+	if err != nil {
+		s.log.Error("Failed to release assignments", zap.Error(err), zap.String("assignee", grantee))
+		return
+	}
+
+	for _, id := range ids {
+		s.invalidateTask(id, owner)
+	}
+	if len(ids) > 0 {
+		s.log.Info("Assignments released", zap.String("assignee", grantee), zap.Int("count", len(ids)))
+	}
+}
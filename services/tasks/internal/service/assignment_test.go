@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskAssignment(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task := createTestTask(service, "Review lab", "student", t)
+	if _, err := service.Assign(task.ID, models.AssignRequest{Assignee: "teacher"}, "student", ctx); err == nil {
+		t.Fatal("Expected assignee without access to be rejected")
+	}
+	if _, err := service.Assign(task.ID, models.AssignRequest{Assignee: " "}, "student", ctx); err == nil {
+		t.Error("Expected empty assignee to be rejected")
+	}
+
+	if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to share task: %v", err)
+	}
+	assigned, err := service.Assign(task.ID, models.AssignRequest{Assignee: "teacher"}, "student", ctx)
+	if err != nil || assigned.Assignee != "teacher" {
+		t.Fatalf("Failed to assign task: %+v, %v", assigned, err)
+	}
+
+	// Уведомление исполнителю ждет worker в task_notifications
+	db := service.repo.(interface{ DB() *sql.DB }).DB()
+	var recipient, event string
+	err = db.QueryRow(`SELECT recipient, event FROM task_notifications WHERE task_id = $1`, task.ID).Scan(&recipient, &event)
+	if err != nil || recipient != "teacher" || event != models.EventTaskAssigned {
+		t.Errorf("Expected assignment notification, got %q %q, %v", recipient, event, err)
+	}
+
+	mine, err := service.AssignedTasks("teacher")
+	if err != nil || len(mine) != 1 || mine[0].Owner != "student" || mine[0].Role != models.RoleViewer {
+		t.Errorf("Expected assigned task with owner, got %+v, %v", mine, err)
+	}
+	filtered, err := service.List("student", models.TaskFilter{Assignee: "teacher"})
+	if err != nil || len(filtered) != 1 || filtered[0].ID != task.ID {
+		t.Errorf("Expected task filtered by assignee, got %+v, %v", filtered, err)
+	}
+	history, _ := service.History(task.ID, "student")
+	if len(history) == 0 || len(history[0].Changes) != 1 || history[0].Changes[0].Field != "assignee" {
+		t.Errorf("Expected assignee change in history, got %+v", history)
+	}
+
+	// Наблюдатель не назначает, но может снять задачу с себя
+	if _, err := service.Assign(task.ID, models.AssignRequest{Assignee: "student"}, "teacher", ctx); !errors.Is(err, models.ErrAccessDenied) {
+		t.Errorf("Expected viewer assignment to be denied, got %v", err)
+	}
+	released, err := service.Unassign(task.ID, "teacher", ctx)
+	if err != nil || released.Assignee != "" || released.Owner != "student" {
+		t.Fatalf("Expected assignee to unassign themselves, got %+v, %v", released, err)
+	}
+
+	// Отзыв доступа снимает исполнителя
+	if _, err := service.Assign(task.ID, models.AssignRequest{Assignee: "teacher"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to assign task: %v", err)
+	}
+	if _, err := service.UnshareTask(task.ID, "teacher", "student", ctx); err != nil {
+		t.Fatalf("Failed to revoke access: %v", err)
+	}
+	if got, _ := service.GetByID(task.ID, "student"); got.Assignee != "" {
+		t.Errorf("Expected assignee to be released, got %q", got.Assignee)
+	}
+	if mine, _ := service.AssignedTasks("teacher"); len(mine) != 0 {
+		t.Errorf("Expected no assigned tasks after revoke, got %d", len(mine))
+	}
+}
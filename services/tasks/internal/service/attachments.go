@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/blob"
+)
+
+// SetAttachmentStore включает вложения с хранилищем store и ограничением
+// размера файла maxSize байт (0 – по умолчанию)
+func (s *TasksService) SetAttachmentStore(store blob.Store, maxSize int64) {
+	s.blobs = store
+	if maxSize > 0 {
+		s.maxAttachmentSize = maxSize
+	}
+}
+
+// Attachments возвращает вложения задачи; nil – задачи нет или она не
+// открыта пользователю
+func (s *TasksService) Attachments(taskID string, subject string) ([]models.Attachment, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return nil, err
+	}
+	return s.repo.GetAttachments(taskID)
+}
+
+// UploadAttachment сохраняет файл в хранилище потоком, не читая его в память.
+// Тип файла определяется по содержимому, а не по заголовку клиента.
+// Загружать может редактор; пустое вложение – задачи нет.
+func (s *TasksService) UploadAttachment(taskID string, upload models.AttachmentUpload, subject string, ctx context.Context) (models.Attachment, error) {
+	if s.blobs == nil {
+		return models.Attachment{}, models.ErrAttachmentsDisabled
+	}
+	if err := upload.Validate(s.maxAttachmentSize); err != nil {
+		return models.Attachment{}, err
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Attachment{}, err
+	}
+
+	// http.DetectContentType смотрит не дальше первых 512 байт
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return models.Attachment{}, err
+	}
+	if n < len(head) && int64(n) < upload.Size {
+		return models.Attachment{}, models.ErrAttachmentIncomplete
+	}
+	head = head[:min(int64(n), upload.Size)]
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !models.AttachmentTypes[contentType] {
+		return models.Attachment{}, models.ErrAttachmentType
+	}
+
+	id := generateUUID()
+	key := "tasks/" + taskID + "/" + id
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), upload.Body), upload.Size)
+	if err := s.blobs.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		return models.Attachment{}, err
+	}
+
+	attachment, err := s.repo.AddAttachment(models.Attachment{
+		ID:          id,
+		TaskID:      taskID,
+		Filename:    upload.Filename,
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  key,
+		UploadedBy:  subject,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		s.deleteBlob(key)
+		return models.Attachment{}, err
+	}
+
+	s.log.Info("Attachment uploaded",
+		zap.String("task_id", taskID),
+		zap.String("attachment_id", id),
+		zap.String("content_type", contentType),
+		zap.Int64("size", upload.Size),
+	)
+	return attachment, nil
+}
+
+// OpenAttachment возвращает вложение и поток его содержимого, который
+// закрывает вызывающий; пустое вложение – задачи или вложения нет
+func (s *TasksService) OpenAttachment(taskID string, id string, subject string, ctx context.Context) (models.Attachment, io.ReadCloser, error) {
+	if s.blobs == nil {
+		return models.Attachment{}, nil, models.ErrAttachmentsDisabled
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return models.Attachment{}, nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(taskID, id)
+	if err != nil || attachment.ID == "" {
+		return models.Attachment{}, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		s.log.Warn("Attachment file missing in storage",
+			zap.String("attachment_id", id),
+			zap.String("storage_key", attachment.StorageKey),
+		)
+		return models.Attachment{}, nil, nil
+	}
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment удаляет вложение вместе с файлом. Удалять может
+// редактор; false – вложения нет.
+func (s *TasksService) DeleteAttachment(taskID string, id string, subject string) (bool, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return false, err
+	}
+
+	attachment, err := s.repo.GetAttachment(taskID, id)
+	if err != nil || attachment.ID == "" {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteAttachment(taskID, id)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.deleteBlob(attachment.StorageKey)
+	s.log.Info("Attachment deleted", zap.String("task_id", taskID), zap.String("attachment_id", id))
+	return true, nil
+}
+
+// deleteBlob удаляет файл из хранилища; ошибка оставляет файл-сироту и
+// только логируется
+func (s *TasksService) deleteBlob(key string) {
+	if s.blobs == nil {
+		return
+	}
+	if err := s.blobs.Delete(context.Background(), key); err != nil {
+		s.log.Error("Failed to delete attachment file", zap.Error(err), zap.String("storage_key", key))
+	}
+}
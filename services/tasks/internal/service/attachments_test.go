@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/blob"
+	"tech-ip-sem2/shared/logger"
+)
+
+// fakeS3 – S3-совместимый сервер в памяти для проверки S3Store
+func fakeS3() *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+func TestTaskAttachments(t *testing.T) {
+	server := fakeS3()
+	defer server.Close()
+
+	local, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create local store: %v", err)
+	}
+	s3, err := blob.NewS3Store(blob.Config{Endpoint: server.URL, Bucket: "attachments", Region: "us-east-1", AccessKey: "test", SecretKey: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to create S3 store: %v", err)
+	}
+
+	for name, store := range map[string]blob.Store{"local": local, "s3": s3} {
+		t.Run(name, func(t *testing.T) {
+			service := NewTasksService(logger.New("test"), nil, nil, nil)
+			service.SetAttachmentStore(store, 1024)
+			ctx := context.Background()
+			task := createTestTask(service, "Lab report", "student", t)
+
+			upload := func(filename string, content []byte) (models.Attachment, error) {
+				return service.UploadAttachment(task.ID, models.AttachmentUpload{
+					Filename: filename,
+					Size:     int64(len(content)),
+					Body:     bytes.NewReader(content),
+				}, "student", ctx)
+			}
+
+			png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+			attachment, err := upload("../screens/result.png", png)
+			if err != nil || attachment.ContentType != "image/png" || attachment.Filename != "result.png" {
+				t.Fatalf("Failed to upload attachment: %+v, %v", attachment, err)
+			}
+
+			// Тип определяется по содержимому, а не по расширению
+			if _, err := upload("page.png", []byte("<html><script>alert(1)</script></html>")); !errors.Is(err, models.ErrAttachmentType) {
+				t.Errorf("Expected HTML content to be rejected, got %v", err)
+			}
+			if _, err := upload("big.txt", bytes.Repeat([]byte("a"), 2048)); !errors.Is(err, models.ErrAttachmentTooLarge) {
+				t.Errorf("Expected large attachment to be rejected, got %v", err)
+			}
+
+			got, content, err := service.OpenAttachment(task.ID, attachment.ID, "student", ctx)
+			if err != nil || got.ID != attachment.ID {
+				t.Fatalf("Failed to open attachment: %+v, %v", got, err)
+			}
+			data, _ := io.ReadAll(content)
+			content.Close()
+			if !bytes.Equal(data, png) {
+				t.Errorf("Expected stored content, got %d bytes", len(data))
+			}
+
+			// Наблюдатель скачивает, но не загружает и не удаляет
+			if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx); err != nil {
+				t.Fatalf("Failed to share task: %v", err)
+			}
+			if list, err := service.Attachments(task.ID, "teacher"); err != nil || len(list) != 1 {
+				t.Errorf("Expected viewer to list attachments, got %+v, %v", list, err)
+			}
+			if _, err := service.DeleteAttachment(task.ID, attachment.ID, "teacher"); !errors.Is(err, models.ErrAccessDenied) {
+				t.Errorf("Expected viewer deletion to be denied, got %v", err)
+			}
+
+			if deleted, err := service.DeleteAttachment(task.ID, attachment.ID, "student"); err != nil || !deleted {
+				t.Fatalf("Failed to delete attachment: %v", err)
+			}
+			if _, err := store.Get(ctx, attachment.StorageKey); !errors.Is(err, blob.ErrNotFound) {
+				t.Errorf("Expected attachment file to be deleted, got %v", err)
+			}
+		})
+	}
+}
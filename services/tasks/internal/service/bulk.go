@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/services/tasks/internal/repository"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// errBulkAborted откатывает транзакцию atomic операции с ошибками элементов
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// bulkBatch копит события и ключи кэша операций одной транзакции runBatch
+type bulkBatch struct {
+	events   []models.TaskEvent
+	taskIDs  map[string]bool
+	subjects map[string]bool
+}
+
+func newBulkBatch() *bulkBatch {
+	return &bulkBatch{taskIDs: map[string]bool{}, subjects: map[string]bool{}}
+}
+
+func (b *bulkBatch) invalidate(id string, subject string) {
+	if id != "" {
+		b.taskIDs[id] = true
+	}
+	if subject != "" {
+		b.subjects[subject] = true
+	}
+}
+
+// Bulk выполняет операцию над задачами в одной транзакции. Каждая задача
+// обрабатывается в своей точке сохранения: ошибка откатывает только ее, а с
+// atomic – всю операцию.
+func (s *TasksService) Bulk(req models.BulkRequest, subject string, ctx context.Context) (models.BulkResult, error) {
+	if err := req.Validate(); err != nil {
+		return models.BulkResult{}, err
+	}
+
+	ids := req.IDs
+	if req.Filter != nil {
+		tasks, err := s.List(subject, req.Filter.TaskFilter())
+		if err != nil {
+			return models.BulkResult{}, err
+		}
+		if len(tasks) > models.MaxBulkItems {
+			return models.BulkResult{}, &models.ValidationError{Message: "filter matches too many tasks (max 100)"}
+		}
+		ids = make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+	}
+	count := len(ids)
+	if req.Action == models.BulkCreate {
+		count = len(req.Tasks)
+	}
+
+	var result models.BulkResult
+	err := s.runBatch(ctx, func(tx *TasksService) error {
+		for i := 0; i < count; i++ {
+			item := models.BulkItem{Index: i}
+			item.Err = tx.batchItem(func() error {
+				var err error
+				item.ID, item.Task, err = tx.bulkItem(req, i, ids, subject, ctx)
+				return err
+			})
+			result.Items = append(result.Items, item)
+		}
+
+		if req.Atomic && result.Failed() > 0 {
+			return errBulkAborted
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkAborted) {
+		s.log.Info("Bulk operation rolled back",
+			zap.String("action", req.Action),
+			zap.Int("failed", result.Failed()),
+		)
+		return result, nil
+	}
+	if err != nil {
+		return models.BulkResult{}, err
+	}
+	result.Committed = true
+
+	s.log.Info("Bulk operation completed",
+		zap.String("action", req.Action),
+		zap.Int("items", len(result.Items)),
+		zap.Int("failed", result.Failed()),
+		zap.String("subject", subject),
+	)
+	return result, nil
+}
+
+// runBatch выполняет fn в одной транзакции копией сервиса, которая копит
+// события и сбросы кэша и не читает кэш; после фиксации они сбрасываются
+// один раз
+func (s *TasksService) runBatch(ctx context.Context, fn func(tx *TasksService) error) error {
+	batch := newBulkBatch()
+	err := s.repo.InTx(func(repo repository.TaskRepository) error {
+		tx := *s
+		tx.repo = repo
+		tx.cache = nil
+		tx.batch = batch
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.flushBatch(ctx, batch)
+	return nil
+}
+
+// inTx выполняет fn в транзакции: в уже открытой транзакции runBatch – этой
+// же копией сервиса, иначе – новой через runBatch
+func (s *TasksService) inTx(ctx context.Context, fn func(tx *TasksService) error) error {
+	if s.batch != nil {
+		return fn(s)
+	}
+	return s.runBatch(ctx, fn)
+}
+
+// batchItem выполняет fn копии сервиса из runBatch в точке сохранения:
+// ошибка откатывает изменения и события только этого элемента
+func (s *TasksService) batchItem(fn func() error) error {
+	events := len(s.batch.events)
+	err := s.repo.InTx(func(repository.TaskRepository) error {
+		return fn()
+	})
+	if err != nil {
+		s.batch.events = s.batch.events[:events]
+	}
+	return err
+}
+
+// bulkItem выполняет операцию над одной задачей и возвращает ее id и
+// результат
+func (s *TasksService) bulkItem(req models.BulkRequest, i int, ids []string, subject string, ctx context.Context) (string, models.Task, error) {
+	switch req.Action {
+	case models.BulkCreate:
+		create := req.Tasks[i]
+		if strings.TrimSpace(create.Title) == "" {
+			return "", models.Task{}, &models.ValidationError{Message: "title is required"}
+		}
+		created, err := s.Create(create.NewTask(), subject, ctx)
+		return created.ID, created, err
+
+	case models.BulkDelete:
+		deleted, err := s.Delete(ids[i], subject, ctx)
+		if err == nil && !deleted {
+			err = models.ErrTaskNotFound
+		}
+		return ids[i], models.Task{}, err
+	}
+
+	var updates models.TaskUpdate
+	if req.Action == models.BulkComplete {
+		done := true
+		updates.Done = &done
+	} else {
+		updates = *req.Update
+		// update очищает заголовок на месте, а запрос общий для всех задач
+		if updates.Title != nil {
+			title := *updates.Title
+			updates.Title = &title
+		}
+	}
+
+	updated, err := s.Update(ids[i], updates, subject, ctx)
+	if err == nil && updated.ID == "" {
+		err = models.ErrTaskNotFound
+	}
+	return ids[i], updated, err
+}
+
+// flushBatch сбрасывает кэш одной командой и публикует события одним
+// сообщением task.bulk
+func (s *TasksService) flushBatch(ctx context.Context, batch *bulkBatch) {
+	if s.cache != nil && s.cache.IsEnabled() && len(batch.taskIDs)+len(batch.subjects) > 0 {
+		ids := make([]string, 0, len(batch.taskIDs))
+		for id := range batch.taskIDs {
+			ids = append(ids, id)
+		}
+		subjects := make([]string, 0, len(batch.subjects))
+		for subject := range batch.subjects {
+			subjects = append(subjects, subject)
+		}
+
+		go func() {
+			if err := s.cache.DeleteTasks(context.Background(), ids, subjects); err != nil {
+				s.log.Warn("Failed to invalidate task cache after bulk operation", zap.Error(err))
+			}
+		}()
+	}
+
+	if s.rabbitPub == nil || len(batch.events) == 0 {
+		return
+	}
+
+	requestID := middleware.GetRequestID(ctx)
+	go func() {
+		pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if err := s.rabbitPub.PublishBatch(pubCtx, batch.events, requestID); err != nil {
+			s.log.Error("Failed to publish task.bulk event",
+				zap.Error(err),
+				zap.Int("events", len(batch.events)),
+			)
+		}
+	}()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestBulkTasks(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	result, err := service.Bulk(models.BulkRequest{
+		Action: models.BulkCreate,
+		Tasks:  []models.CreateTaskRequest{{Title: "Lab 1"}, {Title: ""}, {Title: "Lab 2"}},
+	}, "student", ctx)
+	if err != nil || !result.Committed || len(result.Items) != 3 || result.Failed() != 1 {
+		t.Fatalf("Expected two created tasks and one failure, got %+v, %v", result, err)
+	}
+	first, second := result.Items[0].Task, result.Items[2].Task
+
+	// Ошибка одной задачи не отменяет остальные
+	result, err = service.Bulk(models.BulkRequest{
+		Action: models.BulkComplete,
+		IDs:    []string{first.ID, "missing", first.ID},
+	}, "student", ctx)
+	if err != nil || !result.Committed || len(result.Items) != 2 {
+		t.Fatalf("Expected deduplicated partial completion, got %+v, %v", result, err)
+	}
+	if !errors.Is(result.Items[1].Err, models.ErrTaskNotFound) {
+		t.Errorf("Expected missing task error, got %v", result.Items[1].Err)
+	}
+	if got, _ := service.GetByID(first.ID, "student"); !got.Done {
+		t.Error("Expected task to be completed")
+	}
+
+	// atomic: заблокированная задача откатывает всю операцию
+	blocker := createTestTask(service, "Blocker", "student", t)
+	if _, err := service.AddDependency(second.ID, blocker.ID, "student"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	title := "Renamed"
+	result, err = service.Bulk(models.BulkRequest{
+		Action: models.BulkComplete,
+		IDs:    []string{second.ID, blocker.ID},
+		Atomic: true,
+	}, "student", ctx)
+	if err != nil || result.Committed || !errors.Is(result.Items[0].Err, models.ErrTaskBlocked) {
+		t.Fatalf("Expected rolled back operation, got %+v, %v", result, err)
+	}
+	if got, _ := service.GetByID(blocker.ID, "student"); got.Done {
+		t.Error("Expected rollback to keep blocker open")
+	}
+
+	result, err = service.Bulk(models.BulkRequest{
+		Action: models.BulkUpdate,
+		Filter: &models.BulkFilter{Status: "todo"},
+		Update: &models.TaskUpdate{Title: &title},
+	}, "student", ctx)
+	if err != nil || result.Failed() != 0 || len(result.Items) != 2 {
+		t.Fatalf("Expected filter to update two open tasks, got %+v, %v", result, err)
+	}
+	if got, _ := service.GetByID(second.ID, "student"); got.Title != "Renamed" {
+		t.Errorf("Expected renamed task, got %q", got.Title)
+	}
+
+	result, err = service.Bulk(models.BulkRequest{Action: models.BulkDelete, IDs: []string{first.ID}}, "student", ctx)
+	if err != nil || result.Failed() != 0 {
+		t.Fatalf("Failed to delete tasks: %+v, %v", result, err)
+	}
+	if trash, _ := service.Trash("student"); len(trash) != 1 {
+		t.Errorf("Expected one task in trash, got %d", len(trash))
+	}
+
+	if _, err := service.Bulk(models.BulkRequest{Action: models.BulkDelete}, "student", ctx); err == nil {
+		t.Error("Expected request without ids or filter to be rejected")
+	}
+}
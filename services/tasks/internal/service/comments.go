@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// Comments возвращает обсуждение задачи деревом ответов; nil – задачи нет
+// или она не открыта пользователю
+func (s *TasksService) Comments(taskID string, subject string) ([]models.Comment, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetComments(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return models.CommentThread(comments), nil
+}
+
+// AddComment добавляет комментарий или ответ на него. Комментировать может
+// любой, кому открыта задача; пустой комментарий – задачи нет.
+func (s *TasksService) AddComment(taskID string, req models.CommentRequest, subject string, ctx context.Context) (models.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return models.Comment{}, err
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return models.Comment{}, err
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.repo.GetComment(taskID, req.ParentID)
+		if err != nil {
+			return models.Comment{}, err
+		}
+		if parent.ID == "" {
+			return models.Comment{}, &models.ValidationError{Message: "parent comment not found"}
+		}
+	}
+
+	comment, err := s.repo.AddComment(models.Comment{
+		ID:        generateUUID(),
+		TaskID:    taskID,
+		ParentID:  req.ParentID,
+		Author:    subject,
+		Body:      req.Body,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	s.invalidateTask(taskID, owner)
+	s.notifyMentions(ctx, taskID, owner, comment, comment.Mentions)
+
+	s.log.Info("Comment added",
+		zap.String("task_id", taskID),
+		zap.String("comment_id", comment.ID),
+		zap.String("author", subject),
+	)
+	return comment, nil
+}
+
+// UpdateComment меняет текст комментария; менять может только автор.
+// Уведомляются только пользователи, упомянутые впервые.
+func (s *TasksService) UpdateComment(taskID string, commentID string, req models.CommentRequest, subject string, ctx context.Context) (models.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return models.Comment{}, err
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return models.Comment{}, err
+	}
+
+	before, err := s.repo.GetComment(taskID, commentID)
+	if err != nil || before.ID == "" {
+		return models.Comment{}, err
+	}
+	if before.Author != subject {
+		return models.Comment{}, models.ErrNotCommentAuthor
+	}
+
+	comment, err := s.repo.UpdateComment(taskID, commentID, req.Body)
+	if err != nil || comment.ID == "" {
+		return models.Comment{}, err
+	}
+
+	mentioned := make(map[string]bool, len(before.Mentions))
+	for _, name := range before.Mentions {
+		mentioned[name] = true
+	}
+	var added []string
+	for _, name := range comment.Mentions {
+		if !mentioned[name] {
+			added = append(added, name)
+		}
+	}
+	s.notifyMentions(ctx, taskID, owner, comment, added)
+
+	s.log.Info("Comment updated", zap.String("task_id", taskID), zap.String("comment_id", commentID))
+	return comment, nil
+}
+
+// DeleteComment удаляет комментарий вместе с ответами. Удалить может автор
+// или владелец задачи; false – комментария нет.
+func (s *TasksService) DeleteComment(taskID string, commentID string, subject string) (bool, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return false, err
+	}
+
+	comment, err := s.repo.GetComment(taskID, commentID)
+	if err != nil || comment.ID == "" {
+		return false, err
+	}
+	if comment.Author != subject && owner != subject {
+		return false, models.ErrNotCommentAuthor
+	}
+
+	deleted, err := s.repo.DeleteComment(taskID, commentID)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.invalidateTask(taskID, owner)
+	s.log.Info("Comment deleted", zap.String("task_id", taskID), zap.String("comment_id", commentID))
+	return true, nil
+}
+
+// notifyMentions уведомляет упомянутых пользователей, которым открыта задача;
+// упоминания остальных игнорируются
+func (s *TasksService) notifyMentions(ctx context.Context, taskID string, owner string, comment models.Comment, mentions []string) {
+	if len(mentions) == 0 {
+		return
+	}
+
+	task, err := s.repo.GetByID(taskID, owner)
+	if err != nil || task.ID == "" {
+		return
+	}
+
+	notified := false
+	for _, name := range mentions {
+		access, err := s.repo.TaskAccess(taskID, name)
+		if err != nil {
+			s.log.Error("Failed to check mention access", zap.Error(err), zap.String("task_id", taskID))
+			continue
+		}
+		if access.Owner == "" {
+			continue
+		}
+		s.queueNotification(ctx, task, name, models.EventCommentMentioned, comment.ID)
+		notified = true
+	}
+
+	if notified {
+		s.publishEvent(ctx, models.EventCommentMentioned, task)
+	}
+}
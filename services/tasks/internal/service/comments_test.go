@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskComments(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task := createTestTask(service, "Discuss lab", "student", t)
+	if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to share task: %v", err)
+	}
+
+	comment, err := service.AddComment(task.ID, models.CommentRequest{Body: "@teacher, @stranger check <script>x</script>"}, "student", ctx)
+	if err != nil || comment.ID == "" {
+		t.Fatalf("Failed to add comment: %+v, %v", comment, err)
+	}
+	if strings.Contains(comment.Body, "<script>") || len(comment.Mentions) != 2 {
+		t.Errorf("Expected sanitized body with mentions, got %+v", comment)
+	}
+
+	// Уведомление получает только упомянутый пользователь с доступом к задаче
+	db := service.repo.(interface{ DB() *sql.DB }).DB()
+	var recipient, commentID string
+	err = db.QueryRow(`SELECT recipient, comment_id FROM task_notifications WHERE task_id = $1 AND event = $2`,
+		task.ID, models.EventCommentMentioned).Scan(&recipient, &commentID)
+	if err != nil || recipient != "teacher" || commentID != comment.ID {
+		t.Errorf("Expected mention notification, got %q %q, %v", recipient, commentID, err)
+	}
+
+	reply, err := service.AddComment(task.ID, models.CommentRequest{Body: "Done", ParentID: comment.ID}, "teacher", ctx)
+	if err != nil || reply.ParentID != comment.ID {
+		t.Fatalf("Failed to reply: %+v, %v", reply, err)
+	}
+	if _, err := service.AddComment(task.ID, models.CommentRequest{Body: "x", ParentID: "missing"}, "teacher", ctx); err == nil {
+		t.Error("Expected reply to unknown comment to be rejected")
+	}
+
+	thread, err := service.Comments(task.ID, "teacher")
+	if err != nil || len(thread) != 1 || len(thread[0].Replies) != 1 {
+		t.Errorf("Expected threaded comments, got %+v, %v", thread, err)
+	}
+	if got, _ := service.GetByID(task.ID, "student"); got.CommentCount != 2 {
+		t.Errorf("Expected comment count 2, got %d", got.CommentCount)
+	}
+
+	// Менять комментарий может только автор, удалить – еще и владелец задачи
+	if _, err := service.UpdateComment(task.ID, comment.ID, models.CommentRequest{Body: "edited"}, "teacher", ctx); !errors.Is(err, models.ErrNotCommentAuthor) {
+		t.Errorf("Expected edit by other user to be denied, got %v", err)
+	}
+	edited, err := service.UpdateComment(task.ID, comment.ID, models.CommentRequest{Body: "edited"}, "student", ctx)
+	if err != nil || edited.Body != "edited" {
+		t.Errorf("Failed to edit comment: %+v, %v", edited, err)
+	}
+	if _, err := service.DeleteComment(task.ID, comment.ID, "teacher"); !errors.Is(err, models.ErrNotCommentAuthor) {
+		t.Errorf("Expected deletion by other user to be denied, got %v", err)
+	}
+	if deleted, err := service.DeleteComment(task.ID, comment.ID, "student"); err != nil || !deleted {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	if thread, _ := service.Comments(task.ID, "student"); len(thread) != 0 {
+		t.Errorf("Expected replies to be deleted with comment, got %+v", thread)
+	}
+}
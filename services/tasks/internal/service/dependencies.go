@@ -0,0 +1,190 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// SetEnforceDependencies включает запрет выполнять задачу, пока открыты
+// блокирующие ее задачи (по умолчанию включен)
+func (s *TasksService) SetEnforceDependencies(enforce bool) {
+	s.enforceDependencies = enforce
+}
+
+// AddDependency отмечает, что задача taskID заблокирована blockerID.
+// Пустая задача означает, что задачи taskID нет.
+func (s *TasksService) AddDependency(taskID string, blockerID string, subject string) (models.Task, error) {
+	task, err := s.repo.GetByID(taskID, subject)
+	if err != nil || task.ID == "" {
+		return models.Task{}, err
+	}
+
+	if blockerID == taskID {
+		return models.Task{}, models.ErrDependencyCycle
+	}
+
+	blocker, err := s.repo.GetByID(blockerID, subject)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if blocker.ID == "" {
+		return models.Task{}, models.ErrBlockerNotFound
+	}
+
+	deps, err := s.repo.GetDependencies(subject)
+	if err != nil {
+		return models.Task{}, err
+	}
+	// Цикл: блокирующая задача сама (через цепочку) ждет задачу taskID
+	if dependsOn(deps, blockerID, taskID) {
+		return models.Task{}, models.ErrDependencyCycle
+	}
+
+	if err := s.repo.AddDependency(taskID, blockerID); err != nil {
+		return models.Task{}, err
+	}
+
+	s.invalidateTask(taskID, subject)
+	s.invalidateTask(blockerID, subject)
+	s.log.Info("Task dependency added", zap.String("task_id", taskID), zap.String("blocked_by", blockerID))
+
+	return s.repo.GetByID(taskID, subject)
+}
+
+// RemoveDependency снимает зависимость. removed == false – ее не было.
+func (s *TasksService) RemoveDependency(taskID string, blockerID string, subject string) (models.Task, bool, error) {
+	task, err := s.repo.GetByID(taskID, subject)
+	if err != nil || task.ID == "" {
+		return models.Task{}, false, err
+	}
+
+	removed, err := s.repo.RemoveDependency(taskID, blockerID, subject)
+	if err != nil || !removed {
+		return task, false, err
+	}
+
+	s.invalidateTask(taskID, subject)
+	s.invalidateTask(blockerID, subject)
+	s.log.Info("Task dependency removed", zap.String("task_id", taskID), zap.String("blocked_by", blockerID))
+
+	task, err = s.repo.GetByID(taskID, subject)
+	return task, true, err
+}
+
+// NextTasks упорядочивает открытые задачи топологически: сначала те, что
+// можно делать сейчас, затем разблокируемые ими. Внутри уровня – по сроку.
+func (s *TasksService) NextTasks(subject string, limit int) ([]models.NextTask, error) {
+	tasks, err := s.repo.List(subject, models.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.repo.GetDependencies(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[string]models.Task)
+	for _, task := range tasks {
+		if !task.Done {
+			open[task.ID] = task
+		}
+	}
+
+	// Учитываются только зависимости между открытыми задачами
+	waiting := make(map[string]int)
+	unblocks := make(map[string][]string)
+	for _, dep := range deps {
+		if _, ok := open[dep.TaskID]; !ok {
+			continue
+		}
+		if _, ok := open[dep.BlockedByID]; !ok {
+			continue
+		}
+		waiting[dep.TaskID]++
+		unblocks[dep.BlockedByID] = append(unblocks[dep.BlockedByID], dep.TaskID)
+	}
+
+	var level []models.Task
+	for _, task := range tasks {
+		if _, ok := open[task.ID]; ok && waiting[task.ID] == 0 {
+			level = append(level, task)
+		}
+	}
+
+	result := []models.NextTask{}
+	for depth := 0; len(level) > 0; depth++ {
+		sortByDueDate(level)
+
+		var next []models.Task
+		for _, task := range level {
+			blockedBy := []string{}
+			for _, id := range task.BlockedBy {
+				if _, ok := open[id]; ok {
+					blockedBy = append(blockedBy, id)
+				}
+			}
+			result = append(result, models.NextTask{
+				ID:        task.ID,
+				Title:     task.Title,
+				DueDate:   task.DueDate,
+				Level:     depth,
+				BlockedBy: blockedBy,
+			})
+
+			for _, id := range unblocks[task.ID] {
+				waiting[id]--
+				if waiting[id] == 0 {
+					next = append(next, open[id])
+				}
+			}
+		}
+		level = next
+	}
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// dependsOn проверяет, ждет ли задача from (напрямую или через цепочку) задачу to
+func dependsOn(deps []models.TaskDependency, from string, to string) bool {
+	blockers := make(map[string][]string)
+	for _, dep := range deps {
+		blockers[dep.TaskID] = append(blockers[dep.TaskID], dep.BlockedByID)
+	}
+
+	visited := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, blocker := range blockers[id] {
+			if !visited[blocker] {
+				visited[blocker] = true
+				stack = append(stack, blocker)
+			}
+		}
+	}
+	return false
+}
+
+// sortByDueDate: сначала задачи с ближайшим сроком, без срока – в конце
+func sortByDueDate(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].DueDate, tasks[j].DueDate
+		if !a.Equal(b) {
+			if a.IsZero() || b.IsZero() {
+				return b.IsZero()
+			}
+			return a.Start(time.UTC).Before(b.Start(time.UTC))
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskDependencies(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	design := createTestTask(service, "Design", "student", t)
+	build := createTestTask(service, "Build", "student", t)
+	ship := createTestTask(service, "Ship", "student", t)
+
+	if _, err := service.AddDependency(build.ID, design.ID, "student"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if _, err := service.AddDependency(ship.ID, build.ID, "student"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	// Design ждет Ship, который через Build ждет Design
+	if _, err := service.AddDependency(design.ID, ship.ID, "student"); !errors.Is(err, models.ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle, got %v", err)
+	}
+
+	// Задачи другого пользователя недоступны
+	other := createTestTask(service, "Other", "other", t)
+	if _, err := service.AddDependency(build.ID, other.ID, "student"); !errors.Is(err, models.ErrBlockerNotFound) {
+		t.Errorf("Expected ErrBlockerNotFound, got %v", err)
+	}
+
+	got, _ := service.GetByID(build.ID, "student")
+	if !got.Blocked || len(got.BlockedBy) != 1 || len(got.Blocks) != 1 {
+		t.Errorf("Expected build to be blocked by design and block ship, got %+v", got)
+	}
+
+	done := true
+	if _, err := service.Update(build.ID, models.TaskUpdate{Done: &done}, "student", ctx); !errors.Is(err, models.ErrTaskBlocked) {
+		t.Errorf("Expected ErrTaskBlocked, got %v", err)
+	}
+
+	next, err := service.NextTasks("student", 0)
+	if err != nil {
+		t.Fatalf("Failed to get next tasks: %v", err)
+	}
+	if len(next) != 3 || next[0].ID != design.ID || next[1].ID != build.ID || next[2].Level != 2 {
+		t.Errorf("Expected design, build, ship in order, got %+v", next)
+	}
+
+	if _, err := service.Update(design.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete design: %v", err)
+	}
+	if _, err := service.Update(build.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Errorf("Expected unblocked task to be completed, got %v", err)
+	}
+
+	// Без проверки зависимостей заблокированную задачу можно выполнить
+	review := createTestTask(service, "Review", "student", t)
+	if _, err := service.AddDependency(ship.ID, review.ID, "student"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	service.SetEnforceDependencies(false)
+	if _, err := service.Update(ship.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Errorf("Expected blocked task to be completed without enforcement, got %v", err)
+	}
+}
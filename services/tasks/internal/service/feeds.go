@@ -0,0 +1,102 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// CalendarFeed возвращает состояние ссылки на календарь пользователя
+func (s *TasksService) CalendarFeed(subject string) (models.CalendarFeed, error) {
+	return s.repo.GetCalendarFeed(subject)
+}
+
+// RegenerateCalendarFeed создает новую секретную ссылку на календарь;
+// прежняя ссылка перестает работать
+func (s *TasksService) RegenerateCalendarFeed(subject string) (models.CalendarFeed, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return models.CalendarFeed{}, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feed, err := s.repo.SaveCalendarFeed(subject, feedTokenHash(token))
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+	feed.Token = token
+	feed.URL = "/v1/feeds/" + token + ".ics"
+
+	s.log.Info("Calendar feed regenerated", zap.String("subject", subject))
+	return feed, nil
+}
+
+// RevokeCalendarFeed отключает ссылку на календарь; false – ссылки не было
+func (s *TasksService) RevokeCalendarFeed(subject string) (bool, error) {
+	deleted, err := s.repo.DeleteCalendarFeed(subject)
+	if err != nil {
+		return false, err
+	}
+	if deleted {
+		s.log.Info("Calendar feed revoked", zap.String("subject", subject))
+	}
+	return deleted, nil
+}
+
+// FeedTasks возвращает задачи со сроком для календаря по токену ссылки.
+// Пустой Subject – ссылки нет или она заменена новой.
+func (s *TasksService) FeedTasks(token string, query models.FeedQuery) (models.Feed, error) {
+	if err := query.Validate(); err != nil {
+		return models.Feed{}, err
+	}
+
+	owner, err := s.repo.CalendarFeedOwner(feedTokenHash(token))
+	if err != nil || owner.Subject == "" {
+		return models.Feed{}, err
+	}
+	subject, createdAt := owner.Subject, owner.CreatedAt
+
+	// Ссылка открывается без входа: пространство берется из нее
+	tasks, err := s.ForTenant(owner.Tenant).List(subject, models.TaskFilter{
+		ProjectID:    query.ProjectID,
+		Tags:         query.Tags,
+		MatchAllTags: query.MatchAllTags,
+	})
+	if err != nil {
+		return models.Feed{}, err
+	}
+
+	feed := models.Feed{Subject: subject, Records: []taskio.Record{}, LastModified: createdAt}
+	// Выполнение и срок задачи меняют updated_at, даже если задача выпадает
+	// из календаря; удаление – только задачи в корзине
+	for _, task := range tasks {
+		if task.UpdatedAt.After(feed.LastModified) {
+			feed.LastModified = task.UpdatedAt
+		}
+		if task.DueDate.IsZero() || (task.Done && !query.IncludeDone) {
+			continue
+		}
+		feed.Records = append(feed.Records, taskRecord(task))
+	}
+
+	trash, err := s.repo.GetTrash(subject)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	if len(trash) > 0 && trash[0].DeletedAt != nil && trash[0].DeletedAt.After(feed.LastModified) {
+		feed.LastModified = *trash[0].DeletedAt
+	}
+
+	return feed, nil
+}
+
+func feedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
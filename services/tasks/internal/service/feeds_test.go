@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/logger"
+	"tech-ip-sem2/shared/taskio"
+)
+
+func TestCalendarFeed(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	open := createTestTask(service, "Lab 1", "student", t)
+	done := createTestTask(service, "Lab 2", "student", t)
+	completed := true
+	if _, err := service.Update(done.ID, models.TaskUpdate{Done: &completed}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if _, err := service.Create(models.Task{Title: "No due date"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if feed, _ := service.CalendarFeed("student"); feed.Enabled {
+		t.Fatal("Expected no calendar feed before it is created")
+	}
+	created, err := service.RegenerateCalendarFeed("student")
+	if err != nil || created.Token == "" || created.URL != "/v1/feeds/"+created.Token+".ics" {
+		t.Fatalf("Expected feed token and URL, got %+v, %v", created, err)
+	}
+
+	// Только задачи со сроком; выполненные – по include_done
+	feed, err := service.FeedTasks(created.Token, models.FeedQuery{})
+	if err != nil || feed.Subject != "student" || len(feed.Records) != 1 || feed.Records[0].ID != open.ID {
+		t.Fatalf("Expected one open task with due date, got %+v, %v", feed, err)
+	}
+	feed, _ = service.FeedTasks(created.Token, models.FeedQuery{IncludeDone: true})
+	if len(feed.Records) != 2 {
+		t.Errorf("Expected completed task with include_done, got %d tasks", len(feed.Records))
+	}
+	if _, err := service.FeedTasks(created.Token, models.FeedQuery{Type: "journal"}); err == nil {
+		t.Error("Expected unknown component type to be rejected")
+	}
+
+	// Удаление задачи сдвигает время изменения календаря
+	before := feed.LastModified
+	time.Sleep(10 * time.Millisecond)
+	if _, err := service.Delete(open.ID, "student", ctx); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	feed, _ = service.FeedTasks(created.Token, models.FeedQuery{})
+	if len(feed.Records) != 0 || !feed.LastModified.After(before) {
+		t.Errorf("Expected deletion to update feed, got %+v", feed)
+	}
+
+	// Новая ссылка отзывает старую
+	regenerated, _ := service.RegenerateCalendarFeed("student")
+	if feed, _ := service.FeedTasks(created.Token, models.FeedQuery{}); feed.Subject != "" {
+		t.Error("Expected old feed token to be revoked")
+	}
+	if revoked, _ := service.RevokeCalendarFeed("student"); !revoked {
+		t.Error("Expected feed to be revoked")
+	}
+	if feed, _ := service.FeedTasks(regenerated.Token, models.FeedQuery{}); feed.Subject != "" {
+		t.Error("Expected revoked feed token to stop working")
+	}
+
+	var ics bytes.Buffer
+	record := taskio.Record{ID: "1", Title: "Exam", DueDate: due.At(time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), time.UTC), EstimateMinutes: 90}
+	if err := taskio.WriteCalendar(&ics, taskio.Calendar{Name: "Tasks", Events: true}, []taskio.Record{record, {ID: "2", Title: "No due"}}); err != nil {
+		t.Fatalf("Failed to write calendar: %v", err)
+	}
+	if !strings.Contains(ics.String(), "DTSTART:20260310T073000Z") || strings.Contains(ics.String(), "No due") {
+		t.Errorf("Expected event ending at due time without undated tasks, got %s", ics.String())
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// SetImportQueue поручает worker'у импорты, в которых больше syncRows новых
+// задач (0 – по умолчанию). Без очереди все импорты выполняются сразу.
+func (s *TasksService) SetImportQueue(syncRows int) {
+	s.queueImports = true
+	if syncRows > 0 {
+		s.importSyncRows = syncRows
+	}
+}
+
+// Export возвращает свои активные задачи пользователя для файла обмена
+func (s *TasksService) Export(subject string) ([]taskio.Record, error) {
+	tasks, err := s.GetAll(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]taskio.Record, 0, len(tasks))
+	for _, task := range tasks {
+		records = append(records, taskRecord(task))
+	}
+	return records, nil
+}
+
+func taskRecord(task models.Task) taskio.Record {
+	return taskio.Record{
+		ID:              task.ID,
+		Title:           task.Title,
+		Description:     task.Description,
+		DueDate:         task.DueDate,
+		Done:            task.Done,
+		Status:          task.Status,
+		Priority:        task.Priority,
+		Tags:            task.Tags,
+		EstimatePoints:  task.EstimatePoints,
+		EstimateMinutes: task.EstimateMinutes,
+		UpdatedAt:       task.UpdatedAt,
+	}
+}
+
+// Import создает задачи из файла. Строки с ошибками и повторы существующих
+// задач или строк выше пропускаются и попадают в отчет. dry_run только
+// проверяет строки. Если новых задач больше порога, импорт ставится в
+// очередь worker'а и возвращается со статусом queued.
+func (s *TasksService) Import(req models.ImportRequest, subject string, ctx context.Context) (models.ImportJob, error) {
+	if err := req.Validate(); err != nil {
+		return models.ImportJob{}, err
+	}
+
+	items, err := taskio.Read(req.Body, req.Format, models.MaxImportRows)
+	if errors.Is(err, taskio.ErrTooManyRows) {
+		return models.ImportJob{}, &models.ValidationError{Message: "too many tasks in file (max 10000)"}
+	}
+	if err != nil {
+		return models.ImportJob{}, &models.ValidationError{Message: err.Error()}
+	}
+
+	existing, err := s.repo.GetAll(subject)
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+	dedup := taskio.NewDedup()
+	for _, task := range existing {
+		dedup.Add(taskRecord(task))
+	}
+
+	job := models.ImportJob{
+		Status:   models.ImportCompleted,
+		Format:   req.Format,
+		DryRun:   req.DryRun,
+		Subject:  subject,
+		Timezone: s.location(subject).String(),
+	}
+	job.Total = len(items)
+
+	loc := s.location(subject)
+	for _, item := range items {
+		record := item.Record
+		if item.Err == nil {
+			item.Err = record.Normalize()
+		}
+		if item.Err != nil {
+			job.Add(taskio.RowResult{Line: item.Line, Title: record.Title, Status: taskio.RowFailed, Error: item.Err.Error()})
+			continue
+		}
+
+		record.DueDate = record.DueDate.In(loc)
+		if dedup.Seen(record) {
+			job.Add(taskio.RowResult{Line: item.Line, Title: record.Title, Status: taskio.RowSkipped, Error: "duplicate task"})
+			continue
+		}
+		job.Pending = append(job.Pending, taskio.Queued{Line: item.Line, Record: record})
+	}
+
+	if req.DryRun {
+		for _, pending := range job.Pending {
+			job.Add(taskio.RowResult{Line: pending.Line, Title: pending.Record.Title, Status: taskio.RowValid})
+		}
+		job.Pending = nil
+		job.Sort()
+		return job, nil
+	}
+
+	if s.queueImports && len(job.Pending) > s.importSyncRows {
+		job.ID = generateUUID()
+		job.Status = models.ImportQueued
+		var queued models.ImportJob
+		err = s.inTx(ctx, func(tx *TasksService) error {
+			if err := tx.checkQuota(models.QuotaTasks, len(job.Pending)); err != nil {
+				return err
+			}
+			var err error
+			queued, err = tx.repo.CreateImport(job)
+			return err
+		})
+		if err != nil {
+			return models.ImportJob{}, err
+		}
+		s.log.Info("Import queued",
+			zap.String("import_id", queued.ID),
+			zap.Int("rows", len(queued.Pending)),
+			zap.String("subject", subject),
+		)
+		queued.Pending = nil
+		queued.Sort()
+		return queued, nil
+	}
+
+	err = s.runBatch(ctx, func(tx *TasksService) error {
+		for _, pending := range job.Pending {
+			row := taskio.RowResult{Line: pending.Line, Title: pending.Record.Title, Status: taskio.RowCreated}
+			err := tx.batchItem(func() error {
+				created, err := tx.importRecord(pending.Record, subject, ctx)
+				row.TaskID = created.ID
+				return err
+			})
+			if err != nil {
+				row = taskio.RowResult{Line: pending.Line, Title: pending.Record.Title, Status: taskio.RowFailed, Error: err.Error()}
+			}
+			job.Add(row)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.ImportJob{}, err
+	}
+	job.Pending = nil
+	job.Sort()
+
+	s.log.Info("Tasks imported",
+		zap.String("format", req.Format),
+		zap.Int("created", job.Created),
+		zap.Int("skipped", job.Skipped),
+		zap.Int("failed", job.Failed),
+		zap.String("subject", subject),
+	)
+	return job, nil
+}
+
+// importRecord создает задачу из записи вместе с метками
+func (s *TasksService) importRecord(record taskio.Record, subject string, ctx context.Context) (models.Task, error) {
+	created, err := s.Create(models.Task{
+		Title:           record.Title,
+		Description:     record.Description,
+		DueDate:         record.DueDate,
+		Done:            record.Done,
+		Status:          record.Status,
+		Priority:        record.Priority,
+		EstimatePoints:  record.EstimatePoints,
+		EstimateMinutes: record.EstimateMinutes,
+	}, subject, ctx)
+	if err != nil || len(record.Tags) == 0 {
+		return created, err
+	}
+
+	if err := s.repo.AddTaskTags(created.ID, record.Tags, subject); err != nil {
+		return models.Task{}, err
+	}
+	return created, nil
+}
+
+// ImportStatus возвращает импорт, выполняемый worker'ом; пустой – импорта нет
+func (s *TasksService) ImportStatus(id string, subject string) (models.ImportJob, error) {
+	return s.repo.GetImport(id, subject)
+}
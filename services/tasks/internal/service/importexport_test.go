@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+	"tech-ip-sem2/shared/taskio"
+)
+
+func TestTaskImportExport(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task := createTestTask(service, "Lab 1", "student", t)
+	if err := service.repo.AddTaskTags(task.ID, []string{"study"}, "student"); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+
+	// Выгрузка в CSV загружается обратно без повторов
+	records, err := service.Export("student")
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected one exported task, got %v, %v", records, err)
+	}
+	var csv bytes.Buffer
+	if err := taskio.Write(&csv, taskio.FormatCSV, records); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	job, err := service.Import(models.ImportRequest{Format: taskio.FormatCSV, Body: &csv}, "student", ctx)
+	if err != nil || job.Skipped != 1 || job.Created != 0 {
+		t.Fatalf("Expected exported task to be skipped as duplicate, got %+v, %v", job, err)
+	}
+
+	file := "title,due_date,priority,tags\nLab 2,2026-03-20,high,\"lab,study\"\nlab 2,2026-03-20,,\n,2026-03-21,,\n"
+
+	// dry_run не создает задачи
+	job, err = service.Import(models.ImportRequest{Format: taskio.FormatCSV, DryRun: true, Body: strings.NewReader(file)}, "student", ctx)
+	if err != nil || job.Total != 3 || job.Created != 1 || job.Skipped != 1 || job.Failed != 1 {
+		t.Fatalf("Expected one valid, one duplicate and one invalid row, got %+v, %v", job, err)
+	}
+	if job.Rows[0].Status != taskio.RowValid || job.Rows[2].Line != 4 {
+		t.Errorf("Expected rows in file order, got %+v", job.Rows)
+	}
+	if tasks, _ := service.GetAll("student"); len(tasks) != 1 {
+		t.Fatalf("Expected dry run to create nothing, got %d tasks", len(tasks))
+	}
+
+	job, err = service.Import(models.ImportRequest{Format: taskio.FormatCSV, Body: strings.NewReader(file)}, "student", ctx)
+	if err != nil || job.Status != models.ImportCompleted || job.Created != 1 {
+		t.Fatalf("Expected one created task, got %+v, %v", job, err)
+	}
+	created, _ := service.GetByID(job.Rows[0].TaskID, "student")
+	if created.Priority != "high" || len(created.Tags) != 2 || created.DueDate.String() != "2026-03-20" {
+		t.Errorf("Expected imported fields and tags, got %+v", created)
+	}
+
+	// Большой импорт ставится в очередь worker'а
+	service.SetImportQueue(1)
+	queued, err := service.Import(models.ImportRequest{
+		Format: taskio.FormatJSON,
+		Body:   strings.NewReader(`[{"title": "Lab 3"}, {"title": "Lab 4", "done": true}]`),
+	}, "student", ctx)
+	if err != nil || queued.Status != models.ImportQueued || queued.ID == "" {
+		t.Fatalf("Expected queued import, got %+v, %v", queued, err)
+	}
+	status, err := service.ImportStatus(queued.ID, "student")
+	if err != nil || status.Status != models.ImportQueued || status.Total != 2 {
+		t.Errorf("Expected queued import status, got %+v, %v", status, err)
+	}
+	if status, _ := service.ImportStatus(queued.ID, "other"); status.ID != "" {
+		t.Error("Expected import to be hidden from other users")
+	}
+
+	if _, err := service.Import(models.ImportRequest{Format: "xml", Body: strings.NewReader("")}, "student", ctx); err == nil {
+		t.Error("Expected unknown format to be rejected")
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func (s *TasksService) Projects(subject string, includeArchived bool) ([]models.Project, error) {
+	return s.repo.GetProjects(subject, includeArchived)
+}
+
+// GetProject возвращает свой проект или открытый пользователю проект с
+// владельцем и ролью
+func (s *TasksService) GetProject(id string, subject string) (models.Project, error) {
+	access, err := s.repo.ProjectAccess(id, subject)
+	if err != nil || access.Owner == "" {
+		return models.Project{}, err
+	}
+
+	project, err := s.repo.GetProject(id, access.Owner)
+	if err != nil || project.ID == "" || access.Owner == subject {
+		return project, err
+	}
+	project.Owner = access.Owner
+	project.Role = access.Role
+	return project, nil
+}
+
+func (s *TasksService) CreateProject(req models.CreateProjectRequest, subject string) (models.Project, error) {
+	if err := req.Validate(); err != nil {
+		return models.Project{}, err
+	}
+	var project models.Project
+	err := s.inTx(context.Background(), func(tx *TasksService) error {
+		if err := tx.checkQuota(models.QuotaProjects, 1); err != nil {
+			return err
+		}
+		var err error
+		project, err = tx.repo.CreateProject(models.Project{
+			Name:        req.Name,
+			Description: req.Description,
+			Workflow:    req.Workflow,
+		}, subject)
+		return err
+	})
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	s.log.Info("Project created", zap.String("project_id", project.ID), zap.String("subject", subject))
+	return project, nil
+}
+
+func (s *TasksService) UpdateProject(id string, updates models.ProjectUpdate, subject string) (models.Project, error) {
+	if err := updates.Validate(); err != nil {
+		return models.Project{}, err
+	}
+
+	before, err := s.repo.GetProject(id, subject)
+	if err != nil || before.ID == "" {
+		return models.Project{}, err
+	}
+
+	if updates.Workflow != nil {
+		if err := s.checkWorkflowChange(id, *updates.Workflow, subject); err != nil {
+			return models.Project{}, err
+		}
+	}
+
+	project, err := s.repo.UpdateProject(id, updates, subject)
+	if err != nil || project.ID == "" {
+		return models.Project{}, err
+	}
+
+	// Архивация меняет состав списка задач по умолчанию
+	if project.Archived != before.Archived {
+		s.invalidateTaskList(subject)
+		s.log.Info("Project archive state changed",
+			zap.String("project_id", id),
+			zap.Bool("archived", project.Archived),
+		)
+	}
+
+	return project, nil
+}
+
+// DeleteProject удаляет проект, оставляя его задачи без проекта
+func (s *TasksService) DeleteProject(id string, subject string) (bool, error) {
+	project, err := s.repo.GetProject(id, subject)
+	if err != nil || project.ID == "" {
+		return false, err
+	}
+
+	tasks, err := s.repo.List(subject, models.TaskFilter{ProjectID: id})
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteProject(id, subject)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.invalidateTasks(tasks, subject)
+	s.invalidateTaskList(subject)
+
+	s.log.Info("Project deleted", zap.String("project_id", id), zap.Int("tasks_detached", len(tasks)))
+	return true, nil
+}
+
+// checkProject проверяет, что в проект можно поместить задачу
+func (s *TasksService) checkProject(id string, subject string) error {
+	project, err := s.repo.GetProject(id, subject)
+	if err != nil {
+		return err
+	}
+	if project.ID == "" {
+		return models.ErrProjectNotFound
+	}
+	if project.Archived {
+		return models.ErrProjectArchived
+	}
+	return nil
+}
+
+func (s *TasksService) invalidateTaskList(subject string) {
+	if s.batch != nil {
+		s.batch.invalidate("", subject)
+		return
+	}
+	if s.cache == nil || !s.cache.IsEnabled() {
+		return
+	}
+
+	go func() {
+		if err := s.cache.DeleteTaskList(context.Background(), subject); err != nil {
+			s.log.Warn("Failed to invalidate task list cache", zap.Error(err), zap.String("subject", subject))
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskProjects(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	project, err := service.CreateProject(models.CreateProjectRequest{Name: "Home"}, "student")
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	task, err := service.Create(models.Task{Title: "Fix tap", ProjectID: project.ID}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task in project: %v", err)
+	}
+
+	// Чужой проект недоступен
+	if _, err := service.Create(models.Task{Title: "Other", ProjectID: project.ID}, "other", ctx); !errors.Is(err, models.ErrProjectNotFound) {
+		t.Errorf("Expected ErrProjectNotFound, got %v", err)
+	}
+
+	archived := true
+	if _, err := service.UpdateProject(project.ID, models.ProjectUpdate{Archived: &archived}, "student"); err != nil {
+		t.Fatalf("Failed to archive project: %v", err)
+	}
+
+	tasks, _ := service.List("student", models.TaskFilter{})
+	if len(tasks) != 0 {
+		t.Errorf("Expected archived project tasks to be hidden, got %d", len(tasks))
+	}
+
+	// В архивный проект задачи не переносятся
+	if _, err := service.Create(models.Task{Title: "New"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	projectID := project.ID
+	if _, err := service.Update(task.ID, models.TaskUpdate{ProjectID: &projectID}, "student", ctx); !errors.Is(err, models.ErrProjectArchived) {
+		t.Errorf("Expected ErrProjectArchived, got %v", err)
+	}
+
+	// После удаления проекта задача остается без проекта
+	if deleted, err := service.DeleteProject(project.ID, "student"); err != nil || !deleted {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+	got, _ := service.GetByID(task.ID, "student")
+	if got.ProjectID != "" {
+		t.Errorf("Expected task to be detached, got project %q", got.ProjectID)
+	}
+}
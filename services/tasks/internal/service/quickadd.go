@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/services/tasks/internal/quickadd"
+)
+
+// ParseQuickAdd разбирает строку быстрого добавления в часовом поясе
+// пользователя, не создавая задачу
+func (s *TasksService) ParseQuickAdd(text string, subject string) (quickadd.Result, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return quickadd.Result{}, &models.ValidationError{Message: "text is required"}
+	}
+	if len(text) > 1000 {
+		return quickadd.Result{}, &models.ValidationError{Message: "text too long (max 1000 characters)"}
+	}
+
+	parsed := quickadd.Parse(text, time.Now().In(s.location(subject)))
+	if parsed.Title == "" {
+		return quickadd.Result{}, &models.ValidationError{Message: "title is required"}
+	}
+	if len(parsed.Tags) > 0 {
+		tags, err := normalizeTagNames(parsed.Tags)
+		if err != nil {
+			return quickadd.Result{}, err
+		}
+		parsed.Tags = tags
+	}
+	return parsed, nil
+}
+
+// QuickAdd создает задачу из строки быстрого добавления вместе с метками
+func (s *TasksService) QuickAdd(text string, subject string, ctx context.Context) (models.Task, error) {
+	parsed, err := s.ParseQuickAdd(text, subject)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	created, err := s.Create(models.Task{
+		Title:      parsed.Title,
+		DueDate:    parsed.DueDate,
+		Recurrence: parsed.Recurrence,
+		Priority:   parsed.Priority,
+	}, subject, ctx)
+	if err != nil || len(parsed.Tags) == 0 {
+		return created, err
+	}
+
+	if err := s.repo.AddTaskTags(created.ID, parsed.Tags, subject); err != nil {
+		return models.Task{}, err
+	}
+	s.invalidateTask(created.ID, subject)
+	s.log.Info("Task quick-added", zap.String("task_id", created.ID), zap.Strings("tags", parsed.Tags))
+
+	return s.repo.GetByID(created.ID, subject)
+}
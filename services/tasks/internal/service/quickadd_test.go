@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestQuickAdd(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	if _, err := service.ParseQuickAdd("#home !high", "student"); err == nil {
+		t.Error("Expected quick-add without title to be rejected")
+	}
+	task, err := service.QuickAdd("Pay rent every month on the 1st #Home !high", "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to quick-add task: %v", err)
+	}
+	if task.Title != "Pay rent" || task.Priority != "high" || task.Recurrence != "FREQ=MONTHLY" ||
+		task.DueDate.Date().Day() != 1 || len(task.Tags) != 1 || task.Tags[0] != "home" {
+		t.Errorf("Expected fully populated task, got %+v", task)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+)
+
+// startSeries создает серию с первым вхождением task. Срок задачи – DTSTART;
+// задача без срока начинает серию сегодня.
+func (s *TasksService) startSeries(task models.Task, rrule string, subject string) (models.TaskSeries, due.Date, error) {
+	rule, err := models.ParseRRule(rrule)
+	if err != nil {
+		return models.TaskSeries{}, due.Date{}, err
+	}
+
+	dueDate := task.DueDate
+	if dueDate.IsZero() {
+		today := time.Now().In(s.location(subject))
+		dueDate = due.Day(today.Year(), today.Month(), today.Day())
+	}
+
+	task.DueDate = dueDate
+	series, err := s.repo.CreateSeries(models.TaskSeries{
+		ID:        generateUUID(),
+		Subject:   subject,
+		RRule:     rule.String(),
+		StartDate: dueDate.Date().Format(time.DateOnly),
+		Template:  models.SnapshotOf(task),
+	})
+	if err != nil {
+		return models.TaskSeries{}, due.Date{}, err
+	}
+
+	return series, dueDate, nil
+}
+
+// activeSeries возвращает действующую серию задачи или ErrNotRecurring
+func (s *TasksService) activeSeries(task models.Task, subject string) (models.TaskSeries, error) {
+	if task.SeriesID == "" {
+		return models.TaskSeries{}, models.ErrNotRecurring
+	}
+	series, err := s.repo.GetSeries(task.SeriesID, subject)
+	if err != nil {
+		return models.TaskSeries{}, err
+	}
+	if series.ID == "" || series.StoppedAt != nil {
+		return models.TaskSeries{}, models.ErrNotRecurring
+	}
+	return series, nil
+}
+
+// applyRecurrence обрабатывает recurrence в изменении задачи: задача без
+// серии начинает новую, "" останавливает серию после изменения. Правило
+// существующей серии меняется только для этого и следующих вхождений.
+func (s *TasksService) applyRecurrence(before models.Task, updates *models.TaskUpdate, subject string) error {
+	if updates.Recurrence == nil || *updates.Recurrence == "" {
+		return nil
+	}
+	if before.Recurrence != "" {
+		return &models.ValidationError{Message: "recurrence of a series can only be changed with scope=following"}
+	}
+
+	task := before
+	if updates.DueDate != nil {
+		task.DueDate = *updates.DueDate
+	}
+	series, dueDate, err := s.startSeries(task, *updates.Recurrence, subject)
+	if err != nil {
+		return err
+	}
+	updates.SeriesID = &series.ID
+	updates.DueDate = &dueDate
+	return nil
+}
+
+// stopSeries останавливает серию: текущее вхождение остается задачей,
+// следующие не создаются
+func (s *TasksService) stopSeries(task models.Task, subject string) error {
+	series, err := s.activeSeries(task, subject)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	series.StoppedAt = &now
+	if err := s.repo.UpdateSeries(series); err != nil {
+		return err
+	}
+
+	s.log.Info("Recurring series stopped", zap.String("series_id", series.ID), zap.String("task_id", task.ID))
+	return nil
+}
+
+// nextOccurrence создает следующее вхождение серии после выполнения task.
+// Поля берутся из шаблона серии, исполнитель, метки, напоминания и пункты
+// чек-листа (невыполненными) – из выполненного вхождения. Повторное выполнение не создает дубль.
+func (s *TasksService) nextOccurrence(ctx context.Context, task models.Task, subject string) {
+	series, err := s.activeSeries(task, subject)
+	if err != nil {
+		if err != models.ErrNotRecurring {
+			s.log.Error("Failed to load recurring series", zap.Error(err), zap.String("task_id", task.ID))
+		}
+		return
+	}
+
+	dueDate, err := series.Next(task.DueDate, s.location(subject))
+	if err == models.ErrSeriesFinished {
+		s.log.Info("Recurring series finished", zap.String("series_id", series.ID))
+		return
+	}
+	if err != nil {
+		s.log.Error("Failed to compute next occurrence", zap.Error(err), zap.String("series_id", series.ID))
+		return
+	}
+
+	exists, err := s.repo.HasOccurrence(series.ID, dueDate.Date().Format(time.DateOnly), subject)
+	if err != nil || exists {
+		if err != nil {
+			s.log.Error("Failed to check next occurrence", zap.Error(err), zap.String("series_id", series.ID))
+		}
+		return
+	}
+
+	template := series.Template
+	next, err := s.Create(models.Task{
+		Title:           template.Title,
+		Description:     template.Description,
+		DueDate:         dueDate,
+		Priority:        template.Priority,
+		EstimatePoints:  template.EstimatePoints,
+		EstimateMinutes: template.EstimateMinutes,
+		ProjectID:       template.ProjectID,
+		ParentID:        template.ParentID,
+		AutoComplete:    template.AutoComplete,
+		Assignee:        task.Assignee,
+		SeriesID:        series.ID,
+	}, subject, ctx)
+	if err != nil {
+		s.log.Error("Failed to create next occurrence", zap.Error(err), zap.String("series_id", series.ID))
+		return
+	}
+
+	if len(task.Tags) > 0 {
+		if err := s.repo.AddTaskTags(next.ID, task.Tags, subject); err != nil {
+			s.log.Warn("Failed to copy tags to next occurrence", zap.Error(err), zap.String("task_id", next.ID))
+		}
+	}
+	s.copyReminders(task, next, subject)
+	for _, item := range task.Checklist {
+		if _, err := s.repo.AddChecklistItem(next.ID, item.Title, subject); err != nil {
+			s.log.Warn("Failed to copy checklist to next occurrence", zap.Error(err), zap.String("task_id", next.ID))
+			break
+		}
+	}
+
+	s.log.Info("Next occurrence created",
+		zap.String("series_id", series.ID),
+		zap.String("task_id", next.ID),
+		zap.String("due_date", dueDate.String()),
+	)
+}
+
+// SkipOccurrence пропускает вхождение: срок задачи переносится на следующую
+// дату серии. Пустая задача – задачи нет.
+func (s *TasksService) SkipOccurrence(id string, subject string, ctx context.Context) (models.Task, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
+	}
+
+	task, err := s.repo.GetByID(id, owner)
+	if err != nil || task.ID == "" {
+		return models.Task{}, err
+	}
+	series, err := s.activeSeries(task, owner)
+	if err != nil {
+		return models.Task{}, err
+	}
+	if task.Done {
+		return models.Task{}, &models.ValidationError{Message: "completed occurrence cannot be skipped"}
+	}
+	dueDate, err := series.Next(task.DueDate, s.location(owner))
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return s.update(id, models.TaskUpdate{DueDate: &dueDate}, owner, models.ActionUpdated, ctx)
+}
+
+// StopRecurrence останавливает серию задачи
+func (s *TasksService) StopRecurrence(id string, subject string, ctx context.Context) (models.Task, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
+	}
+
+	task, err := s.repo.GetByID(id, owner)
+	if err != nil || task.ID == "" {
+		return models.Task{}, err
+	}
+
+	if err := s.stopSeries(task, owner); err != nil {
+		return models.Task{}, err
+	}
+
+	s.invalidateTask(id, owner)
+	return s.repo.GetByID(id, owner)
+}
+
+// UpdateFollowing изменяет вхождение и шаблон серии для следующих вхождений.
+// Новое правило начинает серию заново с этого вхождения (COUNT считается от него).
+func (s *TasksService) UpdateFollowing(id string, updates models.TaskUpdate, subject string, ctx context.Context) (models.Task, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
+	}
+
+	before, err := s.repo.GetByID(id, owner)
+	if err != nil || before.ID == "" {
+		return models.Task{}, err
+	}
+	series, err := s.activeSeries(before, owner)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	var rule string
+	if updates.Recurrence != nil && *updates.Recurrence != "" {
+		parsed, err := models.ParseRRule(*updates.Recurrence)
+		if err != nil {
+			return models.Task{}, err
+		}
+		rule = parsed.String()
+		updates.Recurrence = nil
+
+		dueDate := before.DueDate
+		if updates.DueDate != nil {
+			dueDate = *updates.DueDate
+		}
+		if dueDate.IsZero() {
+			return models.Task{}, &models.ValidationError{Message: "recurring task requires due_date"}
+		}
+	}
+
+	updated, err := s.update(id, updates, owner, models.ActionUpdated, ctx)
+	if err != nil || updated.ID == "" {
+		return updated, err
+	}
+	// Серия остановлена этим же изменением
+	if updates.Recurrence != nil {
+		return updated, nil
+	}
+
+	series.Template = models.SnapshotOf(updated)
+	if rule != "" {
+		series.RRule = rule
+		series.StartDate = updated.DueDate.Date().Format(time.DateOnly)
+	}
+	if err := s.repo.UpdateSeries(series); err != nil {
+		return models.Task{}, err
+	}
+
+	s.invalidateTask(id, owner)
+	return s.repo.GetByID(id, owner)
+}
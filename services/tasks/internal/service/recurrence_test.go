@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestRecurringTasks(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	date := func(value string) time.Time {
+		parsed, _ := models.ParseDueDate(value)
+		return parsed
+	}
+	rules := []struct {
+		rule, start, after, want string
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,TH", "2026-03-02", "2026-03-02", "2026-03-05"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2026-01-30", "2026-01-30", "2026-02-27"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31", "2026-01-31", "2026-03-31"},
+		{"FREQ=DAILY;INTERVAL=2;COUNT=3", "2026-03-01", "2026-03-03", "2026-03-05"},
+		{"FREQ=DAILY;COUNT=3", "2026-03-01", "2026-03-03", ""},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", "2024-02-29", "2024-02-29", "2028-02-29"},
+	}
+	for _, tc := range rules {
+		rule, err := models.ParseRRule(tc.rule)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tc.rule, err)
+		}
+		next, ok := rule.Next(date(tc.start), date(tc.after))
+		got := ""
+		if ok {
+			got = next.Format(time.DateOnly)
+		}
+		if got != tc.want {
+			t.Errorf("%s after %s: expected %q, got %q", tc.rule, tc.after, tc.want, got)
+		}
+	}
+	if _, err := models.ParseRRule("FREQ=HOURLY"); err == nil {
+		t.Error("Expected unsupported FREQ to be rejected")
+	}
+
+	chore, err := service.Create(models.Task{Title: "Take out trash", DueDate: due.Day(2026, 3, 2), Recurrence: "RRULE:FREQ=WEEKLY;BYDAY=MO"}, "student", ctx)
+	if err != nil || chore.SeriesID == "" || chore.Recurrence != "FREQ=WEEKLY;BYDAY=MO" {
+		t.Fatalf("Failed to create recurring task: %+v, %v", chore, err)
+	}
+	if _, err := service.AddChecklistItem(chore.ID, models.CreateChecklistItemRequest{Title: "Recycling"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to add checklist item: %v", err)
+	}
+
+	// Выполнение создает следующее вхождение один раз
+	done, notDone := true, false
+	for _, value := range []*bool{&done, &notDone, &done} {
+		if _, err := service.Update(chore.ID, models.TaskUpdate{Done: value}, "student", ctx); err != nil {
+			t.Fatalf("Failed to toggle task: %v", err)
+		}
+	}
+	tasks, _ := service.List("student", models.TaskFilter{})
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 occurrences, got %d", len(tasks))
+	}
+	next, _ := service.GetByID(tasks[0].ID, "student")
+	if next.DueDate.String() != "2026-03-09" || next.SeriesID != chore.SeriesID || next.Done || len(next.Checklist) != 1 {
+		t.Errorf("Unexpected next occurrence: %+v", next)
+	}
+
+	// Пропуск переносит срок, изменение следующих меняет шаблон серии
+	skipped, err := service.SkipOccurrence(next.ID, "student", ctx)
+	if err != nil || skipped.DueDate.String() != "2026-03-16" {
+		t.Fatalf("Expected skip to 2026-03-16, got %+v, %v", skipped, err)
+	}
+	title := "Trash and compost"
+	rule := "FREQ=WEEKLY;BYDAY=TU"
+	if _, err := service.Update(next.ID, models.TaskUpdate{Recurrence: &rule}, "student", ctx); err == nil {
+		t.Error("Expected recurrence change without scope to be rejected")
+	}
+	updated, err := service.UpdateFollowing(next.ID, models.TaskUpdate{Title: &title, Recurrence: &rule}, "student", ctx)
+	if err != nil || updated.Recurrence != rule {
+		t.Fatalf("Failed to update following occurrences: %+v, %v", updated, err)
+	}
+	if _, err := service.Update(next.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete occurrence: %v", err)
+	}
+	tasks, _ = service.List("student", models.TaskFilter{})
+	if len(tasks) != 3 || tasks[0].Title != title || tasks[0].DueDate.String() != "2026-03-17" {
+		t.Errorf("Unexpected occurrence after following edit: %+v", tasks[0])
+	}
+
+	// После остановки серии вхождения не создаются
+	if _, err := service.StopRecurrence(tasks[0].ID, "student", ctx); err != nil {
+		t.Fatalf("Failed to stop series: %v", err)
+	}
+	if _, err := service.Update(tasks[0].ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete last occurrence: %v", err)
+	}
+	if tasks, _ = service.List("student", models.TaskFilter{}); len(tasks) != 3 {
+		t.Errorf("Expected no new occurrence after stop, got %d tasks", len(tasks))
+	}
+	if _, err := service.SkipOccurrence(tasks[0].ID, "student", ctx); !errors.Is(err, models.ErrNotRecurring) {
+		t.Errorf("Expected skip of stopped series to fail, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// Reminders возвращает напоминания задачи; nil – задачи нет
+func (s *TasksService) Reminders(taskID string, subject string) ([]models.Reminder, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return nil, err
+	}
+
+	task, err := s.repo.GetByID(taskID, owner)
+	if err != nil || task.ID == "" {
+		return nil, err
+	}
+
+	return s.repo.GetReminders(taskID, owner)
+}
+
+// AddReminder добавляет напоминание о сроке задачи. Без срока напоминание
+// ждет, пока срок не будет задан. Пустое напоминание – задачи нет.
+// Напоминания открытой задачи меняет редактор.
+func (s *TasksService) AddReminder(taskID string, req models.CreateReminderRequest, subject string) (models.Reminder, error) {
+	if err := req.Validate(); err != nil {
+		return models.Reminder{}, err
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Reminder{}, err
+	}
+
+	task, err := s.repo.GetByID(taskID, owner)
+	if err != nil || task.ID == "" {
+		return models.Reminder{}, err
+	}
+
+	reminder, err := s.repo.AddReminder(models.Reminder{
+		TaskID:        taskID,
+		MinutesBefore: req.MinutesBefore,
+		Channel:       req.Channel,
+		Target:        req.Target,
+		FireAt:        models.ReminderFireAt(task.DueDate, s.location(owner), req.MinutesBefore),
+	}, owner)
+	if err != nil || reminder.ID == "" {
+		return models.Reminder{}, err
+	}
+
+	s.log.Info("Reminder added",
+		zap.String("task_id", taskID),
+		zap.String("reminder_id", reminder.ID),
+		zap.String("channel", reminder.Channel),
+	)
+	return reminder, nil
+}
+
+func (s *TasksService) DeleteReminder(taskID string, reminderID string, subject string) (bool, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteReminder(taskID, reminderID, owner)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.log.Info("Reminder deleted", zap.String("task_id", taskID), zap.String("reminder_id", reminderID))
+	return true, nil
+}
+
+// rescheduleReminders пересчитывает напоминания после смены срока: уже
+// отправленные снова ждут отправки по новому сроку
+func (s *TasksService) rescheduleReminders(task models.Task, subject string) {
+	reminders, err := s.repo.GetReminders(task.ID, subject)
+	if err != nil {
+		s.log.Error("Failed to load reminders", zap.Error(err), zap.String("task_id", task.ID))
+		return
+	}
+
+	loc := s.location(subject)
+	for _, reminder := range reminders {
+		fireAt := models.ReminderFireAt(task.DueDate, loc, reminder.MinutesBefore)
+		if err := s.repo.RescheduleReminder(reminder.ID, fireAt); err != nil {
+			s.log.Error("Failed to reschedule reminder", zap.Error(err), zap.String("reminder_id", reminder.ID))
+		}
+	}
+}
+
+// copyReminders переносит напоминания на следующее вхождение серии
+func (s *TasksService) copyReminders(from models.Task, to models.Task, subject string) {
+	reminders, err := s.repo.GetReminders(from.ID, subject)
+	if err != nil {
+		s.log.Warn("Failed to load reminders of occurrence", zap.Error(err), zap.String("task_id", from.ID))
+		return
+	}
+
+	loc := s.location(subject)
+	for _, reminder := range reminders {
+		_, err := s.repo.AddReminder(models.Reminder{
+			TaskID:        to.ID,
+			MinutesBefore: reminder.MinutesBefore,
+			Channel:       reminder.Channel,
+			Target:        reminder.Target,
+			FireAt:        models.ReminderFireAt(to.DueDate, loc, reminder.MinutesBefore),
+		}, subject)
+		if err != nil {
+			s.log.Warn("Failed to copy reminders to next occurrence", zap.Error(err), zap.String("task_id", to.ID))
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestSharedTaskReminders(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task := createTestTask(service, "Shared report", "student", t)
+	if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to share task: %v", err)
+	}
+	reminder, err := service.AddReminder(task.ID, models.CreateReminderRequest{MinutesBefore: 60}, "student")
+	if err != nil || reminder.ID == "" {
+		t.Fatalf("Failed to add reminder: %+v, %v", reminder, err)
+	}
+
+	// Наблюдатель видит напоминания, но не меняет их
+	if reminders, err := service.Reminders(task.ID, "teacher"); err != nil || len(reminders) != 1 {
+		t.Errorf("Expected viewer to see reminders, got %+v, %v", reminders, err)
+	}
+	if _, err := service.AddReminder(task.ID, models.CreateReminderRequest{MinutesBefore: 30}, "teacher"); !errors.Is(err, models.ErrAccessDenied) {
+		t.Errorf("Expected viewer reminder to be denied, got %v", err)
+	}
+	if _, err := service.DeleteReminder(task.ID, reminder.ID, "teacher"); !errors.Is(err, models.ErrAccessDenied) {
+		t.Errorf("Expected viewer reminder delete to be denied, got %v", err)
+	}
+
+	if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher", Role: models.RoleEditor}, "student", ctx); err != nil {
+		t.Fatalf("Failed to change role: %v", err)
+	}
+	added, err := service.AddReminder(task.ID, models.CreateReminderRequest{MinutesBefore: 30}, "teacher")
+	if err != nil || added.ID == "" || added.TaskID != task.ID {
+		t.Fatalf("Expected editor to add reminder, got %+v, %v", added, err)
+	}
+	if deleted, err := service.DeleteReminder(task.ID, added.ID, "teacher"); err != nil || !deleted {
+		t.Errorf("Expected editor to delete reminder, got %v", err)
+	}
+
+	if other, err := service.AddReminder(task.ID, models.CreateReminderRequest{}, "guest"); err != nil || other.ID != "" {
+		t.Errorf("Expected task to be missing for stranger, got %+v, %v", other, err)
+	}
+}
+
+func TestTaskReminders(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task, err := service.Create(models.Task{Title: "Report", DueDate: due.Day(2026, 3, 10)}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	bad := []models.CreateReminderRequest{
+		{MinutesBefore: -1},
+		{Channel: "sms"},
+		{Channel: "webhook", Target: "ftp://example.com"},
+		{Channel: "email", Target: "not-an-address"},
+	}
+	for _, req := range bad {
+		if _, err := service.AddReminder(task.ID, req, "student"); err == nil {
+			t.Errorf("Expected reminder %+v to be rejected", req)
+		}
+	}
+
+	reminder, err := service.AddReminder(task.ID, models.CreateReminderRequest{MinutesBefore: 24 * 60, Channel: "Email", Target: "Student <student@example.com>"}, "student")
+	if err != nil || reminder.ID == "" {
+		t.Fatalf("Failed to add reminder: %+v, %v", reminder, err)
+	}
+	if reminder.Channel != models.ReminderChannelEmail || reminder.Target != "student@example.com" {
+		t.Errorf("Expected normalized email reminder, got %+v", reminder)
+	}
+	if reminder.FireAt == nil || !reminder.FireAt.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected reminder a day before due date, got %v", reminder.FireAt)
+	}
+	if other, _ := service.AddReminder(task.ID, models.CreateReminderRequest{}, "other"); other.ID != "" {
+		t.Error("Expected reminder on foreign task to be rejected")
+	}
+
+	// Смена срока пересчитывает напоминание, снятие срока откладывает его
+	dueDate := due.Day(2026, 4, 1)
+	if _, err := service.Update(task.ID, models.TaskUpdate{DueDate: &dueDate}, "student", ctx); err != nil {
+		t.Fatalf("Failed to update due date: %v", err)
+	}
+	reminders, err := service.Reminders(task.ID, "student")
+	if err != nil || len(reminders) != 1 || !reminders[0].FireAt.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected rescheduled reminder, got %+v, %v", reminders, err)
+	}
+	noDate := due.Date{}
+	if _, err := service.Update(task.ID, models.TaskUpdate{DueDate: &noDate}, "student", ctx); err != nil {
+		t.Fatalf("Failed to clear due date: %v", err)
+	}
+	if reminders, _ := service.Reminders(task.ID, "student"); len(reminders) != 1 || reminders[0].FireAt != nil {
+		t.Errorf("Expected pending reminder without fire time, got %+v", reminders)
+	}
+
+	// Напоминания переходят к следующему вхождению серии
+	rule := "FREQ=WEEKLY"
+	weekly, err := service.Create(models.Task{Title: "Weekly", DueDate: due.Day(2026, 3, 2), Recurrence: rule}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create recurring task: %v", err)
+	}
+	if _, err := service.AddReminder(weekly.ID, models.CreateReminderRequest{MinutesBefore: 60}, "student"); err != nil {
+		t.Fatalf("Failed to add reminder: %v", err)
+	}
+	done := true
+	if _, err := service.Update(weekly.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete occurrence: %v", err)
+	}
+	tasks, err := service.List("student", models.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	var next models.Task
+	for _, candidate := range tasks {
+		if candidate.SeriesID == weekly.SeriesID && !candidate.Done {
+			next = candidate
+		}
+	}
+	reminders, err = service.Reminders(next.ID, "student")
+	if err != nil || len(reminders) != 1 || !reminders[0].FireAt.Equal(time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected reminder copied to next occurrence, got %+v, %v", reminders, err)
+	}
+
+	if deleted, err := service.DeleteReminder(task.ID, reminder.ID, "student"); err != nil || !deleted {
+		t.Errorf("Failed to delete reminder: %v", err)
+	}
+	if deleted, _ := service.DeleteReminder(task.ID, reminder.ID, "student"); deleted {
+		t.Error("Expected second delete to report missing reminder")
+	}
+}
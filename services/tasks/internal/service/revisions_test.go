@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestRevisionsShareTransactionWithChange(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	created := createTestTask(service, "Original", "student", t)
+
+	// Занятый номер ревизии ломает запись истории: изменение откатывается
+	if err := service.repo.AddRevision(models.TaskRevision{
+		TaskID: created.ID, Revision: created.Version + 1, Subject: "student",
+		Action: models.ActionUpdated, Actor: "student",
+	}); err != nil {
+		t.Fatalf("Failed to add revision: %v", err)
+	}
+	title := "Renamed"
+	if _, err := service.Update(created.ID, models.TaskUpdate{Title: &title}, "student", ctx); err == nil {
+		t.Fatal("Expected update to fail when revision cannot be recorded")
+	}
+	task, err := service.GetByID(created.ID, "student")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.Title != "Original" || task.Version != created.Version {
+		t.Errorf("Expected update to be rolled back, got %+v", task)
+	}
+
+	// Ревизия удаления несет версию, которую вернула база
+	other := createTestTask(service, "Other", "student", t)
+	if deleted, err := service.Delete(other.ID, "student", ctx); err != nil || !deleted {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	history, err := service.History(other.ID, "student")
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 2 || history[0].Action != models.ActionDeleted || history[0].Revision != other.Version+1 {
+		t.Errorf("Unexpected history after delete: %+v", history)
+	}
+}
+
+func TestTaskHistoryAndRevert(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	created := createTestTask(service, "Original", "student", t)
+
+	title := "Renamed"
+	done := true
+	if _, err := service.Update(created.ID, models.TaskUpdate{Title: &title, Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	history, err := service.History(created.ID, "student")
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 revisions, got %d", len(history))
+	}
+
+	latest := history[0]
+	if latest.Revision != 2 || latest.Action != models.ActionUpdated || latest.Actor != "student" {
+		t.Errorf("Unexpected latest revision: %+v", latest)
+	}
+	if len(latest.Changes) != 3 || latest.Changes[0].Field != "title" || latest.Changes[0].Old != "Original" {
+		t.Errorf("Unexpected changes: %+v", latest.Changes)
+	}
+
+	// Откат к первой ревизии создает новую ревизию
+	reverted, err := service.Revert(created.ID, 1, nil, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to revert task: %v", err)
+	}
+	if reverted.Title != "Original" || reverted.Done || reverted.Version != 3 {
+		t.Errorf("Unexpected reverted task: %+v", reverted)
+	}
+
+	history, _ = service.History(created.ID, "student")
+	if len(history) != 3 || history[0].Action != models.ActionReverted {
+		t.Errorf("Expected revert to be recorded, got %+v", history)
+	}
+
+	if _, err := service.Revert(created.ID, 42, nil, "student", ctx); err != models.ErrRevisionNotFound {
+		t.Errorf("Expected ErrRevisionNotFound, got %v", err)
+	}
+
+	// Чужая история не видна
+	if other, _ := service.History(created.ID, "other"); len(other) != 0 {
+		t.Errorf("Expected no history for other subject, got %d", len(other))
+	}
+}
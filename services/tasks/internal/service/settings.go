@@ -0,0 +1,55 @@
+package service
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func (s *TasksService) Settings(subject string) (models.UserSettings, error) {
+	return s.repo.GetSettings(subject)
+}
+
+// UpdateSettings сохраняет настройки пользователя. Новый часовой пояс влияет
+// на сроки без смещения, границы дня и напоминания, заданные после изменения.
+func (s *TasksService) UpdateSettings(req models.UpdateSettingsRequest, subject string) (models.UserSettings, error) {
+	if err := req.Validate(); err != nil {
+		return models.UserSettings{}, err
+	}
+
+	settings, err := s.repo.GetSettings(subject)
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+
+	saved, err := s.repo.SaveSettings(settings, subject)
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+
+	s.log.Info("User settings updated", zap.String("subject", subject), zap.String("timezone", saved.Timezone))
+	return saved, nil
+}
+
+// location возвращает часовой пояс пользователя; при ошибке чтения – UTC
+func (s *TasksService) location(subject string) *time.Location {
+	settings, err := s.repo.GetSettings(subject)
+	if err != nil {
+		s.log.Warn("Failed to load user settings, using UTC", zap.Error(err), zap.String("subject", subject))
+		return time.UTC
+	}
+	return settings.Location()
+}
+
+// setDueFlags пересчитывает overdue и due_today задач из кэша
+func (s *TasksService) setDueFlags(tasks []models.Task, subject string) {
+	now := time.Now()
+	loc := s.location(subject)
+	for i := range tasks {
+		tasks[i].SetDueFlags(now, loc)
+	}
+}
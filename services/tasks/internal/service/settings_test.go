@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestDueDatesAndTimezones(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	invalid := "Mars/Olympus"
+	if _, err := service.UpdateSettings(models.UpdateSettingsRequest{Timezone: &invalid}, "student"); err == nil {
+		t.Error("Expected unknown timezone to be rejected")
+	}
+	moscow := "Europe/Moscow"
+	if settings, err := service.UpdateSettings(models.UpdateSettingsRequest{Timezone: &moscow}, "student"); err != nil || settings.Timezone != moscow {
+		t.Fatalf("Failed to update settings: %+v, %v", settings, err)
+	}
+
+	// Время без смещения – в часовом поясе пользователя
+	local, err := due.Parse("2026-03-10T18:00")
+	if err != nil {
+		t.Fatalf("Failed to parse local due date: %v", err)
+	}
+	timed, err := service.Create(models.Task{Title: "Timed", DueDate: local}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if timed.DueDate.String() != "2026-03-10T15:00:00Z" {
+		t.Errorf("Expected due date resolved in Europe/Moscow, got %s", timed.DueDate)
+	}
+	data, _ := json.Marshal(models.Task{})
+	if !strings.Contains(string(data), `"due_date":null`) {
+		t.Errorf("Expected null due_date, got %s", data)
+	}
+
+	now := time.Now().In(time.FixedZone("MSK", 3*60*60))
+	yesterday := now.AddDate(0, 0, -1)
+	overdue, _ := service.Create(models.Task{Title: "Overdue", DueDate: due.Day(yesterday.Year(), yesterday.Month(), yesterday.Day())}, "student", ctx)
+	today, _ := service.Create(models.Task{Title: "Today", DueDate: due.Day(now.Year(), now.Month(), now.Day())}, "student", ctx)
+	if !overdue.Overdue || overdue.DueToday {
+		t.Errorf("Expected overdue flags, got %+v", overdue)
+	}
+	if today.Overdue || !today.DueToday {
+		t.Errorf("Expected due today flags, got %+v", today)
+	}
+
+	tasks, err := service.List("student", models.TaskFilter{Due: models.DueFilterOverdue})
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Expected 2 overdue tasks, got %d, %v", len(tasks), err)
+	}
+	tasks, err = service.List("student", models.TaskFilter{Due: models.DueFilterToday})
+	if err != nil || len(tasks) != 1 || tasks[0].ID != today.ID {
+		t.Fatalf("Expected task due today, got %+v, %v", tasks, err)
+	}
+	if _, err := service.List("student", models.TaskFilter{Due: "tomorrow"}); err == nil {
+		t.Error("Expected invalid due filter to be rejected")
+	}
+
+	// Выполненная задача не просрочена
+	done := true
+	if updated, err := service.Update(overdue.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil || updated.Overdue {
+		t.Errorf("Expected completed task not to be overdue, got %+v, %v", updated, err)
+	}
+}
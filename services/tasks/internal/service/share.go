@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// taskOwner возвращает владельца задачи, если у subject есть к ней доступ с
+// ролью не ниже role. "" – задачи нет или она не открыта пользователю,
+// ErrAccessDenied – роль недостаточна.
+func (s *TasksService) taskOwner(id string, subject string, role string) (string, error) {
+	access, err := s.repo.TaskAccess(id, subject)
+	if err != nil || access.Owner == "" {
+		return "", err
+	}
+	if !access.Allows(role) {
+		return "", models.ErrAccessDenied
+	}
+	return access.Owner, nil
+}
+
+// TaskShares возвращает доступы к задаче; nil – задачи нет у пользователя
+func (s *TasksService) TaskShares(id string, subject string) ([]models.Share, error) {
+	return s.shares(models.ShareTask, id, subject)
+}
+
+func (s *TasksService) ProjectShares(id string, subject string) ([]models.Share, error) {
+	return s.shares(models.ShareProject, id, subject)
+}
+
+// ShareTask открывает задачу пользователю или меняет его роль. Управлять
+// доступом может только владелец; пустой доступ – задачи нет.
+func (s *TasksService) ShareTask(id string, req models.ShareRequest, subject string, ctx context.Context) (models.Share, error) {
+	return s.share(models.ShareTask, id, req, subject, ctx)
+}
+
+func (s *TasksService) ShareProject(id string, req models.ShareRequest, subject string, ctx context.Context) (models.Share, error) {
+	return s.share(models.ShareProject, id, req, subject, ctx)
+}
+
+// UnshareTask отзывает доступ; false – задачи или доступа нет
+func (s *TasksService) UnshareTask(id string, grantee string, subject string, ctx context.Context) (bool, error) {
+	return s.unshare(models.ShareTask, id, grantee, subject, ctx)
+}
+
+func (s *TasksService) UnshareProject(id string, grantee string, subject string, ctx context.Context) (bool, error) {
+	return s.unshare(models.ShareProject, id, grantee, subject, ctx)
+}
+
+// SharedTasks возвращает задачи, открытые пользователю другими
+func (s *TasksService) SharedTasks(subject string) ([]models.Task, error) {
+	return s.repo.SharedTasks(subject)
+}
+
+func (s *TasksService) SharedProjects(subject string) ([]models.Project, error) {
+	return s.repo.SharedProjects(subject)
+}
+
+// owns проверяет, что задача или проект принадлежит subject
+func (s *TasksService) owns(resourceType string, id string, subject string) (bool, error) {
+	var access models.Access
+	var err error
+	if resourceType == models.ShareProject {
+		access, err = s.repo.ProjectAccess(id, subject)
+	} else {
+		access, err = s.repo.TaskAccess(id, subject)
+	}
+	return access.Role == models.RoleOwner, err
+}
+
+func (s *TasksService) shares(resourceType string, id string, subject string) ([]models.Share, error) {
+	owned, err := s.owns(resourceType, id, subject)
+	if err != nil || !owned {
+		return nil, err
+	}
+	return s.repo.GetShares(resourceType, id, subject)
+}
+
+func (s *TasksService) share(resourceType string, id string, req models.ShareRequest, subject string, ctx context.Context) (models.Share, error) {
+	if err := req.Validate(subject); err != nil {
+		return models.Share{}, err
+	}
+	owned, err := s.owns(resourceType, id, subject)
+	if err != nil || !owned {
+		return models.Share{}, err
+	}
+	if err := s.checkMember(req.Subject); err != nil {
+		return models.Share{}, err
+	}
+
+	share, err := s.repo.SaveShare(models.Share{
+		ResourceType: resourceType,
+		ResourceID:   id,
+		Owner:        subject,
+		Subject:      req.Subject,
+		Role:         req.Role,
+	})
+	if err != nil {
+		return models.Share{}, err
+	}
+
+	s.log.Info("Access granted",
+		zap.String("resource_type", resourceType),
+		zap.String("resource_id", id),
+		zap.String("grantee", share.Subject),
+		zap.String("role", share.Role),
+	)
+	s.publishShareEvent(ctx, resourceType+".shared", share)
+	return share, nil
+}
+
+func (s *TasksService) unshare(resourceType string, id string, grantee string, subject string, ctx context.Context) (bool, error) {
+	owned, err := s.owns(resourceType, id, subject)
+	if err != nil || !owned {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteShare(resourceType, id, grantee, subject)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.log.Info("Access revoked",
+		zap.String("resource_type", resourceType),
+		zap.String("resource_id", id),
+		zap.String("grantee", grantee),
+	)
+	s.releaseAssignments(subject, grantee)
+	s.publishShareEvent(ctx, resourceType+".unshared", models.Share{
+		ResourceType: resourceType,
+		ResourceID:   id,
+		Owner:        subject,
+		Subject:      grantee,
+	})
+	return true, nil
+}
+
+func (s *TasksService) publishShareEvent(ctx context.Context, event string, share models.Share) {
+	if s.rabbitPub == nil {
+		return
+	}
+
+	requestID := middleware.GetRequestID(ctx)
+	go func() {
+		pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if err := s.rabbitPub.PublishShareEvent(pubCtx, event, share, requestID); err != nil {
+			s.log.Error("Failed to publish "+event+" event",
+				zap.Error(err),
+				zap.String("resource_id", share.ResourceID),
+			)
+		}
+	}()
+}
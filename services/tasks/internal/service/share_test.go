@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskSharing(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	task := createTestTask(service, "Shared report", "student", t)
+	if got, _ := service.GetByID(task.ID, "teacher"); got.ID != "" {
+		t.Fatal("Expected task to be hidden before sharing")
+	}
+
+	bad := []models.ShareRequest{
+		{Subject: "student"},
+		{Subject: "teacher", Role: "owner"},
+		{},
+	}
+	for _, req := range bad {
+		if _, err := service.ShareTask(task.ID, req, "student", ctx); err == nil {
+			t.Errorf("Expected share %+v to be rejected", req)
+		}
+	}
+
+	share, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx)
+	if err != nil || share.Role != models.RoleViewer {
+		t.Fatalf("Failed to share task: %+v, %v", share, err)
+	}
+	got, err := service.GetByID(task.ID, "teacher")
+	if err != nil || got.ID != task.ID || got.Owner != "student" || got.Role != models.RoleViewer {
+		t.Fatalf("Expected shared task for viewer, got %+v, %v", got, err)
+	}
+	title := "Edited by teacher"
+	if _, err := service.Update(task.ID, models.TaskUpdate{Title: &title}, "teacher", ctx); !errors.Is(err, models.ErrAccessDenied) {
+		t.Errorf("Expected viewer update to be denied, got %v", err)
+	}
+	if other, _ := service.ShareTask(task.ID, models.ShareRequest{Subject: "guest"}, "teacher", ctx); other.ResourceID != "" {
+		t.Error("Expected only owner to manage access")
+	}
+
+	// Повторная выдача меняет роль
+	if _, err := service.ShareTask(task.ID, models.ShareRequest{Subject: "teacher", Role: "editor"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to change role: %v", err)
+	}
+	updated, err := service.Update(task.ID, models.TaskUpdate{Title: &title}, "teacher", ctx)
+	if err != nil || updated.Title != title {
+		t.Fatalf("Expected editor update, got %+v, %v", updated, err)
+	}
+	if own, _ := service.GetByID(task.ID, "student"); own.Title != title || own.Role != "" {
+		t.Errorf("Expected owner to see edit without role, got %+v", own)
+	}
+	if shares, _ := service.TaskShares(task.ID, "student"); len(shares) != 1 || shares[0].Role != models.RoleEditor {
+		t.Errorf("Expected one editor share, got %+v", shares)
+	}
+
+	// Доступ к проекту открывает его задачи
+	project, err := service.CreateProject(models.CreateProjectRequest{Name: "Course"}, "student")
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	inProject, err := service.Create(models.Task{Title: "Lab 1", ProjectID: project.ID}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := service.ShareProject(project.ID, models.ShareRequest{Subject: "teacher"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to share project: %v", err)
+	}
+	if got, _ := service.GetByID(inProject.ID, "teacher"); got.Role != models.RoleViewer {
+		t.Errorf("Expected project task for viewer, got %+v", got)
+	}
+	tasks, err := service.List("teacher", models.TaskFilter{ProjectID: project.ID})
+	if err != nil || len(tasks) != 1 || tasks[0].ID != inProject.ID {
+		t.Errorf("Expected shared project tasks, got %+v, %v", tasks, err)
+	}
+	shared, err := service.SharedTasks("teacher")
+	if err != nil || len(shared) != 2 {
+		t.Errorf("Expected 2 shared tasks, got %d, %v", len(shared), err)
+	}
+	if projects, _ := service.SharedProjects("teacher"); len(projects) != 1 || projects[0].Owner != "student" {
+		t.Errorf("Expected shared project, got %+v", projects)
+	}
+
+	if revoked, err := service.UnshareTask(task.ID, "teacher", "student", ctx); err != nil || !revoked {
+		t.Fatalf("Failed to revoke access: %v", err)
+	}
+	if got, _ := service.GetByID(task.ID, "teacher"); got.ID != "" {
+		t.Error("Expected task to be hidden after revoking access")
+	}
+}
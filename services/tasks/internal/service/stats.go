@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// Stats возвращает статистику задач пользователя за период. Результат
+// кэшируется в Redis до изменения задач пользователя или истечения TTL.
+func (s *TasksService) Stats(query models.StatsQuery, subject string, ctx context.Context) (models.TaskStats, error) {
+	loc := s.location(subject)
+	period, err := query.Resolve(time.Now(), loc)
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+
+	// Поле кэша включает часовой пояс: от него зависят границы дней
+	key := period.From.Format(time.DateOnly) + ":" + period.To.Format(time.DateOnly) + ":" + loc.String()
+	if s.cache != nil && s.cache.IsEnabled() {
+		cached, err := s.cache.GetStats(ctx, subject, key)
+		if err != nil {
+			s.log.Warn("Cache read error for stats, falling back to database", zap.Error(err), zap.String("subject", subject))
+		} else if cached != nil {
+			return *cached, nil
+		}
+	}
+
+	stats, err := s.computeStats(period, loc, subject)
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+
+	if s.cache != nil && s.cache.IsEnabled() {
+		go func() {
+			if err := s.cache.SetStats(context.Background(), subject, key, &stats); err != nil {
+				s.log.Warn("Failed to cache stats", zap.Error(err), zap.String("subject", subject))
+			}
+		}()
+	}
+	return stats, nil
+}
+
+func (s *TasksService) computeStats(period models.StatsRange, loc *time.Location, subject string) (models.TaskStats, error) {
+	stats := models.TaskStats{
+		From:     period.From.Format(time.DateOnly),
+		To:       period.To.Format(time.DateOnly),
+		Timezone: loc.String(),
+	}
+
+	byStatus, err := s.repo.GetStatusCounts(subject)
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+	stats.ByStatus = byStatus
+	for _, count := range byStatus {
+		stats.Total += count
+	}
+
+	overdue, err := s.repo.GetOverdueCounts(subject, loc)
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+	stats.OverdueByPriority = make(map[string]int, len(overdue))
+	for priority, count := range overdue {
+		if priority == "" {
+			priority = "none"
+		}
+		stats.OverdueByPriority[priority] += count
+		stats.Overdue += count
+	}
+
+	days := period.Days()
+	timeline, err := s.repo.GetTaskTimeline(subject, days)
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+
+	stats.Completed, stats.AvgCompletionSeconds, err = s.repo.GetCompletionTime(subject, days[0], days[len(days)-1])
+	if err != nil {
+		return models.TaskStats{}, err
+	}
+
+	// days[i] – начало дня i, days[i+1] – его конец
+	stats.Burndown = make([]models.BurndownPoint, 0, len(days)-1)
+	for i, day := range days[:len(days)-1] {
+		stats.Burndown = append(stats.Burndown, models.BurndownPoint{
+			Date: day.Format(time.DateOnly),
+			Open: timeline.Open(i + 1),
+		})
+	}
+
+	stats.Weeks = []models.WeekStats{}
+	start := 0
+	for i := 1; i < len(days); i++ {
+		if i < len(days)-1 && days[i].Weekday() != time.Monday {
+			continue
+		}
+		created := timeline.CreatedBefore[i] - timeline.CreatedBefore[start]
+		completed := timeline.CompletedBefore[i] - timeline.CompletedBefore[start]
+		stats.Weeks = append(stats.Weeks, models.WeekStats{
+			WeekStart:      days[start].Format(time.DateOnly),
+			Created:        created,
+			Completed:      completed,
+			CompletionRate: models.CompletionRate(completed, timeline.Open(start)+created),
+		})
+		start = i
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskStats(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	// Сроки 2026-03-10 уже прошли
+	createTestTask(service, "Lab 1", "student", t)
+	_, err := service.Create(models.Task{Title: "Lab 2", DueDate: due.Day(2026, 3, 10), Priority: models.PriorityUrgent}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	done := createTestTask(service, "Lab 3", "student", t)
+	trashed := createTestTask(service, "Lab 4", "student", t)
+	completed := true
+	if _, err := service.Update(done.ID, models.TaskUpdate{Done: &completed}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if _, err := service.Delete(trashed.ID, "student", ctx); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	stats, err := service.Stats(models.StatsQuery{}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+	if stats.Total != 3 || stats.ByStatus["done"] != 1 || stats.ByStatus["todo"] != 2 {
+		t.Errorf("Expected 3 tasks without trash, got %+v", stats.ByStatus)
+	}
+	if stats.Overdue != 2 || stats.OverdueByPriority["urgent"] != 1 || stats.OverdueByPriority["none"] != 1 {
+		t.Errorf("Expected overdue tasks by priority, got %+v", stats.OverdueByPriority)
+	}
+	if stats.Completed != 1 || stats.AvgCompletionSeconds < 0 {
+		t.Errorf("Expected one completed task, got %d", stats.Completed)
+	}
+	if len(stats.Burndown) != models.DefaultStatsDays || stats.Burndown[len(stats.Burndown)-1].Open != 2 || stats.Burndown[0].Open != 0 {
+		t.Errorf("Expected burndown ending with 2 open tasks, got %+v", stats.Burndown)
+	}
+
+	created, weekCompleted := 0, 0
+	for _, week := range stats.Weeks {
+		created += week.Created
+		weekCompleted += week.Completed
+	}
+	last := stats.Weeks[len(stats.Weeks)-1]
+	if created != 3 || weekCompleted != 1 || last.CompletionRate <= 0 || last.CompletionRate > 1 {
+		t.Errorf("Unexpected weekly stats: %+v", stats.Weeks)
+	}
+
+	// Задачи, созданные после периода, не попадают в статистику за него
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+	stats, _ = service.Stats(models.StatsQuery{From: yesterday, To: yesterday}, "student", ctx)
+	if len(stats.Burndown) != 1 || stats.Burndown[0].Open != 0 || stats.Completed != 0 {
+		t.Errorf("Expected empty history for yesterday, got %+v", stats)
+	}
+
+	if _, err := service.Stats(models.StatsQuery{From: "2026-03-10", To: "2026-03-01"}, "student", ctx); err == nil {
+		t.Error("Expected reversed period to be rejected")
+	}
+	if _, err := service.Stats(models.StatsQuery{From: "2025-01-01", To: "2026-03-01"}, "student", ctx); err == nil {
+		t.Error("Expected too long period to be rejected")
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// checkParent проверяет, что задачу id можно поместить под parentID.
+// Для новой задачи id пустой.
+func (s *TasksService) checkParent(id string, parentID string, subject string) error {
+	if parentID == id {
+		return models.ErrTaskCycle
+	}
+
+	parent, err := s.repo.GetByID(parentID, subject)
+	if err != nil {
+		return err
+	}
+	if parent.ID == "" {
+		return models.ErrParentNotFound
+	}
+
+	// Глубина родителя и проверка, что задача не является его предком
+	depth := 1
+	for ancestor := parent.ParentID; ancestor != ""; depth++ {
+		if ancestor == id {
+			return models.ErrTaskCycle
+		}
+		if depth > models.MaxTaskDepth {
+			return models.ErrTaskTooDeep
+		}
+		ancestor, _, err = s.repo.GetParentID(ancestor, subject)
+		if err != nil {
+			return err
+		}
+	}
+
+	height := 1
+	if id != "" {
+		height, err = s.subtreeHeight(id, subject, 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	if depth+height > models.MaxTaskDepth {
+		return models.ErrTaskTooDeep
+	}
+	return nil
+}
+
+// subtreeHeight возвращает число уровней в поддереве задачи; обход
+// прекращается, как только глубина превысила допустимую
+func (s *TasksService) subtreeHeight(id string, subject string, level int) (int, error) {
+	if level > models.MaxTaskDepth {
+		return level, nil
+	}
+
+	children, err := s.repo.GetChildIDs(id, subject)
+	if err != nil {
+		return 0, err
+	}
+
+	height := 1
+	for _, child := range children {
+		h, err := s.subtreeHeight(child, subject, level+1)
+		if err != nil {
+			return 0, err
+		}
+		if h+1 > height {
+			height = h + 1
+		}
+	}
+	return height, nil
+}
+
+// autoComplete выполняет задачу с auto_complete, если выполнены все ее
+// подзадачи и пункты чек-листа. Выполнение задачи в свою очередь
+// проверяет ее родителя. Ошибка не отменяет исходное изменение и только
+// логируется.
+func (s *TasksService) autoComplete(ctx context.Context, id string, subject string) {
+	if id == "" {
+		return
+	}
+
+	task, err := s.repo.GetByID(id, subject)
+	if err != nil {
+		s.log.Error("Failed to load task for auto-complete", zap.Error(err), zap.String("task_id", id))
+		return
+	}
+	if task.ID == "" {
+		return
+	}
+
+	// Заблокированная задача выполнится при следующем изменении подзадач
+	if !task.AutoComplete || task.Done || !task.Progress.Complete() || (s.enforceDependencies && task.Blocked) {
+		// Прогресс родителя в кэше устарел
+		s.invalidateTask(id, subject)
+		return
+	}
+
+	done := true
+	if _, err := s.update(id, models.TaskUpdate{Done: &done}, subject, models.ActionUpdated, ctx); err != nil {
+		s.log.Error("Failed to auto-complete task", zap.Error(err), zap.String("task_id", id))
+		return
+	}
+	s.log.Info("Task auto-completed", zap.String("task_id", id))
+}
+
+// Subtasks возвращает прямые подзадачи; пустой список и false, если задачи нет
+func (s *TasksService) Subtasks(id string, subject string) ([]models.Task, bool, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return nil, false, err
+	}
+
+	task, err := s.repo.GetByID(id, owner)
+	if err != nil || task.ID == "" {
+		return nil, false, err
+	}
+
+	tasks, err := s.repo.List(owner, models.TaskFilter{ParentID: id})
+	return tasks, true, err
+}
+
+// AddChecklistItem добавляет пункт в чек-лист; пустой пункт – задачи нет
+func (s *TasksService) AddChecklistItem(taskID string, req models.CreateChecklistItemRequest, subject string, ctx context.Context) (models.ChecklistItem, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.ChecklistItem{}, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	item, err := s.repo.AddChecklistItem(taskID, req.Title, owner)
+	if err != nil || item.ID == "" {
+		return models.ChecklistItem{}, err
+	}
+
+	s.invalidateTask(taskID, owner)
+	s.log.Info("Checklist item added", zap.String("task_id", taskID), zap.String("item_id", item.ID))
+	return item, nil
+}
+
+func (s *TasksService) UpdateChecklistItem(taskID string, itemID string, updates models.ChecklistItemUpdate, subject string, ctx context.Context) (models.ChecklistItem, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.ChecklistItem{}, err
+	}
+
+	if err := updates.Validate(); err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	item, err := s.repo.UpdateChecklistItem(taskID, itemID, updates, owner)
+	if err != nil || item.ID == "" {
+		return models.ChecklistItem{}, err
+	}
+
+	if updates.Done != nil && *updates.Done {
+		s.autoComplete(ctx, taskID, owner)
+	} else {
+		s.invalidateTask(taskID, owner)
+	}
+
+	return item, nil
+}
+
+func (s *TasksService) DeleteChecklistItem(taskID string, itemID string, subject string, ctx context.Context) (bool, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteChecklistItem(taskID, itemID, owner)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	// Удаление последнего невыполненного пункта завершает задачу
+	s.autoComplete(ctx, taskID, owner)
+	return true, nil
+}
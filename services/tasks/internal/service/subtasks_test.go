@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestSubtasksAndChecklist(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	parent, err := service.Create(models.Task{Title: "Move", AutoComplete: true}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	child, err := service.Create(models.Task{Title: "Pack", ParentID: parent.ID}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+	grandchild, err := service.Create(models.Task{Title: "Buy boxes", ParentID: child.ID}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create nested subtask: %v", err)
+	}
+
+	// Глубина ограничена MaxTaskDepth
+	if _, err := service.Create(models.Task{Title: "Too deep", ParentID: grandchild.ID}, "student", ctx); !errors.Is(err, models.ErrTaskTooDeep) {
+		t.Errorf("Expected ErrTaskTooDeep, got %v", err)
+	}
+
+	// Задача не может стать подзадачей своего потомка
+	grandchildID := grandchild.ID
+	if _, err := service.Update(parent.ID, models.TaskUpdate{ParentID: &grandchildID}, "student", ctx); !errors.Is(err, models.ErrTaskCycle) {
+		t.Errorf("Expected ErrTaskCycle, got %v", err)
+	}
+
+	item, err := service.AddChecklistItem(parent.ID, models.CreateChecklistItemRequest{Title: "Call movers"}, "student", ctx)
+	if err != nil || item.ID == "" {
+		t.Fatalf("Failed to add checklist item: %v", err)
+	}
+
+	got, _ := service.GetByID(parent.ID, "student")
+	if got.Progress == nil || got.Progress.Total != 2 || got.Progress.Done != 0 {
+		t.Fatalf("Expected progress 0/2, got %+v", got.Progress)
+	}
+
+	done := true
+	if _, err := service.Update(child.ID, models.TaskUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete subtask: %v", err)
+	}
+	got, _ = service.GetByID(parent.ID, "student")
+	if got.Done || got.Progress.Percent != 50 {
+		t.Errorf("Expected parent 50%% and not done, got done=%v %+v", got.Done, got.Progress)
+	}
+
+	// Последний пункт чек-листа завершает родителя с auto_complete
+	if _, err := service.UpdateChecklistItem(parent.ID, item.ID, models.ChecklistItemUpdate{Done: &done}, "student", ctx); err != nil {
+		t.Fatalf("Failed to complete checklist item: %v", err)
+	}
+	got, _ = service.GetByID(parent.ID, "student")
+	if !got.Done {
+		t.Error("Expected parent to be auto-completed")
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// List возвращает задачи с учетом фильтра; выборка без фильтра идет через кэш
+func (s *TasksService) List(subject string, filter models.TaskFilter) ([]models.Task, error) {
+	if filter.IsEmpty() {
+		return s.GetAll(subject)
+	}
+
+	if filter.Due != "" && filter.Due != models.DueFilterOverdue && filter.Due != models.DueFilterToday {
+		return nil, &models.ValidationError{Message: "due must be overdue or today"}
+	}
+
+	// Метки фильтра приводятся к виду, в котором они хранятся
+	if len(filter.Tags) > 0 {
+		names, err := normalizeTagNames(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tags = names
+	}
+
+	// Задачи чужого проекта, открытого пользователю
+	if filter.ProjectID != "" {
+		access, err := s.repo.ProjectAccess(filter.ProjectID, subject)
+		if err != nil {
+			return nil, err
+		}
+		if access.Owner != "" && access.Owner != subject {
+			return s.sharedProjectTasks(access, filter)
+		}
+	}
+
+	return s.repo.List(subject, filter)
+}
+
+// sharedProjectTasks возвращает задачи проекта владельца с ролью пользователя
+func (s *TasksService) sharedProjectTasks(access models.Access, filter models.TaskFilter) ([]models.Task, error) {
+	tasks, err := s.repo.List(access.Owner, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].Owner = access.Owner
+		tasks[i].Role = access.Role
+	}
+	return tasks, nil
+}
+
+func (s *TasksService) Tags(subject string) ([]models.Tag, error) {
+	return s.repo.GetTags(subject)
+}
+
+func (s *TasksService) CreateTag(req models.CreateTagRequest, subject string) (models.Tag, error) {
+	name, err := models.NormalizeTagName(req.Name)
+	if err != nil {
+		return models.Tag{}, err
+	}
+	color, err := models.NormalizeTagColor(req.Color)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	tag, err := s.repo.CreateTag(models.Tag{Name: name, Color: color}, subject)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	s.log.Info("Tag created", zap.String("tag_id", tag.ID), zap.String("name", tag.Name))
+	return tag, nil
+}
+
+func (s *TasksService) UpdateTag(id string, updates models.TagUpdate, subject string) (models.Tag, error) {
+	if updates.Name != nil {
+		name, err := models.NormalizeTagName(*updates.Name)
+		if err != nil {
+			return models.Tag{}, err
+		}
+		updates.Name = &name
+	}
+	if updates.Color != nil {
+		color, err := models.NormalizeTagColor(*updates.Color)
+		if err != nil {
+			return models.Tag{}, err
+		}
+		updates.Color = &color
+	}
+
+	tag, err := s.repo.GetTag(id, subject)
+	if err != nil || tag.ID == "" {
+		return models.Tag{}, err
+	}
+
+	// Задачи с меткой в кэше содержат старое имя
+	tagged, err := s.repo.List(subject, models.TaskFilter{Tags: []string{tag.Name}})
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	updated, err := s.repo.UpdateTag(id, updates, subject)
+	if err != nil {
+		return models.Tag{}, err
+	}
+
+	if updated.Name != tag.Name {
+		s.invalidateTasks(tagged, subject)
+	}
+
+	s.log.Info("Tag updated", zap.String("tag_id", id))
+	return updated, nil
+}
+
+func (s *TasksService) DeleteTag(id string, subject string) (bool, error) {
+	tag, err := s.repo.GetTag(id, subject)
+	if err != nil || tag.ID == "" {
+		return false, err
+	}
+
+	tagged, err := s.repo.List(subject, models.TaskFilter{Tags: []string{tag.Name}})
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := s.repo.DeleteTag(id, subject)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.invalidateTasks(tagged, subject)
+
+	s.log.Info("Tag deleted", zap.String("tag_id", id), zap.Int("tasks_affected", len(tagged)))
+	return true, nil
+}
+
+// AddTaskTags назначает метки задаче; недостающие метки создаются
+func (s *TasksService) AddTaskTags(taskID string, names []string, subject string) (models.Task, error) {
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
+	}
+
+	normalized, err := normalizeTagNames(names)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task, err := s.repo.GetByID(taskID, owner)
+	if err != nil || task.ID == "" {
+		return models.Task{}, err
+	}
+
+	if err := s.repo.AddTaskTags(taskID, normalized, owner); err != nil {
+		return models.Task{}, err
+	}
+
+	s.invalidateTask(taskID, owner)
+	s.log.Info("Task tags added", zap.String("task_id", taskID), zap.Strings("tags", normalized))
+
+	return s.repo.GetByID(taskID, owner)
+}
+
+// RemoveTaskTag снимает метку с задачи. Пустая задача означает, что задачи
+// нет; removed == false – метка не была назначена.
+func (s *TasksService) RemoveTaskTag(taskID string, name string, subject string) (models.Task, bool, error) {
+	name, err := models.NormalizeTagName(name)
+	if err != nil {
+		return models.Task{}, false, err
+	}
+
+	owner, err := s.taskOwner(taskID, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, false, err
+	}
+
+	task, err := s.repo.GetByID(taskID, owner)
+	if err != nil || task.ID == "" {
+		return models.Task{}, false, err
+	}
+
+	removed, err := s.repo.RemoveTaskTag(taskID, name, owner)
+	if err != nil || !removed {
+		return task, false, err
+	}
+
+	s.invalidateTask(taskID, owner)
+	s.log.Info("Task tag removed", zap.String("task_id", taskID), zap.String("tag", name))
+
+	task, err = s.repo.GetByID(taskID, owner)
+	return task, true, err
+}
+
+func (s *TasksService) invalidateTasks(tasks []models.Task, subject string) {
+	for _, task := range tasks {
+		s.invalidateTask(task.ID, subject)
+	}
+}
+
+// normalizeTagNames нормализует имена меток и убирает повторы
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		n, err := models.NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, &models.ValidationError{Message: "at least one tag is required"}
+	}
+	return normalized, nil
+}
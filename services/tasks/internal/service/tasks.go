@@ -2,8 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"strings"
 	"sync"
 	"time"
 
@@ -13,42 +11,101 @@ import (
 	"tech-ip-sem2/services/tasks/internal/models"
 	"tech-ip-sem2/services/tasks/internal/rabbitmq"
 	"tech-ip-sem2/services/tasks/internal/repository"
+	"tech-ip-sem2/shared/blob"
 	"tech-ip-sem2/shared/logger"
 	"tech-ip-sem2/shared/middleware"
 	"tech-ip-sem2/shared/sanitize"
 )
 
 type TasksService struct {
-	repo        repository.TaskRepository
-	cache       *cache.RedisCache
-	rabbitPub   *rabbitmq.Publisher
-	log         *logger.Logger
-	memoryTasks map[string]models.Task
-	mu          sync.RWMutex
-	useDatabase bool
-	counter     int64
+	repo      repository.TaskRepository
+	cache     *cache.RedisCache
+	rabbitPub *rabbitmq.Publisher
+	log       *logger.Logger
+
+	enforceDependencies bool
+
+	// Задачи во встроенной SQLite в памяти: уязвимый поиск не выполняется
+	memoryStorage bool
+
+	// Операции bulk копят события и сбросы кэша до конца транзакции
+	batch *bulkBatch
+
+	// Хранилище файлов вложений; nil – вложения отключены
+	blobs             blob.Store
+	maxAttachmentSize int64
+
+	// Импорты с числом задач больше importSyncRows выполняет worker
+	queueImports   bool
+	importSyncRows int
+
+	// Рабочее пространство копии из ForTenant ("" – default), квоты
+	// пространств по умолчанию и известные пространства пользователей
+	tenant  string
+	quotas  models.Quotas
+	members *sync.Map
 }
 
+// Если repo == nil, задачи хранятся во встроенной SQLite в памяти процесса
 func NewTasksService(log *logger.Logger, repo repository.TaskRepository, cache *cache.RedisCache, rabbitPub *rabbitmq.Publisher) *TasksService {
+	useDatabase := repo != nil
+	if repo == nil {
+		memoryRepo, err := repository.NewSQLiteTaskRepository(repository.SQLiteMemory)
+		if err != nil {
+			log.Fatal("Failed to create in-memory storage", zap.Error(err))
+		}
+		repo = memoryRepo
+	}
+
 	log.Info("Tasks service initialized",
-		zap.Bool("use_database", repo != nil),
+		zap.Bool("use_database", useDatabase),
 		zap.Bool("cache_enabled", cache != nil && cache.IsEnabled()),
 		zap.Bool("rabbitmq_enabled", rabbitPub != nil),
 	)
 
 	return &TasksService{
-		repo:        repo,
-		cache:       cache,
-		rabbitPub:   rabbitPub,
-		log:         log,
-		memoryTasks: make(map[string]models.Task),
-		useDatabase: repo != nil,
-		counter:     0,
+		repo:      repo,
+		cache:     cache,
+		rabbitPub: rabbitPub,
+		log:       log,
+
+		enforceDependencies: true,
+		memoryStorage:       !useDatabase,
+		maxAttachmentSize:   models.DefaultMaxAttachmentSize,
+		importSyncRows:      models.DefaultImportSyncRows,
+		members:             &sync.Map{},
 	}
 }
 
-// GetByID с поддержкой кэша (cache-aside)
+// GetByID возвращает свою задачу или открытую пользователю задачу с
+// владельцем и ролью; пустая задача – задачи нет или она недоступна
 func (s *TasksService) GetByID(id string, subject string) (models.Task, error) {
+	return s.getByID(id, subject, s.getTask)
+}
+
+// GetCurrent – GetByID без кэша: по текущей версии задачи в базе
+// проверяются условные запросы (If-Match, If-None-Match)
+func (s *TasksService) GetCurrent(id string, subject string) (models.Task, error) {
+	return s.getByID(id, subject, s.repo.GetByID)
+}
+
+func (s *TasksService) getByID(id string, subject string, get func(id string, owner string) (models.Task, error)) (models.Task, error) {
+	access, err := s.repo.TaskAccess(id, subject)
+	if err != nil || access.Owner == "" {
+		return models.Task{}, err
+	}
+
+	task, err := get(id, access.Owner)
+	if err != nil || task.ID == "" || access.Owner == subject {
+		return task, err
+	}
+	task.Owner = access.Owner
+	task.Role = access.Role
+	return task, nil
+}
+
+// getTask читает задачу владельца subject через кэш
+func (s *TasksService) getTask(id string, subject string) (models.Task, error) {
 	ctx := context.Background()
 
 	if s.cache != nil && s.cache.IsEnabled() {
@@ -65,6 +122,8 @@ func (s *TasksService) GetByID(id string, subject string) (models.Task, error) {
 					zap.String("task_id", id),
 					zap.String("subject", subject),
 				)
+				// Признаки срока зависят от текущего времени
+				cachedTask.SetDueFlags(time.Now(), s.location(subject))
 				return *cachedTask, nil
 			}
 			s.log.Debug("Cache hit but wrong subject",
@@ -78,17 +137,7 @@ func (s *TasksService) GetByID(id string, subject string) (models.Task, error) {
 	}
 
 	// Cache MISS или ошибка
-	var task models.Task
-	var err error
-
-	if s.useDatabase {
-		task, err = s.repo.GetByID(id, subject)
-	} else {
-		s.mu.RLock()
-		task, err = s.getByIDMemory(id, subject)
-		s.mu.RUnlock()
-	}
-
+	task, err := s.repo.GetByID(id, subject)
 	if err != nil {
 		return models.Task{}, err
 	}
@@ -112,15 +161,6 @@ func (s *TasksService) GetByID(id string, subject string) (models.Task, error) {
 	return task, nil
 }
 
-// Вспомогательный метод для получения из памяти
-func (s *TasksService) getByIDMemory(id string, subject string) (models.Task, error) {
-	task, exists := s.memoryTasks[id]
-	if !exists || task.Subject != subject {
-		return models.Task{}, nil
-	}
-	return task, nil
-}
-
 // GetAll с поддержкой кэша
 func (s *TasksService) GetAll(subject string) ([]models.Task, error) {
 	ctx := context.Background()
@@ -138,6 +178,7 @@ func (s *TasksService) GetAll(subject string) ([]models.Task, error) {
 				zap.String("subject", subject),
 				zap.Int("count", len(cachedTasks)),
 			)
+			s.setDueFlags(cachedTasks, subject)
 			return cachedTasks, nil
 		} else {
 			s.log.Debug("Cache miss for task list", zap.String("subject", subject))
@@ -145,17 +186,7 @@ func (s *TasksService) GetAll(subject string) ([]models.Task, error) {
 	}
 
 	// Cache MISS
-	var tasks []models.Task
-	var err error
-
-	if s.useDatabase {
-		tasks, err = s.repo.GetAll(subject)
-	} else {
-		s.mu.RLock()
-		tasks = s.getAllMemory(subject)
-		s.mu.RUnlock()
-	}
-
+	tasks, err := s.repo.GetAll(subject)
 	if err != nil {
 		return nil, err
 	}
@@ -176,68 +207,71 @@ func (s *TasksService) GetAll(subject string) ([]models.Task, error) {
 	return tasks, nil
 }
 
-// Вспомогательный метод для получения всех задач из памяти
-func (s *TasksService) getAllMemory(subject string) []models.Task {
-	tasks := make([]models.Task, 0, len(s.memoryTasks))
-	for _, task := range s.memoryTasks {
-		if task.Subject == subject {
-			tasks = append(tasks, task)
-		}
-	}
-	return tasks
-}
-
 // Create с публикацией события
 func (s *TasksService) Create(task models.Task, subject string, ctx context.Context) (models.Task, error) {
 	task.Sanitize()
 
-	var created models.Task
-	var err error
+	priority, err := models.NormalizePriority(task.Priority)
+	if err != nil {
+		return models.Task{}, err
+	}
+	task.Priority = priority
+	if err := models.ValidateEstimates(task.EstimatePoints, task.EstimateMinutes); err != nil {
+		return models.Task{}, err
+	}
 
-	if s.useDatabase {
-		task.ID = generateUUID()
-		created, err = s.repo.Create(task, subject)
-	} else {
-		s.mu.Lock()
-		created = s.createMemory(task, subject)
-		s.mu.Unlock()
+	if task.ProjectID != "" {
+		if err := s.checkProject(task.ProjectID, subject); err != nil {
+			return models.Task{}, err
+		}
+	}
+	if task.ParentID != "" {
+		if err := s.checkParent("", task.ParentID, subject); err != nil {
+			return models.Task{}, err
+		}
+	}
+	if err := s.initialStatus(&task, subject); err != nil {
+		return models.Task{}, err
+	}
+	task.DueDate = task.DueDate.In(s.location(subject))
+	var series models.TaskSeries
+	if task.Recurrence != "" && task.SeriesID == "" {
+		series, task.DueDate, err = s.startSeries(task, task.Recurrence, subject)
+		if err != nil {
+			return models.Task{}, err
+		}
+		task.SeriesID = series.ID
 	}
 
+	// Задача и первая запись ее истории создаются вместе
+	task.ID = generateUUID()
+	var created models.Task
+	err = s.inTx(ctx, func(tx *TasksService) error {
+		if err := tx.checkQuota(models.QuotaTasks, 1); err != nil {
+			return err
+		}
+		var err error
+		created, err = tx.repo.Create(task, subject)
+		if err != nil {
+			return err
+		}
+		created.Recurrence = series.RRule
+
+		snapshot := models.SnapshotOf(created)
+		return tx.recordRevision(ctx, models.ActionCreated, created, models.CreationChanges(snapshot))
+	})
 	if err != nil {
 		return models.Task{}, err
 	}
 
-	if s.cache != nil && s.cache.IsEnabled() {
-		go func() {
-			if err := s.cache.DeleteTaskList(context.Background(), subject); err != nil {
-				s.log.Warn("Failed to invalidate task list cache",
-					zap.Error(err),
-					zap.String("subject", subject),
-				)
-			}
-		}()
+	if created.ParentID != "" {
+		s.invalidateTask(created.ParentID, subject)
 	}
 
-	// Публикация события в RabbitMQ
-	if s.rabbitPub != nil {
-		requestID := middleware.GetRequestID(ctx)
-		go func() {
-			pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
+	s.invalidateTaskList(subject)
 
-			if err := s.rabbitPub.PublishEvent(pubCtx, "task.created", created, requestID); err != nil {
-				s.log.Error("Failed to publish task.created event",
-					zap.Error(err),
-					zap.String("task_id", created.ID),
-				)
-			} else {
-				s.log.Info("Task event published",
-					zap.String("event", "task.created"),
-					zap.String("task_id", created.ID),
-				)
-			}
-		}()
-	}
+	// Публикация события в RabbitMQ
+	s.publishEvent(ctx, "task.created", created)
 
 	s.log.Info("Task created",
 		zap.String("task_id", created.ID),
@@ -248,18 +282,17 @@ func (s *TasksService) Create(task models.Task, subject string, ctx context.Cont
 	return created, nil
 }
 
-// Вспомогательный метод для создания в памяти
-func (s *TasksService) createMemory(task models.Task, subject string) models.Task {
-	s.counter++
-	task.ID = s.generateID()
-	task.Done = false
-	task.Subject = subject
-	s.memoryTasks[task.ID] = task
-	return task
-}
-
 // Update с публикацией события
 func (s *TasksService) Update(id string, updates models.TaskUpdate, subject string, ctx context.Context) (models.Task, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
+	}
+
+	return s.update(id, updates, owner, models.ActionUpdated, ctx)
+}
+
+func (s *TasksService) update(id string, updates models.TaskUpdate, subject string, action string, ctx context.Context) (models.Task, error) {
 	if updates.Description != nil {
 		sanitized, err := sanitize.ValidateAndSanitizeDescription(*updates.Description)
 		if err != nil {
@@ -270,18 +303,68 @@ func (s *TasksService) Update(id string, updates models.TaskUpdate, subject stri
 	if updates.Title != nil {
 		*updates.Title = sanitize.SanitizeText(*updates.Title)
 	}
+	if updates.Priority != nil {
+		priority, err := models.NormalizePriority(*updates.Priority)
+		if err != nil {
+			return models.Task{}, err
+		}
+		updates.Priority = &priority
+	}
+	if updates.EstimatePoints != nil || updates.EstimateMinutes != nil {
+		var points, minutes int
+		if updates.EstimatePoints != nil {
+			points = *updates.EstimatePoints
+		}
+		if updates.EstimateMinutes != nil {
+			minutes = *updates.EstimateMinutes
+		}
+		if err := models.ValidateEstimates(points, minutes); err != nil {
+			return models.Task{}, err
+		}
+	}
+	if updates.ProjectID != nil && *updates.ProjectID != "" {
+		if err := s.checkProject(*updates.ProjectID, subject); err != nil {
+			return models.Task{}, err
+		}
+	}
+	if updates.DueDate != nil {
+		dueDate := updates.DueDate.In(s.location(subject))
+		updates.DueDate = &dueDate
+	}
+
+	// Изменение и запись истории выполняются в одной транзакции; задача
+	// блокируется, поэтому история сравнивает именно измененное состояние
+	var before, updated models.Task
+	err := s.inTx(ctx, func(tx *TasksService) error {
+		var err error
+		before, err = tx.repo.LockTask(id, subject)
+		if err != nil || before.ID == "" {
+			return err
+		}
 
-	var updated models.Task
-	var err error
+		if err := tx.applyStatus(before, &updates, action, subject); err != nil {
+			return err
+		}
+		if err := tx.applyRecurrence(before, &updates, subject); err != nil {
+			return err
+		}
+		if tx.enforceDependencies && *updates.Done && !before.Done && before.Blocked {
+			return models.ErrTaskBlocked
+		}
+		if updates.ParentID != nil && *updates.ParentID != "" && *updates.ParentID != before.ParentID {
+			if err := tx.checkParent(id, *updates.ParentID, subject); err != nil {
+				return err
+			}
+		}
 
-	if s.useDatabase {
-		updated, err = s.repo.Update(id, updates, subject)
-	} else {
-		s.mu.Lock()
-		updated, err = s.updateMemory(id, updates, subject)
-		s.mu.Unlock()
-	}
+		updated, err = tx.repo.Update(id, updates, subject)
+		if err != nil || updated.ID == "" {
+			return err
+		}
 
+		changes := models.DiffTasks(models.SnapshotOf(before), models.SnapshotOf(updated))
+		return tx.recordRevision(ctx, action, updated, changes)
+	})
 	if err != nil {
 		return models.Task{}, err
 	}
@@ -290,177 +373,304 @@ func (s *TasksService) Update(id string, updates models.TaskUpdate, subject stri
 		return models.Task{}, nil
 	}
 
-	if s.cache != nil && s.cache.IsEnabled() {
-		go func() {
-			ctx := context.Background()
-			if err := s.cache.DeleteTask(ctx, id); err != nil {
-				s.log.Warn("Failed to invalidate task cache", zap.Error(err), zap.String("task_id", id))
-			}
-			if err := s.cache.DeleteTaskList(ctx, subject); err != nil {
-				s.log.Warn("Failed to invalidate task list cache", zap.Error(err), zap.String("subject", subject))
-			}
-		}()
-	}
+	s.invalidateTask(id, subject)
 
 	// Публикация события в RabbitMQ
-	if s.rabbitPub != nil {
-		requestID := middleware.GetRequestID(ctx)
-		go func() {
-			pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
+	s.publishEvent(ctx, "task.updated", updated)
 
-			if err := s.rabbitPub.PublishEvent(pubCtx, "task.updated", updated, requestID); err != nil {
-				s.log.Error("Failed to publish task.updated event",
-					zap.Error(err),
-					zap.String("task_id", updated.ID),
-				)
-			}
-		}()
+	if before.Status != updated.Status {
+		s.publishStatusChanged(ctx, updated, before.Status)
+	}
+	if !before.DueDate.Equal(updated.DueDate) {
+		s.rescheduleReminders(updated, subject)
 	}
 
-	s.log.Info("Task updated", zap.String("task_id", id))
-	return updated, nil
-}
-
-// Вспомогательный метод для обновления в памяти
-func (s *TasksService) updateMemory(id string, updates models.TaskUpdate, subject string) (models.Task, error) {
-	task, exists := s.memoryTasks[id]
-	if !exists || task.Subject != subject {
-		return models.Task{}, nil
+	// Остановка серии, затем следующее вхождение выполненной повторяющейся задачи
+	if updates.Recurrence != nil && *updates.Recurrence == "" && updated.Recurrence != "" {
+		if err := s.stopSeries(updated, subject); err != nil {
+			s.log.Error("Failed to stop recurring series", zap.Error(err), zap.String("task_id", id))
+		} else {
+			updated.Recurrence = ""
+		}
+	}
+	if !before.Done && updated.Done && updated.Recurrence != "" {
+		s.nextOccurrence(ctx, updated, subject)
 	}
 
-	if updates.Title != nil {
-		task.Title = *updates.Title
+	s.log.Info("Task updated", zap.String("task_id", id))
+
+	// Изменение подзадачи влияет на прогресс родителей
+	if before.ParentID != updated.ParentID {
+		s.autoComplete(ctx, before.ParentID, subject)
 	}
-	if updates.Description != nil {
-		task.Description = *updates.Description
+	if before.ParentID != updated.ParentID || before.Done != updated.Done {
+		s.autoComplete(ctx, updated.ParentID, subject)
 	}
-	if updates.DueDate != nil {
-		task.DueDate = *updates.DueDate
+	// Задачи, которые блокирует эта, в кэше содержат старый признак blocked
+	if before.Done != updated.Done {
+		for _, dependent := range updated.Blocks {
+			s.invalidateTask(dependent, subject)
+		}
 	}
-	if updates.Done != nil {
-		task.Done = *updates.Done
+	if updated.AutoComplete && !before.AutoComplete {
+		s.autoComplete(ctx, id, subject)
 	}
 
-	s.memoryTasks[id] = task
-	return task, nil
+	return updated, nil
 }
 
 // Delete с публикацией события
 func (s *TasksService) Delete(id string, subject string, ctx context.Context) (bool, error) {
-	var deleted bool
-	var err error
 	var task models.Task
-
-	if s.useDatabase {
-		task, _ = s.repo.GetByID(id, subject)
-		deleted, err = s.repo.Delete(id, subject)
-	} else {
-		s.mu.Lock()
-		task, _ = s.getByIDMemory(id, subject)
-		deleted = s.deleteMemory(id, subject)
-		s.mu.Unlock()
-	}
-
+	err := s.inTx(ctx, func(tx *TasksService) error {
+		var err error
+		task, err = tx.repo.Delete(id, subject)
+		if err != nil || task.ID == "" {
+			return err
+		}
+		return tx.recordRevision(ctx, models.ActionDeleted, task, []models.FieldChange{})
+	})
 	if err != nil {
 		return false, err
 	}
 
-	if !deleted {
+	if task.ID == "" {
 		return false, nil
 	}
 
-	if s.cache != nil && s.cache.IsEnabled() {
-		go func() {
-			ctx := context.Background()
-			if err := s.cache.DeleteTask(ctx, id); err != nil {
-				s.log.Warn("Failed to invalidate task cache on delete", zap.Error(err), zap.String("task_id", id))
-			}
-			if err := s.cache.DeleteTaskList(ctx, subject); err != nil {
-				s.log.Warn("Failed to invalidate task list cache on delete", zap.Error(err), zap.String("subject", subject))
-			}
-		}()
-	}
+	s.invalidateTask(id, subject)
 
 	// Публикация события в RabbitMQ
-	if s.rabbitPub != nil && task.ID != "" {
-		requestID := middleware.GetRequestID(ctx)
-		go func() {
-			pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			defer cancel()
+	s.publishEvent(ctx, "task.deleted", task)
 
-			if err := s.rabbitPub.PublishEvent(pubCtx, "task.deleted", task, requestID); err != nil {
-				s.log.Error("Failed to publish task.deleted event",
-					zap.Error(err),
-					zap.String("task_id", task.ID),
-				)
-			}
-		}()
-	}
+	s.log.Info("Task moved to trash", zap.String("task_id", id))
 
-	s.log.Info("Task deleted", zap.String("task_id", id))
+	// Подзадачи в корзине не учитываются в прогрессе родителя, а задачи
+	// в корзине не блокируют другие
+	s.autoComplete(ctx, task.ParentID, subject)
+	for _, dependent := range task.Blocks {
+		s.invalidateTask(dependent, subject)
+	}
 	return true, nil
 }
 
-// Вспомогательный метод для удаления из памяти
-func (s *TasksService) deleteMemory(id string, subject string) bool {
-	task, exists := s.memoryTasks[id]
-	if !exists || task.Subject != subject {
-		return false
+// Trash возвращает задачи из корзины
+func (s *TasksService) Trash(subject string) ([]models.Task, error) {
+	return s.repo.GetTrash(subject)
+}
+
+// Restore возвращает задачу из корзины с публикацией события
+func (s *TasksService) Restore(id string, subject string, ctx context.Context) (models.Task, error) {
+	var restored models.Task
+	err := s.inTx(ctx, func(tx *TasksService) error {
+		var err error
+		restored, err = tx.repo.Restore(id, subject)
+		if err != nil || restored.ID == "" {
+			return err
+		}
+		// Задачи в корзине не расходуют квоту: восстановленная уже учтена
+		if err := tx.checkQuota(models.QuotaTasks, 0); err != nil {
+			return err
+		}
+		return tx.recordRevision(ctx, models.ActionRestored, restored, []models.FieldChange{})
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if restored.ID == "" {
+		return models.Task{}, nil
+	}
+
+	s.invalidateTask(id, subject)
+	s.publishEvent(ctx, "task.restored", restored)
+
+	s.log.Info("Task restored", zap.String("task_id", id))
+	s.autoComplete(ctx, restored.ParentID, subject)
+	for _, dependent := range restored.Blocks {
+		s.invalidateTask(dependent, subject)
+	}
+	return restored, nil
+}
+
+// History возвращает ревизии задачи, новые первыми
+func (s *TasksService) History(id string, subject string) ([]models.TaskRevision, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleViewer)
+	if err != nil || owner == "" {
+		return nil, err
 	}
-	delete(s.memoryTasks, id)
-	return true
+
+	return s.repo.GetHistory(id, owner)
 }
 
-// SearchByTitle
-func (s *TasksService) SearchByTitle(term string, subject string) ([]models.Task, error) {
-	if s.useDatabase {
-		return s.repo.SearchByTitle(term, subject)
+// Revert возвращает поля задачи к состоянию из ревизии. Откат сохраняется
+// как новая ревизия, поэтому его тоже можно отменить.
+func (s *TasksService) Revert(id string, revision int64, expectedVersion *int64, subject string, ctx context.Context) (models.Task, error) {
+	owner, err := s.taskOwner(id, subject, models.RoleEditor)
+	if err != nil || owner == "" {
+		return models.Task{}, err
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	rev, err := s.repo.GetRevision(id, revision, owner)
+	if err != nil {
+		return models.Task{}, err
+	}
 
-	var results []models.Task
-	for _, task := range s.memoryTasks {
-		if task.Subject == subject && contains(task.Title, term) {
-			results = append(results, task)
+	snapshot := rev.Snapshot
+	// Удаленный с тех пор проект не восстанавливается
+	if snapshot.ProjectID != "" {
+		project, err := s.repo.GetProject(snapshot.ProjectID, owner)
+		if err != nil {
+			return models.Task{}, err
+		}
+		if project.ID == "" {
+			snapshot.ProjectID = ""
+		}
+	}
+	// Родитель, удаленный с тех пор, тоже не восстанавливается
+	if snapshot.ParentID != "" {
+		parent, err := s.repo.GetByID(snapshot.ParentID, owner)
+		if err != nil {
+			return models.Task{}, err
+		}
+		if parent.ID == "" {
+			snapshot.ParentID = ""
 		}
 	}
 
-	return results, nil
-}
+	// Ревизии до появления статусов восстанавливаются по done
+	var status *string
+	if snapshot.Status != "" {
+		status = &snapshot.Status
+	}
 
-// Демонстрация SQL-инъекции
-func (s *TasksService) SearchByTitleVulnerable(term string, subject string) ([]models.Task, error) {
-	if s.useDatabase {
-		s.log.Warn("Using VULNERABLE search method - FOR DEMO ONLY",
-			zap.String("term", term),
-			zap.String("subject", subject))
-		return s.repo.SearchByTitleVulnerable(term, subject)
+	updates := models.TaskUpdate{
+		Status:          status,
+		Title:           &snapshot.Title,
+		Description:     &snapshot.Description,
+		DueDate:         &snapshot.DueDate,
+		Done:            &snapshot.Done,
+		Priority:        &snapshot.Priority,
+		EstimatePoints:  &snapshot.EstimatePoints,
+		EstimateMinutes: &snapshot.EstimateMinutes,
+		ProjectID:       &snapshot.ProjectID,
+		ParentID:        &snapshot.ParentID,
+		AutoComplete:    &snapshot.AutoComplete,
+		Version:         expectedVersion,
+	}
+
+	reverted, err := s.update(id, updates, owner, models.ActionReverted, ctx)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if reverted.ID != "" {
+		s.log.Info("Task reverted",
+			zap.String("task_id", id),
+			zap.Int64("revision", revision),
+		)
 	}
-	return s.SearchByTitle(term, subject)
+	return reverted, nil
 }
 
-// Генерация ID для in-memory режима
-func (s *TasksService) generateID() string {
-	s.counter++
-	timestamp := time.Now().Format("20060102150405")
-	return fmt.Sprintf("t%s-%05d", timestamp, s.counter)
+// recordRevision сохраняет запись истории. Вызывается в транзакции изменения
+// задачи: ошибка отменяет и само изменение.
+func (s *TasksService) recordRevision(ctx context.Context, action string, task models.Task, changes []models.FieldChange) error {
+	actor, _ := ctx.Value("subject").(string)
+	if actor == "" {
+		actor = task.Subject
+	}
+
+	rev := models.TaskRevision{
+		TaskID:    task.ID,
+		Revision:  task.Version,
+		Subject:   task.Subject,
+		Action:    action,
+		Actor:     actor,
+		RequestID: middleware.GetRequestID(ctx),
+		Changes:   changes,
+		Snapshot:  models.SnapshotOf(task),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.repo.AddRevision(rev); err != nil {
+		s.log.Error("Failed to record task revision",
+			zap.Error(err),
+			zap.String("task_id", task.ID),
+			zap.String("action", action),
+		)
+		return err
+	}
+	return nil
 }
 
-func generateUUID() string {
-	return uuid.New().String()
+// invalidateTask сбрасывает кэш задачи и списка задач пользователя
+func (s *TasksService) invalidateTask(id string, subject string) {
+	if s.batch != nil {
+		s.batch.invalidate(id, subject)
+		return
+	}
+	if s.cache == nil || !s.cache.IsEnabled() {
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		if err := s.cache.DeleteTask(ctx, id); err != nil {
+			s.log.Warn("Failed to invalidate task cache", zap.Error(err), zap.String("task_id", id))
+		}
+		if err := s.cache.DeleteTaskList(ctx, subject); err != nil {
+			s.log.Warn("Failed to invalidate task list cache", zap.Error(err), zap.String("subject", subject))
+		}
+	}()
 }
 
-func contains(s, substr string) bool {
-	if substr == "" {
-		return true
+// publishEvent асинхронно публикует событие задачи в RabbitMQ
+func (s *TasksService) publishEvent(ctx context.Context, event string, task models.Task) {
+	if s.batch != nil {
+		s.batch.events = append(s.batch.events, models.TaskEvent{Event: event, Task: task})
+		return
 	}
-	if len(substr) > len(s) {
-		return false
+	if s.rabbitPub == nil {
+		return
 	}
-	sLower := strings.ToLower(s)
-	substrLower := strings.ToLower(substr)
-	return strings.Contains(sLower, substrLower)
+
+	requestID := middleware.GetRequestID(ctx)
+	go func() {
+		pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if err := s.rabbitPub.PublishEvent(pubCtx, event, task, requestID); err != nil {
+			s.log.Error("Failed to publish "+event+" event",
+				zap.Error(err),
+				zap.String("task_id", task.ID),
+			)
+		}
+	}()
+}
+
+// SearchByTitle
+func (s *TasksService) SearchByTitle(term string, subject string) ([]models.Task, error) {
+	return s.repo.SearchByTitle(term, subject)
+}
+
+// SetMemoryStorage отмечает, что repo – встроенная SQLite в памяти процесса
+// (база данных не настроена)
+func (s *TasksService) SetMemoryStorage(memory bool) {
+	s.memoryStorage = memory
+}
+
+// Демонстрация SQL-инъекции; без базы данных – безопасный поиск
+func (s *TasksService) SearchByTitleVulnerable(term string, subject string) ([]models.Task, error) {
+	if s.memoryStorage {
+		return s.SearchByTitle(term, subject)
+	}
+	s.log.Warn("Using VULNERABLE search method - FOR DEMO ONLY",
+		zap.String("term", term),
+		zap.String("subject", subject))
+	return s.repo.SearchByTitleVulnerable(term, subject)
+}
+
+func generateUUID() string {
+	return uuid.New().String()
 }
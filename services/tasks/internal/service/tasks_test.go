@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/due"
 	"tech-ip-sem2/shared/logger"
 )
 
@@ -15,7 +16,7 @@ func createTestTask(service *TasksService, title, subject string, t *testing.T)
 	task := models.Task{
 		Title:       title,
 		Description: "Test Description",
-		DueDate:     "2026-03-10",
+		DueDate:     due.Day(2026, 3, 10),
 	}
 
 	created, err := service.Create(task, subject, context.Background())
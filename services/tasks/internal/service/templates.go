@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+func (s *TasksService) Templates(subject string) ([]models.TaskTemplate, error) {
+	templates, err := s.repo.GetTemplates(subject)
+	for i := range templates {
+		templates[i].Variables = templates[i].Task.TemplateVariables()
+	}
+	return templates, err
+}
+
+// GetTemplate возвращает шаблон пользователя; пустой – шаблона нет
+func (s *TasksService) GetTemplate(id string, subject string) (models.TaskTemplate, error) {
+	template, err := s.repo.GetTemplate(id, subject)
+	template.Variables = template.Task.TemplateVariables()
+	return template, err
+}
+
+func (s *TasksService) CreateTemplate(req models.CreateTemplateRequest, subject string) (models.TaskTemplate, error) {
+	if err := req.Validate(); err != nil {
+		return models.TaskTemplate{}, err
+	}
+
+	template, err := s.repo.CreateTemplate(models.TaskTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Task:        req.Task,
+	}, subject)
+	if err != nil {
+		return models.TaskTemplate{}, err
+	}
+	template.Variables = template.Task.TemplateVariables()
+
+	s.log.Info("Template created", zap.String("template_id", template.ID), zap.String("subject", subject))
+	return template, nil
+}
+
+func (s *TasksService) UpdateTemplate(id string, updates models.TemplateUpdate, subject string) (models.TaskTemplate, error) {
+	if err := updates.Validate(); err != nil {
+		return models.TaskTemplate{}, err
+	}
+
+	template, err := s.repo.UpdateTemplate(id, updates, subject)
+	if err != nil || template.ID == "" {
+		return models.TaskTemplate{}, err
+	}
+	template.Variables = template.Task.TemplateVariables()
+
+	s.log.Info("Template updated", zap.String("template_id", id))
+	return template, nil
+}
+
+// DeleteTemplate удаляет шаблон; созданные из него задачи остаются
+func (s *TasksService) DeleteTemplate(id string, subject string) (bool, error) {
+	deleted, err := s.repo.DeleteTemplate(id, subject)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	s.log.Info("Template deleted", zap.String("template_id", id))
+	return true, nil
+}
+
+// InstantiateTemplate создает дерево задач шаблона в одной транзакции:
+// ошибка любой задачи откатывает все дерево. Пустой результат – шаблона нет.
+func (s *TasksService) InstantiateTemplate(id string, req models.InstantiateTemplateRequest, subject string, ctx context.Context) (models.TemplateInstance, error) {
+	template, err := s.repo.GetTemplate(id, subject)
+	if err != nil || template.ID == "" {
+		return models.TemplateInstance{}, err
+	}
+
+	start := time.Now().In(s.location(subject))
+	if req.StartDate != "" {
+		start, err = time.Parse(time.DateOnly, req.StartDate)
+		if err != nil {
+			return models.TemplateInstance{}, &models.ValidationError{Message: "start_date must be in YYYY-MM-DD format"}
+		}
+	}
+
+	values := map[string]string{models.TemplateDateVariable: start.Format(time.DateOnly)}
+	for name, value := range req.Variables {
+		if name != models.TemplateDateVariable {
+			values[name] = value
+		}
+	}
+
+	var instance models.TemplateInstance
+	err = s.runBatch(ctx, func(tx *TasksService) error {
+		var err error
+		instance.Task, err = tx.instantiateTask(template.Task, "", req.ProjectID, start, values, &instance, subject, ctx)
+		return err
+	})
+	if err != nil {
+		return models.TemplateInstance{}, err
+	}
+	if instance.Subtasks == nil {
+		instance.Subtasks = []models.Task{}
+	}
+
+	s.log.Info("Template instantiated",
+		zap.String("template_id", id),
+		zap.String("task_id", instance.Task.ID),
+		zap.Int("subtasks", len(instance.Subtasks)),
+		zap.String("subject", subject),
+	)
+	return instance, nil
+}
+
+// instantiateTask создает задачу шаблона с метками и чек-листом, затем ее
+// подзадачи; подзадачи добавляются в instance.Subtasks вслед за родителем
+func (s *TasksService) instantiateTask(node models.TemplateTask, parentID string, projectID string, start time.Time, values map[string]string, instance *models.TemplateInstance, subject string, ctx context.Context) (models.Task, error) {
+	title, err := models.ExpandTemplate(node.Title, values)
+	if err != nil {
+		return models.Task{}, err
+	}
+	description, err := models.ExpandTemplate(node.Description, values)
+	if err != nil {
+		return models.Task{}, err
+	}
+	dueDate, err := node.TemplateDue(start)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task := models.Task{
+		Title:           title,
+		Description:     description,
+		DueDate:         dueDate,
+		Priority:        node.Priority,
+		EstimatePoints:  node.EstimatePoints,
+		EstimateMinutes: node.EstimateMinutes,
+		ProjectID:       projectID,
+		ParentID:        parentID,
+	}
+	if err := (&models.CreateTaskRequest{Title: task.Title, Description: task.Description}).Validate(); err != nil {
+		return models.Task{}, err
+	}
+
+	created, err := s.Create(task, subject, ctx)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if len(node.Tags) > 0 {
+		if err := s.repo.AddTaskTags(created.ID, node.Tags, subject); err != nil {
+			return models.Task{}, err
+		}
+		created.Tags = node.Tags
+	}
+	for _, item := range node.Checklist {
+		if _, err := s.repo.AddChecklistItem(created.ID, item, subject); err != nil {
+			return models.Task{}, err
+		}
+	}
+	if parentID != "" {
+		instance.Subtasks = append(instance.Subtasks, created)
+	}
+
+	for _, child := range node.Subtasks {
+		if _, err := s.instantiateTask(child, created.ID, projectID, start, values, instance, subject, ctx); err != nil {
+			return models.Task{}, err
+		}
+	}
+	return created, nil
+}
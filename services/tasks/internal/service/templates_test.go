@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskTemplates(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	zero, three := 0, 3
+	template, err := service.CreateTemplate(models.CreateTemplateRequest{
+		Name: "Onboarding",
+		Task: models.TemplateTask{
+			Title:     "Onboarding {{name}}",
+			Tags:      []string{"HR"},
+			DueInDays: &three,
+			Checklist: []string{"Laptop", "Badge"},
+			Subtasks: []models.TemplateTask{
+				{Title: "Accounts for {{name}}", DueInDays: &zero, DueTime: "10:00", Subtasks: []models.TemplateTask{{Title: "Mail"}}},
+				{Title: "Meet the team on {{date}}"},
+			},
+		},
+	}, "student")
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	if len(template.Variables) != 1 || template.Variables[0] != "name" || template.Task.Tags[0] != "hr" {
+		t.Errorf("Expected variable name and normalized tags, got %+v", template)
+	}
+
+	deep := models.TemplateTask{Title: "Level"}
+	for i := 1; i < models.MaxTaskDepth+1; i++ {
+		deep = models.TemplateTask{Title: "Level", Subtasks: []models.TemplateTask{deep}}
+	}
+	if _, err := service.CreateTemplate(models.CreateTemplateRequest{Name: "Deep", Task: deep}, "student"); err == nil {
+		t.Error("Expected too deep template to be rejected")
+	}
+
+	// Без значения переменной не создается ни одна задача
+	if _, err := service.InstantiateTemplate(template.ID, models.InstantiateTemplateRequest{}, "student", ctx); err == nil {
+		t.Error("Expected missing template variable to be rejected")
+	}
+	if tasks, _ := service.GetAll("student"); len(tasks) != 0 {
+		t.Fatalf("Expected failed instantiation to roll back, got %d tasks", len(tasks))
+	}
+
+	instance, err := service.InstantiateTemplate(template.ID, models.InstantiateTemplateRequest{
+		StartDate: "2026-03-10",
+		Variables: map[string]string{"name": "Ivan"},
+	}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to instantiate template: %v", err)
+	}
+	if instance.Task.Title != "Onboarding Ivan" || instance.Task.DueDate.String() != "2026-03-13" {
+		t.Errorf("Expected expanded root due in 3 days, got %+v", instance.Task)
+	}
+	if len(instance.Subtasks) != 3 || instance.Subtasks[0].Title != "Accounts for Ivan" || instance.Subtasks[1].Title != "Mail" ||
+		instance.Subtasks[2].Title != "Meet the team on 2026-03-10" {
+		t.Fatalf("Expected subtasks in tree order, got %+v", instance.Subtasks)
+	}
+	if instance.Subtasks[1].ParentID != instance.Subtasks[0].ID || !instance.Subtasks[0].DueDate.HasTime() {
+		t.Errorf("Expected nested subtask with timed due date, got %+v", instance.Subtasks)
+	}
+
+	root, _ := service.GetByID(instance.Task.ID, "student")
+	if len(root.Tags) != 1 || root.Tags[0] != "hr" || len(root.Checklist) != 2 {
+		t.Errorf("Expected tags and checklist on root task, got %+v", root)
+	}
+
+	if found, _ := service.GetTemplate(template.ID, "teacher"); found.ID != "" {
+		t.Error("Expected template to be private")
+	}
+	if deleted, _ := service.DeleteTemplate(template.ID, "student"); !deleted {
+		t.Error("Expected template to be deleted")
+	}
+	if tasks, _ := service.GetAll("student"); len(tasks) != 4 {
+		t.Errorf("Expected created tasks to remain after template deletion, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// StartTimer запускает таймер на задаче. У пользователя один таймер:
+// запущенный на другой задаче останавливается, на этой – возвращается как есть.
+// Пустая запись – задачи нет; ErrTimerRunning – параллельный запрос запустил
+// другой таймер.
+func (s *TasksService) StartTimer(taskID string, req models.TimerRequest, subject string) (models.TimeEntry, error) {
+	if err := req.Validate(); err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	var entry models.TimeEntry
+	err := s.inTx(context.Background(), func(tx *TasksService) error {
+		running, err := tx.repo.GetRunningTimeEntry(subject)
+		if err != nil {
+			return err
+		}
+		if running.TaskID == taskID {
+			entry = running
+			return nil
+		}
+
+		task, err := tx.repo.GetByID(taskID, subject)
+		if err != nil || task.ID == "" {
+			return err
+		}
+
+		now := time.Now()
+		if running.ID != "" {
+			if _, err := tx.stopTimeEntry(running, now, subject); err != nil {
+				return err
+			}
+		}
+
+		entry, err = tx.repo.AddTimeEntry(models.TimeEntry{
+			TaskID:    taskID,
+			StartedAt: now,
+			Note:      req.Note,
+		}, subject)
+		if err != nil {
+			return err
+		}
+		// Задача есть, значит запись не сохранил уникальный индекс
+		// запущенных таймеров
+		if entry.ID == "" {
+			return models.ErrTimerRunning
+		}
+
+		tx.log.Info("Timer started", zap.String("task_id", taskID), zap.String("entry_id", entry.ID))
+		return nil
+	})
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// StopTimer останавливает таймер, запущенный на задаче
+func (s *TasksService) StopTimer(taskID string, subject string) (models.TimeEntry, error) {
+	running, err := s.repo.GetRunningTimeEntry(subject)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+	if running.ID == "" || running.TaskID != taskID {
+		return models.TimeEntry{}, models.ErrTimerNotRunning
+	}
+
+	return s.stopTimeEntry(running, time.Now(), subject)
+}
+
+func (s *TasksService) stopTimeEntry(entry models.TimeEntry, endedAt time.Time, subject string) (models.TimeEntry, error) {
+	duration := int64(endedAt.Sub(entry.StartedAt).Seconds())
+	if duration < 0 {
+		duration = 0
+	}
+
+	stopped, err := s.repo.StopTimeEntry(entry.ID, endedAt, duration, subject)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+	// Таймер остановлен параллельным запросом
+	if stopped.ID == "" {
+		return models.TimeEntry{}, models.ErrTimerNotRunning
+	}
+
+	s.log.Info("Timer stopped",
+		zap.String("task_id", stopped.TaskID),
+		zap.String("entry_id", stopped.ID),
+		zap.Int64("duration_seconds", stopped.DurationSeconds),
+	)
+	return stopped, nil
+}
+
+// LogTime добавляет ручную запись времени; пустая запись – задачи нет
+func (s *TasksService) LogTime(taskID string, req models.CreateTimeEntryRequest, subject string) (models.TimeEntry, error) {
+	now := time.Now()
+	if err := req.Validate(now); err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	duration := time.Duration(req.Minutes) * time.Minute
+	startedAt := now.Add(-duration)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	endedAt := startedAt.Add(duration)
+
+	entry, err := s.repo.AddTimeEntry(models.TimeEntry{
+		TaskID:          taskID,
+		StartedAt:       startedAt,
+		EndedAt:         &endedAt,
+		DurationSeconds: int64(duration.Seconds()),
+		Note:            req.Note,
+	}, subject)
+	if err != nil || entry.ID == "" {
+		return models.TimeEntry{}, err
+	}
+
+	s.log.Info("Time logged", zap.String("task_id", taskID), zap.Int("minutes", req.Minutes))
+	return entry, nil
+}
+
+// TimeEntries возвращает записи времени задачи; false – задачи нет
+func (s *TasksService) TimeEntries(taskID string, subject string) ([]models.TimeEntry, bool, error) {
+	task, err := s.repo.GetByID(taskID, subject)
+	if err != nil || task.ID == "" {
+		return nil, false, err
+	}
+
+	entries, err := s.repo.GetTimeEntries(taskID, subject)
+	return entries, true, err
+}
+
+func (s *TasksService) DeleteTimeEntry(taskID string, entryID string, subject string) (bool, error) {
+	return s.repo.DeleteTimeEntry(taskID, entryID, subject)
+}
+
+// TimeReport суммирует завершенные записи времени за дни from..to
+// включительно (YYYY-MM-DD в часовом поясе пользователя) по задачам,
+// проектам или дням. Пустые границы не ограничивают период.
+func (s *TasksService) TimeReport(subject string, groupBy string, fromDay, toDay string) (models.TimeReport, error) {
+	switch groupBy {
+	case models.TimeReportByTask, models.TimeReportByProject, models.TimeReportByDay:
+	default:
+		return models.TimeReport{}, &models.ValidationError{Message: "group_by must be one of task, project, day"}
+	}
+
+	loc := s.location(subject)
+	var from, to time.Time
+	var err error
+	if fromDay != "" {
+		from, err = time.ParseInLocation(time.DateOnly, fromDay, loc)
+	}
+	if toDay != "" && err == nil {
+		to, err = time.ParseInLocation(time.DateOnly, toDay, loc)
+		to = to.AddDate(0, 0, 1)
+	}
+	if err != nil {
+		return models.TimeReport{}, &models.ValidationError{Message: "from and to must be dates in YYYY-MM-DD format"}
+	}
+
+	logged, err := s.repo.GetLoggedTime(subject, from, to)
+	if err != nil {
+		return models.TimeReport{}, err
+	}
+
+	report := models.TimeReport{GroupBy: groupBy, From: fromDay, To: toDay, Rows: []models.TimeReportRow{}}
+	index := make(map[string]int)
+	for _, entry := range logged {
+		var key, title string
+		switch groupBy {
+		case models.TimeReportByTask:
+			key, title = entry.TaskID, entry.TaskTitle
+		case models.TimeReportByProject:
+			key, title = entry.ProjectID, entry.ProjectName
+		case models.TimeReportByDay:
+			key = entry.StartedAt.In(loc).Format(time.DateOnly)
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(report.Rows)
+			index[key] = i
+			report.Rows = append(report.Rows, models.TimeReportRow{Key: key, Title: title})
+		}
+		report.Rows[i].Seconds += entry.DurationSeconds
+		report.TotalSeconds += entry.DurationSeconds
+	}
+
+	// Дни – по порядку, задачи и проекты – по убыванию времени
+	if groupBy == models.TimeReportByDay {
+		sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Key < report.Rows[j].Key })
+	} else {
+		sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Seconds > report.Rows[j].Seconds })
+	}
+
+	return report, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTimeReportUsesUserTimezone(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("Skipping time report test - no time zone data: %v", err)
+	}
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+
+	tokyo := "Asia/Tokyo"
+	if _, err := service.UpdateSettings(models.UpdateSettingsRequest{Timezone: &tokyo}, "student"); err != nil {
+		t.Fatalf("Failed to update settings: %v", err)
+	}
+	task, err := service.Create(models.Task{Title: "Report"}, "student", context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// 20:00 UTC 10 марта – уже 11 марта в Токио
+	for _, startedAt := range []time.Time{
+		time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC),
+	} {
+		if _, err := service.LogTime(task.ID, models.CreateTimeEntryRequest{StartedAt: &startedAt, Minutes: 30}, "student"); err != nil {
+			t.Fatalf("Failed to log time: %v", err)
+		}
+	}
+
+	report, err := service.TimeReport("student", models.TimeReportByDay, "2026-03-10", "2026-03-11")
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+	if len(report.Rows) != 2 || report.Rows[0].Key != "2026-03-10" || report.Rows[1].Key != "2026-03-11" {
+		t.Errorf("Expected days in user's timezone, got %+v", report.Rows)
+	}
+
+	report, _ = service.TimeReport("student", models.TimeReportByDay, "2026-03-11", "2026-03-11")
+	if report.TotalSeconds != 30*60 || report.From != "2026-03-11" {
+		t.Errorf("Expected only the Tokyo day 2026-03-11, got %+v", report)
+	}
+
+	if _, err := service.TimeReport("student", models.TimeReportByDay, "10.03.2026", ""); err == nil {
+		t.Error("Expected invalid date to be rejected")
+	}
+}
+
+func TestPrioritiesAndTimeTracking(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	low, err := service.Create(models.Task{Title: "Low", Priority: models.PriorityLow}, "student", ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	createTestTask(service, "None", "student", t)
+	urgent, err := service.Create(models.Task{Title: "Urgent", Priority: "URGENT", EstimatePoints: 3}, "student", ctx)
+	if err != nil || urgent.Priority != models.PriorityUrgent {
+		t.Fatalf("Expected normalized urgent priority, got %+v, %v", urgent, err)
+	}
+	if _, err := service.Create(models.Task{Title: "Bad", Priority: "asap"}, "student", ctx); err == nil {
+		t.Error("Expected unknown priority to be rejected")
+	}
+	negative := -1
+	if _, err := service.Update(low.ID, models.TaskUpdate{EstimateMinutes: &negative}, "student", ctx); err == nil {
+		t.Error("Expected negative estimate to be rejected")
+	}
+
+	tasks, err := service.List("student", models.TaskFilter{SortByPriority: true})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].ID != urgent.ID || tasks[1].ID != low.ID {
+		t.Errorf("Unexpected priority order: %+v", tasks)
+	}
+
+	// Запуск таймера на другой задаче останавливает текущий
+	first, err := service.StartTimer(low.ID, models.TimerRequest{}, "student")
+	if err != nil || !first.Running() {
+		t.Fatalf("Failed to start timer: %+v, %v", first, err)
+	}
+	if _, err := service.StartTimer(urgent.ID, models.TimerRequest{Note: "review"}, "student"); err != nil {
+		t.Fatalf("Failed to switch timer: %v", err)
+	}
+	if _, err := service.StopTimer(low.ID, "student"); !errors.Is(err, models.ErrTimerNotRunning) {
+		t.Errorf("Expected ErrTimerNotRunning, got %v", err)
+	}
+	stopped, err := service.StopTimer(urgent.ID, "student")
+	if err != nil || stopped.Running() {
+		t.Fatalf("Failed to stop timer: %+v, %v", stopped, err)
+	}
+
+	startedAt := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if _, err := service.LogTime(low.ID, models.CreateTimeEntryRequest{StartedAt: &startedAt, Minutes: 90}, "student"); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	if _, err := service.LogTime(low.ID, models.CreateTimeEntryRequest{Minutes: 0}, "student"); err == nil {
+		t.Error("Expected zero minutes to be rejected")
+	}
+
+	entries, found, err := service.TimeEntries(low.ID, "student")
+	if err != nil || !found || len(entries) != 2 {
+		t.Fatalf("Expected 2 time entries, got %d, %v", len(entries), err)
+	}
+
+	report, err := service.TimeReport("student", models.TimeReportByDay, "2026-03-10", "2026-03-10")
+	if err != nil {
+		t.Fatalf("Failed to build report: %v", err)
+	}
+	if len(report.Rows) != 1 || report.Rows[0].Key != "2026-03-10" || report.TotalSeconds != 90*60 {
+		t.Errorf("Unexpected day report: %+v", report)
+	}
+
+	report, _ = service.TimeReport("student", models.TimeReportByTask, "", "")
+	if len(report.Rows) != 2 || report.Rows[0].Key != low.ID {
+		t.Errorf("Unexpected task report: %+v", report)
+	}
+	if _, err := service.TimeReport("student", "week", "", ""); err == nil {
+		t.Error("Expected unknown grouping to be rejected")
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/middleware"
+)
+
+// workflowFor возвращает процесс проекта; для задач без проекта и проектов
+// без своего процесса действует процесс по умолчанию
+func (s *TasksService) workflowFor(projectID string, subject string) (models.Workflow, error) {
+	if projectID == "" {
+		return models.DefaultWorkflow(), nil
+	}
+
+	project, err := s.repo.GetProject(projectID, subject)
+	if err != nil {
+		return models.Workflow{}, err
+	}
+	if project.Workflow == nil {
+		return models.DefaultWorkflow(), nil
+	}
+	return *project.Workflow, nil
+}
+
+// initialStatus задает статус новой задачи: явный статус или статус,
+// соответствующий done
+func (s *TasksService) initialStatus(task *models.Task, subject string) error {
+	workflow, err := s.workflowFor(task.ProjectID, subject)
+	if err != nil {
+		return err
+	}
+
+	task.Status = models.NormalizeStatus(task.Status)
+	if task.Status == "" {
+		task.Status = workflow.StatusFor(task.Done)
+	} else if !workflow.Has(task.Status) {
+		return models.ErrUnknownStatus
+	}
+	task.Done = workflow.IsDone(task.Status)
+	return nil
+}
+
+// applyStatus приводит статус и done в изменении к процессу проекта задачи.
+// Явный статус важнее done; done от старых клиентов переводит задачу в
+// статус выполнения или в начальный статус. При переносе в проект с другим
+// процессом неизвестный ему статус заменяется по done.
+func (s *TasksService) applyStatus(before models.Task, updates *models.TaskUpdate, action string, subject string) error {
+	projectID := before.ProjectID
+	if updates.ProjectID != nil {
+		projectID = *updates.ProjectID
+	}
+
+	workflow, err := s.workflowFor(projectID, subject)
+	if err != nil {
+		return err
+	}
+
+	current := before.Status
+	target := current
+	switch {
+	case updates.Status != nil:
+		target = models.NormalizeStatus(*updates.Status)
+		if !workflow.Has(target) {
+			// Откат к ревизии из другого процесса восстанавливает только done
+			if action != models.ActionReverted {
+				return models.ErrUnknownStatus
+			}
+			target = workflow.StatusFor(updates.Done != nil && *updates.Done)
+		}
+	case updates.Done != nil && *updates.Done != before.Done:
+		target = workflow.StatusFor(*updates.Done)
+	case !workflow.Has(current):
+		target = workflow.StatusFor(before.Done)
+	}
+
+	// Откат восстанавливает прошлое состояние в обход правил переходов
+	if action != models.ActionReverted && !workflow.Allows(current, target) {
+		return models.ErrStatusTransition
+	}
+
+	done := workflow.IsDone(target)
+	updates.Status = &target
+	updates.Done = &done
+	return nil
+}
+
+// checkWorkflowChange не дает сменить процесс проекта, если задачи проекта
+// окажутся в неизвестном статусе или изменится их признак выполнения
+func (s *TasksService) checkWorkflowChange(projectID string, workflow models.Workflow, subject string) error {
+	tasks, err := s.repo.List(subject, models.TaskFilter{ProjectID: projectID})
+	if err != nil {
+		return err
+	}
+
+	missing := make(map[string]bool)
+	changed := make(map[string]bool)
+	for _, task := range tasks {
+		switch {
+		case !workflow.Has(task.Status):
+			missing[task.Status] = true
+		case workflow.IsDone(task.Status) != task.Done:
+			changed[task.Status] = true
+		}
+	}
+
+	if len(missing) > 0 {
+		return &models.ValidationError{Message: "workflow is missing statuses used by tasks: " + joinKeys(missing)}
+	}
+	if len(changed) > 0 {
+		return &models.ValidationError{Message: "workflow changes done state of tasks in statuses: " + joinKeys(changed)}
+	}
+	return nil
+}
+
+// publishStatusChanged асинхронно публикует task.status_changed
+func (s *TasksService) publishStatusChanged(ctx context.Context, task models.Task, previousStatus string) {
+	if s.batch != nil {
+		s.batch.events = append(s.batch.events, models.TaskEvent{Event: "task.status_changed", Task: task, PreviousStatus: previousStatus})
+		return
+	}
+	if s.rabbitPub == nil {
+		return
+	}
+
+	requestID := middleware.GetRequestID(ctx)
+	go func() {
+		pubCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		if err := s.rabbitPub.PublishStatusChanged(pubCtx, task, previousStatus, requestID); err != nil {
+			s.log.Error("Failed to publish task.status_changed event",
+				zap.Error(err),
+				zap.String("task_id", task.ID),
+			)
+		}
+	}()
+}
+
+func joinKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, fmt.Sprintf("%q", key))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
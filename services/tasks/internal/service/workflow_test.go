@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestTaskStatusWorkflow(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	ctx := context.Background()
+
+	// Задачи без проекта работают по процессу по умолчанию
+	task := createTestTask(service, "Plain", "student", t)
+	if task.Status != models.StatusTodo {
+		t.Errorf("Expected default status %q, got %q", models.StatusTodo, task.Status)
+	}
+	done := true
+	updated, err := service.Update(task.ID, models.TaskUpdate{Done: &done}, "student", ctx)
+	if err != nil || updated.Status != models.StatusDone {
+		t.Fatalf("Expected done to map to status done, got %+v, %v", updated, err)
+	}
+
+	project, err := service.CreateProject(models.CreateProjectRequest{
+		Name: "Support",
+		Workflow: &models.Workflow{
+			Statuses:     []string{"open", "triage", "fixed", "wontfix"},
+			DoneStatuses: []string{"fixed", "wontfix"},
+			Transitions: map[string][]string{
+				"open":    {"triage"},
+				"triage":  {"fixed", "wontfix", "open"},
+				"fixed":   {"open"},
+				"wontfix": {"open"},
+			},
+		},
+	}, "student")
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	ticket, err := service.Create(models.Task{Title: "Crash", ProjectID: project.ID}, "student", ctx)
+	if err != nil || ticket.Status != "open" {
+		t.Fatalf("Expected initial status open, got %+v, %v", ticket, err)
+	}
+	if _, err := service.Create(models.Task{Title: "Bad", ProjectID: project.ID, Status: "review"}, "student", ctx); !errors.Is(err, models.ErrUnknownStatus) {
+		t.Errorf("Expected ErrUnknownStatus, got %v", err)
+	}
+
+	fixed := "fixed"
+	if _, err := service.Update(ticket.ID, models.TaskUpdate{Status: &fixed}, "student", ctx); !errors.Is(err, models.ErrStatusTransition) {
+		t.Errorf("Expected ErrStatusTransition, got %v", err)
+	}
+
+	triage := "triage"
+	if _, err := service.Update(ticket.ID, models.TaskUpdate{Status: &triage}, "student", ctx); err != nil {
+		t.Fatalf("Failed to move to triage: %v", err)
+	}
+	wontfix := "wontfix"
+	ticket, err = service.Update(ticket.ID, models.TaskUpdate{Status: &wontfix}, "student", ctx)
+	if err != nil || !ticket.Done {
+		t.Fatalf("Expected wontfix to mark task done, got %+v, %v", ticket, err)
+	}
+
+	// Снятие done возвращает задачу в начальный статус
+	notDone := false
+	ticket, err = service.Update(ticket.ID, models.TaskUpdate{Done: &notDone}, "student", ctx)
+	if err != nil || ticket.Status != "open" || ticket.Done {
+		t.Errorf("Expected done=false to reopen task, got %+v, %v", ticket, err)
+	}
+
+	// Процесс без используемого статуса не сохраняется
+	if _, err := service.UpdateProject(project.ID, models.ProjectUpdate{Workflow: &models.Workflow{
+		Statuses:     []string{"new", "closed"},
+		DoneStatuses: []string{"closed"},
+	}}, "student"); err == nil {
+		t.Error("Expected workflow without used status to be rejected")
+	}
+}
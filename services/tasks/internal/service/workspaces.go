@@ -0,0 +1,94 @@
+package service
+
+import (
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/tasks/internal/models"
+)
+
+// SetQuotas задает квоты пространств, для которых в базе не заданы свои
+func (s *TasksService) SetQuotas(quotas models.Quotas) {
+	s.quotas = quotas
+}
+
+// ForTenant возвращает копию сервиса, работающую в пространстве tenant:
+// запросы, ключи кэша и квоты – этого пространства
+func (s *TasksService) ForTenant(tenant string) *TasksService {
+	if tenant == "" {
+		tenant = models.DefaultTenant
+	}
+	scoped := *s
+	scoped.tenant = tenant
+	scoped.repo = s.repo.ForTenant(tenant)
+	if s.cache != nil {
+		scoped.cache = s.cache.ForTenant(tenant)
+	}
+	return &scoped
+}
+
+// workspace возвращает пространство сервиса
+func (s *TasksService) workspace() string {
+	if s.tenant == "" {
+		return models.DefaultTenant
+	}
+	return s.tenant
+}
+
+// JoinWorkspace запоминает пространство пользователя. Вызывается на каждый
+// запрос, поэтому в базу пишется только смена пространства.
+func (s *TasksService) JoinWorkspace(subject string) error {
+	tenant := s.workspace()
+	if known, ok := s.members.Load(subject); ok && known == tenant {
+		return nil
+	}
+	if err := s.repo.JoinWorkspace(subject, tenant); err != nil {
+		return err
+	}
+	s.members.Store(subject, tenant)
+	return nil
+}
+
+// Workspace возвращает пространство сервиса с действующими квотами и их
+// расходом
+func (s *TasksService) Workspace() (models.Workspace, error) {
+	workspace, err := s.repo.GetWorkspace(s.workspace())
+	if err != nil {
+		return models.Workspace{}, err
+	}
+	workspace.ApplyDefaults(s.quotas)
+	return workspace, nil
+}
+
+// checkQuota возвращает QuotaError, если added новых задач или проектов
+// превысят квоту пространства. Вызывается в транзакции создания: блокировка
+// пространства не дает параллельным запросам превысить квоту вместе.
+func (s *TasksService) checkQuota(resource string, added int) error {
+	if err := s.repo.LockWorkspace(s.workspace()); err != nil {
+		return err
+	}
+	workspace, err := s.Workspace()
+	if err != nil {
+		return err
+	}
+	if err := workspace.Check(resource, added); err != nil {
+		s.log.Info("Workspace quota exceeded",
+			zap.String("tenant", workspace.ID),
+			zap.String("resource", resource),
+		)
+		return err
+	}
+	return nil
+}
+
+// checkMember отклоняет пользователя, который входил в другом пространстве.
+// Пользователь, еще не входивший в сервис, проверяется при первом входе:
+// задачи чужого пространства ему не видны.
+func (s *TasksService) checkMember(subject string) error {
+	tenant, err := s.repo.MemberTenant(subject)
+	if err != nil {
+		return err
+	}
+	if tenant != "" && tenant != s.workspace() {
+		return &models.ValidationError{Message: "user is not a member of this workspace"}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"tech-ip-sem2/services/tasks/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+func TestWorkspaceQuotaConcurrentCreate(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	service.SetQuotas(models.Quotas{MaxTasks: 3})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.Create(models.Task{Title: "Task"}, "student", ctx); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 3 {
+		t.Errorf("Expected exactly 3 tasks within quota, got %d", created)
+	}
+}
+
+func TestWorkspaceQuotaTrash(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	service.SetQuotas(models.Quotas{MaxTasks: 1})
+	ctx := context.Background()
+
+	trashed := createTestTask(service, "Trashed", "student", t)
+	if deleted, err := service.Delete(trashed.ID, "student", ctx); err != nil || !deleted {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	// Корзина не расходует квоту
+	workspace, err := service.Workspace()
+	if err != nil || workspace.Usage.Tasks != 0 {
+		t.Errorf("Expected trashed task not to be counted, got %+v, %v", workspace.Usage, err)
+	}
+	createTestTask(service, "Active", "student", t)
+
+	// Восстановление сверх квоты отклоняется, задача остается в корзине
+	var quotaErr *models.QuotaError
+	if _, err := service.Restore(trashed.ID, "student", ctx); !errors.As(err, &quotaErr) {
+		t.Errorf("Expected quota error on restore, got %v", err)
+	}
+	if trash, _ := service.Trash("student"); len(trash) != 1 || trash[0].ID != trashed.ID {
+		t.Errorf("Expected task to stay in trash, got %+v", trash)
+	}
+}
+
+func TestWorkspaces(t *testing.T) {
+	service := NewTasksService(logger.New("test"), nil, nil, nil)
+	service.SetQuotas(models.Quotas{MaxTasks: 2, MaxProjects: 1})
+	ctx := context.Background()
+
+	// Пользователь без пространства работает в default, как до пространств
+	task := createTestTask(service, "Default task", "student", t)
+	acme := service.ForTenant("acme")
+	if err := acme.JoinWorkspace("student"); err != nil {
+		t.Fatalf("Failed to join workspace: %v", err)
+	}
+
+	if got, _ := acme.GetByID(task.ID, "student"); got.ID != "" {
+		t.Error("Expected task of another workspace to be hidden")
+	}
+	if tasks, _ := acme.List("student", models.TaskFilter{}); len(tasks) != 0 {
+		t.Errorf("Expected no tasks in new workspace, got %d", len(tasks))
+	}
+	if deleted, _ := acme.Delete(task.ID, "student", ctx); deleted {
+		t.Error("Expected task of another workspace not to be deleted")
+	}
+
+	// Квоты считаются по пространству: в default уже есть задача
+	if _, err := service.Create(models.Task{Title: "Second"}, "student", ctx); err != nil {
+		t.Fatalf("Failed to create task within quota: %v", err)
+	}
+	var quotaErr *models.QuotaError
+	if _, err := service.Create(models.Task{Title: "Third"}, "student", ctx); !errors.As(err, &quotaErr) || quotaErr.Limit != 2 {
+		t.Errorf("Expected task quota error, got %v", err)
+	}
+	createTestTask(acme, "Acme task", "student", t)
+	if _, err := acme.CreateProject(models.CreateProjectRequest{Name: "Acme"}, "student"); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if _, err := acme.CreateProject(models.CreateProjectRequest{Name: "Second"}, "student"); !errors.As(err, &quotaErr) {
+		t.Errorf("Expected project quota error, got %v", err)
+	}
+
+	workspace, err := acme.Workspace()
+	if err != nil || workspace.ID != "acme" || workspace.Usage != (models.WorkspaceUsage{Members: 1, Tasks: 1, Projects: 1}) {
+		t.Errorf("Unexpected workspace usage: %+v, %v", workspace, err)
+	}
+
+	// Делиться можно только с участниками своего пространства
+	if err := service.JoinWorkspace("teacher"); err != nil {
+		t.Fatalf("Failed to join workspace: %v", err)
+	}
+	acmeTask := createTestTask(acme, "Private", "admin", t)
+	if _, err := acme.ShareTask(acmeTask.ID, models.ShareRequest{Subject: "teacher"}, "admin", ctx); err == nil {
+		t.Error("Expected share with member of another workspace to be rejected")
+	}
+}
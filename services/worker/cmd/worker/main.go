@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/assign"
 	"tech-ip-sem2/services/worker/internal/consumer"
+	"tech-ip-sem2/services/worker/internal/importer"
+	"tech-ip-sem2/services/worker/internal/notify"
+	"tech-ip-sem2/services/worker/internal/purge"
+	"tech-ip-sem2/services/worker/internal/remind"
+	"tech-ip-sem2/services/worker/internal/storage"
+	"tech-ip-sem2/shared/blob"
 	"tech-ip-sem2/shared/logger"
 )
 
@@ -57,6 +67,33 @@ func main() {
 		log.Fatal("Failed to start job consumer", zap.Error(err))
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Очистка корзины задач (нужен доступ к базе tasks сервиса)
+	if purger := newTrashPurger(rabbitURL, workerID, log); purger != nil {
+		defer purger.Close()
+		go purger.Run(ctx)
+	}
+
+	// Напоминания о сроках задач (таблица task_reminders tasks сервиса)
+	if scheduler := newReminderScheduler(workerID, log); scheduler != nil {
+		defer scheduler.Close()
+		go scheduler.Run(ctx)
+	}
+
+	// Уведомления исполнителям задач (таблица task_notifications tasks сервиса)
+	if dispatcher := newAssignmentDispatcher(workerID, log); dispatcher != nil {
+		defer dispatcher.Close()
+		go dispatcher.Run(ctx)
+	}
+
+	// Большие импорты задач (таблица task_imports tasks сервиса)
+	if runner := newImportRunner(workerID, log); runner != nil {
+		defer runner.Close()
+		go runner.Run(ctx)
+	}
+
 	log.Info("Worker fully initialized and waiting for jobs...")
 
 	quit := make(chan os.Signal, 1)
@@ -66,3 +103,209 @@ func main() {
 	log.Info("Worker shutting down...")
 	time.Sleep(1 * time.Second)
 }
+
+// dbConfig описывает базу tasks сервиса по тем же переменным, что и у него;
+// false – база не настроена
+func dbConfig() (storage.DBConfig, bool) {
+	if os.Getenv("DB_DRIVER") == storage.DriverSQLite {
+		// База в памяти tasks сервиса worker'у недоступна
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "tasks.db"
+		}
+		return storage.DBConfig{Driver: storage.DriverSQLite, DSN: path}, path != ":memory:"
+	}
+
+	connStr := dbConnString()
+	return storage.DBConfig{Driver: storage.DriverPostgres, DSN: connStr}, connStr != ""
+}
+
+// dbConnString собирает строку подключения к PostgreSQL tasks сервиса;
+// пустая строка – база не настроена или tasks сервис работает с SQLite
+func dbConnString() string {
+	if os.Getenv("DB_DRIVER") == storage.DriverSQLite {
+		return ""
+	}
+
+	dbHost := os.Getenv("DB_HOST")
+	dbUser := os.Getenv("DB_USER")
+	if dbHost == "" || dbUser == "" {
+		return ""
+	}
+
+	dbSSLMode := os.Getenv("DB_SSLMODE")
+	if dbSSLMode == "" {
+		dbSSLMode = "disable"
+	}
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dbHost, os.Getenv("DB_PORT"), dbUser, os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"), dbSSLMode)
+}
+
+func newTrashPurger(rabbitURL, workerID string, log *logger.Logger) *purge.Purger {
+	config, ok := dbConfig()
+	if !ok {
+		log.Info("Database not configured, trash purge disabled")
+		return nil
+	}
+
+	store, err := storage.NewTrashStore(config)
+	if err != nil {
+		log.Warn("Failed to connect to database, trash purge disabled", zap.Error(err))
+		return nil
+	}
+
+	retentionDays := 30
+	if val, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && val > 0 {
+		retentionDays = val
+	}
+	intervalMinutes := 60
+	if val, err := strconv.Atoi(os.Getenv("TRASH_PURGE_INTERVAL_MINUTES")); err == nil && val > 0 {
+		intervalMinutes = val
+	}
+
+	queue := os.Getenv("RABBITMQ_QUEUE")
+	if queue == "" {
+		queue = "task_events"
+	}
+
+	// Файлы вложений удаляются из того же хранилища, что использует tasks сервис
+	blobs, err := blob.New(blob.ConfigFromEnv())
+	if err != nil {
+		log.Warn("Failed to create blob store, attachment files will not be purged", zap.Error(err))
+	}
+
+	purger, err := purge.NewPurger(purge.PurgerConfig{
+		URL:       rabbitURL,
+		Queue:     queue,
+		Retention: time.Duration(retentionDays) * 24 * time.Hour,
+		Interval:  time.Duration(intervalMinutes) * time.Minute,
+		Instance:  workerID,
+		Blobs:     blobs,
+	}, store, log)
+	if err != nil {
+		store.Close()
+		log.Warn("Failed to create trash purger, trash purge disabled", zap.Error(err))
+		return nil
+	}
+
+	return purger
+}
+
+func newReminderScheduler(workerID string, log *logger.Logger) *remind.Scheduler {
+	config, ok := dbConfig()
+	if !ok {
+		log.Info("Database not configured, reminders disabled")
+		return nil
+	}
+
+	store, err := storage.NewReminderStore(config)
+	if err != nil {
+		log.Warn("Failed to connect to database, reminders disabled", zap.Error(err))
+		return nil
+	}
+
+	intervalSeconds := 30
+	if val, err := strconv.Atoi(os.Getenv("REMINDER_INTERVAL_SECONDS")); err == nil && val > 0 {
+		intervalSeconds = val
+	}
+	maxAttempts := 5
+	if val, err := strconv.Atoi(os.Getenv("REMINDER_MAX_ATTEMPTS")); err == nil && val > 0 {
+		maxAttempts = val
+	}
+
+	channels := []notify.Channel{
+		notify.NewLogChannel(log),
+		notify.NewWebhookChannel(os.Getenv("REMINDER_WEBHOOK_URL"), 5*time.Second),
+	}
+	// Без SMTP напоминания по email не отправляются и повторяются до отказа
+	if smtpAddr := os.Getenv("SMTP_ADDR"); smtpAddr != "" {
+		from := os.Getenv("REMINDER_EMAIL_FROM")
+		if from == "" {
+			from = "reminders@tasks.local"
+		}
+		channels = append(channels, notify.NewEmailChannel(smtpAddr, from))
+	}
+
+	return remind.NewScheduler(remind.SchedulerConfig{
+		Interval:    time.Duration(intervalSeconds) * time.Second,
+		Lease:       time.Minute,
+		MaxAttempts: maxAttempts,
+		BatchSize:   100,
+		Instance:    workerID,
+	}, store, channels, log)
+}
+
+func newAssignmentDispatcher(workerID string, log *logger.Logger) *assign.Dispatcher {
+	config, ok := dbConfig()
+	if !ok {
+		log.Info("Database not configured, assignment notifications disabled")
+		return nil
+	}
+
+	store, err := storage.NewNotificationStore(config)
+	if err != nil {
+		log.Warn("Failed to connect to database, assignment notifications disabled", zap.Error(err))
+		return nil
+	}
+
+	intervalSeconds := 10
+	if val, err := strconv.Atoi(os.Getenv("NOTIFICATION_INTERVAL_SECONDS")); err == nil && val > 0 {
+		intervalSeconds = val
+	}
+
+	// Без webhook уведомления только пишутся в лог
+	var notifier notify.Notifier = notify.NewLogChannel(log)
+	webhookURL := os.Getenv("ASSIGNMENT_WEBHOOK_URL")
+	if webhookURL == "" {
+		webhookURL = os.Getenv("REMINDER_WEBHOOK_URL")
+	}
+	if webhookURL != "" {
+		notifier = notify.NewWebhookChannel(webhookURL, 5*time.Second)
+	}
+
+	return assign.NewDispatcher(assign.DispatcherConfig{
+		Interval:    time.Duration(intervalSeconds) * time.Second,
+		Lease:       time.Minute,
+		MaxAttempts: 5,
+		BatchSize:   100,
+		Instance:    workerID,
+	}, store, notifier, log)
+}
+
+func newImportRunner(workerID string, log *logger.Logger) *importer.Runner {
+	connStr := dbConnString()
+	if connStr == "" {
+		log.Info("Database not configured, task imports disabled")
+		return nil
+	}
+
+	store, err := storage.NewImportStore(connStr)
+	if err != nil {
+		log.Warn("Failed to connect to database, task imports disabled", zap.Error(err))
+		return nil
+	}
+
+	intervalSeconds := 5
+	if val, err := strconv.Atoi(os.Getenv("IMPORT_INTERVAL_SECONDS")); err == nil && val > 0 {
+		intervalSeconds = val
+	}
+
+	// Без Redis список задач обновится по истечении TTL кэша tasks сервиса
+	var cache *redis.Client
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		redisDB, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
+		cache = redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: os.Getenv("REDIS_PASSWORD"),
+			DB:       redisDB,
+		})
+	}
+
+	return importer.NewRunner(importer.RunnerConfig{
+		Interval:    time.Duration(intervalSeconds) * time.Second,
+		Lease:       2 * time.Minute,
+		MaxAttempts: 3,
+		ChunkSize:   100,
+		Instance:    workerID,
+	}, store, cache, log)
+}
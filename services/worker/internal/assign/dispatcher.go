@@ -0,0 +1,137 @@
+package assign
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/services/worker/internal/notify"
+	"tech-ip-sem2/services/worker/internal/storage"
+	"tech-ip-sem2/shared/logger"
+)
+
+// Dispatcher периодически захватывает уведомления исполнителям задач и
+// доставляет их через notifier. Захват устроен так же, как у напоминаний:
+// уведомление доставляется одним worker'ом.
+type Dispatcher struct {
+	store       *storage.NotificationStore
+	notifier    notify.Notifier
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	batchSize   int
+	instance    string
+	log         *logger.Logger
+}
+
+type DispatcherConfig struct {
+	Interval    time.Duration // период проверки очереди уведомлений
+	Lease       time.Duration // время, на которое worker захватывает уведомление
+	MaxAttempts int           // попыток доставки до отказа
+	BatchSize   int           // уведомлений за одну проверку
+	Instance    string
+}
+
+func NewDispatcher(config DispatcherConfig, store *storage.NotificationStore, notifier notify.Notifier, log *logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		notifier:    notifier,
+		interval:    config.Interval,
+		lease:       config.Lease,
+		maxAttempts: config.MaxAttempts,
+		batchSize:   config.BatchSize,
+		instance:    config.Instance,
+		log:         log,
+	}
+}
+
+// Run проверяет очередь сразу и затем каждые interval до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.log.Info("Assignment notifications started",
+		zap.String("instance", d.instance),
+		zap.String("notifier", d.notifier.Name()),
+		zap.Duration("interval", d.interval),
+	)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchPending доставляет до batchSize уведомлений, захватывая каждое
+// непосредственно перед доставкой
+func (d *Dispatcher) dispatchPending(ctx context.Context) {
+	for i := 0; i < d.batchSize && ctx.Err() == nil; i++ {
+		notification, ok, err := d.store.ClaimNext(ctx, d.instance, d.lease, d.maxAttempts)
+		if err != nil {
+			d.log.Error("Failed to claim notification", zap.Error(err))
+			return
+		}
+		if !ok {
+			return
+		}
+
+		d.deliver(ctx, notification)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, notification models.TaskNotification) {
+	// Доставка должна уложиться в захват
+	sendCtx, cancel := context.WithTimeout(ctx, d.lease/2)
+	err := d.notifier.Notify(sendCtx, notification)
+	cancel()
+
+	if err != nil {
+		// Экспоненциальная задержка перед следующей попыткой
+		retryAt := time.Now().Add(d.interval * time.Duration(1<<min(notification.Attempt, 6)))
+		d.log.Warn("Failed to deliver notification",
+			zap.Error(err),
+			zap.String("notification_id", notification.ID),
+			zap.Int("attempt", notification.Attempt),
+		)
+		reason := err.Error()
+		if len(reason) > 255 {
+			reason = reason[:255]
+		}
+		if err := d.store.MarkFailed(ctx, notification.ID, d.instance, retryAt, reason); err != nil {
+			d.log.Error("Failed to record notification failure", zap.Error(err), zap.String("notification_id", notification.ID))
+		}
+		return
+	}
+
+	sent, err := d.store.MarkSent(ctx, notification.ID, d.instance)
+	if err != nil {
+		d.log.Error("Failed to mark notification sent", zap.Error(err), zap.String("notification_id", notification.ID))
+		return
+	}
+	if !sent {
+		d.log.Warn("Notification claim expired before it was confirmed", zap.String("notification_id", notification.ID))
+		return
+	}
+
+	d.log.Info("Notification delivered",
+		zap.String("instance", d.instance),
+		zap.String("notification_id", notification.ID),
+		zap.String("event", notification.Event),
+		zap.String("task_id", notification.TaskID),
+		zap.String("recipient", notification.Recipient),
+	)
+}
+
+// Close закрывает хранилище уведомлений
+func (d *Dispatcher) Close() error {
+	if d.store != nil {
+		return d.store.Close()
+	}
+	return nil
+}
@@ -159,6 +159,50 @@ func (c *Consumer) processMessage(msg amqp.Delivery) {
 		c.log.Info("Task updated event processed", zap.String("task_id", taskID))
 	case "task.deleted":
 		c.log.Info("Task deleted event processed", zap.String("task_id", taskID))
+	case "task.status_changed":
+		previousStatus, _ := event["previous_status"].(string)
+		status, _ := event["status"].(string)
+		c.log.Info("Task status changed event processed",
+			zap.String("task_id", taskID),
+			zap.String("previous_status", previousStatus),
+			zap.String("status", status),
+		)
+	case "task.bulk":
+		// Одно сообщение на bulk операцию; события задач лежат в events
+		events, _ := event["events"].([]interface{})
+		counts := map[string]int{}
+		for _, item := range events {
+			if itemEvent, ok := item.(map[string]interface{}); ok {
+				name, _ := itemEvent["event"].(string)
+				counts[name]++
+			}
+		}
+		c.log.Info("Task bulk event processed",
+			zap.Int("events", len(events)),
+			zap.Any("by_event", counts),
+		)
+	case "task.assigned", "task.unassigned":
+		assignee, _ := event["assignee"].(string)
+		c.log.Info("Task assignment event processed",
+			zap.String("event", eventType),
+			zap.String("task_id", taskID),
+			zap.String("assignee", assignee),
+		)
+	case "comment.mentioned":
+		c.log.Info("Comment mention event processed",
+			zap.String("task_id", taskID),
+		)
+	case "task.shared", "task.unshared", "project.shared", "project.unshared":
+		resourceType, _ := event["resource_type"].(string)
+		resourceID, _ := event[resourceType+"_id"].(string)
+		grantee, _ := event["grantee"].(string)
+		role, _ := event["role"].(string)
+		c.log.Info("Access change event processed",
+			zap.String("event", eventType),
+			zap.String("resource_id", resourceID),
+			zap.String("grantee", grantee),
+			zap.String("role", role),
+		)
 	default:
 		c.log.Warn("Unknown event type", zap.String("event", eventType))
 	}
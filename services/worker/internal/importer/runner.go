@@ -0,0 +1,229 @@
+package importer
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/services/worker/internal/storage"
+	"tech-ip-sem2/shared/logger"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Ключи кэша списков задач и статистики tasks сервиса; ключи пространства
+// default не содержат его имени
+const (
+	keyPrefix     = "tasks:"
+	listKeyPart   = "list:"
+	statsKeyPart  = "stats:"
+	defaultTenant = "default"
+)
+
+type RunnerConfig struct {
+	Interval    time.Duration
+	Lease       time.Duration
+	MaxAttempts int
+	// Число задач между сохранениями прогресса импорта
+	ChunkSize int
+	Instance  string
+}
+
+// Runner выполняет большие импорты задач, поставленные tasks сервисом в
+// очередь. Прогресс сохраняется частями, поэтому прерванный импорт
+// продолжается другим worker'ом без повторного создания задач.
+type Runner struct {
+	store       *storage.ImportStore
+	cache       *redis.Client
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	chunkSize   int
+	instance    string
+	log         *logger.Logger
+}
+
+// NewRunner создает исполнитель импортов; cache (может быть nil) – Redis
+// tasks сервиса для сброса кэша списка задач после импорта
+func NewRunner(cfg RunnerConfig, store *storage.ImportStore, cache *redis.Client, log *logger.Logger) *Runner {
+	return &Runner{
+		store:       store,
+		cache:       cache,
+		interval:    cfg.Interval,
+		lease:       cfg.Lease,
+		maxAttempts: cfg.MaxAttempts,
+		chunkSize:   cfg.ChunkSize,
+		instance:    cfg.Instance,
+		log:         log,
+	}
+}
+
+// Run выполняет импорты из очереди до отмены контекста
+func (r *Runner) Run(ctx context.Context) {
+	r.log.Info("Task imports started",
+		zap.String("instance", r.instance),
+		zap.Duration("interval", r.interval),
+	)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.runQueued(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) runQueued(ctx context.Context) {
+	imports, err := r.store.ClaimQueued(ctx, r.instance, r.lease, r.maxAttempts, 1)
+	if err != nil {
+		r.log.Error("Failed to claim imports", zap.Error(err))
+		return
+	}
+
+	for i := range imports {
+		if err := r.process(ctx, &imports[i]); err != nil {
+			r.fail(ctx, imports[i], err)
+		}
+	}
+}
+
+// process создает задачи из оставшихся записей импорта. Строки с ошибками и
+// повторы задач попадают в отчет, ошибка базы прерывает импорт, оставляя в
+// job необработанные записи.
+func (r *Runner) process(ctx context.Context, job *models.TaskImport) error {
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	existing, err := r.store.ExistingTasks(ctx, job.Subject, job.Tenant)
+	if err != nil {
+		return err
+	}
+	dedup := taskio.NewDedup()
+	for _, record := range existing {
+		dedup.Add(record)
+	}
+
+	for len(job.Pending) > 0 {
+		record := job.Pending[0].Record
+		row := taskio.RowResult{Line: job.Pending[0].Line, Title: record.Title}
+		err := record.Normalize()
+		record.DueDate = record.DueDate.In(loc)
+		switch {
+		case err != nil:
+			row.Status, row.Error = taskio.RowFailed, err.Error()
+		case dedup.Seen(record):
+			row.Status, row.Error = taskio.RowSkipped, "duplicate task"
+		default:
+			id, err := r.store.CreateTask(ctx, job.Subject, job.Tenant, record)
+			if err != nil {
+				// Уже созданные задачи не должны повториться при следующей попытке
+				r.saveProgress(ctx, *job)
+				return err
+			}
+			row.Status, row.TaskID = taskio.RowCreated, id
+		}
+		job.Report.Add(row)
+		job.Pending = job.Pending[1:]
+
+		if len(job.Report.Rows)%r.chunkSize == 0 && len(job.Pending) > 0 && !r.saveProgress(ctx, *job) {
+			return nil
+		}
+	}
+
+	job.Report.Sort()
+	if err := r.store.Finish(ctx, *job, r.instance, "completed", ""); err != nil {
+		return err
+	}
+	r.invalidate(ctx, *job)
+
+	r.log.Info("Import completed",
+		zap.String("instance", r.instance),
+		zap.String("import_id", job.ID),
+		zap.String("subject", job.Subject),
+		zap.Int("created", job.Report.Created),
+		zap.Int("skipped", job.Report.Skipped),
+		zap.Int("failed", job.Report.Failed),
+	)
+	return nil
+}
+
+// saveProgress сохраняет прогресс импорта; false – продолжать импорт нельзя
+func (r *Runner) saveProgress(ctx context.Context, job models.TaskImport) bool {
+	saved, err := r.store.SaveProgress(ctx, job, r.instance, r.lease)
+	if err != nil {
+		r.log.Error("Failed to save import progress", zap.Error(err), zap.String("import_id", job.ID))
+		return false
+	}
+	if !saved {
+		r.log.Warn("Import claim expired before progress was saved", zap.String("import_id", job.ID))
+	}
+	return saved
+}
+
+// fail откладывает импорт до следующей попытки, а после последней
+// завершает его с ошибкой
+func (r *Runner) fail(ctx context.Context, job models.TaskImport, err error) {
+	r.log.Warn("Failed to run import",
+		zap.Error(err),
+		zap.String("import_id", job.ID),
+		zap.Int("attempt", job.Attempt),
+	)
+	reason := err.Error()
+	if len(reason) > 255 {
+		reason = reason[:255]
+	}
+
+	if job.Attempt >= r.maxAttempts {
+		for _, pending := range job.Pending {
+			job.Report.Add(taskio.RowResult{Line: pending.Line, Title: pending.Record.Title, Status: taskio.RowFailed, Error: "import failed"})
+		}
+		job.Pending = nil
+		job.Report.Sort()
+		if err := r.store.Finish(ctx, job, r.instance, "failed", reason); err != nil {
+			r.log.Error("Failed to record import failure", zap.Error(err), zap.String("import_id", job.ID))
+		}
+		r.invalidate(ctx, job)
+		return
+	}
+
+	// Экспоненциальная задержка перед следующей попыткой
+	retryAt := time.Now().Add(r.interval * time.Duration(1<<min(job.Attempt, 6)))
+	if err := r.store.MarkFailed(ctx, job.ID, r.instance, retryAt, reason); err != nil {
+		r.log.Error("Failed to record import failure", zap.Error(err), zap.String("import_id", job.ID))
+	}
+}
+
+// invalidate сбрасывает кэш списка задач и статистики пользователя импорта
+// в tasks сервисе
+func (r *Runner) invalidate(ctx context.Context, job models.TaskImport) {
+	if r.cache == nil {
+		return
+	}
+	prefix := keyPrefix
+	if job.Tenant != "" && job.Tenant != defaultTenant {
+		prefix += job.Tenant + ":"
+	}
+	if err := r.cache.Del(ctx, prefix+listKeyPart+job.Subject, prefix+statsKeyPart+job.Subject).Err(); err != nil {
+		r.log.Warn("Failed to invalidate task list cache", zap.Error(err), zap.String("subject", job.Subject))
+	}
+}
+
+// Close закрывает хранилище импортов и соединение с Redis
+func (r *Runner) Close() error {
+	if r.cache != nil {
+		r.cache.Close()
+	}
+	if r.store != nil {
+		return r.store.Close()
+	}
+	return nil
+}
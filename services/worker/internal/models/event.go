@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"tech-ip-sem2/shared/taskio"
+)
 
 type TaskEvent struct {
 	Event     string    `json:"event"`
@@ -12,7 +16,75 @@ type TaskEvent struct {
 }
 
 const (
-	EventTaskCreated = "task.created"
-	EventTaskUpdated = "task.updated"
-	EventTaskDeleted = "task.deleted"
+	EventTaskCreated  = "task.created"
+	EventTaskUpdated  = "task.updated"
+	EventTaskDeleted  = "task.deleted"
+	EventTaskRestored = "task.restored"
+	EventTaskPurged   = "task.purged"
+	// Событие сервиса задач о смене статуса по процессу проекта
+	EventTaskStatusChanged = "task.status_changed"
+	// События bulk операции сервиса задач одним сообщением
+	EventTaskBulk = "task.bulk"
 )
+
+// PurgedTask – задача, окончательно удаленная из корзины
+type PurgedTask struct {
+	ID      string
+	Title   string
+	Subject string
+	// Ключи файлов вложений задачи в blob-хранилище
+	AttachmentKeys []string
+}
+
+// Напоминание о сроке задачи, отправляемое worker'ом
+const EventTaskReminder = "task.reminder"
+
+// DueReminder – напоминание, захваченное worker'ом для отправки
+type DueReminder struct {
+	ID            string    `json:"reminder_id"`
+	TaskID        string    `json:"task_id"`
+	Title         string    `json:"title"`
+	Subject       string    `json:"subject"`
+	DueDate       string    `json:"due_date"`
+	MinutesBefore int       `json:"minutes_before"`
+	Channel       string    `json:"channel"`
+	Target        string    `json:"-"`
+	FireAt        time.Time `json:"fire_at"`
+	Attempt       int       `json:"attempt"`
+}
+
+// Уведомления из очереди task_notifications: исполнителю задачи и
+// упомянутому в комментарии
+const (
+	EventTaskAssigned     = "task.assigned"
+	EventTaskUnassigned   = "task.unassigned"
+	EventCommentMentioned = "comment.mentioned"
+)
+
+// TaskNotification – уведомление пользователю Recipient, захваченное
+// worker'ом для доставки; Owner – владелец задачи, Actor – кто ее изменил
+type TaskNotification struct {
+	ID        string    `json:"notification_id"`
+	Event     string    `json:"event"`
+	TaskID    string    `json:"task_id"`
+	Title     string    `json:"title"`
+	Owner     string    `json:"owner"`
+	Recipient string    `json:"recipient"`
+	Actor     string    `json:"actor"`
+	CommentID string    `json:"comment_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Attempt   int       `json:"attempt"`
+}
+
+// TaskImport – импорт задач из файла, поставленный tasks сервисом в очередь
+// worker'а (таблица task_imports). Pending – еще не созданные строки файла,
+// Report – отчет по уже обработанным.
+type TaskImport struct {
+	ID       string
+	Subject  string
+	Tenant   string
+	Timezone string
+	Pending  []taskio.Queued
+	Report   taskio.Report
+	Attempt  int
+}
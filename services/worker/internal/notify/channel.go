@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/shared/logger"
+)
+
+// Channel доставляет напоминание о сроке задачи. Новый канал подключается
+// регистрацией в планировщике под именем из task_reminders.channel.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, reminder models.DueReminder) error
+}
+
+// LogChannel пишет напоминание в лог worker'а
+type LogChannel struct {
+	log *logger.Logger
+}
+
+func NewLogChannel(log *logger.Logger) *LogChannel {
+	return &LogChannel{log: log}
+}
+
+func (c *LogChannel) Name() string { return "log" }
+
+func (c *LogChannel) Send(ctx context.Context, reminder models.DueReminder) error {
+	c.log.Info("Task reminder",
+		zap.String("event", models.EventTaskReminder),
+		zap.String("reminder_id", reminder.ID),
+		zap.String("task_id", reminder.TaskID),
+		zap.String("title", reminder.Title),
+		zap.String("subject", reminder.Subject),
+		zap.String("due_date", reminder.DueDate),
+	)
+	return nil
+}
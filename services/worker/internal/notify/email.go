@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"tech-ip-sem2/services/worker/internal/models"
+)
+
+// EmailChannel отправляет письмо через SMTP без авторизации
+// (локальная заглушка вроде mailpit)
+type EmailChannel struct {
+	addr string
+	from string
+}
+
+func NewEmailChannel(addr string, from string) *EmailChannel {
+	return &EmailChannel{addr: addr, from: from}
+}
+
+func (c *EmailChannel) Name() string { return "email" }
+
+func (c *EmailChannel) Send(ctx context.Context, reminder models.DueReminder) error {
+	if reminder.Target == "" {
+		return fmt.Errorf("email recipient not set")
+	}
+
+	// Заголовки не должны содержать переводов строк из названия задачи
+	title := strings.NewReplacer("\r", " ", "\n", " ").Replace(reminder.Title)
+	message := strings.Join([]string{
+		"From: " + c.from,
+		"To: " + reminder.Target,
+		"Subject: Reminder: " + title,
+		"X-Reminder-ID: " + reminder.ID,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Task %q is due on %s.", reminder.Title, reminder.DueDate),
+		"",
+	}, "\r\n")
+
+	if err := smtp.SendMail(c.addr, nil, c.from, []string{reminder.Target}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+)
+
+// Notifier доставляет пользователю уведомление о событии задачи
+// (назначение исполнителем и снятие, упоминание в комментарии)
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, notification models.TaskNotification) error
+}
+
+// Notify пишет уведомление в лог worker'а
+func (c *LogChannel) Notify(ctx context.Context, notification models.TaskNotification) error {
+	c.log.Info("Task notification",
+		zap.String("event", notification.Event),
+		zap.String("notification_id", notification.ID),
+		zap.String("task_id", notification.TaskID),
+		zap.String("title", notification.Title),
+		zap.String("recipient", notification.Recipient),
+		zap.String("actor", notification.Actor),
+		zap.String("comment_id", notification.CommentID),
+	)
+	return nil
+}
+
+type notificationPayload struct {
+	models.TaskNotification
+	Timestamp time.Time `json:"ts"`
+}
+
+// Notify отправляет POST с JSON уведомлением на адрес по умолчанию
+func (c *WebhookChannel) Notify(ctx context.Context, notification models.TaskNotification) error {
+	if c.defaultURL == "" {
+		return errors.New("webhook url not configured")
+	}
+
+	body, err := json.Marshal(notificationPayload{
+		TaskNotification: notification,
+		Timestamp:        time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.defaultURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notification-ID", notification.ID)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
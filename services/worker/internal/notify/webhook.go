@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"tech-ip-sem2/services/worker/internal/models"
+)
+
+type webhookPayload struct {
+	Event string `json:"event"`
+	models.DueReminder
+	Timestamp time.Time `json:"ts"`
+}
+
+// WebhookChannel отправляет POST с JSON событием task.reminder на target
+// напоминания или на адрес по умолчанию
+type WebhookChannel struct {
+	client     *http.Client
+	defaultURL string
+}
+
+func NewWebhookChannel(defaultURL string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		client:     &http.Client{Timeout: timeout},
+		defaultURL: defaultURL,
+	}
+}
+
+func (c *WebhookChannel) Name() string { return "webhook" }
+
+func (c *WebhookChannel) Send(ctx context.Context, reminder models.DueReminder) error {
+	url := reminder.Target
+	if url == "" {
+		url = c.defaultURL
+	}
+	if url == "" {
+		return errors.New("webhook url not configured")
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Event:       models.EventTaskReminder,
+		DueReminder: reminder,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Получатель может отбрасывать повторы по идентификатору напоминания
+	req.Header.Set("X-Reminder-ID", reminder.ID)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/services/worker/internal/storage"
+	"tech-ip-sem2/shared/blob"
+	"tech-ip-sem2/shared/logger"
+)
+
+// Purger периодически очищает корзину задач и публикует task.purged
+type Purger struct {
+	store     *storage.TrashStore
+	blobs     blob.Store
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	queue     string
+	retention time.Duration
+	interval  time.Duration
+	instance  string
+	log       *logger.Logger
+}
+
+type PurgerConfig struct {
+	URL       string
+	Queue     string        // очередь событий задач
+	Retention time.Duration // срок хранения задачи в корзине
+	Interval  time.Duration // период запуска очистки
+	Instance  string
+	// Хранилище вложений tasks сервиса; nil – файлы не удаляются
+	Blobs blob.Store
+}
+
+func NewPurger(config PurgerConfig, store *storage.TrashStore, log *logger.Logger) (*Purger, error) {
+	conn, err := amqp.Dial(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	// Та же очередь, в которую tasks сервис публикует события
+	_, err = ch.QueueDeclare(
+		config.Queue, // name
+		true,         // durable
+		false,        // delete when unused
+		false,        // exclusive
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	return &Purger{
+		store:     store,
+		blobs:     config.Blobs,
+		conn:      conn,
+		channel:   ch,
+		queue:     config.Queue,
+		retention: config.Retention,
+		interval:  config.Interval,
+		instance:  config.Instance,
+		log:       log,
+	}, nil
+}
+
+// Run запускает очистку сразу и затем каждые interval до отмены ctx
+func (p *Purger) Run(ctx context.Context) {
+	p.log.Info("Trash purger started",
+		zap.String("instance", p.instance),
+		zap.Duration("retention", p.retention),
+		zap.Duration("interval", p.interval),
+	)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purgeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purgeOnce(ctx context.Context) {
+	before := time.Now().Add(-p.retention)
+
+	purged, err := p.store.PurgeDeleted(ctx, before)
+	if err != nil {
+		p.log.Error("Failed to purge trash", zap.Error(err))
+		return
+	}
+
+	for _, task := range purged {
+		p.deleteAttachments(ctx, task)
+		if err := p.publishPurged(ctx, task); err != nil {
+			p.log.Error("Failed to publish task.purged event",
+				zap.Error(err),
+				zap.String("task_id", task.ID),
+			)
+		}
+	}
+
+	if len(purged) > 0 {
+		p.log.Info("Trash purged",
+			zap.String("instance", p.instance),
+			zap.Int("count", len(purged)),
+			zap.Time("deleted_before", before),
+		)
+	}
+}
+
+// deleteAttachments удаляет файлы вложений задачи; ошибка оставляет
+// файл-сироту и только логируется
+func (p *Purger) deleteAttachments(ctx context.Context, task models.PurgedTask) {
+	if p.blobs == nil {
+		return
+	}
+	for _, key := range task.AttachmentKeys {
+		if err := p.blobs.Delete(ctx, key); err != nil {
+			p.log.Error("Failed to delete attachment file",
+				zap.Error(err),
+				zap.String("task_id", task.ID),
+				zap.String("storage_key", key),
+			)
+		}
+	}
+}
+
+func (p *Purger) publishPurged(ctx context.Context, task models.PurgedTask) error {
+	event := models.TaskEvent{
+		Event:     models.EventTaskPurged,
+		TaskID:    task.ID,
+		Title:     task.Title,
+		Subject:   task.Subject,
+		Timestamp: time.Now(),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return p.channel.PublishWithContext(pubCtx,
+		"",      // exchange
+		p.queue, // routing key
+		false,   // mandatory
+		false,   // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+		})
+}
+
+// Close закрывает соединение с RabbitMQ и хранилище корзины
+func (p *Purger) Close() error {
+	if p.channel != nil {
+		p.channel.Close()
+	}
+	if p.store != nil {
+		p.store.Close()
+	}
+	if p.conn != nil {
+		return p.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/services/worker/internal/notify"
+	"tech-ip-sem2/services/worker/internal/storage"
+	"tech-ip-sem2/shared/logger"
+)
+
+// Scheduler периодически захватывает наступившие напоминания и отправляет
+// их через каналы. Напоминание отмечается отправленным только захватившим
+// его worker'ом, поэтому реплики не дублируют отправку.
+type Scheduler struct {
+	store       *storage.ReminderStore
+	channels    map[string]notify.Channel
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	batchSize   int
+	instance    string
+	log         *logger.Logger
+}
+
+type SchedulerConfig struct {
+	Interval    time.Duration // период проверки напоминаний
+	Lease       time.Duration // время, на которое worker захватывает напоминание
+	MaxAttempts int           // попыток отправки до отказа
+	BatchSize   int           // напоминаний за одну проверку
+	Instance    string
+}
+
+func NewScheduler(config SchedulerConfig, store *storage.ReminderStore, channels []notify.Channel, log *logger.Logger) *Scheduler {
+	byName := make(map[string]notify.Channel, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+
+	return &Scheduler{
+		store:       store,
+		channels:    byName,
+		interval:    config.Interval,
+		lease:       config.Lease,
+		maxAttempts: config.MaxAttempts,
+		batchSize:   config.BatchSize,
+		instance:    config.Instance,
+		log:         log,
+	}
+}
+
+// Run проверяет напоминания сразу и затем каждые interval до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	s.log.Info("Reminder scheduler started",
+		zap.String("instance", s.instance),
+		zap.Duration("interval", s.interval),
+	)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.fireDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fireDue отправляет до batchSize наступивших напоминаний, захватывая
+// каждое непосредственно перед отправкой
+func (s *Scheduler) fireDue(ctx context.Context) {
+	for i := 0; i < s.batchSize && ctx.Err() == nil; i++ {
+		reminder, ok, err := s.store.ClaimNext(ctx, s.instance, s.lease, s.maxAttempts)
+		if err != nil {
+			s.log.Error("Failed to claim reminder", zap.Error(err))
+			return
+		}
+		if !ok {
+			return
+		}
+
+		s.fire(ctx, reminder)
+	}
+}
+
+func (s *Scheduler) fire(ctx context.Context, reminder models.DueReminder) {
+	err := s.send(ctx, reminder)
+	if err != nil {
+		// Экспоненциальная задержка перед следующей попыткой
+		retryAt := time.Now().Add(s.interval * time.Duration(1<<min(reminder.Attempt, 6)))
+		s.log.Warn("Failed to send reminder",
+			zap.Error(err),
+			zap.String("reminder_id", reminder.ID),
+			zap.String("channel", reminder.Channel),
+			zap.Int("attempt", reminder.Attempt),
+		)
+		if err := s.store.MarkFailed(ctx, reminder.ID, s.instance, retryAt, truncate(err.Error(), 255)); err != nil {
+			s.log.Error("Failed to record reminder failure", zap.Error(err), zap.String("reminder_id", reminder.ID))
+		}
+		return
+	}
+
+	fired, err := s.store.MarkFired(ctx, reminder.ID, s.instance)
+	if err != nil {
+		s.log.Error("Failed to mark reminder fired", zap.Error(err), zap.String("reminder_id", reminder.ID))
+		return
+	}
+	if !fired {
+		s.log.Warn("Reminder claim expired before it was confirmed", zap.String("reminder_id", reminder.ID))
+		return
+	}
+
+	s.log.Info("Reminder fired",
+		zap.String("instance", s.instance),
+		zap.String("reminder_id", reminder.ID),
+		zap.String("task_id", reminder.TaskID),
+		zap.String("channel", reminder.Channel),
+	)
+}
+
+func (s *Scheduler) send(ctx context.Context, reminder models.DueReminder) error {
+	channel, ok := s.channels[reminder.Channel]
+	if !ok {
+		return fmt.Errorf("unknown reminder channel %q", reminder.Channel)
+	}
+
+	// Отправка должна уложиться в захват
+	sendCtx, cancel := context.WithTimeout(ctx, s.lease/2)
+	defer cancel()
+
+	return channel.Send(sendCtx, reminder)
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	return value[:limit]
+}
+
+// Close закрывает хранилище напоминаний
+func (s *Scheduler) Close() error {
+	if s.store != nil {
+		return s.store.Close()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// Драйверы базы tasks сервиса (DB_DRIVER)
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// DBConfig – подключение к базе tasks сервиса. Для SQLite worker должен
+// видеть тот же файл, что и tasks сервис.
+type DBConfig struct {
+	Driver string // postgres (по умолчанию) или sqlite
+	DSN    string // строка подключения PostgreSQL или путь к файлу SQLite
+}
+
+// dialect описывает отличия СУБД в запросах worker'а. Общие запросы пишутся
+// в синтаксисе, который понимают обе СУБД.
+type dialect struct {
+	sqlite bool
+}
+
+func openDB(config DBConfig) (*sql.DB, dialect, error) {
+	var d dialect
+	driver, dsn := config.Driver, config.DSN
+	switch driver {
+	case "", DriverPostgres:
+		driver = DriverPostgres
+	case DriverSQLite:
+		// Параметры как у tasks сервиса: каскадное удаление и ожидание
+		// блокировки записи, которую может держать tasks сервис
+		params := "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+		if strings.Contains(dsn, "?") {
+			dsn = "file:" + dsn + "&" + params
+		} else {
+			dsn = "file:" + dsn + "?" + params
+		}
+		d.sqlite = true
+	default:
+		return nil, d, fmt.Errorf("unknown database driver %q", driver)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, d, fmt.Errorf("failed to open database: %w", err)
+	}
+	if d.sqlite {
+		// SQLite допускает только одного писателя
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, d, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, d, nil
+}
+
+// instant возвращает выражение момента времени, сравнимое с другими
+// моментами: SQLite хранит время строкой в часовом поясе записавшего
+func (d dialect) instant(expr string) string {
+	if d.sqlite {
+		return "unixepoch(" + expr + ", 'subsec')"
+	}
+	return expr
+}
+
+// skipLocked – блокировка строк, выбранных подзапросом захвата, без ожидания
+// чужих блокировок. SQLite выполняет запись целиком под блокировкой базы.
+func (d dialect) skipLocked(alias string) string {
+	if d.sqlite {
+		return ""
+	}
+	if alias != "" {
+		return "FOR UPDATE OF " + alias + " SKIP LOCKED"
+	}
+	return "FOR UPDATE SKIP LOCKED"
+}
+
+// inList возвращает условие вхождения column в список, переданный одним
+// аргументом с номером n, и значение этого аргумента
+func (d dialect) inList(column string, n int, values []string) (string, any) {
+	placeholder := "$" + strconv.Itoa(n)
+	if d.sqlite {
+		list, _ := json.Marshal(values)
+		return column + " IN (SELECT value FROM json_each(" + placeholder + "))", string(list)
+	}
+	return column + " = ANY(" + placeholder + ")", pq.Array(values)
+}
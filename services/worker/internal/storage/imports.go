@@ -0,0 +1,315 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/taskio"
+)
+
+// Цвет меток, создаваемых импортом, как у tasks сервиса
+const defaultTagColor = "#808080"
+
+// ImportStore выполняет импорты задач, поставленные tasks сервисом в очередь.
+// Схему (таблицы task_imports, tasks, tags и task_revisions) создают
+// миграции tasks сервиса.
+type ImportStore struct {
+	db *sql.DB
+}
+
+func NewImportStore(connStr string) (*ImportStore, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &ImportStore{db: db}, nil
+}
+
+// ClaimQueued захватывает до limit импортов на время lease. Импорт, захват
+// которого истек (worker остановился), захватывается снова и продолжается
+// с сохраненного места.
+func (s *ImportStore) ClaimQueued(ctx context.Context, instance string, lease time.Duration, maxAttempts int, limit int) ([]models.TaskImport, error) {
+	now := time.Now()
+	query := `
+        UPDATE task_imports
+        SET status = 'running', claimed_by = $1, claimed_until = $2, attempts = attempts + 1
+        WHERE id IN (
+            SELECT id
+            FROM task_imports
+            WHERE status IN ('queued', 'running')
+              AND (claimed_until IS NULL OR claimed_until < $3)
+              AND attempts < $4
+            ORDER BY created_at
+            LIMIT $5
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, subject, tenant, timezone, payload, report, attempts
+    `
+
+	rows, err := s.db.QueryContext(ctx, query, instance, now.Add(lease), now, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim imports: %w", err)
+	}
+	defer rows.Close()
+
+	var imports []models.TaskImport
+	for rows.Next() {
+		var job models.TaskImport
+		var payload, report string
+		if err := rows.Scan(&job.ID, &job.Subject, &job.Tenant, &job.Timezone, &payload, &report, &job.Attempt); err != nil {
+			return nil, fmt.Errorf("failed to scan import: %w", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &job.Pending); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal import payload: %w", err)
+		}
+		if err := json.Unmarshal([]byte(report), &job.Report); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal import report: %w", err)
+		}
+		imports = append(imports, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate imports: %w", err)
+	}
+
+	return imports, nil
+}
+
+// ExistingTasks возвращает активные задачи пользователя в пространстве
+// tenant для поиска повторов
+func (s *ImportStore) ExistingTasks(ctx context.Context, subject string, tenant string) ([]taskio.Record, error) {
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT id, title, COALESCE(due_date::text, ''), due_at
+        FROM tasks
+        WHERE subject = $1 AND tenant = $2 AND deleted_at IS NULL
+    `, subject, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var records []taskio.Record
+	for rows.Next() {
+		var record taskio.Record
+		var day string
+		var at sql.NullTime
+		if err := rows.Scan(&record.ID, &record.Title, &day, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		var dueAt *time.Time
+		if at.Valid {
+			dueAt = &at.Time
+		}
+		record.DueDate = due.FromColumns(day, dueAt)
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+
+	return records, nil
+}
+
+// CreateTask создает задачу из записи импорта в пространстве tenant вместе с
+// метками и первой записью истории, как создание через API
+func (s *ImportStore) CreateTask(ctx context.Context, subject string, tenant string, record taskio.Record) (string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id := uuid.New().String()
+	now := time.Now()
+	status := record.Status
+	if status == "" {
+		status = "todo"
+		if record.Done {
+			status = "done"
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO tasks (id, title, description, due_date, due_at, done, status, priority, estimate_points, estimate_minutes,
+            subject, created_at, updated_at, completed_at, tenant)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CASE WHEN $6 THEN $13 END, $14)
+    `, id, record.Title, record.Description, record.DueDate.DayValue(), record.DueDate.TimeValue(), record.Done, status,
+		record.Priority, record.EstimatePoints, record.EstimateMinutes, subject, now, now, tenant)
+	if err != nil {
+		return "", fmt.Errorf("failed to create task: %w", err)
+	}
+
+	for _, name := range record.Tags {
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO tags (id, subject, name, color, created_at)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (subject, name) DO NOTHING
+        `, uuid.New().String(), subject, name, defaultTagColor, now)
+		if err != nil {
+			return "", fmt.Errorf("failed to create tag: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO task_tags (task_id, tag_id)
+            SELECT $1, id FROM tags WHERE subject = $2 AND name = $3
+            ON CONFLICT (task_id, tag_id) DO NOTHING
+        `, id, subject, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to assign tag: %w", err)
+		}
+	}
+
+	changes, snapshot, err := creationRevision(record, status)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO task_revisions (task_id, revision, subject, action, actor, changes, snapshot, created_at)
+        VALUES ($1, 1, $2, 'created', $2, $3, $4, $5)
+    `, id, subject, changes, snapshot, now)
+	if err != nil {
+		return "", fmt.Errorf("failed to add task revision: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+// creationRevision собирает изменения и снимок истории созданной задачи в
+// формате task_revisions tasks сервиса
+func creationRevision(record taskio.Record, status string) (string, string, error) {
+	type fieldChange struct {
+		Field string `json:"field"`
+		Old   any    `json:"old"`
+		New   any    `json:"new"`
+	}
+	changes := []fieldChange{
+		{Field: "title", New: record.Title},
+		{Field: "description", New: record.Description},
+		{Field: "due_date", New: record.DueDate},
+		{Field: "done", New: record.Done},
+		{Field: "status", New: status},
+	}
+	if record.Priority != "" {
+		changes = append(changes, fieldChange{Field: "priority", New: record.Priority})
+	}
+	if record.EstimatePoints != 0 {
+		changes = append(changes, fieldChange{Field: "estimate_points", New: record.EstimatePoints})
+	}
+	if record.EstimateMinutes != 0 {
+		changes = append(changes, fieldChange{Field: "estimate_minutes", New: record.EstimateMinutes})
+	}
+
+	snapshot := struct {
+		Title           string   `json:"title"`
+		Description     string   `json:"description"`
+		DueDate         due.Date `json:"due_date"`
+		Done            bool     `json:"done"`
+		Status          string   `json:"status,omitempty"`
+		Priority        string   `json:"priority,omitempty"`
+		EstimatePoints  int      `json:"estimate_points,omitempty"`
+		EstimateMinutes int      `json:"estimate_minutes,omitempty"`
+	}{record.Title, record.Description, record.DueDate, record.Done, status, record.Priority, record.EstimatePoints, record.EstimateMinutes}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal revision changes: %w", err)
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal revision snapshot: %w", err)
+	}
+	return string(changesJSON), string(snapshotJSON), nil
+}
+
+// SaveProgress сохраняет оставшиеся записи и отчет и продлевает захват.
+// false – захват истек и импорт перешел к другому worker'у.
+func (s *ImportStore) SaveProgress(ctx context.Context, job models.TaskImport, instance string, lease time.Duration) (bool, error) {
+	payload, report, err := marshalImport(job)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+        UPDATE task_imports
+        SET payload = $1, report = $2, claimed_until = $3
+        WHERE id = $4 AND claimed_by = $5 AND status = 'running'
+    `, payload, report, time.Now().Add(lease), job.ID, instance)
+	if err != nil {
+		return false, fmt.Errorf("failed to save import progress: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Finish завершает импорт со статусом completed или failed
+func (s *ImportStore) Finish(ctx context.Context, job models.TaskImport, instance string, status string, reason string) error {
+	payload, report, err := marshalImport(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+        UPDATE task_imports
+        SET status = $1, payload = $2, report = $3, error = NULLIF($4, ''), finished_at = $5,
+            claimed_by = NULL, claimed_until = NULL
+        WHERE id = $6 AND claimed_by = $7
+    `, status, payload, report, reason, time.Now(), job.ID, instance)
+	if err != nil {
+		return fmt.Errorf("failed to finish import: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed сохраняет ошибку и откладывает повторную попытку до retryAt
+func (s *ImportStore) MarkFailed(ctx context.Context, id string, instance string, retryAt time.Time, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE task_imports
+        SET claimed_until = $1, error = $2
+        WHERE id = $3 AND claimed_by = $4 AND status = 'running'
+    `, retryAt, reason, id, instance)
+	if err != nil {
+		return fmt.Errorf("failed to mark import failed: %w", err)
+	}
+	return nil
+}
+
+func marshalImport(job models.TaskImport) (string, string, error) {
+	pending := job.Pending
+	if pending == nil {
+		pending = []taskio.Queued{}
+	}
+	payload, err := json.Marshal(pending)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal import payload: %w", err)
+	}
+	report, err := json.Marshal(job.Report)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal import report: %w", err)
+	}
+	return string(payload), string(report), nil
+}
+
+func (s *ImportStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/worker/internal/models"
+)
+
+// NotificationStore выдает worker'ам уведомления исполнителям задач. Схему
+// (таблицу task_notifications) создают миграции tasks сервиса.
+type NotificationStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func NewNotificationStore(config DBConfig) (*NotificationStore, error) {
+	db, d, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationStore{db: db, dialect: d}, nil
+}
+
+// ClaimNext захватывает одно недоставленное уведомление на время lease так
+// же, как ReminderStore.ClaimNext захватывает напоминания; false – очередь пуста
+func (s *NotificationStore) ClaimNext(ctx context.Context, instance string, lease time.Duration, maxAttempts int) (models.TaskNotification, bool, error) {
+	now := time.Now()
+	d := s.dialect
+	query := `
+        UPDATE task_notifications
+        SET claimed_by = $1, claimed_until = $2, attempts = attempts + 1
+        WHERE id = (
+            SELECT n.id
+            FROM task_notifications n
+            WHERE n.sent_at IS NULL
+              AND (n.claimed_until IS NULL OR ` + d.instant("n.claimed_until") + ` < ` + d.instant("$3") + `)
+              AND n.attempts < $4
+            ORDER BY ` + d.instant("n.created_at") + `
+            LIMIT 1
+            ` + d.skipLocked("") + `
+        )
+        RETURNING id, event, task_id, recipient, actor, COALESCE(comment_id, ''), created_at, attempts
+    `
+
+	var notification models.TaskNotification
+	err := s.db.QueryRowContext(ctx, query, instance, now.Add(lease), now, maxAttempts).Scan(
+		&notification.ID,
+		&notification.Event,
+		&notification.TaskID,
+		&notification.Recipient,
+		&notification.Actor,
+		&notification.CommentID,
+		&notification.CreatedAt,
+		&notification.Attempt,
+	)
+	if err == sql.ErrNoRows {
+		return models.TaskNotification{}, false, nil
+	}
+	if err != nil {
+		return models.TaskNotification{}, false, fmt.Errorf("failed to claim notification: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `SELECT title, subject FROM tasks WHERE id = $1`, notification.TaskID).
+		Scan(&notification.Title, &notification.Owner)
+	if err != nil {
+		return models.TaskNotification{}, false, fmt.Errorf("failed to get notification task: %w", err)
+	}
+
+	return notification, true, nil
+}
+
+// MarkSent отмечает уведомление доставленным. false – захват истек и
+// уведомление перешло к другому worker'у.
+func (s *NotificationStore) MarkSent(ctx context.Context, id string, instance string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+        UPDATE task_notifications
+        SET sent_at = $1, claimed_by = NULL, claimed_until = NULL, last_error = NULL
+        WHERE id = $2 AND claimed_by = $3 AND sent_at IS NULL
+    `, time.Now(), id, instance)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification sent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// MarkFailed откладывает повторную попытку до retryAt
+func (s *NotificationStore) MarkFailed(ctx context.Context, id string, instance string, retryAt time.Time, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE task_notifications
+        SET claimed_until = $1, last_error = $2
+        WHERE id = $3 AND claimed_by = $4 AND sent_at IS NULL
+    `, retryAt, reason, id, instance)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification failed: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationStore) Close() error {
+	return s.db.Close()
+}
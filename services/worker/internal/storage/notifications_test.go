@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotificationClaimConcurrent(t *testing.T) {
+	forEachDB(t, testNotificationClaimConcurrent)
+}
+
+func testNotificationClaimConcurrent(t *testing.T, config DBConfig, db *sql.DB) {
+	ctx := context.Background()
+	now := time.Now()
+
+	exec(t, db, `INSERT INTO tasks (id, title, subject) VALUES ('task-1', 'Report', 'student')`)
+	const total = 20
+	for i := 0; i < total; i++ {
+		exec(t, db, `INSERT INTO task_notifications (id, task_id, recipient, event, actor, created_at) VALUES ($1, 'task-1', 'teacher', 'task.assigned', 'student', $2)`,
+			fmt.Sprintf("n%02d", i), now.Add(-time.Duration(i)*time.Second))
+	}
+
+	// Две реплики разбирают очередь одновременно; каждое уведомление
+	// должно достаться ровно одной
+	var mu sync.Mutex
+	claimed := make(map[string]string)
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, instance := range []string{"worker-1", "worker-2"} {
+		store, err := NewNotificationStore(config)
+		if err != nil {
+			t.Fatalf("Failed to create notification store: %v", err)
+		}
+		defer store.Close()
+
+		wg.Add(1)
+		go func(instance string, store *NotificationStore) {
+			defer wg.Done()
+			for {
+				notification, ok, err := store.ClaimNext(ctx, instance, time.Minute, 5)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				if other, dup := claimed[notification.ID]; dup {
+					errs <- fmt.Errorf("notification %s claimed by %s and %s", notification.ID, other, instance)
+				}
+				claimed[notification.ID] = instance
+				mu.Unlock()
+
+				if notification.Title != "Report" || notification.Owner != "student" || notification.Recipient != "teacher" {
+					errs <- fmt.Errorf("unexpected claimed notification %+v", notification)
+					return
+				}
+				if sent, err := store.MarkSent(ctx, notification.ID, instance); err != nil || !sent {
+					errs <- fmt.Errorf("failed to mark %s sent: %v", notification.ID, err)
+					return
+				}
+			}
+		}(instance, store)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if len(claimed) != total {
+		t.Errorf("Expected %d notifications claimed once, got %d", total, len(claimed))
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM task_notifications WHERE sent_at IS NULL`); n != 0 {
+		t.Errorf("Expected all notifications sent, %d pending", n)
+	}
+}
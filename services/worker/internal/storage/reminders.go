@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/worker/internal/models"
+	"tech-ip-sem2/shared/due"
+)
+
+// ReminderStore выдает напоминания о сроках задач worker'ам. Схему
+// (таблицу task_reminders) создают миграции tasks сервиса.
+type ReminderStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func NewReminderStore(config DBConfig) (*ReminderStore, error) {
+	db, d, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReminderStore{db: db, dialect: d}, nil
+}
+
+// ClaimNext захватывает одно наступившее напоминание на время lease; false –
+// наступивших напоминаний нет. Напоминания захватываются по одному перед
+// отправкой, поэтому аренда каждого отсчитывается от начала его отправки.
+// FOR UPDATE SKIP LOCKED (в SQLite – блокировка записи) не дает двум
+// worker'ам захватить одно напоминание; захват, не подтвержденный MarkFired
+// до конца lease, снова становится доступен.
+func (s *ReminderStore) ClaimNext(ctx context.Context, instance string, lease time.Duration, maxAttempts int) (models.DueReminder, bool, error) {
+	now := time.Now()
+	d := s.dialect
+	query := `
+        UPDATE task_reminders
+        SET claimed_by = $1, claimed_until = $2, attempts = attempts + 1
+        WHERE id = (
+            SELECT r.id
+            FROM task_reminders r
+            JOIN tasks t ON t.id = r.task_id
+            WHERE r.fired_at IS NULL
+              AND ` + d.instant("r.fire_at") + ` <= ` + d.instant("$3") + `
+              AND (r.claimed_until IS NULL OR ` + d.instant("r.claimed_until") + ` < ` + d.instant("$3") + `)
+              AND r.attempts < $4
+              AND t.done = FALSE AND t.deleted_at IS NULL
+            ORDER BY ` + d.instant("r.fire_at") + `
+            LIMIT 1
+            ` + d.skipLocked("r") + `
+        )
+        RETURNING id, task_id, minutes_before, channel, target, fire_at, attempts
+    `
+
+	var reminder models.DueReminder
+	err := s.db.QueryRowContext(ctx, query, instance, now.Add(lease), now, maxAttempts).Scan(
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.MinutesBefore,
+		&reminder.Channel,
+		&reminder.Target,
+		&reminder.FireAt,
+		&reminder.Attempt,
+	)
+	if err == sql.ErrNoRows {
+		return models.DueReminder{}, false, nil
+	}
+	if err != nil {
+		return models.DueReminder{}, false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+
+	var day sql.NullString
+	var at sql.NullTime
+	err = s.db.QueryRowContext(ctx, `
+        SELECT title, subject, due_date, due_at
+        FROM tasks
+        WHERE id = $1
+    `, reminder.TaskID).Scan(&reminder.Title, &reminder.Subject, &day, &at)
+	if err != nil {
+		return models.DueReminder{}, false, fmt.Errorf("failed to get reminder task: %w", err)
+	}
+	var dueAt *time.Time
+	if at.Valid {
+		dueAt = &at.Time
+	}
+	reminder.DueDate = due.FromColumns(day.String, dueAt).String()
+
+	return reminder, true, nil
+}
+
+// MarkFired отмечает напоминание отправленным. false – захват истек и
+// напоминание перешло к другому worker'у.
+func (s *ReminderStore) MarkFired(ctx context.Context, id string, instance string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+        UPDATE task_reminders
+        SET fired_at = $1, claimed_by = NULL, claimed_until = NULL, last_error = NULL
+        WHERE id = $2 AND claimed_by = $3 AND fired_at IS NULL
+    `, time.Now(), id, instance)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark reminder fired: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// MarkFailed откладывает повторную попытку до retryAt
+func (s *ReminderStore) MarkFailed(ctx context.Context, id string, instance string, retryAt time.Time, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+        UPDATE task_reminders
+        SET claimed_until = $1, last_error = $2
+        WHERE id = $3 AND claimed_by = $4 AND fired_at IS NULL
+    `, retryAt, reason, id, instance)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder failed: %w", err)
+	}
+	return nil
+}
+
+func (s *ReminderStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReminderClaimConcurrent(t *testing.T) {
+	forEachDB(t, testReminderClaimConcurrent)
+}
+
+func testReminderClaimConcurrent(t *testing.T, config DBConfig, db *sql.DB) {
+	ctx := context.Background()
+	now := time.Now()
+
+	exec(t, db, `INSERT INTO tasks (id, title, subject, due_date) VALUES ('task-1', 'Report', 'student', '2026-03-10')`)
+	exec(t, db, `INSERT INTO tasks (id, title, subject, done) VALUES ('task-done', 'Done', 'student', TRUE)`)
+	const total = 20
+	for i := 0; i < total; i++ {
+		exec(t, db, `INSERT INTO task_reminders (id, task_id, minutes_before, channel, fire_at) VALUES ($1, 'task-1', 60, 'log', $2)`,
+			fmt.Sprintf("r%02d", i), now.Add(-time.Duration(i)*time.Minute))
+	}
+	exec(t, db, `INSERT INTO task_reminders (id, task_id, minutes_before, channel, fire_at) VALUES ('future', 'task-1', 60, 'log', $1)`, now.Add(time.Hour))
+	exec(t, db, `INSERT INTO task_reminders (id, task_id, minutes_before, channel, fire_at) VALUES ('of-done', 'task-done', 60, 'log', $1)`, now)
+
+	// Две реплики разбирают очередь одновременно; каждое напоминание
+	// должно достаться ровно одной
+	var mu sync.Mutex
+	claimed := make(map[string]string)
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, instance := range []string{"worker-1", "worker-2"} {
+		store, err := NewReminderStore(config)
+		if err != nil {
+			t.Fatalf("Failed to create reminder store: %v", err)
+		}
+		defer store.Close()
+
+		wg.Add(1)
+		go func(instance string, store *ReminderStore) {
+			defer wg.Done()
+			for {
+				reminder, ok, err := store.ClaimNext(ctx, instance, time.Minute, 5)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !ok {
+					return
+				}
+
+				mu.Lock()
+				if other, dup := claimed[reminder.ID]; dup {
+					errs <- fmt.Errorf("reminder %s claimed by %s and %s", reminder.ID, other, instance)
+				}
+				claimed[reminder.ID] = instance
+				mu.Unlock()
+
+				if reminder.Title != "Report" || reminder.Subject != "student" || reminder.DueDate != "2026-03-10" || reminder.Attempt != 1 {
+					errs <- fmt.Errorf("unexpected claimed reminder %+v", reminder)
+					return
+				}
+				if fired, err := store.MarkFired(ctx, reminder.ID, instance); err != nil || !fired {
+					errs <- fmt.Errorf("failed to mark %s fired: %v", reminder.ID, err)
+					return
+				}
+			}
+		}(instance, store)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if len(claimed) != total {
+		t.Errorf("Expected %d due reminders claimed once, got %d", total, len(claimed))
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM task_reminders WHERE fired_at IS NULL`); n != 2 {
+		t.Errorf("Expected future reminder and reminder of done task to stay pending, got %d", n)
+	}
+}
+
+func TestReminderClaimLeaseExpires(t *testing.T) {
+	forEachDB(t, testReminderClaimLeaseExpires)
+}
+
+func testReminderClaimLeaseExpires(t *testing.T, config DBConfig, db *sql.DB) {
+	ctx := context.Background()
+	store, err := NewReminderStore(config)
+	if err != nil {
+		t.Fatalf("Failed to create reminder store: %v", err)
+	}
+	defer store.Close()
+
+	exec(t, db, `INSERT INTO tasks (id, title, subject) VALUES ('task-1', 'Report', 'student')`)
+	exec(t, db, `INSERT INTO task_reminders (id, task_id, minutes_before, channel, fire_at) VALUES ('r1', 'task-1', 0, 'log', $1)`, time.Now().Add(-time.Minute))
+
+	first, ok, err := store.ClaimNext(ctx, "worker-1", 50*time.Millisecond, 5)
+	if err != nil || !ok || first.ID != "r1" {
+		t.Fatalf("Expected reminder to be claimed, got %+v, %v, %v", first, ok, err)
+	}
+	if _, ok, _ := store.ClaimNext(ctx, "worker-2", time.Minute, 5); ok {
+		t.Fatal("Expected claimed reminder to be unavailable while lease is held")
+	}
+
+	// Захват, не подтвержденный до конца аренды, переходит к другой реплике
+	time.Sleep(100 * time.Millisecond)
+	second, ok, err := store.ClaimNext(ctx, "worker-2", time.Minute, 5)
+	if err != nil || !ok || second.ID != "r1" || second.Attempt != 2 {
+		t.Fatalf("Expected expired claim to be taken over, got %+v, %v, %v", second, ok, err)
+	}
+	if fired, _ := store.MarkFired(ctx, "r1", "worker-1"); fired {
+		t.Error("Expected stale claimer not to confirm reminder")
+	}
+	if fired, err := store.MarkFired(ctx, "r1", "worker-2"); err != nil || !fired {
+		t.Errorf("Expected current claimer to confirm reminder: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSchema – часть схемы миграций tasks сервиса, с которой работает worker
+const testSchema = `
+CREATE TABLE tasks (
+    id VARCHAR(50) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    due_date DATE,
+    due_at TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+CREATE TABLE task_revisions (
+    task_id VARCHAR(50) NOT NULL,
+    revision INTEGER NOT NULL,
+    PRIMARY KEY (task_id, revision)
+);
+CREATE TABLE shares (
+    resource_type VARCHAR(20) NOT NULL,
+    resource_id VARCHAR(50) NOT NULL,
+    grantee VARCHAR(100) NOT NULL,
+    PRIMARY KEY (resource_type, resource_id, grantee)
+);
+CREATE TABLE task_attachments (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL
+);
+CREATE TABLE task_reminders (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    minutes_before INTEGER NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    fire_at TIMESTAMP,
+    fired_at TIMESTAMP,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(255)
+);
+CREATE TABLE task_notifications (
+    id VARCHAR(50) PRIMARY KEY,
+    task_id VARCHAR(50) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    recipient VARCHAR(100) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    comment_id VARCHAR(50),
+    sent_at TIMESTAMP,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error VARCHAR(255),
+    created_at TIMESTAMP
+);
+`
+
+// forEachDB выполняет тест на файле SQLite и, если задана переменная
+// WORKER_TEST_POSTGRES_DSN (формат key=value), на отдельной схеме PostgreSQL.
+// db – подключение для подготовки данных.
+func forEachDB(t *testing.T, fn func(t *testing.T, config DBConfig, db *sql.DB)) {
+	t.Run(DriverSQLite, func(t *testing.T) {
+		config := DBConfig{Driver: DriverSQLite, DSN: filepath.Join(t.TempDir(), "tasks.db")}
+		fn(t, config, openTestDB(t, config, testSchema))
+	})
+
+	t.Run(DriverPostgres, func(t *testing.T) {
+		dsn := os.Getenv("WORKER_TEST_POSTGRES_DSN")
+		if dsn == "" {
+			t.Skip("Skipping PostgreSQL storage test - WORKER_TEST_POSTGRES_DSN not set")
+		}
+
+		admin, _, err := openDB(DBConfig{Driver: DriverPostgres, DSN: dsn})
+		if err != nil {
+			t.Fatalf("Failed to connect to PostgreSQL: %v", err)
+		}
+		schema := fmt.Sprintf("worker_test_%d", time.Now().UnixNano())
+		if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+			admin.Close()
+			t.Fatalf("Failed to create schema: %v", err)
+		}
+		t.Cleanup(func() {
+			admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+			admin.Close()
+		})
+
+		config := DBConfig{Driver: DriverPostgres, DSN: dsn + " search_path=" + schema}
+		fn(t, config, openTestDB(t, config, strings.ReplaceAll(testSchema, "TIMESTAMP", "TIMESTAMP WITH TIME ZONE")))
+	})
+}
+
+func openTestDB(t *testing.T, config DBConfig, schema string) *sql.DB {
+	t.Helper()
+
+	db, _, err := openDB(config)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	return db
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("Failed to exec %q: %v", query, err)
+	}
+}
+
+func count(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("Failed to query %q: %v", query, err)
+	}
+	return n
+}
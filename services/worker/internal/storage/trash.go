@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tech-ip-sem2/services/worker/internal/models"
+)
+
+// TrashStore окончательно удаляет задачи из корзины tasks сервиса.
+// Схему (колонку deleted_at) создают миграции tasks сервиса.
+type TrashStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func NewTrashStore(config DBConfig) (*TrashStore, error) {
+	db, d, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrashStore{db: db, dialect: d}, nil
+}
+
+// PurgeDeleted удаляет задачи, помещенные в корзину раньше before, вместе
+// с их историей изменений, доступами и вложениями, и возвращает ключи файлов
+// вложений. Остальные данные задачи удаляются каскадно.
+// DELETE ... RETURNING атомарен, поэтому несколько worker'ов не удалят одну
+// задачу дважды.
+func (s *TrashStore) PurgeDeleted(ctx context.Context, before time.Time) ([]models.PurgedTask, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin purge: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Вложения удаляются до задач: каскадное удаление не вернуло бы ключи файлов
+	keys, err := s.purgeAttachments(ctx, tx, before)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        DELETE FROM tasks
+        WHERE deleted_at IS NOT NULL AND ` + s.dialect.instant("deleted_at") + ` < ` + s.dialect.instant("$1") + `
+        RETURNING id, title, subject
+    `
+
+	rows, err := tx.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge tasks: %w", err)
+	}
+
+	var purged []models.PurgedTask
+	var ids []string
+	for rows.Next() {
+		var task models.PurgedTask
+		if err := rows.Scan(&task.ID, &task.Title, &task.Subject); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan purged task: %w", err)
+		}
+		task.AttachmentKeys = keys[task.ID]
+		purged = append(purged, task)
+		ids = append(ids, task.ID)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate purged tasks: %w", err)
+	}
+
+	// История и доступы ссылаются на задачу без внешнего ключа
+	if len(ids) > 0 {
+		condition, list := s.dialect.inList("task_id", 1, ids)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM task_revisions WHERE `+condition, list); err != nil {
+			return nil, fmt.Errorf("failed to purge task revisions: %w", err)
+		}
+
+		condition, list = s.dialect.inList("resource_id", 1, ids)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM shares WHERE resource_type = 'task' AND `+condition, list); err != nil {
+			return nil, fmt.Errorf("failed to purge task shares: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purge: %w", err)
+	}
+
+	return purged, nil
+}
+
+// purgeAttachments удаляет вложения задач из корзины и возвращает ключи их
+// файлов по задачам
+func (s *TrashStore) purgeAttachments(ctx context.Context, tx *sql.Tx, before time.Time) (map[string][]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+        DELETE FROM task_attachments
+        WHERE task_id IN (
+            SELECT id FROM tasks
+            WHERE deleted_at IS NOT NULL AND `+s.dialect.instant("deleted_at")+` < `+s.dialect.instant("$1")+`
+        )
+        RETURNING task_id, storage_key
+    `, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge attachments: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string][]string)
+	for rows.Next() {
+		var taskID, key string
+		if err := rows.Scan(&taskID, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan purged attachment: %w", err)
+		}
+		keys[taskID] = append(keys[taskID], key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate purged attachments: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *TrashStore) Close() error {
+	return s.db.Close()
+}
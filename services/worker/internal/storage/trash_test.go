@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestPurgeDeleted(t *testing.T) {
+	forEachDB(t, testPurgeDeleted)
+}
+
+func testPurgeDeleted(t *testing.T, config DBConfig, db *sql.DB) {
+	store, err := NewTrashStore(config)
+	if err != nil {
+		t.Fatalf("Failed to create trash store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now()
+	// Время в разных часовых поясах сравнивается как момент
+	old := now.Add(-48 * time.Hour).In(time.FixedZone("MSK", 3*60*60))
+	exec(t, db, `INSERT INTO tasks (id, title, subject, deleted_at) VALUES ('old', 'Old', 'student', $1)`, old)
+	// Строкой этот момент раньше границы очистки
+	recent := now.Add(-23 * time.Hour).In(time.FixedZone("BIT", -12*60*60))
+	exec(t, db, `INSERT INTO tasks (id, title, subject, deleted_at) VALUES ('recent', 'Recent', 'student', $1)`, recent)
+	exec(t, db, `INSERT INTO tasks (id, title, subject) VALUES ('active', 'Active', 'student')`)
+	for _, id := range []string{"old", "recent", "active"} {
+		exec(t, db, `INSERT INTO task_revisions (task_id, revision) VALUES ($1, 1)`, id)
+		exec(t, db, `INSERT INTO shares (resource_type, resource_id, grantee) VALUES ('task', $1, 'teacher')`, id)
+	}
+	exec(t, db, `INSERT INTO task_attachments (id, task_id, storage_key) VALUES ('a1', 'old', 'old/report.pdf')`)
+
+	purged, err := store.PurgeDeleted(context.Background(), now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to purge trash: %v", err)
+	}
+	if len(purged) != 1 || purged[0].ID != "old" || len(purged[0].AttachmentKeys) != 1 || purged[0].AttachmentKeys[0] != "old/report.pdf" {
+		t.Fatalf("Expected only old task purged with its attachment, got %+v", purged)
+	}
+
+	if n := count(t, db, `SELECT COUNT(*) FROM tasks`); n != 2 {
+		t.Errorf("Expected 2 tasks left, got %d", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM task_revisions WHERE task_id = 'old'`); n != 0 {
+		t.Errorf("Expected revisions of purged task to be deleted, got %d", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM shares WHERE resource_id = 'old'`); n != 0 {
+		t.Errorf("Expected shares of purged task to be deleted, got %d", n)
+	}
+	if n := count(t, db, `SELECT COUNT(*) FROM shares`); n != 2 {
+		t.Errorf("Expected shares of other tasks to stay, got %d", n)
+	}
+}
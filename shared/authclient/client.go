@@ -54,7 +54,14 @@ func (c *Client) Close() error {
 	return nil
 }
 
-func (c *Client) VerifyToken(ctx context.Context, token string) (bool, string, error) {
+// Identity – пользователь токена и его рабочее пространство; пустой Tenant
+// возвращает auth сервис без пространств
+type Identity struct {
+	Subject string
+	Tenant  string
+}
+
+func (c *Client) VerifyToken(ctx context.Context, token string) (bool, Identity, error) {
 	requestID := middleware.GetRequestID(ctx)
 	log := c.log.WithRequestID(requestID)
 
@@ -75,23 +82,24 @@ func (c *Client) VerifyToken(ctx context.Context, token string) (bool, string, e
 		if st, ok := status.FromError(err); ok {
 			switch st.Code() {
 			case codes.DeadlineExceeded:
-				return false, "", fmt.Errorf("auth service timeout")
+				return false, Identity{}, fmt.Errorf("auth service timeout")
 			case codes.Unavailable:
-				return false, "", fmt.Errorf("auth service unavailable")
+				return false, Identity{}, fmt.Errorf("auth service unavailable")
 			case codes.Unauthenticated:
 				log.Info("Token is invalid")
-				return false, "", nil
+				return false, Identity{}, nil
 			default:
-				return false, "", fmt.Errorf("auth service error: %v", st.Message())
+				return false, Identity{}, fmt.Errorf("auth service error: %v", st.Message())
 			}
 		}
-		return false, "", fmt.Errorf("failed to verify token: %w", err)
+		return false, Identity{}, fmt.Errorf("failed to verify token: %w", err)
 	}
 
 	log.Info("gRPC verify success",
 		zap.Bool("valid", resp.Valid),
 		zap.String("subject", resp.Subject),
+		zap.String("tenant", resp.Tenant),
 	)
 
-	return resp.Valid, resp.Subject, nil
+	return resp.Valid, Identity{Subject: resp.Subject, Tenant: resp.Tenant}, nil
 }
@@ -0,0 +1,78 @@
+// Package blob хранит файлы вложений задач. Tasks сервис загружает и отдает
+// файлы, worker удаляет их при очистке корзины, поэтому хранилище общее:
+// каталог на диске или S3-совместимый bucket.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound – в хранилище нет объекта с таким ключом
+var ErrNotFound = errors.New("blob not found")
+
+// Store – хранилище файлов по ключу. Put читает содержимое потоком, size –
+// точный размер в байтах. Delete несуществующего ключа не ошибка.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config выбирает и настраивает хранилище
+type Config struct {
+	Backend   string // local или s3
+	Dir       string // каталог для local
+	Endpoint  string // адрес S3 API, например http://minio:9000
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// ConfigFromEnv читает настройки хранилища из окружения:
+// BLOB_STORE, BLOB_DIR и S3_* для s3
+func ConfigFromEnv() Config {
+	config := Config{
+		Backend:   os.Getenv("BLOB_STORE"),
+		Dir:       os.Getenv("BLOB_DIR"),
+		Endpoint:  os.Getenv("S3_ENDPOINT"),
+		Bucket:    os.Getenv("S3_BUCKET"),
+		Region:    os.Getenv("S3_REGION"),
+		AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_SECRET_KEY"),
+	}
+	if config.Backend == "" {
+		config.Backend = "local"
+	}
+	if config.Dir == "" {
+		config.Dir = "attachments"
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	return config
+}
+
+// New создает хранилище по настройкам
+func New(config Config) (Store, error) {
+	switch config.Backend {
+	case "local":
+		store, err := NewLocalStore(config.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "s3":
+		store, err := NewS3Store(config)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q, expected local or s3", config.Backend)
+	}
+}
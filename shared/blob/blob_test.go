@@ -0,0 +1,127 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testStore проверяет контракт Store на любой реализации
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	key := "tasks/t1/report 1.txt"
+
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "hello" {
+		t.Errorf("Expected stored content, got %q", data)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Expected delete of missing key to succeed, got %v", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	testStore(t, store)
+
+	// Недописанный файл не сохраняется
+	ctx := context.Background()
+	if err := store.Put(ctx, "short", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Error("Expected size mismatch to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "short")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file after failed put, got %v", err)
+	}
+
+	for _, key := range []string{"", "../escape", "tasks/../../escape", "/"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
+
+// fakeS3 – bucket в памяти с проверкой подписи запросов
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/") || !strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.EscapedPath()] = string(data)
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(Config{Endpoint: server.URL + "/", Bucket: "files", Region: "us-east-1", AccessKey: "key", SecretKey: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	testStore(t, store)
+
+	// Ключ кодируется по правилам SigV4
+	if err := store.Put(context.Background(), "a b+c", strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok := fake.objects["/files/a%20b%2Bc"]; !ok {
+		t.Errorf("Expected escaped object path, got %v", fake.objects)
+	}
+
+	if _, err := NewS3Store(Config{Endpoint: server.URL}); err == nil {
+		t.Error("Expected missing bucket to be rejected")
+	}
+	if _, err := New(Config{Backend: "ftp"}); err == nil {
+		t.Error("Expected unknown backend to be rejected")
+	}
+}
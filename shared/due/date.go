@@ -0,0 +1,234 @@
+// Package due описывает срок задачи: календарную дату (на весь день) или
+// момент времени. Срок сериализуется одинаково в REST, GraphQL и событиях:
+// дата – full-date, момент – date-time в UTC (RFC 3339).
+package due
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Форматы времени срока без смещения: часовой пояс задает пользователь
+const (
+	localMinutes = "2006-01-02T15:04"
+	localSeconds = "2006-01-02T15:04:05"
+)
+
+var ErrInvalid = errors.New("due_date must be YYYY-MM-DD, RFC 3339 date-time or YYYY-MM-DDTHH:MM")
+
+// Date – срок задачи. Нулевое значение – срока нет.
+type Date struct {
+	day time.Time // календарный день срока, 00:00 UTC
+	at  time.Time // момент срока; нулевой – срок на весь день
+	// Время без смещения: момент вычисляет In в часовом поясе пользователя
+	floating bool
+}
+
+// Day возвращает срок на весь день
+func Day(year int, month time.Month, day int) Date {
+	return Date{day: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// At возвращает срок-момент; день срока считается в часовом поясе loc
+func At(t time.Time, loc *time.Location) Date {
+	return Date{day: dateOf(t.In(loc)), at: t.UTC()}
+}
+
+// Parse разбирает срок: YYYY-MM-DD, RFC 3339 date-time или время без
+// смещения (YYYY-MM-DDTHH:MM[:SS]), которое затем уточняет In. Пустая
+// строка – срока нет.
+func Parse(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return Date{day: t}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return At(t, time.UTC), nil
+	}
+	for _, layout := range []string{localMinutes, localSeconds} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Date{day: dateOf(t), at: t, floating: true}, nil
+		}
+	}
+	return Date{}, ErrInvalid
+}
+
+// FromColumns собирает срок из колонок due_date (DATE) и due_at. База
+// возвращает DATE как 2026-03-02 или 2026-03-02T00:00:00Z.
+func FromColumns(day string, at *time.Time) Date {
+	if at != nil {
+		return Date{day: dateOf(dayOnly(day, *at)), at: at.UTC()}
+	}
+	if len(day) > len(time.DateOnly) {
+		day = day[:len(time.DateOnly)]
+	}
+	t, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return Date{}
+	}
+	return Date{day: t}
+}
+
+func dayOnly(day string, at time.Time) time.Time {
+	if len(day) >= len(time.DateOnly) {
+		if t, err := time.Parse(time.DateOnly, day[:len(time.DateOnly)]); err == nil {
+			return t
+		}
+	}
+	return at
+}
+
+// In привязывает срок к часовому поясу пользователя: время без смещения
+// становится моментом в loc, день срока-момента считается в loc
+func (d Date) In(loc *time.Location) Date {
+	if d.at.IsZero() {
+		return d
+	}
+	at := d.at
+	if d.floating {
+		at = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, loc)
+	}
+	return At(at, loc)
+}
+
+// OnDay переносит срок на другой день с тем же временем суток в loc
+func (d Date) OnDay(day time.Time, loc *time.Location) Date {
+	if d.at.IsZero() {
+		return Day(day.Year(), day.Month(), day.Day())
+	}
+	local := d.at.In(loc)
+	return At(time.Date(day.Year(), day.Month(), day.Day(), local.Hour(), local.Minute(), local.Second(), 0, loc), loc)
+}
+
+func (d Date) IsZero() bool { return d.day.IsZero() }
+
+// HasTime – срок задан с точностью до времени
+func (d Date) HasTime() bool { return !d.at.IsZero() }
+
+// Date возвращает календарный день срока (00:00 UTC)
+func (d Date) Date() time.Time { return d.day }
+
+func (d Date) Equal(other Date) bool {
+	return d.day.Equal(other.day) && d.at.Equal(other.at) && d.floating == other.floating
+}
+
+// Start – начало срока: момент или начало дня в loc
+func (d Date) Start(loc *time.Location) time.Time {
+	if !d.at.IsZero() {
+		return d.at
+	}
+	return time.Date(d.day.Year(), d.day.Month(), d.day.Day(), 0, 0, 0, 0, loc)
+}
+
+// Deadline – момент, после которого задача просрочена: момент срока или
+// конец дня в loc
+func (d Date) Deadline(loc *time.Location) time.Time {
+	if !d.at.IsZero() {
+		return d.at
+	}
+	return time.Date(d.day.Year(), d.day.Month(), d.day.Day()+1, 0, 0, 0, 0, loc)
+}
+
+func (d Date) Overdue(now time.Time, loc *time.Location) bool {
+	return !d.IsZero() && !now.Before(d.Deadline(loc))
+}
+
+// DueToday – срок приходится на сегодняшний день в loc
+func (d Date) DueToday(now time.Time, loc *time.Location) bool {
+	if d.IsZero() {
+		return false
+	}
+	day := d.day
+	if !d.at.IsZero() {
+		day = dateOf(d.at.In(loc))
+	}
+	return day.Equal(dateOf(now.In(loc)))
+}
+
+// String возвращает срок в RFC 3339: 2026-03-10 или 2026-03-10T15:00:00Z
+func (d Date) String() string {
+	switch {
+	case d.IsZero():
+		return ""
+	case d.floating:
+		return d.at.Format(localSeconds)
+	case !d.at.IsZero():
+		return d.at.UTC().Format(time.RFC3339)
+	default:
+		return d.day.Format(time.DateOnly)
+	}
+}
+
+// DayValue и TimeValue – значения колонок due_date и due_at
+func (d Date) DayValue() any {
+	if d.IsZero() {
+		return nil
+	}
+	return d.day.Format(time.DateOnly)
+}
+
+func (d Date) TimeValue() any {
+	if d.at.IsZero() {
+		return nil
+	}
+	return d.at.UTC()
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ErrInvalid
+	}
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalGQL и UnmarshalGQL реализуют скаляр DueDate схемы GraphQL
+func (d Date) MarshalGQL(w io.Writer) {
+	if d.IsZero() {
+		io.WriteString(w, "null")
+		return
+	}
+	io.WriteString(w, strconv.Quote(d.String()))
+}
+
+func (d *Date) UnmarshalGQL(v any) error {
+	value, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("DueDate must be a string")
+	}
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,122 @@
+package due
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		hasTime bool
+	}{
+		{"", "", false},
+		{"2026-03-10", "2026-03-10", false},
+		{" 2026-03-10 ", "2026-03-10", false},
+		{"2026-03-10T15:00:00Z", "2026-03-10T15:00:00Z", true},
+		{"2026-03-10T18:00:00+03:00", "2026-03-10T15:00:00Z", true},
+		{"2026-03-10T18:00", "2026-03-10T18:00:00", true},
+		{"2026-03-10T18:00:30", "2026-03-10T18:00:30", true},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.value)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if d.String() != tt.want || d.HasTime() != tt.hasTime {
+			t.Errorf("Parse(%q) = %s (time %v), want %s (time %v)", tt.value, d, d.HasTime(), tt.want, tt.hasTime)
+		}
+	}
+
+	for _, value := range []string{"10.03.2026", "2026-02-30", "tomorrow", "2026-03-10 18:00"} {
+		if _, err := Parse(value); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestInAndDeadline(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// Время без смещения – момент в часовом поясе пользователя
+	d, _ := Parse("2026-03-10T01:30")
+	d = d.In(moscow)
+	if d.String() != "2026-03-09T22:30:00Z" || !d.Date().Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected floating due date in Moscow: %s, day %s", d, d.Date())
+	}
+
+	// Срок на весь день заканчивается в полночь пользователя
+	day := Day(2026, 3, 10)
+	if got := day.Deadline(moscow); !got.Equal(time.Date(2026, 3, 10, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected all-day deadline %s", got)
+	}
+	if day.Overdue(time.Date(2026, 3, 10, 20, 59, 0, 0, time.UTC), moscow) {
+		t.Error("Expected task not to be overdue before midnight in Moscow")
+	}
+	if !day.Overdue(time.Date(2026, 3, 10, 21, 0, 0, 0, time.UTC), moscow) {
+		t.Error("Expected task to be overdue at midnight in Moscow")
+	}
+	if !day.DueToday(time.Date(2026, 3, 9, 22, 0, 0, 0, time.UTC), moscow) {
+		t.Error("Expected due today after midnight in Moscow")
+	}
+	if (Date{}).Overdue(time.Now(), moscow) || (Date{}).DueToday(time.Now(), moscow) {
+		t.Error("Expected empty due date to be neither overdue nor due today")
+	}
+
+	// Перенос на другой день сохраняет время суток в loc
+	moved := d.OnDay(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), moscow)
+	if moved.String() != "2026-03-31T22:30:00Z" {
+		t.Errorf("Unexpected moved due date %s", moved)
+	}
+	if moved := day.OnDay(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), moscow); moved.String() != "2026-04-01" {
+		t.Errorf("Unexpected moved all-day due date %s", moved)
+	}
+}
+
+func TestFromColumns(t *testing.T) {
+	if d := FromColumns("2026-03-10T00:00:00Z", nil); !d.Equal(Day(2026, 3, 10)) {
+		t.Errorf("Unexpected all-day due date %s", d)
+	}
+	if d := FromColumns("", nil); !d.IsZero() {
+		t.Errorf("Expected empty due date, got %s", d)
+	}
+
+	// День срока-момента берется из due_date, а не из UTC-момента
+	at := time.Date(2026, 3, 9, 22, 30, 0, 0, time.UTC)
+	d := FromColumns("2026-03-10", &at)
+	if d.String() != "2026-03-09T22:30:00Z" || d.Date().Day() != 10 {
+		t.Errorf("Unexpected due date %s, day %s", d, d.Date())
+	}
+	if d.DayValue() != "2026-03-10" || d.TimeValue() != at {
+		t.Errorf("Unexpected column values %v, %v", d.DayValue(), d.TimeValue())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var value struct {
+		Due Date `json:"due"`
+	}
+	if err := json.Unmarshal([]byte(`{"due": "2026-03-10T15:00:00Z"}`), &value); err != nil || value.Due.String() != "2026-03-10T15:00:00Z" {
+		t.Fatalf("Unexpected due date %s, %v", value.Due, err)
+	}
+	data, _ := json.Marshal(value)
+	if string(data) != `{"due":"2026-03-10T15:00:00Z"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"due": null}`), &value); err != nil || !value.Due.IsZero() {
+		t.Errorf("Expected null to clear due date, got %s, %v", value.Due, err)
+	}
+	data, _ = json.Marshal(value)
+	if string(data) != `{"due":null}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"due": 20260310}`), &value); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid for number, got %v", err)
+	}
+}
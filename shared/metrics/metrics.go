@@ -124,5 +124,10 @@ func normalizeRoute(path string) string {
 		return "/v1/tasks"
 	}
 
+	// Токен календаря – секрет и не должен попадать в метки
+	if strings.HasPrefix(path, "/v1/feeds/") {
+		return "/v1/feeds/:token"
+	}
+
 	return path
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -48,7 +49,7 @@ func AccessLog(log *logger.Logger) func(http.Handler) http.Handler {
 			if requestID != "" {
 				log.WithRequestID(requestID).Debug("request started",
 					zap.String("method", r.Method),
-					zap.String("path", r.URL.Path),
+					zap.String("path", logPath(r.URL.Path)),
 				)
 			}
 
@@ -63,7 +64,7 @@ func AccessLog(log *logger.Logger) func(http.Handler) http.Handler {
 
 			loggerWithID.Info("request completed",
 				zap.String("method", r.Method),
-				zap.String("path", r.URL.Path),
+				zap.String("path", logPath(r.URL.Path)),
 				zap.Int("status", rw.status),
 				zap.Float64("duration_ms", float64(duration.Milliseconds())),
 				zap.String("remote_ip", r.RemoteAddr),
@@ -72,3 +73,11 @@ func AccessLog(log *logger.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// logPath скрывает секретный токен ссылки на календарь задач
+func logPath(path string) string {
+	if strings.HasPrefix(path, "/v1/feeds/") {
+		return "/v1/feeds/***"
+	}
+	return path
+}
@@ -0,0 +1,151 @@
+package taskio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// Колонки CSV; при импорте обязательна только title, порядок любой,
+// неизвестные колонки пропускаются. Метки разделяются запятой.
+var csvColumns = []string{
+	"id", "title", "description", "due_date", "done", "status", "priority",
+	"tags", "estimate_points", "estimate_minutes",
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		err := writer.Write([]string{
+			r.ID,
+			r.Title,
+			r.Description,
+			r.DueDate.String(),
+			strconv.FormatBool(r.Done),
+			r.Status,
+			r.Priority,
+			strings.Join(r.Tags, ","),
+			formatEstimate(r.EstimatePoints),
+			formatEstimate(r.EstimateMinutes),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatEstimate(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+func readCSV(r io.Reader, limit int) ([]Item, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV header must contain a title column")
+	}
+
+	var items []Item
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(items) == limit {
+			return nil, ErrTooManyRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		item := Item{Line: line}
+		item.Record, item.Err = csvRecord(field)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func csvRecord(field func(name string) string) (Record, error) {
+	record := Record{
+		ID:          field("id"),
+		Title:       field("title"),
+		Description: field("description"),
+		Status:      field("status"),
+		Priority:    field("priority"),
+	}
+
+	var err error
+	if record.DueDate, err = due.Parse(field("due_date")); err != nil {
+		return Record{}, err
+	}
+	if record.Done, err = parseBool(field("done")); err != nil {
+		return Record{}, err
+	}
+	if record.EstimatePoints, err = parseInt("estimate_points", field("estimate_points")); err != nil {
+		return Record{}, err
+	}
+	if record.EstimateMinutes, err = parseInt("estimate_minutes", field("estimate_minutes")); err != nil {
+		return Record{}, err
+	}
+	if tags := field("tags"); tags != "" {
+		record.Tags = strings.Split(tags, ",")
+	}
+	return record, nil
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "false", "0", "no":
+		return false, nil
+	case "true", "1", "yes", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("done must be true or false, got %q", value)
+}
+
+func parseInt(name string, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer, got %q", name, value)
+	}
+	return n, nil
+}
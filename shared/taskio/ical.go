@@ -0,0 +1,430 @@
+package taskio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tech-ip-sem2/shared/due"
+)
+
+// Форматы дат iCalendar (RFC 5545)
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+)
+
+// Статус задачи сохраняется в X-TASKS-STATUS: в STATUS VTODO нет review
+const statusProperty = "X-TASKS-STATUS"
+
+// Calendar – параметры календаря WriteCalendar
+type Calendar struct {
+	Name string
+	// Задачи записываются событиями VEVENT в срок задачи вместо VTODO;
+	// задачи без срока пропускаются
+	Events bool
+	// Период обновления подписки приложением календаря; 0 – не указывается
+	Refresh time.Duration
+}
+
+// Длительность события задачи со сроком-временем без оценки
+const defaultEventDuration = 30 * time.Minute
+
+// WriteCalendar записывает задачи календарем с компонентами VTODO или VEVENT
+func WriteCalendar(w io.Writer, cal Calendar, records []Record) error {
+	out := &icalWriter{w: bufio.NewWriter(w)}
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:-//tech-ip-sem2//tasks//EN")
+	out.line("CALSCALE:GREGORIAN")
+	out.property("X-WR-CALNAME", escapeText(cal.Name))
+	if cal.Refresh > 0 {
+		refresh := "PT" + strconv.Itoa(int(cal.Refresh.Minutes())) + "M"
+		out.property("REFRESH-INTERVAL;VALUE=DURATION", refresh)
+		out.property("X-PUBLISHED-TTL", refresh)
+	}
+
+	// DTSTAMP календаря без METHOD – время изменения задачи (RFC 5545)
+	now := time.Now()
+	for _, r := range records {
+		if cal.Events && r.DueDate.IsZero() {
+			continue
+		}
+		component := "VTODO"
+		if cal.Events {
+			component = "VEVENT"
+		}
+
+		stamp := r.UpdatedAt
+		if stamp.IsZero() {
+			stamp = now
+		}
+		out.line("BEGIN:" + component)
+		out.property("UID", escapeText(r.ID))
+		out.property("DTSTAMP", stamp.UTC().Format(icalDateTime)+"Z")
+		if !r.UpdatedAt.IsZero() {
+			out.property("LAST-MODIFIED", r.UpdatedAt.UTC().Format(icalDateTime)+"Z")
+		}
+		out.property("SUMMARY", escapeText(r.Title))
+		if r.Description != "" {
+			out.property("DESCRIPTION", escapeText(r.Description))
+		}
+		if cal.Events {
+			out.eventTime(r)
+		} else {
+			switch {
+			case r.DueDate.HasTime():
+				out.property("DUE", r.DueDate.Start(time.UTC).Format(icalDateTime)+"Z")
+			case !r.DueDate.IsZero():
+				out.property("DUE;VALUE=DATE", r.DueDate.Date().Format(icalDate))
+			}
+			out.property("STATUS", icalStatus(r))
+		}
+		if r.Status != "" {
+			out.property(statusProperty, r.Status)
+		}
+		if priority := icalPriority(r.Priority); priority != 0 {
+			out.property("PRIORITY", strconv.Itoa(priority))
+		}
+		if len(r.Tags) > 0 {
+			tags := make([]string, 0, len(r.Tags))
+			for _, tag := range r.Tags {
+				tags = append(tags, escapeText(tag))
+			}
+			out.property("CATEGORIES", strings.Join(tags, ","))
+		}
+		out.line("END:" + component)
+	}
+	out.line("END:VCALENDAR")
+
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (w *icalWriter) property(name string, value string) {
+	w.line(name + ":" + value)
+}
+
+// line пишет строку, перенося ее по 75 октетов без разрыва символов UTF-8
+func (w *icalWriter) line(s string) {
+	if w.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, w.err = w.w.WriteString(s[:cut] + "\r\n "); w.err != nil {
+			return
+		}
+		s = s[cut:]
+		// Строка продолжения начинается с пробела
+		limit = 74
+	}
+	_, w.err = w.w.WriteString(s + "\r\n")
+}
+
+// eventTime записывает время события задачи: весь день срока или интервал,
+// который заканчивается в срок и длится оценку времени задачи
+func (w *icalWriter) eventTime(r Record) {
+	if !r.DueDate.HasTime() {
+		day := r.DueDate.Date()
+		w.property("DTSTART;VALUE=DATE", day.Format(icalDate))
+		w.property("DTEND;VALUE=DATE", day.AddDate(0, 0, 1).Format(icalDate))
+		return
+	}
+
+	duration := time.Duration(r.EstimateMinutes) * time.Minute
+	if duration <= 0 {
+		duration = defaultEventDuration
+	}
+	end := r.DueDate.Start(time.UTC)
+	w.property("DTSTART", end.Add(-duration).Format(icalDateTime)+"Z")
+	w.property("DTEND", end.Format(icalDateTime)+"Z")
+}
+
+func icalStatus(r Record) string {
+	switch {
+	case r.Done:
+		return "COMPLETED"
+	case r.Status == "in_progress" || r.Status == "review":
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// Приоритет iCalendar: 1 – наивысший, 9 – низший, 0 – не задан
+func icalPriority(priority string) int {
+	switch priority {
+	case "urgent":
+		return 1
+	case "high":
+		return 3
+	case "medium":
+		return 5
+	case "low":
+		return 7
+	}
+	return 0
+}
+
+func priorityFromICal(value int) string {
+	switch {
+	case value <= 0:
+		return ""
+	case value == 1:
+		return "urgent"
+	case value <= 4:
+		return "high"
+	case value == 5:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitText делит список значений по запятым, не экранированным "\"
+func splitText(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescapeText(s[start:]))
+}
+
+// icalLine – строка содержимого после снятия переноса
+type icalLine struct {
+	number int
+	name   string
+	params map[string]string
+	value  string
+}
+
+func readCalendar(r io.Reader, limit int) ([]Item, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	var current *Item
+	// Вложенные компоненты задачи (VALARM) пропускаются
+	nested := 0
+	for _, raw := range lines {
+		line, ok := parseLine(raw.text)
+		if !ok {
+			continue
+		}
+		line.number = raw.number
+
+		switch {
+		case line.name == "BEGIN" && strings.EqualFold(line.value, "VTODO"):
+			if len(items) == limit {
+				return nil, ErrTooManyRows
+			}
+			current = &Item{Line: line.number}
+			nested = 0
+		case line.name == "END" && strings.EqualFold(line.value, "VTODO"):
+			if current != nil {
+				items = append(items, *current)
+				current = nil
+			}
+		case current != nil && line.name == "BEGIN":
+			nested++
+		case current != nil && line.name == "END":
+			nested--
+		case current != nil && nested == 0 && current.Err == nil:
+			current.Err = applyProperty(&current.Record, line)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("calendar contains no VTODO components")
+	}
+	return items, nil
+}
+
+type rawLine struct {
+	number int
+	text   string
+}
+
+// unfoldLines соединяет строки, перенесенные с пробелом или табуляцией в начале
+func unfoldLines(r io.Reader) ([]rawLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []rawLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		if text != "" {
+			lines = append(lines, rawLine{number: number, text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine разбирает NAME;PARAM=VALUE:value; двоеточие в кавычках
+// параметра не отделяет значение
+func parseLine(text string) (icalLine, bool) {
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(text) && colon < 0; i++ {
+		switch text[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return icalLine{}, false
+	}
+
+	parts := strings.Split(text[:colon], ";")
+	line := icalLine{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  text[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		line.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return line, true
+}
+
+func applyProperty(r *Record, line icalLine) error {
+	switch line.name {
+	case "UID":
+		r.ID = unescapeText(line.value)
+	case "SUMMARY":
+		r.Title = unescapeText(line.value)
+	case "DESCRIPTION":
+		r.Description = unescapeText(line.value)
+	case "DUE":
+		date, err := parseICalDue(line)
+		if err != nil {
+			return err
+		}
+		r.DueDate = date
+	case "STATUS":
+		switch strings.ToUpper(line.value) {
+		case "COMPLETED":
+			r.Done = true
+		case "IN-PROCESS":
+			if r.Status == "" {
+				r.Status = "in_progress"
+			}
+		}
+	case "COMPLETED":
+		r.Done = true
+	case statusProperty:
+		r.Status = line.value
+	case "PRIORITY":
+		value, err := strconv.Atoi(strings.TrimSpace(line.value))
+		if err != nil {
+			return fmt.Errorf("PRIORITY must be an integer, got %q", line.value)
+		}
+		r.Priority = priorityFromICal(value)
+	case "CATEGORIES":
+		r.Tags = append(r.Tags, splitText(line.value)...)
+	}
+	return nil
+}
+
+// parseICalDue разбирает DUE: дату, момент в UTC, время в часовом поясе
+// TZID или время без пояса
+func parseICalDue(line icalLine) (due.Date, error) {
+	value := strings.TrimSpace(line.value)
+	if line.params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		t, err := time.Parse(icalDate, value)
+		if err != nil {
+			return due.Date{}, fmt.Errorf("invalid DUE date %q", value)
+		}
+		return due.Day(t.Year(), t.Month(), t.Day()), nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalDateTime, strings.TrimSuffix(value, "Z"))
+		if err != nil {
+			return due.Date{}, fmt.Errorf("invalid DUE date-time %q", value)
+		}
+		return due.At(t, time.UTC), nil
+	}
+
+	if tzid := line.params["TZID"]; tzid != "" {
+		loc, err := time.LoadLocation(tzid)
+		if err != nil {
+			return due.Date{}, fmt.Errorf("unknown DUE time zone %q", tzid)
+		}
+		t, err := time.ParseInLocation(icalDateTime, value, loc)
+		if err != nil {
+			return due.Date{}, fmt.Errorf("invalid DUE date-time %q", value)
+		}
+		return due.At(t, loc), nil
+	}
+
+	t, err := time.Parse(icalDateTime, value)
+	if err != nil {
+		return due.Date{}, fmt.Errorf("invalid DUE date-time %q", value)
+	}
+	return due.Parse(t.Format("2006-01-02T15:04:05"))
+}
@@ -0,0 +1,81 @@
+package taskio
+
+import "sort"
+
+// Результаты строк импорта; valid – строка прошла проверку при dry_run
+const (
+	RowCreated = "created"
+	RowSkipped = "skipped"
+	RowFailed  = "failed"
+	RowValid   = "valid"
+)
+
+// RowResult – результат импорта одной строки файла
+type RowResult struct {
+	Line   int    `json:"line"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"`
+	TaskID string `json:"task_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report – отчет об импорте; Created при dry_run – число задач, которые
+// были бы созданы
+type Report struct {
+	Total   int         `json:"total"`
+	Created int         `json:"created"`
+	Skipped int         `json:"skipped"`
+	Failed  int         `json:"failed"`
+	Rows    []RowResult `json:"rows"`
+}
+
+func (r *Report) Add(row RowResult) {
+	switch row.Status {
+	case RowCreated, RowValid:
+		r.Created++
+	case RowSkipped:
+		r.Skipped++
+	case RowFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// Sort упорядочивает строки отчета по номеру строки файла
+func (r *Report) Sort() {
+	sort.SliceStable(r.Rows, func(i, j int) bool { return r.Rows[i].Line < r.Rows[j].Line })
+}
+
+// Queued – проверенная запись импорта, ожидающая создания worker'ом
+type Queued struct {
+	Line   int    `json:"line"`
+	Record Record `json:"record"`
+}
+
+// Dedup находит повторы: запись с id существующей задачи или с тем же
+// названием и сроком, что у существующей или уже принятой записи
+type Dedup struct {
+	ids  map[string]bool
+	keys map[string]bool
+}
+
+func NewDedup() *Dedup {
+	return &Dedup{ids: map[string]bool{}, keys: map[string]bool{}}
+}
+
+// Add запоминает существующую задачу
+func (d *Dedup) Add(r Record) {
+	if r.ID != "" {
+		d.ids[r.ID] = true
+	}
+	d.keys[r.Key()] = true
+}
+
+// Seen проверяет запись и запоминает ее, если она новая
+func (d *Dedup) Seen(r Record) bool {
+	if (r.ID != "" && d.ids[r.ID]) || d.keys[r.Key()] {
+		return true
+	}
+	d.Add(r)
+	return false
+}
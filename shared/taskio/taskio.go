@@ -0,0 +1,192 @@
+// Package taskio переводит задачи в файлы обмена и обратно: JSON, CSV и
+// iCalendar (VTODO). Tasks сервис экспортирует и импортирует задачи, worker
+// выполняет большие импорты, поэтому форматы и проверка записей общие.
+package taskio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tech-ip-sem2/shared/due"
+	"tech-ip-sem2/shared/sanitize"
+)
+
+// Форматы файлов
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatICal = "ics"
+)
+
+// Статусы процесса по умолчанию: импортированные задачи создаются без проекта
+var Statuses = []string{"todo", "in_progress", "review", "done"}
+
+var Priorities = []string{"low", "medium", "high", "urgent"}
+
+var (
+	ErrUnknownFormat = errors.New("format must be json, csv or ics")
+	ErrTooManyRows   = errors.New("too many rows in file")
+)
+
+// Record – задача в файле обмена. ID при импорте не сохраняется и служит
+// только для поиска повторов.
+type Record struct {
+	ID              string    `json:"id,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description,omitempty"`
+	DueDate         due.Date  `json:"due_date"`
+	Done            bool      `json:"done"`
+	Status          string    `json:"status,omitempty"`
+	Priority        string    `json:"priority,omitempty"`
+	Tags            []string  `json:"tags,omitempty"`
+	EstimatePoints  int       `json:"estimate_points,omitempty"`
+	EstimateMinutes int       `json:"estimate_minutes,omitempty"`
+	UpdatedAt       time.Time `json:"-"`
+}
+
+// Item – прочитанная запись; Line – строка файла, для JSON – номер элемента.
+// Err – запись не удалось разобрать.
+type Item struct {
+	Line   int
+	Record Record
+	Err    error
+}
+
+// Normalize очищает поля записи так же, как tasks сервис при создании
+// задачи, и согласует статус с done
+func (r *Record) Normalize() error {
+	r.Title = strings.TrimSpace(sanitize.SanitizeText(r.Title))
+	if r.Title == "" {
+		return errors.New("title is required")
+	}
+
+	description, err := sanitize.ValidateAndSanitizeDescription(r.Description)
+	if err != nil {
+		return err
+	}
+	r.Description = description
+
+	r.Priority = strings.ToLower(strings.TrimSpace(r.Priority))
+	if r.Priority != "" && !slices.Contains(Priorities, r.Priority) {
+		return errors.New("priority must be one of low, medium, high, urgent")
+	}
+
+	r.Status = strings.ToLower(strings.TrimSpace(r.Status))
+	switch {
+	case r.Status == "" && r.Done:
+		r.Status = "done"
+	case r.Status == "":
+		r.Status = "todo"
+	case !slices.Contains(Statuses, r.Status):
+		return fmt.Errorf("status must be one of %s", strings.Join(Statuses, ", "))
+	}
+	r.Done = r.Status == "done"
+
+	if r.EstimatePoints < 0 || r.EstimatePoints > 1000 {
+		return errors.New("estimate_points must be between 0 and 1000")
+	}
+	if r.EstimateMinutes < 0 || r.EstimateMinutes > 100000 {
+		return errors.New("estimate_minutes must be between 0 and 100000")
+	}
+
+	tags := make([]string, 0, len(r.Tags))
+	for _, tag := range r.Tags {
+		tag = strings.ToLower(strings.TrimSpace(sanitize.SanitizeText(tag)))
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > 50 {
+			return errors.New("tag name too long (max 50 characters)")
+		}
+		tags = append(tags, tag)
+	}
+	r.Tags = tags
+	return nil
+}
+
+// Key – ключ поиска повторов: название без учета регистра и срок
+func (r Record) Key() string {
+	return strings.ToLower(r.Title) + "\x00" + r.DueDate.String()
+}
+
+// ContentType возвращает тип содержимого файла формата
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatICal:
+		return "text/calendar; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// Write записывает задачи в формате format
+func Write(w io.Writer, format string, records []Record) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []Record{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case FormatCSV:
+		return writeCSV(w, records)
+	case FormatICal:
+		return WriteCalendar(w, Calendar{Name: "Tasks"}, records)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// Read читает не больше limit задач в формате format. Ошибка отдельной
+// записи возвращается в Item.Err, ошибка формата файла – вторым значением.
+func Read(r io.Reader, format string, limit int) ([]Item, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r, limit)
+	case FormatCSV:
+		return readCSV(r, limit)
+	case FormatICal:
+		return readCalendar(r, limit)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// readJSON читает массив задач; элементы разбираются по одному, чтобы
+// ошибка в одной задаче не отменяла остальные
+func readJSON(r io.Reader, limit int) ([]Item, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: expected an array of tasks")
+	}
+	if len(raw) > limit {
+		return nil, ErrTooManyRows
+	}
+
+	items := make([]Item, 0, len(raw))
+	for i, data := range raw {
+		item := Item{Line: i + 1}
+		if err := json.Unmarshal(data, &item.Record); err != nil {
+			item.Err = fmt.Errorf("invalid task: %w", unwrapJSONError(err))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func unwrapJSONError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("field %s has wrong type", typeErr.Field)
+	}
+	return err
+}
@@ -0,0 +1,211 @@
+package taskio
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"tech-ip-sem2/shared/due"
+)
+
+func testRecords() []Record {
+	at, _ := due.Parse("2026-03-10T15:00:00Z")
+	return []Record{
+		{
+			ID:              "t1",
+			Title:           "Write report; part 1, draft",
+			Description:     "Line one\nLine two",
+			DueDate:         due.Day(2026, 3, 10),
+			Status:          "review",
+			Priority:        "high",
+			Tags:            []string{"work", "q1,plan"},
+			EstimatePoints:  3,
+			EstimateMinutes: 90,
+		},
+		{ID: "t2", Title: "Call", DueDate: at, Done: true, Status: "done", Priority: "urgent"},
+		{ID: "t3", Title: "Someday" + strings.Repeat(" длинное название", 10), Status: "todo"},
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatCSV, FormatICal} {
+		t.Run(format, func(t *testing.T) {
+			records := testRecords()
+			var buf bytes.Buffer
+			if err := Write(&buf, format, records); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			items, err := Read(&buf, format, 10)
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			if len(items) != len(records) {
+				t.Fatalf("Expected %d items, got %d", len(records), len(items))
+			}
+			for i, item := range items {
+				want := records[i]
+				got := item.Record
+				// Запятая в метке CSV делит ее на две
+				if format == FormatCSV && i == 0 {
+					want.Tags = []string{"work", "q1", "plan"}
+				}
+				if item.Err != nil || got.ID != want.ID || got.Title != want.Title || got.Description != want.Description ||
+					!got.DueDate.Equal(want.DueDate) || got.Done != want.Done || got.Status != want.Status ||
+					got.Priority != want.Priority || !slices.Equal(got.Tags, want.Tags) {
+					t.Errorf("Item %d: expected %+v, got %+v (%v)", i, want, got, item.Err)
+				}
+				if format != FormatICal && (got.EstimatePoints != want.EstimatePoints || got.EstimateMinutes != want.EstimateMinutes) {
+					t.Errorf("Item %d: expected estimates to survive, got %+v", i, got)
+				}
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, "xml", nil); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestReadRowErrors(t *testing.T) {
+	csv := "\ufeffTitle,Done,Due_Date,Extra\nOk,yes,2026-03-10,x\nBad done,maybe,,\nBad due,,10.03.2026,\n"
+	items, err := Read(strings.NewReader(csv), FormatCSV, 10)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(items) != 3 || items[0].Err != nil || !items[0].Record.Done || items[1].Err == nil || items[2].Err == nil {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+	if items[1].Line != 3 {
+		t.Errorf("Expected line 3, got %d", items[1].Line)
+	}
+
+	if _, err := Read(strings.NewReader("name\nTask\n"), FormatCSV, 10); err == nil {
+		t.Error("Expected CSV without title column to be rejected")
+	}
+	if _, err := Read(strings.NewReader("title\na\nb\nc\n"), FormatCSV, 2); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("Expected ErrTooManyRows, got %v", err)
+	}
+
+	items, err = Read(strings.NewReader(`[{"title": "Ok"}, {"title": 5}]`), FormatJSON, 10)
+	if err != nil || len(items) != 2 || items[0].Err != nil || items[1].Err == nil || items[1].Line != 2 {
+		t.Errorf("Unexpected JSON items: %+v, %v", items, err)
+	}
+	if _, err := Read(strings.NewReader(`{"title": "Ok"}`), FormatJSON, 10); err == nil {
+		t.Error("Expected JSON object to be rejected")
+	}
+}
+
+func TestReadCalendar(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Moscow"); err != nil {
+		t.Skipf("Skipping calendar test - no time zone data: %v", err)
+	}
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		"SUMMARY:Folded",
+		"  title",
+		"DUE;TZID=Europe/Moscow:20260310T180000",
+		"STATUS:IN-PROCESS",
+		"PRIORITY:1",
+		"BEGIN:VALARM",
+		"SUMMARY:Alarm",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:Floating",
+		"DUE:20260310T180000",
+		"COMPLETED:20260310T190000Z",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	items, err := Read(strings.NewReader(ics), FormatICal, 10)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+	first := items[0].Record
+	if items[0].Err != nil || first.Title != "Folded title" || first.Status != "in_progress" || first.Priority != "urgent" {
+		t.Errorf("Unexpected first item: %+v, %v", first, items[0].Err)
+	}
+	if first.DueDate.String() != "2026-03-10T15:00:00Z" {
+		t.Errorf("Expected due in Moscow time, got %s", first.DueDate)
+	}
+	second := items[1].Record
+	if !second.Done || second.DueDate.String() != "2026-03-10T18:00:00" {
+		t.Errorf("Unexpected second item: %+v", second)
+	}
+
+	if _, err := Read(strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), FormatICal, 10); err == nil {
+		t.Error("Expected calendar without VTODO to be rejected")
+	}
+}
+
+func TestWriteCalendarFoldsLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCalendar(&buf, Calendar{Name: "Tasks", Events: true}, testRecords()); err != nil {
+		t.Fatalf("WriteCalendar failed: %v", err)
+	}
+
+	// Строки не длиннее 75 октетов, задачи без срока не попадают в события
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+	}
+	if strings.Count(buf.String(), "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected 2 events, got:\n%s", buf.String())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	r := Record{Title: "  Task  ", Done: true, Priority: " HIGH ", Tags: []string{"Work", "work", " ", "home"}}
+	if err := r.Normalize(); err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if r.Title != "Task" || r.Status != "done" || r.Priority != "high" || !slices.Equal(r.Tags, []string{"work", "home"}) {
+		t.Errorf("Unexpected normalized record: %+v", r)
+	}
+
+	// Статус определяет done
+	r = Record{Title: "Task", Done: true, Status: "review"}
+	if err := r.Normalize(); err != nil || r.Done {
+		t.Errorf("Expected status review to clear done, got %+v, %v", r, err)
+	}
+
+	for _, bad := range []Record{
+		{Title: " "},
+		{Title: "Task", Priority: "asap"},
+		{Title: "Task", Status: "blocked"},
+		{Title: "Task", EstimatePoints: -1},
+		{Title: "Task", Tags: []string{strings.Repeat("t", 51)}},
+	} {
+		if err := bad.Normalize(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestDedup(t *testing.T) {
+	dedup := NewDedup()
+	dedup.Add(Record{ID: "t1", Title: "Existing", DueDate: due.Day(2026, 3, 10)})
+
+	if !dedup.Seen(Record{ID: "t1", Title: "Renamed"}) {
+		t.Error("Expected record with existing id to be a duplicate")
+	}
+	if !dedup.Seen(Record{Title: "EXISTING", DueDate: due.Day(2026, 3, 10)}) {
+		t.Error("Expected record with same title and due date to be a duplicate")
+	}
+	if dedup.Seen(Record{Title: "Existing", DueDate: due.Day(2026, 3, 11)}) {
+		t.Error("Expected record with another due date to be new")
+	}
+	if !dedup.Seen(Record{Title: "Existing", DueDate: due.Day(2026, 3, 11)}) {
+		t.Error("Expected repeated record in the same file to be a duplicate")
+	}
+}